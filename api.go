@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	l "gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/brocaar/lorawan"
+	"github.com/iegomez/lds/lds"
+	"github.com/pkg/errors"
+	matx "github.com/scartill/giox/material"
+	log "github.com/sirupsen/logrus"
+)
+
+// apiServer is the HTTP API, when running.
+var apiServer *lds.APIServer
+
+var (
+	apiEnabledCheckbox widget.Bool
+	apiBindEdit        widget.Editor
+	apiStartButton     widget.Clickable
+	apiStopButton      widget.Clickable
+)
+
+func apiResetGuiValues() {
+	apiEnabledCheckbox.Value = config.API.Enabled
+	apiBindEdit.SetText(config.API.Bind)
+}
+
+func apiForm(th *material.Theme) l.FlexChild {
+
+	config.API.Enabled = apiEnabledCheckbox.Value
+	config.API.Bind = apiBindEdit.Text()
+
+	for apiStartButton.Clicked() {
+		startAPI()
+	}
+
+	for apiStopButton.Clicked() {
+		stopAPI()
+	}
+
+	widgets := []l.FlexChild{
+		matx.RigidSection(th, "HTTP API"),
+		matx.RigidCheckBox(th, "Start on launch", &apiEnabledCheckbox),
+		matx.RigidEditor(th, "Bind:", lds.DefaultAPIBind, &apiBindEdit),
+	}
+
+	if apiServer == nil {
+		widgets = append(widgets, matx.RigidButton(th, "Start", &apiStartButton))
+	} else {
+		widgets = append(widgets, matx.RigidLabel(th, fmt.Sprintf("Listening on %s", apiServer.Addr())))
+		widgets = append(widgets, matx.RigidButton(th, "Stop", &apiStopButton))
+	}
+
+	inset := l.Inset{Left: unit.Dp(30)}
+	return l.Rigid(func(gtx l.Context) l.Dimensions {
+		return inset.Layout(gtx, func(gtx l.Context) l.Dimensions {
+			return l.Flex{Axis: l.Vertical}.Layout(gtx, widgets...)
+		})
+	})
+}
+
+func startAPI() {
+	if apiServer != nil {
+		return
+	}
+
+	s, err := lds.NewAPIServer(config.API.Bind, guiTarget{}, config)
+	if err != nil {
+		log.Errorf("couldn't start the HTTP API: %s", err)
+		return
+	}
+	apiServer = s
+}
+
+func stopAPI() {
+	if apiServer == nil {
+		return
+	}
+
+	if err := apiServer.Close(); err != nil {
+		log.Errorf("HTTP API close error: %s", err)
+	}
+	apiServer = nil
+	log.Infoln("HTTP API stopped")
+}
+
+// startMetrics serves the Prometheus metrics until the GUI exits, the HTTP API serves them as well.
+func startMetrics() {
+	if _, err := lds.NewMetricsServer(config.Metrics.Bind); err != nil {
+		log.Errorf("couldn't serve the metrics: %s", err)
+	}
+}
+
+// The GUI always shows a device, it's considered deleted when it has no DevEUI.
+func hasDevice() bool {
+	return config.Device.DevEUI != ""
+}
+
+func (guiTarget) DeviceConfig() (lds.DeviceConfig, error) {
+	if !hasDevice() {
+		return lds.DeviceConfig{}, lds.ErrNoDevice
+	}
+	return config.Device, nil
+}
+
+func (guiTarget) PutDeviceConfig(dc lds.DeviceConfig, create bool) error {
+	if create && hasDevice() {
+		return lds.ErrDeviceExists
+	}
+
+	//The version and message type aren't part of the device json, keep the selected ones.
+	dc.Major = config.Device.Major
+	dc.MType = config.Device.MType
+	if dc.Marshaler == "" {
+		dc.Marshaler = config.Device.Marshaler
+	}
+
+	config.Device = dc
+	deviceResetGuiValues()
+	setDevice()
+	log.Infof("device %s set", dc.DevEUI)
+	return nil
+}
+
+func (guiTarget) DeleteDevice() error {
+	if !hasDevice() {
+		return lds.ErrNoDevice
+	}
+	if err := cDevice.Reset(); err != nil {
+		return err
+	}
+
+	config.Device = lds.DeviceConfig{
+		Marshaler: config.Device.Marshaler,
+		MType:     lorawan.UnconfirmedDataUp,
+		Profile:   "OTAA",
+	}
+	deviceResetGuiValues()
+	log.Warningln("Device was deleted")
+	return nil
+}
+
+func (guiTarget) SetValues(ulFcnt, dlFcnt, devNonce, joinNonce int) error {
+	if !hasDevice() {
+		return lds.ErrNoDevice
+	}
+	if err := cDevice.SetValues(ulFcnt, dlFcnt, devNonce, joinNonce); err != nil {
+		return err
+	}
+
+	ulFcntEdit.SetText(fmt.Sprintf("%d", ulFcnt))
+	dlFcntEdit.SetText(fmt.Sprintf("%d", dlFcnt))
+	devNonceEdit.SetText(fmt.Sprintf("%d", devNonce))
+	joinNonceEdit.SetText(fmt.Sprintf("%d", joinNonce))
+	return nil
+}
+
+// Start sends data as the Data tab "Send every X seconds" option does.
+func (guiTarget) Start(d time.Duration) error {
+	if !hasDevice() {
+		return lds.ErrNoDevice
+	}
+	if running {
+		return errors.New("already running")
+	}
+	if err := checkConnected(); err != nil {
+		return err
+	}
+
+	interval = int32(d / time.Second)
+	repeat = true
+	intervalEditor.SetText(fmt.Sprintf("%d", interval))
+	repeatCheckbox.Value = true
+	go run()
+	return nil
+}
+
+func (guiTarget) Stop() error {
+	running = false
+	return nil
+}
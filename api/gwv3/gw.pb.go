@@ -0,0 +1,701 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.23.0
+// 	protoc        (unknown)
+// source: gwv3/gw.proto
+
+// Package gwv3 holds the ChirpStack v3 gateway messages that were extended
+// after the gw package lds depends on, keeping their field numbers and JSON
+// names so that they are wire compatible with the ChirpStack Network Server.
+
+package gwv3
+
+import (
+	gw "github.com/brocaar/chirpstack-api/go/gw"
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type TxAckStatus int32
+
+const (
+	// Ignored (when a previous item was already emitted).
+	TxAckStatus_IGNORED TxAckStatus = 0
+	// Packet has been programmed for downlink.
+	TxAckStatus_OK TxAckStatus = 1
+	// Rejected because it was already too late to program this packet for downlink.
+	TxAckStatus_TOO_LATE TxAckStatus = 2
+	// Rejected because downlink packet timestamp is too much in advance.
+	TxAckStatus_TOO_EARLY TxAckStatus = 3
+	// Rejected because there was already a packet programmed in requested timeframe.
+	TxAckStatus_COLLISION_PACKET TxAckStatus = 4
+	// Rejected because there was already a beacon planned in requested timeframe.
+	TxAckStatus_COLLISION_BEACON TxAckStatus = 5
+	// Rejected because requested frequency is not supported by TX RF chain.
+	TxAckStatus_TX_FREQ TxAckStatus = 6
+	// Rejected because requested power is not supported by gateway.
+	TxAckStatus_TX_POWER TxAckStatus = 7
+	// Rejected because GPS is unlocked, so GPS timestamp cannot be used.
+	TxAckStatus_GPS_UNLOCKED TxAckStatus = 8
+	// Downlink queue is full.
+	TxAckStatus_QUEUE_FULL TxAckStatus = 9
+	// Internal error.
+	TxAckStatus_INTERNAL_ERROR TxAckStatus = 10
+)
+
+// Enum value maps for TxAckStatus.
+var (
+	TxAckStatus_name = map[int32]string{
+		0:  "IGNORED",
+		1:  "OK",
+		2:  "TOO_LATE",
+		3:  "TOO_EARLY",
+		4:  "COLLISION_PACKET",
+		5:  "COLLISION_BEACON",
+		6:  "TX_FREQ",
+		7:  "TX_POWER",
+		8:  "GPS_UNLOCKED",
+		9:  "QUEUE_FULL",
+		10: "INTERNAL_ERROR",
+	}
+	TxAckStatus_value = map[string]int32{
+		"IGNORED":          0,
+		"OK":               1,
+		"TOO_LATE":         2,
+		"TOO_EARLY":        3,
+		"COLLISION_PACKET": 4,
+		"COLLISION_BEACON": 5,
+		"TX_FREQ":          6,
+		"TX_POWER":         7,
+		"GPS_UNLOCKED":     8,
+		"QUEUE_FULL":       9,
+		"INTERNAL_ERROR":   10,
+	}
+)
+
+func (x TxAckStatus) Enum() *TxAckStatus {
+	p := new(TxAckStatus)
+	*p = x
+	return p
+}
+
+func (x TxAckStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TxAckStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_gwv3_gw_proto_enumTypes[0].Descriptor()
+}
+
+func (TxAckStatus) Type() protoreflect.EnumType {
+	return &file_gwv3_gw_proto_enumTypes[0]
+}
+
+func (x TxAckStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TxAckStatus.Descriptor instead.
+func (TxAckStatus) EnumDescriptor() ([]byte, []int) {
+	return file_gwv3_gw_proto_rawDescGZIP(), []int{0}
+}
+
+type ConnState_State int32
+
+const (
+	ConnState_OFFLINE ConnState_State = 0
+	ConnState_ONLINE  ConnState_State = 1
+)
+
+// Enum value maps for ConnState_State.
+var (
+	ConnState_State_name = map[int32]string{
+		0: "OFFLINE",
+		1: "ONLINE",
+	}
+	ConnState_State_value = map[string]int32{
+		"OFFLINE": 0,
+		"ONLINE":  1,
+	}
+)
+
+func (x ConnState_State) Enum() *ConnState_State {
+	p := new(ConnState_State)
+	*p = x
+	return p
+}
+
+func (x ConnState_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ConnState_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_gwv3_gw_proto_enumTypes[1].Descriptor()
+}
+
+func (ConnState_State) Type() protoreflect.EnumType {
+	return &file_gwv3_gw_proto_enumTypes[1]
+}
+
+func (x ConnState_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ConnState_State.Descriptor instead.
+func (ConnState_State) EnumDescriptor() ([]byte, []int) {
+	return file_gwv3_gw_proto_rawDescGZIP(), []int{4, 0}
+}
+
+type DownlinkFrame struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// PHYPayload.
+	// Deprecated: replaced by items.
+	PhyPayload []byte `protobuf:"bytes,1,opt,name=phy_payload,json=phyPayload,proto3" json:"phy_payload,omitempty"`
+	// TX meta-data.
+	// Deprecated: replaced by items.
+	TxInfo *gw.DownlinkTXInfo `protobuf:"bytes,2,opt,name=tx_info,json=txInfo,proto3" json:"tx_info,omitempty"`
+	// Token (uint16 value).
+	Token uint32 `protobuf:"varint,3,opt,name=token,proto3" json:"token,omitempty"`
+	// Downlink ID (UUID).
+	DownlinkId []byte `protobuf:"bytes,4,opt,name=downlink_id,json=downlinkID,proto3" json:"downlink_id,omitempty"`
+	// Downlink frame items.
+	// This makes it possible to send multiple downlink opportunities to the
+	// gateway at once (e.g. RX1 and RX2 in LoRaWAN). The first item has the
+	// highest priority, the last the lowest. The gateway will emit at most
+	// one item.
+	Items []*DownlinkFrameItem `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+	// Gateway ID.
+	GatewayId []byte `protobuf:"bytes,6,opt,name=gateway_id,json=gatewayID,proto3" json:"gateway_id,omitempty"`
+}
+
+func (x *DownlinkFrame) Reset() {
+	*x = DownlinkFrame{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gwv3_gw_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownlinkFrame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownlinkFrame) ProtoMessage() {}
+
+func (x *DownlinkFrame) ProtoReflect() protoreflect.Message {
+	mi := &file_gwv3_gw_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownlinkFrame.ProtoReflect.Descriptor instead.
+func (*DownlinkFrame) Descriptor() ([]byte, []int) {
+	return file_gwv3_gw_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DownlinkFrame) GetPhyPayload() []byte {
+	if x != nil {
+		return x.PhyPayload
+	}
+	return nil
+}
+
+func (x *DownlinkFrame) GetTxInfo() *gw.DownlinkTXInfo {
+	if x != nil {
+		return x.TxInfo
+	}
+	return nil
+}
+
+func (x *DownlinkFrame) GetToken() uint32 {
+	if x != nil {
+		return x.Token
+	}
+	return 0
+}
+
+func (x *DownlinkFrame) GetDownlinkId() []byte {
+	if x != nil {
+		return x.DownlinkId
+	}
+	return nil
+}
+
+func (x *DownlinkFrame) GetItems() []*DownlinkFrameItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *DownlinkFrame) GetGatewayId() []byte {
+	if x != nil {
+		return x.GatewayId
+	}
+	return nil
+}
+
+type DownlinkFrameItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// PHYPayload.
+	PhyPayload []byte `protobuf:"bytes,1,opt,name=phy_payload,json=phyPayload,proto3" json:"phy_payload,omitempty"`
+	// TX meta-data.
+	TxInfo *gw.DownlinkTXInfo `protobuf:"bytes,2,opt,name=tx_info,json=txInfo,proto3" json:"tx_info,omitempty"`
+}
+
+func (x *DownlinkFrameItem) Reset() {
+	*x = DownlinkFrameItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gwv3_gw_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownlinkFrameItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownlinkFrameItem) ProtoMessage() {}
+
+func (x *DownlinkFrameItem) ProtoReflect() protoreflect.Message {
+	mi := &file_gwv3_gw_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownlinkFrameItem.ProtoReflect.Descriptor instead.
+func (*DownlinkFrameItem) Descriptor() ([]byte, []int) {
+	return file_gwv3_gw_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DownlinkFrameItem) GetPhyPayload() []byte {
+	if x != nil {
+		return x.PhyPayload
+	}
+	return nil
+}
+
+func (x *DownlinkFrameItem) GetTxInfo() *gw.DownlinkTXInfo {
+	if x != nil {
+		return x.TxInfo
+	}
+	return nil
+}
+
+type DownlinkTXAck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Gateway ID.
+	GatewayId []byte `protobuf:"bytes,1,opt,name=gateway_id,json=gatewayID,proto3" json:"gateway_id,omitempty"`
+	// Token (uint16 value).
+	Token uint32 `protobuf:"varint,2,opt,name=token,proto3" json:"token,omitempty"`
+	// Error.
+	// Deprecated: replaced by items.
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// Downlink ID (UUID).
+	DownlinkId []byte `protobuf:"bytes,4,opt,name=downlink_id,json=downlinkID,proto3" json:"downlink_id,omitempty"`
+	// Downlink frame items.
+	// This list has the same length as the request and indicates which
+	// downlink frame has been emitted of the requested list (or why it failed).
+	Items []*DownlinkTXAckItem `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *DownlinkTXAck) Reset() {
+	*x = DownlinkTXAck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gwv3_gw_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownlinkTXAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownlinkTXAck) ProtoMessage() {}
+
+func (x *DownlinkTXAck) ProtoReflect() protoreflect.Message {
+	mi := &file_gwv3_gw_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownlinkTXAck.ProtoReflect.Descriptor instead.
+func (*DownlinkTXAck) Descriptor() ([]byte, []int) {
+	return file_gwv3_gw_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DownlinkTXAck) GetGatewayId() []byte {
+	if x != nil {
+		return x.GatewayId
+	}
+	return nil
+}
+
+func (x *DownlinkTXAck) GetToken() uint32 {
+	if x != nil {
+		return x.Token
+	}
+	return 0
+}
+
+func (x *DownlinkTXAck) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DownlinkTXAck) GetDownlinkId() []byte {
+	if x != nil {
+		return x.DownlinkId
+	}
+	return nil
+}
+
+func (x *DownlinkTXAck) GetItems() []*DownlinkTXAckItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type DownlinkTXAckItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The Ack status of this item.
+	Status TxAckStatus `protobuf:"varint,1,opt,name=status,proto3,enum=lds.gwv3.TxAckStatus" json:"status,omitempty"`
+}
+
+func (x *DownlinkTXAckItem) Reset() {
+	*x = DownlinkTXAckItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gwv3_gw_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownlinkTXAckItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownlinkTXAckItem) ProtoMessage() {}
+
+func (x *DownlinkTXAckItem) ProtoReflect() protoreflect.Message {
+	mi := &file_gwv3_gw_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownlinkTXAckItem.ProtoReflect.Descriptor instead.
+func (*DownlinkTXAckItem) Descriptor() ([]byte, []int) {
+	return file_gwv3_gw_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DownlinkTXAckItem) GetStatus() TxAckStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TxAckStatus_IGNORED
+}
+
+type ConnState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Gateway ID.
+	GatewayId []byte `protobuf:"bytes,1,opt,name=gateway_id,json=gatewayID,proto3" json:"gateway_id,omitempty"`
+	// Gateway connection state.
+	State ConnState_State `protobuf:"varint,2,opt,name=state,proto3,enum=lds.gwv3.ConnState_State" json:"state,omitempty"`
+}
+
+func (x *ConnState) Reset() {
+	*x = ConnState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gwv3_gw_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnState) ProtoMessage() {}
+
+func (x *ConnState) ProtoReflect() protoreflect.Message {
+	mi := &file_gwv3_gw_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnState.ProtoReflect.Descriptor instead.
+func (*ConnState) Descriptor() ([]byte, []int) {
+	return file_gwv3_gw_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ConnState) GetGatewayId() []byte {
+	if x != nil {
+		return x.GatewayId
+	}
+	return nil
+}
+
+func (x *ConnState) GetState() ConnState_State {
+	if x != nil {
+		return x.State
+	}
+	return ConnState_OFFLINE
+}
+
+var File_gwv3_gw_proto protoreflect.FileDescriptor
+
+var file_gwv3_gw_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x67, 0x77, 0x76, 0x33, 0x2f, 0x67, 0x77, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x08, 0x6c, 0x64, 0x73, 0x2e, 0x67, 0x77, 0x76, 0x33, 0x1a, 0x0b, 0x67, 0x77, 0x2f, 0x67, 0x77,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe6, 0x01, 0x0a, 0x0d, 0x44, 0x6f, 0x77, 0x6e, 0x6c,
+	0x69, 0x6e, 0x6b, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x68, 0x79, 0x5f,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x70,
+	0x68, 0x79, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x2b, 0x0a, 0x07, 0x74, 0x78, 0x5f,
+	0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x77, 0x2e,
+	0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x58, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06,
+	0x74, 0x78, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1f, 0x0a, 0x0b,
+	0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0a, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x49, 0x44, 0x12, 0x31, 0x0a,
+	0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c,
+	0x64, 0x73, 0x2e, 0x67, 0x77, 0x76, 0x33, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b,
+	0x46, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x49, 0x44, 0x22,
+	0x61, 0x0a, 0x11, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x46, 0x72, 0x61, 0x6d, 0x65,
+	0x49, 0x74, 0x65, 0x6d, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x68, 0x79, 0x5f, 0x70, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x70, 0x68, 0x79, 0x50, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x2b, 0x0a, 0x07, 0x74, 0x78, 0x5f, 0x69, 0x6e, 0x66, 0x6f,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x77, 0x2e, 0x44, 0x6f, 0x77, 0x6e,
+	0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x58, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x74, 0x78, 0x49, 0x6e,
+	0x66, 0x6f, 0x22, 0xae, 0x01, 0x0a, 0x0d, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x54,
+	0x58, 0x41, 0x63, 0x6b, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x49, 0x44,
+	0x12, 0x31, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x67, 0x77, 0x76, 0x33, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c,
+	0x69, 0x6e, 0x6b, 0x54, 0x58, 0x41, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74,
+	0x65, 0x6d, 0x73, 0x22, 0x42, 0x0a, 0x11, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x54,
+	0x58, 0x41, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x67,
+	0x77, 0x76, 0x33, 0x2e, 0x54, 0x78, 0x41, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x7d, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x6e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x49, 0x44, 0x12, 0x2f, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x19, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x67, 0x77, 0x76, 0x33, 0x2e, 0x43, 0x6f,
+	0x6e, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x22, 0x20, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a,
+	0x07, 0x4f, 0x46, 0x46, 0x4c, 0x49, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x4f, 0x4e,
+	0x4c, 0x49, 0x4e, 0x45, 0x10, 0x01, 0x2a, 0xbc, 0x01, 0x0a, 0x0b, 0x54, 0x78, 0x41, 0x63, 0x6b,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x47, 0x4e, 0x4f, 0x52, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x54,
+	0x4f, 0x4f, 0x5f, 0x4c, 0x41, 0x54, 0x45, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x4f, 0x4f,
+	0x5f, 0x45, 0x41, 0x52, 0x4c, 0x59, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x4f, 0x4c, 0x4c,
+	0x49, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x50, 0x41, 0x43, 0x4b, 0x45, 0x54, 0x10, 0x04, 0x12, 0x14,
+	0x0a, 0x10, 0x43, 0x4f, 0x4c, 0x4c, 0x49, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x42, 0x45, 0x41, 0x43,
+	0x4f, 0x4e, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x54, 0x58, 0x5f, 0x46, 0x52, 0x45, 0x51, 0x10,
+	0x06, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x58, 0x5f, 0x50, 0x4f, 0x57, 0x45, 0x52, 0x10, 0x07, 0x12,
+	0x10, 0x0a, 0x0c, 0x47, 0x50, 0x53, 0x5f, 0x55, 0x4e, 0x4c, 0x4f, 0x43, 0x4b, 0x45, 0x44, 0x10,
+	0x08, 0x12, 0x0e, 0x0a, 0x0a, 0x51, 0x55, 0x45, 0x55, 0x45, 0x5f, 0x46, 0x55, 0x4c, 0x4c, 0x10,
+	0x09, 0x12, 0x12, 0x0a, 0x0e, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x5f, 0x45, 0x52,
+	0x52, 0x4f, 0x52, 0x10, 0x0a, 0x42, 0x21, 0x5a, 0x1f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x69, 0x65, 0x67, 0x6f, 0x6d, 0x65, 0x7a, 0x2f, 0x6c, 0x64, 0x73, 0x2f,
+	0x61, 0x70, 0x69, 0x2f, 0x67, 0x77, 0x76, 0x33, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_gwv3_gw_proto_rawDescOnce sync.Once
+	file_gwv3_gw_proto_rawDescData = file_gwv3_gw_proto_rawDesc
+)
+
+func file_gwv3_gw_proto_rawDescGZIP() []byte {
+	file_gwv3_gw_proto_rawDescOnce.Do(func() {
+		file_gwv3_gw_proto_rawDescData = protoimpl.X.CompressGZIP(file_gwv3_gw_proto_rawDescData)
+	})
+	return file_gwv3_gw_proto_rawDescData
+}
+
+var file_gwv3_gw_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_gwv3_gw_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_gwv3_gw_proto_goTypes = []interface{}{
+	(TxAckStatus)(0),          // 0: lds.gwv3.TxAckStatus
+	(ConnState_State)(0),      // 1: lds.gwv3.ConnState.State
+	(*DownlinkFrame)(nil),     // 2: lds.gwv3.DownlinkFrame
+	(*DownlinkFrameItem)(nil), // 3: lds.gwv3.DownlinkFrameItem
+	(*DownlinkTXAck)(nil),     // 4: lds.gwv3.DownlinkTXAck
+	(*DownlinkTXAckItem)(nil), // 5: lds.gwv3.DownlinkTXAckItem
+	(*ConnState)(nil),         // 6: lds.gwv3.ConnState
+	(*gw.DownlinkTXInfo)(nil), // 7: gw.DownlinkTXInfo
+}
+var file_gwv3_gw_proto_depIdxs = []int32{
+	7, // 0: lds.gwv3.DownlinkFrame.tx_info:type_name -> gw.DownlinkTXInfo
+	3, // 1: lds.gwv3.DownlinkFrame.items:type_name -> lds.gwv3.DownlinkFrameItem
+	7, // 2: lds.gwv3.DownlinkFrameItem.tx_info:type_name -> gw.DownlinkTXInfo
+	5, // 3: lds.gwv3.DownlinkTXAck.items:type_name -> lds.gwv3.DownlinkTXAckItem
+	0, // 4: lds.gwv3.DownlinkTXAckItem.status:type_name -> lds.gwv3.TxAckStatus
+	1, // 5: lds.gwv3.ConnState.state:type_name -> lds.gwv3.ConnState.State
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_gwv3_gw_proto_init() }
+func file_gwv3_gw_proto_init() {
+	if File_gwv3_gw_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_gwv3_gw_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownlinkFrame); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gwv3_gw_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownlinkFrameItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gwv3_gw_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownlinkTXAck); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gwv3_gw_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownlinkTXAckItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gwv3_gw_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConnState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_gwv3_gw_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_gwv3_gw_proto_goTypes,
+		DependencyIndexes: file_gwv3_gw_proto_depIdxs,
+		EnumInfos:         file_gwv3_gw_proto_enumTypes,
+		MessageInfos:      file_gwv3_gw_proto_msgTypes,
+	}.Build()
+	File_gwv3_gw_proto = out.File
+	file_gwv3_gw_proto_rawDesc = nil
+	file_gwv3_gw_proto_goTypes = nil
+	file_gwv3_gw_proto_depIdxs = nil
+}
@@ -0,0 +1,2713 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.23.0
+// 	protoc        (unknown)
+// source: ldspb/lds.proto
+
+// Package ldspb is the gRPC API of the simulator. It drives the simulated device
+// and gateway, and streams the events of the device. The reception metadata and
+// gateway stats are the ChirpStack gw messages.
+
+package ldspb
+
+import (
+	context "context"
+	gw "github.com/brocaar/chirpstack-api/go/gw"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// MType is the LoRaWAN message type, with the values of the MHDR field.
+type MType int32
+
+const (
+	MType_JOIN_REQUEST          MType = 0
+	MType_JOIN_ACCEPT           MType = 1
+	MType_UNCONFIRMED_DATA_UP   MType = 2
+	MType_UNCONFIRMED_DATA_DOWN MType = 3
+	MType_CONFIRMED_DATA_UP     MType = 4
+	MType_CONFIRMED_DATA_DOWN   MType = 5
+	MType_REJOIN_REQUEST        MType = 6
+	MType_PROPRIETARY           MType = 7
+)
+
+// Enum value maps for MType.
+var (
+	MType_name = map[int32]string{
+		0: "JOIN_REQUEST",
+		1: "JOIN_ACCEPT",
+		2: "UNCONFIRMED_DATA_UP",
+		3: "UNCONFIRMED_DATA_DOWN",
+		4: "CONFIRMED_DATA_UP",
+		5: "CONFIRMED_DATA_DOWN",
+		6: "REJOIN_REQUEST",
+		7: "PROPRIETARY",
+	}
+	MType_value = map[string]int32{
+		"JOIN_REQUEST":          0,
+		"JOIN_ACCEPT":           1,
+		"UNCONFIRMED_DATA_UP":   2,
+		"UNCONFIRMED_DATA_DOWN": 3,
+		"CONFIRMED_DATA_UP":     4,
+		"CONFIRMED_DATA_DOWN":   5,
+		"REJOIN_REQUEST":        6,
+		"PROPRIETARY":           7,
+	}
+)
+
+func (x MType) Enum() *MType {
+	p := new(MType)
+	*p = x
+	return p
+}
+
+func (x MType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MType) Descriptor() protoreflect.EnumDescriptor {
+	return file_ldspb_lds_proto_enumTypes[0].Descriptor()
+}
+
+func (MType) Type() protoreflect.EnumType {
+	return &file_ldspb_lds_proto_enumTypes[0]
+}
+
+func (x MType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MType.Descriptor instead.
+func (MType) EnumDescriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{0}
+}
+
+type Profile int32
+
+const (
+	Profile_OTAA Profile = 0
+	Profile_ABP  Profile = 1
+)
+
+// Enum value maps for Profile.
+var (
+	Profile_name = map[int32]string{
+		0: "OTAA",
+		1: "ABP",
+	}
+	Profile_value = map[string]int32{
+		"OTAA": 0,
+		"ABP":  1,
+	}
+)
+
+func (x Profile) Enum() *Profile {
+	p := new(Profile)
+	*p = x
+	return p
+}
+
+func (x Profile) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Profile) Descriptor() protoreflect.EnumDescriptor {
+	return file_ldspb_lds_proto_enumTypes[1].Descriptor()
+}
+
+func (Profile) Type() protoreflect.EnumType {
+	return &file_ldspb_lds_proto_enumTypes[1]
+}
+
+func (x Profile) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Profile.Descriptor instead.
+func (Profile) EnumDescriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{1}
+}
+
+// MACVersion is the LoRaWAN MAC version of the device.
+type MACVersion int32
+
+const (
+	MACVersion_LORAWAN_1_0 MACVersion = 0
+	MACVersion_LORAWAN_1_1 MACVersion = 1
+)
+
+// Enum value maps for MACVersion.
+var (
+	MACVersion_name = map[int32]string{
+		0: "LORAWAN_1_0",
+		1: "LORAWAN_1_1",
+	}
+	MACVersion_value = map[string]int32{
+		"LORAWAN_1_0": 0,
+		"LORAWAN_1_1": 1,
+	}
+)
+
+func (x MACVersion) Enum() *MACVersion {
+	p := new(MACVersion)
+	*p = x
+	return p
+}
+
+func (x MACVersion) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MACVersion) Descriptor() protoreflect.EnumDescriptor {
+	return file_ldspb_lds_proto_enumTypes[2].Descriptor()
+}
+
+func (MACVersion) Type() protoreflect.EnumType {
+	return &file_ldspb_lds_proto_enumTypes[2]
+}
+
+func (x MACVersion) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MACVersion.Descriptor instead.
+func (MACVersion) EnumDescriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{2}
+}
+
+type Device struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DevEui     []byte     `protobuf:"bytes,1,opt,name=dev_eui,json=devEui,proto3" json:"dev_eui,omitempty"`
+	JoinEui    []byte     `protobuf:"bytes,2,opt,name=join_eui,json=joinEui,proto3" json:"join_eui,omitempty"`
+	NwkKey     []byte     `protobuf:"bytes,3,opt,name=nwk_key,json=nwkKey,proto3" json:"nwk_key,omitempty"`
+	AppKey     []byte     `protobuf:"bytes,4,opt,name=app_key,json=appKey,proto3" json:"app_key,omitempty"`
+	Profile    Profile    `protobuf:"varint,5,opt,name=profile,proto3,enum=lds.ldspb.Profile" json:"profile,omitempty"`
+	MacVersion MACVersion `protobuf:"varint,6,opt,name=mac_version,json=macVersion,proto3,enum=lds.ldspb.MACVersion" json:"mac_version,omitempty"`
+	// Session of an ABP or already joined device, missing fields are zero filled.
+	DevAddr       []byte `protobuf:"bytes,7,opt,name=dev_addr,json=devAddr,proto3" json:"dev_addr,omitempty"`
+	NwkSEncKey    []byte `protobuf:"bytes,8,opt,name=nwk_s_enc_key,json=nwkSEncKey,proto3" json:"nwk_s_enc_key,omitempty"`
+	SNwkSIntKey   []byte `protobuf:"bytes,9,opt,name=s_nwk_s_int_key,json=sNwkSIntKey,proto3" json:"s_nwk_s_int_key,omitempty"`
+	FNwkSIntKey   []byte `protobuf:"bytes,10,opt,name=f_nwk_s_int_key,json=fNwkSIntKey,proto3" json:"f_nwk_s_int_key,omitempty"`
+	AppSKey       []byte `protobuf:"bytes,11,opt,name=app_s_key,json=appSKey,proto3" json:"app_s_key,omitempty"`
+	Joined        bool   `protobuf:"varint,12,opt,name=joined,proto3" json:"joined,omitempty"`
+	SkipFCntCheck bool   `protobuf:"varint,13,opt,name=skip_f_cnt_check,json=skipFCntCheck,proto3" json:"skip_f_cnt_check,omitempty"`
+	// Marshaler of the gateway bridge messages, the configured one is kept when empty.
+	Marshaler string `protobuf:"bytes,14,opt,name=marshaler,proto3" json:"marshaler,omitempty"`
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Device) GetDevEui() []byte {
+	if x != nil {
+		return x.DevEui
+	}
+	return nil
+}
+
+func (x *Device) GetJoinEui() []byte {
+	if x != nil {
+		return x.JoinEui
+	}
+	return nil
+}
+
+func (x *Device) GetNwkKey() []byte {
+	if x != nil {
+		return x.NwkKey
+	}
+	return nil
+}
+
+func (x *Device) GetAppKey() []byte {
+	if x != nil {
+		return x.AppKey
+	}
+	return nil
+}
+
+func (x *Device) GetProfile() Profile {
+	if x != nil {
+		return x.Profile
+	}
+	return Profile_OTAA
+}
+
+func (x *Device) GetMacVersion() MACVersion {
+	if x != nil {
+		return x.MacVersion
+	}
+	return MACVersion_LORAWAN_1_0
+}
+
+func (x *Device) GetDevAddr() []byte {
+	if x != nil {
+		return x.DevAddr
+	}
+	return nil
+}
+
+func (x *Device) GetNwkSEncKey() []byte {
+	if x != nil {
+		return x.NwkSEncKey
+	}
+	return nil
+}
+
+func (x *Device) GetSNwkSIntKey() []byte {
+	if x != nil {
+		return x.SNwkSIntKey
+	}
+	return nil
+}
+
+func (x *Device) GetFNwkSIntKey() []byte {
+	if x != nil {
+		return x.FNwkSIntKey
+	}
+	return nil
+}
+
+func (x *Device) GetAppSKey() []byte {
+	if x != nil {
+		return x.AppSKey
+	}
+	return nil
+}
+
+func (x *Device) GetJoined() bool {
+	if x != nil {
+		return x.Joined
+	}
+	return false
+}
+
+func (x *Device) GetSkipFCntCheck() bool {
+	if x != nil {
+		return x.SkipFCntCheck
+	}
+	return false
+}
+
+func (x *Device) GetMarshaler() string {
+	if x != nil {
+		return x.Marshaler
+	}
+	return ""
+}
+
+type Session struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DevEui      []byte  `protobuf:"bytes,1,opt,name=dev_eui,json=devEui,proto3" json:"dev_eui,omitempty"`
+	Profile     Profile `protobuf:"varint,2,opt,name=profile,proto3,enum=lds.ldspb.Profile" json:"profile,omitempty"`
+	Joined      bool    `protobuf:"varint,3,opt,name=joined,proto3" json:"joined,omitempty"`
+	DevAddr     []byte  `protobuf:"bytes,4,opt,name=dev_addr,json=devAddr,proto3" json:"dev_addr,omitempty"`
+	UlFCnt      uint32  `protobuf:"varint,5,opt,name=ul_f_cnt,json=ulFCnt,proto3" json:"ul_f_cnt,omitempty"`
+	DlFCnt      uint32  `protobuf:"varint,6,opt,name=dl_f_cnt,json=dlFCnt,proto3" json:"dl_f_cnt,omitempty"`
+	DevNonce    uint32  `protobuf:"varint,7,opt,name=dev_nonce,json=devNonce,proto3" json:"dev_nonce,omitempty"`
+	JoinNonce   uint32  `protobuf:"varint,8,opt,name=join_nonce,json=joinNonce,proto3" json:"join_nonce,omitempty"`
+	NwkSEncKey  []byte  `protobuf:"bytes,9,opt,name=nwk_s_enc_key,json=nwkSEncKey,proto3" json:"nwk_s_enc_key,omitempty"`
+	SNwkSIntKey []byte  `protobuf:"bytes,10,opt,name=s_nwk_s_int_key,json=sNwkSIntKey,proto3" json:"s_nwk_s_int_key,omitempty"`
+	FNwkSIntKey []byte  `protobuf:"bytes,11,opt,name=f_nwk_s_int_key,json=fNwkSIntKey,proto3" json:"f_nwk_s_int_key,omitempty"`
+	AppSKey     []byte  `protobuf:"bytes,12,opt,name=app_s_key,json=appSKey,proto3" json:"app_s_key,omitempty"`
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Session) GetDevEui() []byte {
+	if x != nil {
+		return x.DevEui
+	}
+	return nil
+}
+
+func (x *Session) GetProfile() Profile {
+	if x != nil {
+		return x.Profile
+	}
+	return Profile_OTAA
+}
+
+func (x *Session) GetJoined() bool {
+	if x != nil {
+		return x.Joined
+	}
+	return false
+}
+
+func (x *Session) GetDevAddr() []byte {
+	if x != nil {
+		return x.DevAddr
+	}
+	return nil
+}
+
+func (x *Session) GetUlFCnt() uint32 {
+	if x != nil {
+		return x.UlFCnt
+	}
+	return 0
+}
+
+func (x *Session) GetDlFCnt() uint32 {
+	if x != nil {
+		return x.DlFCnt
+	}
+	return 0
+}
+
+func (x *Session) GetDevNonce() uint32 {
+	if x != nil {
+		return x.DevNonce
+	}
+	return 0
+}
+
+func (x *Session) GetJoinNonce() uint32 {
+	if x != nil {
+		return x.JoinNonce
+	}
+	return 0
+}
+
+func (x *Session) GetNwkSEncKey() []byte {
+	if x != nil {
+		return x.NwkSEncKey
+	}
+	return nil
+}
+
+func (x *Session) GetSNwkSIntKey() []byte {
+	if x != nil {
+		return x.SNwkSIntKey
+	}
+	return nil
+}
+
+func (x *Session) GetFNwkSIntKey() []byte {
+	if x != nil {
+		return x.FNwkSIntKey
+	}
+	return nil
+}
+
+func (x *Session) GetAppSKey() []byte {
+	if x != nil {
+		return x.AppSKey
+	}
+	return nil
+}
+
+type Counters struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UlFCnt    uint32 `protobuf:"varint,1,opt,name=ul_f_cnt,json=ulFCnt,proto3" json:"ul_f_cnt,omitempty"`
+	DlFCnt    uint32 `protobuf:"varint,2,opt,name=dl_f_cnt,json=dlFCnt,proto3" json:"dl_f_cnt,omitempty"`
+	DevNonce  uint32 `protobuf:"varint,3,opt,name=dev_nonce,json=devNonce,proto3" json:"dev_nonce,omitempty"`
+	JoinNonce uint32 `protobuf:"varint,4,opt,name=join_nonce,json=joinNonce,proto3" json:"join_nonce,omitempty"`
+}
+
+func (x *Counters) Reset() {
+	*x = Counters{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Counters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Counters) ProtoMessage() {}
+
+func (x *Counters) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Counters.ProtoReflect.Descriptor instead.
+func (*Counters) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Counters) GetUlFCnt() uint32 {
+	if x != nil {
+		return x.UlFCnt
+	}
+	return 0
+}
+
+func (x *Counters) GetDlFCnt() uint32 {
+	if x != nil {
+		return x.DlFCnt
+	}
+	return 0
+}
+
+func (x *Counters) GetDevNonce() uint32 {
+	if x != nil {
+		return x.DevNonce
+	}
+	return 0
+}
+
+func (x *Counters) GetJoinNonce() uint32 {
+	if x != nil {
+		return x.JoinNonce
+	}
+	return 0
+}
+
+type Gateway struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Gateway ID, read only.
+	GatewayId []byte `protobuf:"bytes,1,opt,name=gateway_id,json=gatewayId,proto3" json:"gateway_id,omitempty"`
+	// LoRaWAN band name, e.g. EU868.
+	Band string `protobuf:"bytes,2,opt,name=band,proto3" json:"band,omitempty"`
+	// Reception metadata given to the next uplinks.
+	Frequency          uint32                 `protobuf:"varint,3,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	LoraModulationInfo *gw.LoRaModulationInfo `protobuf:"bytes,4,opt,name=lora_modulation_info,json=loraModulationInfo,proto3" json:"lora_modulation_info,omitempty"`
+	Rssi               int32                  `protobuf:"varint,5,opt,name=rssi,proto3" json:"rssi,omitempty"`
+	LoraSnr            float64                `protobuf:"fixed64,6,opt,name=lora_snr,json=loraSnr,proto3" json:"lora_snr,omitempty"`
+	Channel            uint32                 `protobuf:"varint,7,opt,name=channel,proto3" json:"channel,omitempty"`
+	RfChain            uint32                 `protobuf:"varint,8,opt,name=rf_chain,json=rfChain,proto3" json:"rf_chain,omitempty"`
+	CrcStatus          int32                  `protobuf:"varint,9,opt,name=crc_status,json=crcStatus,proto3" json:"crc_status,omitempty"`
+	// Packet counters, read only.
+	Stats *gw.GatewayStats `protobuf:"bytes,10,opt,name=stats,proto3" json:"stats,omitempty"`
+	// Downlinks scheduled by the network server with a wrong context or timing, read only.
+	SchedulingErrors uint64 `protobuf:"varint,11,opt,name=scheduling_errors,json=schedulingErrors,proto3" json:"scheduling_errors,omitempty"`
+}
+
+func (x *Gateway) Reset() {
+	*x = Gateway{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Gateway) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Gateway) ProtoMessage() {}
+
+func (x *Gateway) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Gateway.ProtoReflect.Descriptor instead.
+func (*Gateway) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Gateway) GetGatewayId() []byte {
+	if x != nil {
+		return x.GatewayId
+	}
+	return nil
+}
+
+func (x *Gateway) GetBand() string {
+	if x != nil {
+		return x.Band
+	}
+	return ""
+}
+
+func (x *Gateway) GetFrequency() uint32 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+func (x *Gateway) GetLoraModulationInfo() *gw.LoRaModulationInfo {
+	if x != nil {
+		return x.LoraModulationInfo
+	}
+	return nil
+}
+
+func (x *Gateway) GetRssi() int32 {
+	if x != nil {
+		return x.Rssi
+	}
+	return 0
+}
+
+func (x *Gateway) GetLoraSnr() float64 {
+	if x != nil {
+		return x.LoraSnr
+	}
+	return 0
+}
+
+func (x *Gateway) GetChannel() uint32 {
+	if x != nil {
+		return x.Channel
+	}
+	return 0
+}
+
+func (x *Gateway) GetRfChain() uint32 {
+	if x != nil {
+		return x.RfChain
+	}
+	return 0
+}
+
+func (x *Gateway) GetCrcStatus() int32 {
+	if x != nil {
+		return x.CrcStatus
+	}
+	return 0
+}
+
+func (x *Gateway) GetStats() *gw.GatewayStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *Gateway) GetSchedulingErrors() uint64 {
+	if x != nil {
+		return x.SchedulingErrors
+	}
+	return 0
+}
+
+type FCtrl struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Adr       bool `protobuf:"varint,1,opt,name=adr,proto3" json:"adr,omitempty"`
+	AdrAckReq bool `protobuf:"varint,2,opt,name=adr_ack_req,json=adrAckReq,proto3" json:"adr_ack_req,omitempty"`
+	Ack       bool `protobuf:"varint,3,opt,name=ack,proto3" json:"ack,omitempty"`
+	FPending  bool `protobuf:"varint,4,opt,name=f_pending,json=fPending,proto3" json:"f_pending,omitempty"`
+	ClassB    bool `protobuf:"varint,5,opt,name=class_b,json=classB,proto3" json:"class_b,omitempty"`
+}
+
+func (x *FCtrl) Reset() {
+	*x = FCtrl{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FCtrl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FCtrl) ProtoMessage() {}
+
+func (x *FCtrl) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FCtrl.ProtoReflect.Descriptor instead.
+func (*FCtrl) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FCtrl) GetAdr() bool {
+	if x != nil {
+		return x.Adr
+	}
+	return false
+}
+
+func (x *FCtrl) GetAdrAckReq() bool {
+	if x != nil {
+		return x.AdrAckReq
+	}
+	return false
+}
+
+func (x *FCtrl) GetAck() bool {
+	if x != nil {
+		return x.Ack
+	}
+	return false
+}
+
+func (x *FCtrl) GetFPending() bool {
+	if x != nil {
+		return x.FPending
+	}
+	return false
+}
+
+func (x *FCtrl) GetClassB() bool {
+	if x != nil {
+		return x.ClassB
+	}
+	return false
+}
+
+type MACCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cid     uint32 `protobuf:"varint,1,opt,name=cid,proto3" json:"cid,omitempty"`
+	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (x *MACCommand) Reset() {
+	*x = MACCommand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MACCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MACCommand) ProtoMessage() {}
+
+func (x *MACCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MACCommand.ProtoReflect.Descriptor instead.
+func (*MACCommand) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MACCommand) GetCid() uint32 {
+	if x != nil {
+		return x.Cid
+	}
+	return 0
+}
+
+func (x *MACCommand) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type UplinkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Payload, the configured data is sent when empty.
+	FrmPayload []byte `protobuf:"bytes,1,opt,name=frm_payload,json=frmPayload,proto3" json:"frm_payload,omitempty"`
+	// FPort, the configured one is used when not set.
+	FPort *wrapperspb.UInt32Value `protobuf:"bytes,2,opt,name=f_port,json=fPort,proto3" json:"f_port,omitempty"`
+	// Confirmed, the configured message type is used when not set.
+	Confirmed *wrapperspb.BoolValue `protobuf:"bytes,3,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	// MAC commands sent in FOpts.
+	MacCommands []*MACCommand `protobuf:"bytes,4,rep,name=mac_commands,json=macCommands,proto3" json:"mac_commands,omitempty"`
+	FCtrl       *FCtrl        `protobuf:"bytes,5,opt,name=f_ctrl,json=fCtrl,proto3" json:"f_ctrl,omitempty"`
+}
+
+func (x *UplinkRequest) Reset() {
+	*x = UplinkRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UplinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UplinkRequest) ProtoMessage() {}
+
+func (x *UplinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UplinkRequest.ProtoReflect.Descriptor instead.
+func (*UplinkRequest) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UplinkRequest) GetFrmPayload() []byte {
+	if x != nil {
+		return x.FrmPayload
+	}
+	return nil
+}
+
+func (x *UplinkRequest) GetFPort() *wrapperspb.UInt32Value {
+	if x != nil {
+		return x.FPort
+	}
+	return nil
+}
+
+func (x *UplinkRequest) GetConfirmed() *wrapperspb.BoolValue {
+	if x != nil {
+		return x.Confirmed
+	}
+	return nil
+}
+
+func (x *UplinkRequest) GetMacCommands() []*MACCommand {
+	if x != nil {
+		return x.MacCommands
+	}
+	return nil
+}
+
+func (x *UplinkRequest) GetFCtrl() *FCtrl {
+	if x != nil {
+		return x.FCtrl
+	}
+	return nil
+}
+
+type UplinkResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Uplink frame counter after the uplink.
+	FCnt uint32 `protobuf:"varint,1,opt,name=f_cnt,json=fCnt,proto3" json:"f_cnt,omitempty"`
+}
+
+func (x *UplinkResponse) Reset() {
+	*x = UplinkResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UplinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UplinkResponse) ProtoMessage() {}
+
+func (x *UplinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UplinkResponse.ProtoReflect.Descriptor instead.
+func (*UplinkResponse) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UplinkResponse) GetFCnt() uint32 {
+	if x != nil {
+		return x.FCnt
+	}
+	return 0
+}
+
+type StartRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Interval *durationpb.Duration `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+}
+
+func (x *StartRequest) Reset() {
+	*x = StartRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRequest) ProtoMessage() {}
+
+func (x *StartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRequest.ProtoReflect.Descriptor instead.
+func (*StartRequest) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StartRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+type SubscribeEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SubscribeEventsRequest) Reset() {
+	*x = SubscribeEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeEventsRequest) ProtoMessage() {}
+
+func (x *SubscribeEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeEventsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeEventsRequest) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{9}
+}
+
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	DevEui []byte                 `protobuf:"bytes,2,opt,name=dev_eui,json=devEui,proto3" json:"dev_eui,omitempty"`
+	// Types that are assignable to Event:
+	//	*Event_UplinkSent
+	//	*Event_DownlinkReceived
+	//	*Event_JoinAccepted
+	//	*Event_MicFailure
+	Event isEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Event) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Event) GetDevEui() []byte {
+	if x != nil {
+		return x.DevEui
+	}
+	return nil
+}
+
+func (m *Event) GetEvent() isEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *Event) GetUplinkSent() *UplinkSentEvent {
+	if x, ok := x.GetEvent().(*Event_UplinkSent); ok {
+		return x.UplinkSent
+	}
+	return nil
+}
+
+func (x *Event) GetDownlinkReceived() *DownlinkReceivedEvent {
+	if x, ok := x.GetEvent().(*Event_DownlinkReceived); ok {
+		return x.DownlinkReceived
+	}
+	return nil
+}
+
+func (x *Event) GetJoinAccepted() *JoinAcceptedEvent {
+	if x, ok := x.GetEvent().(*Event_JoinAccepted); ok {
+		return x.JoinAccepted
+	}
+	return nil
+}
+
+func (x *Event) GetMicFailure() *MICFailureEvent {
+	if x, ok := x.GetEvent().(*Event_MicFailure); ok {
+		return x.MicFailure
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_UplinkSent struct {
+	UplinkSent *UplinkSentEvent `protobuf:"bytes,3,opt,name=uplink_sent,json=uplinkSent,proto3,oneof"`
+}
+
+type Event_DownlinkReceived struct {
+	DownlinkReceived *DownlinkReceivedEvent `protobuf:"bytes,4,opt,name=downlink_received,json=downlinkReceived,proto3,oneof"`
+}
+
+type Event_JoinAccepted struct {
+	JoinAccepted *JoinAcceptedEvent `protobuf:"bytes,5,opt,name=join_accepted,json=joinAccepted,proto3,oneof"`
+}
+
+type Event_MicFailure struct {
+	MicFailure *MICFailureEvent `protobuf:"bytes,6,opt,name=mic_failure,json=micFailure,proto3,oneof"`
+}
+
+func (*Event_UplinkSent) isEvent_Event() {}
+
+func (*Event_DownlinkReceived) isEvent_Event() {}
+
+func (*Event_JoinAccepted) isEvent_Event() {}
+
+func (*Event_MicFailure) isEvent_Event() {}
+
+// UplinkSentEvent is a join request or data uplink received by the gateway and sent to the network server.
+type UplinkSentEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MType      MType            `protobuf:"varint,1,opt,name=m_type,json=mType,proto3,enum=lds.ldspb.MType" json:"m_type,omitempty"`
+	PhyPayload []byte           `protobuf:"bytes,2,opt,name=phy_payload,json=phyPayload,proto3" json:"phy_payload,omitempty"`
+	RxInfo     *gw.UplinkRXInfo `protobuf:"bytes,3,opt,name=rx_info,json=rxInfo,proto3" json:"rx_info,omitempty"`
+	TxInfo     *gw.UplinkTXInfo `protobuf:"bytes,4,opt,name=tx_info,json=txInfo,proto3" json:"tx_info,omitempty"`
+	// Data uplink fields.
+	DevAddr     []byte        `protobuf:"bytes,5,opt,name=dev_addr,json=devAddr,proto3" json:"dev_addr,omitempty"`
+	FCtrl       *FCtrl        `protobuf:"bytes,6,opt,name=f_ctrl,json=fCtrl,proto3" json:"f_ctrl,omitempty"`
+	FCnt        uint32        `protobuf:"varint,7,opt,name=f_cnt,json=fCnt,proto3" json:"f_cnt,omitempty"`
+	FPort       uint32        `protobuf:"varint,8,opt,name=f_port,json=fPort,proto3" json:"f_port,omitempty"`
+	FrmPayload  []byte        `protobuf:"bytes,9,opt,name=frm_payload,json=frmPayload,proto3" json:"frm_payload,omitempty"`
+	MacCommands []*MACCommand `protobuf:"bytes,10,rep,name=mac_commands,json=macCommands,proto3" json:"mac_commands,omitempty"`
+	// Join request field.
+	DevNonce uint32 `protobuf:"varint,11,opt,name=dev_nonce,json=devNonce,proto3" json:"dev_nonce,omitempty"`
+}
+
+func (x *UplinkSentEvent) Reset() {
+	*x = UplinkSentEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UplinkSentEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UplinkSentEvent) ProtoMessage() {}
+
+func (x *UplinkSentEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UplinkSentEvent.ProtoReflect.Descriptor instead.
+func (*UplinkSentEvent) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UplinkSentEvent) GetMType() MType {
+	if x != nil {
+		return x.MType
+	}
+	return MType_JOIN_REQUEST
+}
+
+func (x *UplinkSentEvent) GetPhyPayload() []byte {
+	if x != nil {
+		return x.PhyPayload
+	}
+	return nil
+}
+
+func (x *UplinkSentEvent) GetRxInfo() *gw.UplinkRXInfo {
+	if x != nil {
+		return x.RxInfo
+	}
+	return nil
+}
+
+func (x *UplinkSentEvent) GetTxInfo() *gw.UplinkTXInfo {
+	if x != nil {
+		return x.TxInfo
+	}
+	return nil
+}
+
+func (x *UplinkSentEvent) GetDevAddr() []byte {
+	if x != nil {
+		return x.DevAddr
+	}
+	return nil
+}
+
+func (x *UplinkSentEvent) GetFCtrl() *FCtrl {
+	if x != nil {
+		return x.FCtrl
+	}
+	return nil
+}
+
+func (x *UplinkSentEvent) GetFCnt() uint32 {
+	if x != nil {
+		return x.FCnt
+	}
+	return 0
+}
+
+func (x *UplinkSentEvent) GetFPort() uint32 {
+	if x != nil {
+		return x.FPort
+	}
+	return 0
+}
+
+func (x *UplinkSentEvent) GetFrmPayload() []byte {
+	if x != nil {
+		return x.FrmPayload
+	}
+	return nil
+}
+
+func (x *UplinkSentEvent) GetMacCommands() []*MACCommand {
+	if x != nil {
+		return x.MacCommands
+	}
+	return nil
+}
+
+func (x *UplinkSentEvent) GetDevNonce() uint32 {
+	if x != nil {
+		return x.DevNonce
+	}
+	return 0
+}
+
+// DownlinkReceivedEvent is a data downlink processed by the device, with its payload decrypted.
+type DownlinkReceivedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MType   MType  `protobuf:"varint,1,opt,name=m_type,json=mType,proto3,enum=lds.ldspb.MType" json:"m_type,omitempty"`
+	DevAddr []byte `protobuf:"bytes,2,opt,name=dev_addr,json=devAddr,proto3" json:"dev_addr,omitempty"`
+	FCtrl   *FCtrl `protobuf:"bytes,3,opt,name=f_ctrl,json=fCtrl,proto3" json:"f_ctrl,omitempty"`
+	FCnt    uint32 `protobuf:"varint,4,opt,name=f_cnt,json=fCnt,proto3" json:"f_cnt,omitempty"`
+	// FPort isn't set when the frame has no payload.
+	FPort      *wrapperspb.UInt32Value `protobuf:"bytes,5,opt,name=f_port,json=fPort,proto3" json:"f_port,omitempty"`
+	FrmPayload []byte                  `protobuf:"bytes,6,opt,name=frm_payload,json=frmPayload,proto3" json:"frm_payload,omitempty"`
+	// MAC commands sent either in FOpts or on FPort 0.
+	MacCommands []*MACCommand `protobuf:"bytes,7,rep,name=mac_commands,json=macCommands,proto3" json:"mac_commands,omitempty"`
+}
+
+func (x *DownlinkReceivedEvent) Reset() {
+	*x = DownlinkReceivedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownlinkReceivedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownlinkReceivedEvent) ProtoMessage() {}
+
+func (x *DownlinkReceivedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownlinkReceivedEvent.ProtoReflect.Descriptor instead.
+func (*DownlinkReceivedEvent) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DownlinkReceivedEvent) GetMType() MType {
+	if x != nil {
+		return x.MType
+	}
+	return MType_JOIN_REQUEST
+}
+
+func (x *DownlinkReceivedEvent) GetDevAddr() []byte {
+	if x != nil {
+		return x.DevAddr
+	}
+	return nil
+}
+
+func (x *DownlinkReceivedEvent) GetFCtrl() *FCtrl {
+	if x != nil {
+		return x.FCtrl
+	}
+	return nil
+}
+
+func (x *DownlinkReceivedEvent) GetFCnt() uint32 {
+	if x != nil {
+		return x.FCnt
+	}
+	return 0
+}
+
+func (x *DownlinkReceivedEvent) GetFPort() *wrapperspb.UInt32Value {
+	if x != nil {
+		return x.FPort
+	}
+	return nil
+}
+
+func (x *DownlinkReceivedEvent) GetFrmPayload() []byte {
+	if x != nil {
+		return x.FrmPayload
+	}
+	return nil
+}
+
+func (x *DownlinkReceivedEvent) GetMacCommands() []*MACCommand {
+	if x != nil {
+		return x.MacCommands
+	}
+	return nil
+}
+
+type JoinAcceptedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DevAddr []byte `protobuf:"bytes,1,opt,name=dev_addr,json=devAddr,proto3" json:"dev_addr,omitempty"`
+}
+
+func (x *JoinAcceptedEvent) Reset() {
+	*x = JoinAcceptedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JoinAcceptedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinAcceptedEvent) ProtoMessage() {}
+
+func (x *JoinAcceptedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinAcceptedEvent.ProtoReflect.Descriptor instead.
+func (*JoinAcceptedEvent) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *JoinAcceptedEvent) GetDevAddr() []byte {
+	if x != nil {
+		return x.DevAddr
+	}
+	return nil
+}
+
+// MICFailureEvent is a join accept or data downlink dropped because its MIC is invalid.
+type MICFailureEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MType      MType  `protobuf:"varint,1,opt,name=m_type,json=mType,proto3,enum=lds.ldspb.MType" json:"m_type,omitempty"`
+	PhyPayload []byte `protobuf:"bytes,2,opt,name=phy_payload,json=phyPayload,proto3" json:"phy_payload,omitempty"`
+	Error      string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *MICFailureEvent) Reset() {
+	*x = MICFailureEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ldspb_lds_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MICFailureEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MICFailureEvent) ProtoMessage() {}
+
+func (x *MICFailureEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_ldspb_lds_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MICFailureEvent.ProtoReflect.Descriptor instead.
+func (*MICFailureEvent) Descriptor() ([]byte, []int) {
+	return file_ldspb_lds_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MICFailureEvent) GetMType() MType {
+	if x != nil {
+		return x.MType
+	}
+	return MType_JOIN_REQUEST
+}
+
+func (x *MICFailureEvent) GetPhyPayload() []byte {
+	if x != nil {
+		return x.PhyPayload
+	}
+	return nil
+}
+
+func (x *MICFailureEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_ldspb_lds_proto protoreflect.FileDescriptor
+
+var file_ldspb_lds_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2f, 0x6c, 0x64, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x09, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x1a, 0x0b, 0x67, 0x77,
+	0x2f, 0x67, 0x77, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd9, 0x03, 0x0a, 0x06, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x75, 0x69, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x65, 0x76, 0x45, 0x75, 0x69, 0x12, 0x19, 0x0a, 0x08, 0x6a,
+	0x6f, 0x69, 0x6e, 0x5f, 0x65, 0x75, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6a,
+	0x6f, 0x69, 0x6e, 0x45, 0x75, 0x69, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x77, 0x6b, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6e, 0x77, 0x6b, 0x4b, 0x65, 0x79, 0x12,
+	0x17, 0x0a, 0x07, 0x61, 0x70, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x61, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6c, 0x64, 0x73, 0x2e,
+	0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x07, 0x70,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x36, 0x0a, 0x0b, 0x6d, 0x61, 0x63, 0x5f, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x6c, 0x64,
+	0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x4d, 0x41, 0x43, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x0a, 0x6d, 0x61, 0x63, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x19,
+	0x0a, 0x08, 0x64, 0x65, 0x76, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x07, 0x64, 0x65, 0x76, 0x41, 0x64, 0x64, 0x72, 0x12, 0x21, 0x0a, 0x0d, 0x6e, 0x77, 0x6b,
+	0x5f, 0x73, 0x5f, 0x65, 0x6e, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x6e, 0x77, 0x6b, 0x53, 0x45, 0x6e, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0f,
+	0x73, 0x5f, 0x6e, 0x77, 0x6b, 0x5f, 0x73, 0x5f, 0x69, 0x6e, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x4e, 0x77, 0x6b, 0x53, 0x49, 0x6e, 0x74, 0x4b,
+	0x65, 0x79, 0x12, 0x24, 0x0a, 0x0f, 0x66, 0x5f, 0x6e, 0x77, 0x6b, 0x5f, 0x73, 0x5f, 0x69, 0x6e,
+	0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x66, 0x4e, 0x77,
+	0x6b, 0x53, 0x49, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x09, 0x61, 0x70, 0x70, 0x5f,
+	0x73, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x70, 0x70,
+	0x53, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x6a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x10,
+	0x73, 0x6b, 0x69, 0x70, 0x5f, 0x66, 0x5f, 0x63, 0x6e, 0x74, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x6b, 0x69, 0x70, 0x46, 0x43, 0x6e, 0x74,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x61, 0x72, 0x73, 0x68, 0x61, 0x6c,
+	0x65, 0x72, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x61, 0x72, 0x73, 0x68, 0x61,
+	0x6c, 0x65, 0x72, 0x22, 0xfe, 0x02, 0x0a, 0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x17, 0x0a, 0x07, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x75, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x64, 0x65, 0x76, 0x45, 0x75, 0x69, 0x12, 0x2c, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6c, 0x64, 0x73, 0x2e,
+	0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x07, 0x70,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6a, 0x6f, 0x69, 0x6e, 0x65, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x12, 0x19,
+	0x0a, 0x08, 0x64, 0x65, 0x76, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x07, 0x64, 0x65, 0x76, 0x41, 0x64, 0x64, 0x72, 0x12, 0x18, 0x0a, 0x08, 0x75, 0x6c, 0x5f,
+	0x66, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x75, 0x6c, 0x46,
+	0x43, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x08, 0x64, 0x6c, 0x5f, 0x66, 0x5f, 0x63, 0x6e, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x64, 0x6c, 0x46, 0x43, 0x6e, 0x74, 0x12, 0x1b, 0x0a,
+	0x09, 0x64, 0x65, 0x76, 0x5f, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x08, 0x64, 0x65, 0x76, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6a, 0x6f,
+	0x69, 0x6e, 0x5f, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
+	0x6a, 0x6f, 0x69, 0x6e, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x21, 0x0a, 0x0d, 0x6e, 0x77, 0x6b,
+	0x5f, 0x73, 0x5f, 0x65, 0x6e, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x6e, 0x77, 0x6b, 0x53, 0x45, 0x6e, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0f,
+	0x73, 0x5f, 0x6e, 0x77, 0x6b, 0x5f, 0x73, 0x5f, 0x69, 0x6e, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73, 0x4e, 0x77, 0x6b, 0x53, 0x49, 0x6e, 0x74, 0x4b,
+	0x65, 0x79, 0x12, 0x24, 0x0a, 0x0f, 0x66, 0x5f, 0x6e, 0x77, 0x6b, 0x5f, 0x73, 0x5f, 0x69, 0x6e,
+	0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x66, 0x4e, 0x77,
+	0x6b, 0x53, 0x49, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x09, 0x61, 0x70, 0x70, 0x5f,
+	0x73, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x70, 0x70,
+	0x53, 0x4b, 0x65, 0x79, 0x22, 0x7a, 0x0a, 0x08, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x73,
+	0x12, 0x18, 0x0a, 0x08, 0x75, 0x6c, 0x5f, 0x66, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x06, 0x75, 0x6c, 0x46, 0x43, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x08, 0x64, 0x6c,
+	0x5f, 0x66, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x64, 0x6c,
+	0x46, 0x43, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x5f, 0x6e, 0x6f, 0x6e, 0x63,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x65, 0x76, 0x4e, 0x6f, 0x6e, 0x63,
+	0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6a, 0x6f, 0x69, 0x6e, 0x5f, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6a, 0x6f, 0x69, 0x6e, 0x4e, 0x6f, 0x6e, 0x63, 0x65,
+	0x22, 0xfc, 0x02, 0x0a, 0x07, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x1d, 0x0a, 0x0a,
+	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x09, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x62,
+	0x61, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x61, 0x6e, 0x64, 0x12,
+	0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x48, 0x0a,
+	0x14, 0x6c, 0x6f, 0x72, 0x61, 0x5f, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x77,
+	0x2e, 0x4c, 0x6f, 0x52, 0x61, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x12, 0x6c, 0x6f, 0x72, 0x61, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x73, 0x73, 0x69, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x73, 0x73, 0x69, 0x12, 0x19, 0x0a, 0x08, 0x6c,
+	0x6f, 0x72, 0x61, 0x5f, 0x73, 0x6e, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x6c,
+	0x6f, 0x72, 0x61, 0x53, 0x6e, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x12, 0x19, 0x0a, 0x08, 0x72, 0x66, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x07, 0x72, 0x66, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x63,
+	0x72, 0x63, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x63, 0x72, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x77, 0x2e, 0x47,
+	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x69, 0x6e, 0x67,
+	0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x73,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x69, 0x6e, 0x67, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22,
+	0x81, 0x01, 0x0a, 0x05, 0x46, 0x43, 0x74, 0x72, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x64, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x61, 0x64, 0x72, 0x12, 0x1e, 0x0a, 0x0b, 0x61,
+	0x64, 0x72, 0x5f, 0x61, 0x63, 0x6b, 0x5f, 0x72, 0x65, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x61, 0x64, 0x72, 0x41, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x12, 0x10, 0x0a, 0x03, 0x61,
+	0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x61, 0x63, 0x6b, 0x12, 0x1b, 0x0a,
+	0x09, 0x66, 0x5f, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x66, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x6c,
+	0x61, 0x73, 0x73, 0x5f, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x6c, 0x61,
+	0x73, 0x73, 0x42, 0x22, 0x38, 0x0a, 0x0a, 0x4d, 0x41, 0x43, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03,
+	0x63, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x82, 0x02,
+	0x0a, 0x0d, 0x55, 0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1f, 0x0a, 0x0b, 0x66, 0x72, 0x6d, 0x5f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x66, 0x72, 0x6d, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x12, 0x33, 0x0a, 0x06, 0x66, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x55, 0x49, 0x6e, 0x74, 0x33, 0x32, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05,
+	0x66, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x38, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x42, 0x6f, 0x6f, 0x6c, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x12,
+	0x38, 0x0a, 0x0c, 0x6d, 0x61, 0x63, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70,
+	0x62, 0x2e, 0x4d, 0x41, 0x43, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x0b, 0x6d, 0x61,
+	0x63, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x27, 0x0a, 0x06, 0x66, 0x5f, 0x63,
+	0x74, 0x72, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x64, 0x73, 0x2e,
+	0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x46, 0x43, 0x74, 0x72, 0x6c, 0x52, 0x05, 0x66, 0x43, 0x74,
+	0x72, 0x6c, 0x22, 0x25, 0x0a, 0x0e, 0x55, 0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x13, 0x0a, 0x05, 0x66, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x04, 0x66, 0x43, 0x6e, 0x74, 0x22, 0x45, 0x0a, 0x0c, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x08, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
+	0x22, 0x18, 0x0a, 0x16, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xed, 0x02, 0x0a, 0x05, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x65, 0x76, 0x5f, 0x65, 0x75, 0x69, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x65, 0x76, 0x45, 0x75, 0x69, 0x12, 0x3d, 0x0a,
+	0x0b, 0x75, 0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x55,
+	0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x53, 0x65, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
+	0x52, 0x0a, 0x75, 0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x53, 0x65, 0x6e, 0x74, 0x12, 0x4f, 0x0a, 0x11,
+	0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64,
+	0x73, 0x70, 0x62, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x10, 0x64, 0x6f, 0x77,
+	0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x43, 0x0a,
+	0x0d, 0x6a, 0x6f, 0x69, 0x6e, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62,
+	0x2e, 0x4a, 0x6f, 0x69, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x48, 0x00, 0x52, 0x0c, 0x6a, 0x6f, 0x69, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x65, 0x64, 0x12, 0x3d, 0x0a, 0x0b, 0x6d, 0x69, 0x63, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64,
+	0x73, 0x70, 0x62, 0x2e, 0x4d, 0x49, 0x43, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x6d, 0x69, 0x63, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x99, 0x03, 0x0a, 0x0f, 0x55,
+	0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x53, 0x65, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x27,
+	0x0a, 0x06, 0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10,
+	0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x4d, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x05, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x68, 0x79, 0x5f, 0x70,
+	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x70, 0x68,
+	0x79, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x29, 0x0a, 0x07, 0x72, 0x78, 0x5f, 0x69,
+	0x6e, 0x66, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x77, 0x2e, 0x55,
+	0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x58, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x72, 0x78, 0x49,
+	0x6e, 0x66, 0x6f, 0x12, 0x29, 0x0a, 0x07, 0x74, 0x78, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x77, 0x2e, 0x55, 0x70, 0x6c, 0x69, 0x6e, 0x6b,
+	0x54, 0x58, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x74, 0x78, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x19,
+	0x0a, 0x08, 0x64, 0x65, 0x76, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x07, 0x64, 0x65, 0x76, 0x41, 0x64, 0x64, 0x72, 0x12, 0x27, 0x0a, 0x06, 0x66, 0x5f, 0x63,
+	0x74, 0x72, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x64, 0x73, 0x2e,
+	0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x46, 0x43, 0x74, 0x72, 0x6c, 0x52, 0x05, 0x66, 0x43, 0x74,
+	0x72, 0x6c, 0x12, 0x13, 0x0a, 0x05, 0x66, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x04, 0x66, 0x43, 0x6e, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x66, 0x5f, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x66, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1f,
+	0x0a, 0x0b, 0x66, 0x72, 0x6d, 0x5f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0a, 0x66, 0x72, 0x6d, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12,
+	0x38, 0x0a, 0x0c, 0x6d, 0x61, 0x63, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18,
+	0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70,
+	0x62, 0x2e, 0x4d, 0x41, 0x43, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x0b, 0x6d, 0x61,
+	0x63, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76,
+	0x5f, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x65,
+	0x76, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0xa9, 0x02, 0x0a, 0x15, 0x44, 0x6f, 0x77, 0x6e, 0x6c,
+	0x69, 0x6e, 0x6b, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x27, 0x0a, 0x06, 0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x10, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x4d, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x05, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x65, 0x76,
+	0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x64, 0x65, 0x76,
+	0x41, 0x64, 0x64, 0x72, 0x12, 0x27, 0x0a, 0x06, 0x66, 0x5f, 0x63, 0x74, 0x72, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62,
+	0x2e, 0x46, 0x43, 0x74, 0x72, 0x6c, 0x52, 0x05, 0x66, 0x43, 0x74, 0x72, 0x6c, 0x12, 0x13, 0x0a,
+	0x05, 0x66, 0x5f, 0x63, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x66, 0x43,
+	0x6e, 0x74, 0x12, 0x33, 0x0a, 0x06, 0x66, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x55, 0x49, 0x6e, 0x74, 0x33, 0x32, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x52, 0x05, 0x66, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x72, 0x6d, 0x5f, 0x70,
+	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x66, 0x72,
+	0x6d, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x38, 0x0a, 0x0c, 0x6d, 0x61, 0x63, 0x5f,
+	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x4d, 0x41, 0x43, 0x43, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x0b, 0x6d, 0x61, 0x63, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x73, 0x22, 0x2e, 0x0a, 0x11, 0x4a, 0x6f, 0x69, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x65, 0x76, 0x5f, 0x61,
+	0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x64, 0x65, 0x76, 0x41, 0x64,
+	0x64, 0x72, 0x22, 0x71, 0x0a, 0x0f, 0x4d, 0x49, 0x43, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x06, 0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70,
+	0x62, 0x2e, 0x4d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f,
+	0x0a, 0x0b, 0x70, 0x68, 0x79, 0x5f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0a, 0x70, 0x68, 0x79, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x2a, 0xb3, 0x01, 0x0a, 0x05, 0x4d, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x10, 0x0a, 0x0c, 0x4a, 0x4f, 0x49, 0x4e, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x10,
+	0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4a, 0x4f, 0x49, 0x4e, 0x5f, 0x41, 0x43, 0x43, 0x45, 0x50, 0x54,
+	0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x55, 0x4e, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45,
+	0x44, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x55, 0x50, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x55,
+	0x4e, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45, 0x44, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f,
+	0x44, 0x4f, 0x57, 0x4e, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52,
+	0x4d, 0x45, 0x44, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x55, 0x50, 0x10, 0x04, 0x12, 0x17, 0x0a,
+	0x13, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45, 0x44, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f,
+	0x44, 0x4f, 0x57, 0x4e, 0x10, 0x05, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x45, 0x4a, 0x4f, 0x49, 0x4e,
+	0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x10, 0x06, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x52,
+	0x4f, 0x50, 0x52, 0x49, 0x45, 0x54, 0x41, 0x52, 0x59, 0x10, 0x07, 0x2a, 0x1c, 0x0a, 0x07, 0x50,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x4f, 0x54, 0x41, 0x41, 0x10, 0x00,
+	0x12, 0x07, 0x0a, 0x03, 0x41, 0x42, 0x50, 0x10, 0x01, 0x2a, 0x2e, 0x0a, 0x0a, 0x4d, 0x41, 0x43,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0f, 0x0a, 0x0b, 0x4c, 0x4f, 0x52, 0x41, 0x57,
+	0x41, 0x4e, 0x5f, 0x31, 0x5f, 0x30, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4c, 0x4f, 0x52, 0x41,
+	0x57, 0x41, 0x4e, 0x5f, 0x31, 0x5f, 0x31, 0x10, 0x01, 0x32, 0xca, 0x06, 0x0a, 0x09, 0x53, 0x69,
+	0x6d, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x36, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x11, 0x2e, 0x6c,
+	0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x34, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x11, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x1a, 0x11, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x34, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x11, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70,
+	0x62, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x11, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c,
+	0x64, 0x73, 0x70, 0x62, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x38, 0x0a, 0x0a, 0x47,
+	0x65, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x12, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x0b, 0x53, 0x65, 0x74, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x65, 0x72, 0x73, 0x12, 0x13, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62,
+	0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x12, 0x3d, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x12, 0x38, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x12, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73,
+	0x70, 0x62, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x37, 0x0a, 0x0d, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x12, 0x2e, 0x6c, 0x64,
+	0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x1a,
+	0x12, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x47, 0x61, 0x74, 0x65,
+	0x77, 0x61, 0x79, 0x12, 0x36, 0x0a, 0x04, 0x4a, 0x6f, 0x69, 0x6e, 0x12, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3d, 0x0a, 0x06, 0x55,
+	0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x12, 0x18, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70,
+	0x62, 0x2e, 0x55, 0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x19, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x55, 0x70, 0x6c, 0x69,
+	0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x05, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x12, 0x17, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x12, 0x36, 0x0a, 0x04, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x48, 0x0a, 0x0f,
+	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x21, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x10, 0x2e, 0x6c, 0x64, 0x73, 0x2e, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x2e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x22, 0x5a, 0x20, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x69, 0x65, 0x67, 0x6f, 0x6d, 0x65, 0x7a, 0x2f, 0x6c, 0x64, 0x73,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6c, 0x64, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_ldspb_lds_proto_rawDescOnce sync.Once
+	file_ldspb_lds_proto_rawDescData = file_ldspb_lds_proto_rawDesc
+)
+
+func file_ldspb_lds_proto_rawDescGZIP() []byte {
+	file_ldspb_lds_proto_rawDescOnce.Do(func() {
+		file_ldspb_lds_proto_rawDescData = protoimpl.X.CompressGZIP(file_ldspb_lds_proto_rawDescData)
+	})
+	return file_ldspb_lds_proto_rawDescData
+}
+
+var file_ldspb_lds_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_ldspb_lds_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_ldspb_lds_proto_goTypes = []interface{}{
+	(MType)(0),                     // 0: lds.ldspb.MType
+	(Profile)(0),                   // 1: lds.ldspb.Profile
+	(MACVersion)(0),                // 2: lds.ldspb.MACVersion
+	(*Device)(nil),                 // 3: lds.ldspb.Device
+	(*Session)(nil),                // 4: lds.ldspb.Session
+	(*Counters)(nil),               // 5: lds.ldspb.Counters
+	(*Gateway)(nil),                // 6: lds.ldspb.Gateway
+	(*FCtrl)(nil),                  // 7: lds.ldspb.FCtrl
+	(*MACCommand)(nil),             // 8: lds.ldspb.MACCommand
+	(*UplinkRequest)(nil),          // 9: lds.ldspb.UplinkRequest
+	(*UplinkResponse)(nil),         // 10: lds.ldspb.UplinkResponse
+	(*StartRequest)(nil),           // 11: lds.ldspb.StartRequest
+	(*SubscribeEventsRequest)(nil), // 12: lds.ldspb.SubscribeEventsRequest
+	(*Event)(nil),                  // 13: lds.ldspb.Event
+	(*UplinkSentEvent)(nil),        // 14: lds.ldspb.UplinkSentEvent
+	(*DownlinkReceivedEvent)(nil),  // 15: lds.ldspb.DownlinkReceivedEvent
+	(*JoinAcceptedEvent)(nil),      // 16: lds.ldspb.JoinAcceptedEvent
+	(*MICFailureEvent)(nil),        // 17: lds.ldspb.MICFailureEvent
+	(*gw.LoRaModulationInfo)(nil),  // 18: gw.LoRaModulationInfo
+	(*gw.GatewayStats)(nil),        // 19: gw.GatewayStats
+	(*wrapperspb.UInt32Value)(nil), // 20: google.protobuf.UInt32Value
+	(*wrapperspb.BoolValue)(nil),   // 21: google.protobuf.BoolValue
+	(*durationpb.Duration)(nil),    // 22: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),  // 23: google.protobuf.Timestamp
+	(*gw.UplinkRXInfo)(nil),        // 24: gw.UplinkRXInfo
+	(*gw.UplinkTXInfo)(nil),        // 25: gw.UplinkTXInfo
+	(*emptypb.Empty)(nil),          // 26: google.protobuf.Empty
+}
+var file_ldspb_lds_proto_depIdxs = []int32{
+	1,  // 0: lds.ldspb.Device.profile:type_name -> lds.ldspb.Profile
+	2,  // 1: lds.ldspb.Device.mac_version:type_name -> lds.ldspb.MACVersion
+	1,  // 2: lds.ldspb.Session.profile:type_name -> lds.ldspb.Profile
+	18, // 3: lds.ldspb.Gateway.lora_modulation_info:type_name -> gw.LoRaModulationInfo
+	19, // 4: lds.ldspb.Gateway.stats:type_name -> gw.GatewayStats
+	20, // 5: lds.ldspb.UplinkRequest.f_port:type_name -> google.protobuf.UInt32Value
+	21, // 6: lds.ldspb.UplinkRequest.confirmed:type_name -> google.protobuf.BoolValue
+	8,  // 7: lds.ldspb.UplinkRequest.mac_commands:type_name -> lds.ldspb.MACCommand
+	7,  // 8: lds.ldspb.UplinkRequest.f_ctrl:type_name -> lds.ldspb.FCtrl
+	22, // 9: lds.ldspb.StartRequest.interval:type_name -> google.protobuf.Duration
+	23, // 10: lds.ldspb.Event.time:type_name -> google.protobuf.Timestamp
+	14, // 11: lds.ldspb.Event.uplink_sent:type_name -> lds.ldspb.UplinkSentEvent
+	15, // 12: lds.ldspb.Event.downlink_received:type_name -> lds.ldspb.DownlinkReceivedEvent
+	16, // 13: lds.ldspb.Event.join_accepted:type_name -> lds.ldspb.JoinAcceptedEvent
+	17, // 14: lds.ldspb.Event.mic_failure:type_name -> lds.ldspb.MICFailureEvent
+	0,  // 15: lds.ldspb.UplinkSentEvent.m_type:type_name -> lds.ldspb.MType
+	24, // 16: lds.ldspb.UplinkSentEvent.rx_info:type_name -> gw.UplinkRXInfo
+	25, // 17: lds.ldspb.UplinkSentEvent.tx_info:type_name -> gw.UplinkTXInfo
+	7,  // 18: lds.ldspb.UplinkSentEvent.f_ctrl:type_name -> lds.ldspb.FCtrl
+	8,  // 19: lds.ldspb.UplinkSentEvent.mac_commands:type_name -> lds.ldspb.MACCommand
+	0,  // 20: lds.ldspb.DownlinkReceivedEvent.m_type:type_name -> lds.ldspb.MType
+	7,  // 21: lds.ldspb.DownlinkReceivedEvent.f_ctrl:type_name -> lds.ldspb.FCtrl
+	20, // 22: lds.ldspb.DownlinkReceivedEvent.f_port:type_name -> google.protobuf.UInt32Value
+	8,  // 23: lds.ldspb.DownlinkReceivedEvent.mac_commands:type_name -> lds.ldspb.MACCommand
+	0,  // 24: lds.ldspb.MICFailureEvent.m_type:type_name -> lds.ldspb.MType
+	26, // 25: lds.ldspb.Simulator.GetDevice:input_type -> google.protobuf.Empty
+	3,  // 26: lds.ldspb.Simulator.CreateDevice:input_type -> lds.ldspb.Device
+	3,  // 27: lds.ldspb.Simulator.UpdateDevice:input_type -> lds.ldspb.Device
+	26, // 28: lds.ldspb.Simulator.DeleteDevice:input_type -> google.protobuf.Empty
+	26, // 29: lds.ldspb.Simulator.GetSession:input_type -> google.protobuf.Empty
+	5,  // 30: lds.ldspb.Simulator.SetCounters:input_type -> lds.ldspb.Counters
+	26, // 31: lds.ldspb.Simulator.ResetDevice:input_type -> google.protobuf.Empty
+	26, // 32: lds.ldspb.Simulator.GetGateway:input_type -> google.protobuf.Empty
+	6,  // 33: lds.ldspb.Simulator.UpdateGateway:input_type -> lds.ldspb.Gateway
+	26, // 34: lds.ldspb.Simulator.Join:input_type -> google.protobuf.Empty
+	9,  // 35: lds.ldspb.Simulator.Uplink:input_type -> lds.ldspb.UplinkRequest
+	11, // 36: lds.ldspb.Simulator.Start:input_type -> lds.ldspb.StartRequest
+	26, // 37: lds.ldspb.Simulator.Stop:input_type -> google.protobuf.Empty
+	12, // 38: lds.ldspb.Simulator.SubscribeEvents:input_type -> lds.ldspb.SubscribeEventsRequest
+	3,  // 39: lds.ldspb.Simulator.GetDevice:output_type -> lds.ldspb.Device
+	3,  // 40: lds.ldspb.Simulator.CreateDevice:output_type -> lds.ldspb.Device
+	3,  // 41: lds.ldspb.Simulator.UpdateDevice:output_type -> lds.ldspb.Device
+	26, // 42: lds.ldspb.Simulator.DeleteDevice:output_type -> google.protobuf.Empty
+	4,  // 43: lds.ldspb.Simulator.GetSession:output_type -> lds.ldspb.Session
+	26, // 44: lds.ldspb.Simulator.SetCounters:output_type -> google.protobuf.Empty
+	26, // 45: lds.ldspb.Simulator.ResetDevice:output_type -> google.protobuf.Empty
+	6,  // 46: lds.ldspb.Simulator.GetGateway:output_type -> lds.ldspb.Gateway
+	6,  // 47: lds.ldspb.Simulator.UpdateGateway:output_type -> lds.ldspb.Gateway
+	26, // 48: lds.ldspb.Simulator.Join:output_type -> google.protobuf.Empty
+	10, // 49: lds.ldspb.Simulator.Uplink:output_type -> lds.ldspb.UplinkResponse
+	26, // 50: lds.ldspb.Simulator.Start:output_type -> google.protobuf.Empty
+	26, // 51: lds.ldspb.Simulator.Stop:output_type -> google.protobuf.Empty
+	13, // 52: lds.ldspb.Simulator.SubscribeEvents:output_type -> lds.ldspb.Event
+	39, // [39:53] is the sub-list for method output_type
+	25, // [25:39] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_ldspb_lds_proto_init() }
+func file_ldspb_lds_proto_init() {
+	if File_ldspb_lds_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_ldspb_lds_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Device); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Session); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Counters); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Gateway); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FCtrl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MACCommand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UplinkRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UplinkResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UplinkSentEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownlinkReceivedEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JoinAcceptedEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ldspb_lds_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MICFailureEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_ldspb_lds_proto_msgTypes[10].OneofWrappers = []interface{}{
+		(*Event_UplinkSent)(nil),
+		(*Event_DownlinkReceived)(nil),
+		(*Event_JoinAccepted)(nil),
+		(*Event_MicFailure)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_ldspb_lds_proto_rawDesc,
+			NumEnums:      3,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ldspb_lds_proto_goTypes,
+		DependencyIndexes: file_ldspb_lds_proto_depIdxs,
+		EnumInfos:         file_ldspb_lds_proto_enumTypes,
+		MessageInfos:      file_ldspb_lds_proto_msgTypes,
+	}.Build()
+	File_ldspb_lds_proto = out.File
+	file_ldspb_lds_proto_rawDesc = nil
+	file_ldspb_lds_proto_goTypes = nil
+	file_ldspb_lds_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// SimulatorClient is the client API for Simulator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SimulatorClient interface {
+	// GetDevice returns the device configuration.
+	GetDevice(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Device, error)
+	// CreateDevice creates the device, it fails with ALREADY_EXISTS when there's one.
+	CreateDevice(ctx context.Context, in *Device, opts ...grpc.CallOption) (*Device, error)
+	// UpdateDevice creates or replaces the device.
+	UpdateDevice(ctx context.Context, in *Device, opts ...grpc.CallOption) (*Device, error)
+	// DeleteDevice resets the device session and removes the device.
+	DeleteDevice(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetSession returns the device session state.
+	GetSession(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Session, error)
+	// SetCounters sets the frame counters and nonces.
+	SetCounters(ctx context.Context, in *Counters, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ResetDevice deletes the device session, counters and nonces.
+	ResetDevice(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetGateway returns the gateway, with the reception metadata given to the uplinks and its stats.
+	GetGateway(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Gateway, error)
+	// UpdateGateway sets the band and the reception metadata of the next uplinks. It replaces
+	// them all, but an empty band or missing modulation info keeps the current ones.
+	UpdateGateway(ctx context.Context, in *Gateway, opts ...grpc.CallOption) (*Gateway, error)
+	// Join sends a join request, the join accept is delivered as an event.
+	Join(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Uplink sends a data uplink.
+	Uplink(ctx context.Context, in *UplinkRequest, opts ...grpc.CallOption) (*UplinkResponse, error)
+	// Start sends the configured data every interval until stopped.
+	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Stop stops the periodic uplinks.
+	Stop(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// SubscribeEvents streams the device events until the client cancels.
+	SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Simulator_SubscribeEventsClient, error)
+}
+
+type simulatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSimulatorClient(cc grpc.ClientConnInterface) SimulatorClient {
+	return &simulatorClient{cc}
+}
+
+func (c *simulatorClient) GetDevice(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Device, error) {
+	out := new(Device)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/GetDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) CreateDevice(ctx context.Context, in *Device, opts ...grpc.CallOption) (*Device, error) {
+	out := new(Device)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/CreateDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) UpdateDevice(ctx context.Context, in *Device, opts ...grpc.CallOption) (*Device, error) {
+	out := new(Device)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/UpdateDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) DeleteDevice(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/DeleteDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) GetSession(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/GetSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) SetCounters(ctx context.Context, in *Counters, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/SetCounters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) ResetDevice(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/ResetDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) GetGateway(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Gateway, error) {
+	out := new(Gateway)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/GetGateway", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) UpdateGateway(ctx context.Context, in *Gateway, opts ...grpc.CallOption) (*Gateway, error) {
+	out := new(Gateway)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/UpdateGateway", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) Join(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/Join", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) Uplink(ctx context.Context, in *UplinkRequest, opts ...grpc.CallOption) (*UplinkResponse, error) {
+	out := new(UplinkResponse)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/Uplink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/Start", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) Stop(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/lds.ldspb.Simulator/Stop", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Simulator_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Simulator_serviceDesc.Streams[0], "/lds.ldspb.Simulator/SubscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &simulatorSubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Simulator_SubscribeEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type simulatorSubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *simulatorSubscribeEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SimulatorServer is the server API for Simulator service.
+type SimulatorServer interface {
+	// GetDevice returns the device configuration.
+	GetDevice(context.Context, *emptypb.Empty) (*Device, error)
+	// CreateDevice creates the device, it fails with ALREADY_EXISTS when there's one.
+	CreateDevice(context.Context, *Device) (*Device, error)
+	// UpdateDevice creates or replaces the device.
+	UpdateDevice(context.Context, *Device) (*Device, error)
+	// DeleteDevice resets the device session and removes the device.
+	DeleteDevice(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// GetSession returns the device session state.
+	GetSession(context.Context, *emptypb.Empty) (*Session, error)
+	// SetCounters sets the frame counters and nonces.
+	SetCounters(context.Context, *Counters) (*emptypb.Empty, error)
+	// ResetDevice deletes the device session, counters and nonces.
+	ResetDevice(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// GetGateway returns the gateway, with the reception metadata given to the uplinks and its stats.
+	GetGateway(context.Context, *emptypb.Empty) (*Gateway, error)
+	// UpdateGateway sets the band and the reception metadata of the next uplinks. It replaces
+	// them all, but an empty band or missing modulation info keeps the current ones.
+	UpdateGateway(context.Context, *Gateway) (*Gateway, error)
+	// Join sends a join request, the join accept is delivered as an event.
+	Join(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// Uplink sends a data uplink.
+	Uplink(context.Context, *UplinkRequest) (*UplinkResponse, error)
+	// Start sends the configured data every interval until stopped.
+	Start(context.Context, *StartRequest) (*emptypb.Empty, error)
+	// Stop stops the periodic uplinks.
+	Stop(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// SubscribeEvents streams the device events until the client cancels.
+	SubscribeEvents(*SubscribeEventsRequest, Simulator_SubscribeEventsServer) error
+}
+
+// UnimplementedSimulatorServer can be embedded to have forward compatible implementations.
+type UnimplementedSimulatorServer struct {
+}
+
+func (*UnimplementedSimulatorServer) GetDevice(context.Context, *emptypb.Empty) (*Device, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDevice not implemented")
+}
+func (*UnimplementedSimulatorServer) CreateDevice(context.Context, *Device) (*Device, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDevice not implemented")
+}
+func (*UnimplementedSimulatorServer) UpdateDevice(context.Context, *Device) (*Device, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDevice not implemented")
+}
+func (*UnimplementedSimulatorServer) DeleteDevice(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDevice not implemented")
+}
+func (*UnimplementedSimulatorServer) GetSession(context.Context, *emptypb.Empty) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSession not implemented")
+}
+func (*UnimplementedSimulatorServer) SetCounters(context.Context, *Counters) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCounters not implemented")
+}
+func (*UnimplementedSimulatorServer) ResetDevice(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetDevice not implemented")
+}
+func (*UnimplementedSimulatorServer) GetGateway(context.Context, *emptypb.Empty) (*Gateway, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGateway not implemented")
+}
+func (*UnimplementedSimulatorServer) UpdateGateway(context.Context, *Gateway) (*Gateway, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGateway not implemented")
+}
+func (*UnimplementedSimulatorServer) Join(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Join not implemented")
+}
+func (*UnimplementedSimulatorServer) Uplink(context.Context, *UplinkRequest) (*UplinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Uplink not implemented")
+}
+func (*UnimplementedSimulatorServer) Start(context.Context, *StartRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Start not implemented")
+}
+func (*UnimplementedSimulatorServer) Stop(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+func (*UnimplementedSimulatorServer) SubscribeEvents(*SubscribeEventsRequest, Simulator_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
+
+func RegisterSimulatorServer(s *grpc.Server, srv SimulatorServer) {
+	s.RegisterService(&_Simulator_serviceDesc, srv)
+}
+
+func _Simulator_GetDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).GetDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/GetDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).GetDevice(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_CreateDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Device)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).CreateDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/CreateDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).CreateDevice(ctx, req.(*Device))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_UpdateDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Device)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).UpdateDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/UpdateDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).UpdateDevice(ctx, req.(*Device))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_DeleteDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).DeleteDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/DeleteDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).DeleteDevice(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_GetSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).GetSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/GetSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).GetSession(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_SetCounters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Counters)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).SetCounters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/SetCounters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).SetCounters(ctx, req.(*Counters))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_ResetDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).ResetDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/ResetDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).ResetDevice(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_GetGateway_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).GetGateway(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/GetGateway",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).GetGateway(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_UpdateGateway_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Gateway)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).UpdateGateway(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/UpdateGateway",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).UpdateGateway(ctx, req.(*Gateway))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_Join_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).Join(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/Join",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).Join(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_Uplink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UplinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).Uplink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/Uplink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).Uplink(ctx, req.(*UplinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).Start(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/Start",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).Start(ctx, req.(*StartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lds.ldspb.Simulator/Stop",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).Stop(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SimulatorServer).SubscribeEvents(m, &simulatorSubscribeEventsServer{stream})
+}
+
+type Simulator_SubscribeEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type simulatorSubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *simulatorSubscribeEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Simulator_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "lds.ldspb.Simulator",
+	HandlerType: (*SimulatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDevice",
+			Handler:    _Simulator_GetDevice_Handler,
+		},
+		{
+			MethodName: "CreateDevice",
+			Handler:    _Simulator_CreateDevice_Handler,
+		},
+		{
+			MethodName: "UpdateDevice",
+			Handler:    _Simulator_UpdateDevice_Handler,
+		},
+		{
+			MethodName: "DeleteDevice",
+			Handler:    _Simulator_DeleteDevice_Handler,
+		},
+		{
+			MethodName: "GetSession",
+			Handler:    _Simulator_GetSession_Handler,
+		},
+		{
+			MethodName: "SetCounters",
+			Handler:    _Simulator_SetCounters_Handler,
+		},
+		{
+			MethodName: "ResetDevice",
+			Handler:    _Simulator_ResetDevice_Handler,
+		},
+		{
+			MethodName: "GetGateway",
+			Handler:    _Simulator_GetGateway_Handler,
+		},
+		{
+			MethodName: "UpdateGateway",
+			Handler:    _Simulator_UpdateGateway_Handler,
+		},
+		{
+			MethodName: "Join",
+			Handler:    _Simulator_Join_Handler,
+		},
+		{
+			MethodName: "Uplink",
+			Handler:    _Simulator_Uplink_Handler,
+		},
+		{
+			MethodName: "Start",
+			Handler:    _Simulator_Start_Handler,
+		},
+		{
+			MethodName: "Stop",
+			Handler:    _Simulator_Stop_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _Simulator_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ldspb/lds.proto",
+}
@@ -0,0 +1,1351 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.23.0
+// 	protoc        (unknown)
+// source: ttnpb/ttn.proto
+
+// Package ttnpb holds the subset of The Things Stack v3 messages used by its
+// gateway MQTT (protobuf) API, keeping their field numbers so that they are
+// wire compatible with the Gateway Server. Fields lds doesn't use are left out.
+
+package ttnpb
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type TxAcknowledgment_Result int32
+
+const (
+	TxAcknowledgment_SUCCESS          TxAcknowledgment_Result = 0
+	TxAcknowledgment_UNKNOWN_ERROR    TxAcknowledgment_Result = 1
+	TxAcknowledgment_TOO_LATE         TxAcknowledgment_Result = 2
+	TxAcknowledgment_TOO_EARLY        TxAcknowledgment_Result = 3
+	TxAcknowledgment_COLLISION_PACKET TxAcknowledgment_Result = 4
+	TxAcknowledgment_COLLISION_BEACON TxAcknowledgment_Result = 5
+	TxAcknowledgment_TX_FREQ          TxAcknowledgment_Result = 6
+	TxAcknowledgment_TX_POWER         TxAcknowledgment_Result = 7
+	TxAcknowledgment_GPS_UNLOCKED     TxAcknowledgment_Result = 8
+)
+
+// Enum value maps for TxAcknowledgment_Result.
+var (
+	TxAcknowledgment_Result_name = map[int32]string{
+		0: "SUCCESS",
+		1: "UNKNOWN_ERROR",
+		2: "TOO_LATE",
+		3: "TOO_EARLY",
+		4: "COLLISION_PACKET",
+		5: "COLLISION_BEACON",
+		6: "TX_FREQ",
+		7: "TX_POWER",
+		8: "GPS_UNLOCKED",
+	}
+	TxAcknowledgment_Result_value = map[string]int32{
+		"SUCCESS":          0,
+		"UNKNOWN_ERROR":    1,
+		"TOO_LATE":         2,
+		"TOO_EARLY":        3,
+		"COLLISION_PACKET": 4,
+		"COLLISION_BEACON": 5,
+		"TX_FREQ":          6,
+		"TX_POWER":         7,
+		"GPS_UNLOCKED":     8,
+	}
+)
+
+func (x TxAcknowledgment_Result) Enum() *TxAcknowledgment_Result {
+	p := new(TxAcknowledgment_Result)
+	*p = x
+	return p
+}
+
+func (x TxAcknowledgment_Result) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TxAcknowledgment_Result) Descriptor() protoreflect.EnumDescriptor {
+	return file_ttnpb_ttn_proto_enumTypes[0].Descriptor()
+}
+
+func (TxAcknowledgment_Result) Type() protoreflect.EnumType {
+	return &file_ttnpb_ttn_proto_enumTypes[0]
+}
+
+func (x TxAcknowledgment_Result) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TxAcknowledgment_Result.Descriptor instead.
+func (TxAcknowledgment_Result) EnumDescriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{10, 0}
+}
+
+type GatewayIdentifiers struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GatewayId string `protobuf:"bytes,1,opt,name=gateway_id,json=gatewayId,proto3" json:"gateway_id,omitempty"`
+	Eui       []byte `protobuf:"bytes,2,opt,name=eui,proto3" json:"eui,omitempty"`
+}
+
+func (x *GatewayIdentifiers) Reset() {
+	*x = GatewayIdentifiers{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GatewayIdentifiers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GatewayIdentifiers) ProtoMessage() {}
+
+func (x *GatewayIdentifiers) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GatewayIdentifiers.ProtoReflect.Descriptor instead.
+func (*GatewayIdentifiers) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GatewayIdentifiers) GetGatewayId() string {
+	if x != nil {
+		return x.GatewayId
+	}
+	return ""
+}
+
+func (x *GatewayIdentifiers) GetEui() []byte {
+	if x != nil {
+		return x.Eui
+	}
+	return nil
+}
+
+type DataRate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Modulation:
+	//	*DataRate_Lora
+	//	*DataRate_Fsk
+	Modulation isDataRate_Modulation `protobuf_oneof:"modulation"`
+}
+
+func (x *DataRate) Reset() {
+	*x = DataRate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DataRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataRate) ProtoMessage() {}
+
+func (x *DataRate) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataRate.ProtoReflect.Descriptor instead.
+func (*DataRate) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{1}
+}
+
+func (m *DataRate) GetModulation() isDataRate_Modulation {
+	if m != nil {
+		return m.Modulation
+	}
+	return nil
+}
+
+func (x *DataRate) GetLora() *LoRaDataRate {
+	if x, ok := x.GetModulation().(*DataRate_Lora); ok {
+		return x.Lora
+	}
+	return nil
+}
+
+func (x *DataRate) GetFsk() *FSKDataRate {
+	if x, ok := x.GetModulation().(*DataRate_Fsk); ok {
+		return x.Fsk
+	}
+	return nil
+}
+
+type isDataRate_Modulation interface {
+	isDataRate_Modulation()
+}
+
+type DataRate_Lora struct {
+	Lora *LoRaDataRate `protobuf:"bytes,1,opt,name=lora,proto3,oneof"`
+}
+
+type DataRate_Fsk struct {
+	Fsk *FSKDataRate `protobuf:"bytes,2,opt,name=fsk,proto3,oneof"`
+}
+
+func (*DataRate_Lora) isDataRate_Modulation() {}
+
+func (*DataRate_Fsk) isDataRate_Modulation() {}
+
+type LoRaDataRate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Bandwidth (Hz).
+	Bandwidth       uint32 `protobuf:"varint,1,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`
+	SpreadingFactor uint32 `protobuf:"varint,2,opt,name=spreading_factor,json=spreadingFactor,proto3" json:"spreading_factor,omitempty"`
+	CodingRate      string `protobuf:"bytes,3,opt,name=coding_rate,json=codingRate,proto3" json:"coding_rate,omitempty"`
+}
+
+func (x *LoRaDataRate) Reset() {
+	*x = LoRaDataRate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoRaDataRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoRaDataRate) ProtoMessage() {}
+
+func (x *LoRaDataRate) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoRaDataRate.ProtoReflect.Descriptor instead.
+func (*LoRaDataRate) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LoRaDataRate) GetBandwidth() uint32 {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return 0
+}
+
+func (x *LoRaDataRate) GetSpreadingFactor() uint32 {
+	if x != nil {
+		return x.SpreadingFactor
+	}
+	return 0
+}
+
+func (x *LoRaDataRate) GetCodingRate() string {
+	if x != nil {
+		return x.CodingRate
+	}
+	return ""
+}
+
+type FSKDataRate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Bit rate (bps).
+	BitRate uint32 `protobuf:"varint,1,opt,name=bit_rate,json=bitRate,proto3" json:"bit_rate,omitempty"`
+}
+
+func (x *FSKDataRate) Reset() {
+	*x = FSKDataRate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FSKDataRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FSKDataRate) ProtoMessage() {}
+
+func (x *FSKDataRate) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FSKDataRate.ProtoReflect.Descriptor instead.
+func (*FSKDataRate) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FSKDataRate) GetBitRate() uint32 {
+	if x != nil {
+		return x.BitRate
+	}
+	return 0
+}
+
+type TxSettings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataRate *DataRate `protobuf:"bytes,1,opt,name=data_rate,json=dataRate,proto3" json:"data_rate,omitempty"`
+	// LoRa coding rate, used by Gateway Servers before it was moved to LoRaDataRate.
+	CodingRate string `protobuf:"bytes,3,opt,name=coding_rate,json=codingRate,proto3" json:"coding_rate,omitempty"`
+	// Frequency (Hz).
+	Frequency uint64 `protobuf:"varint,4,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	EnableCrc bool   `protobuf:"varint,5,opt,name=enable_crc,json=enableCrc,proto3" json:"enable_crc,omitempty"`
+	// Concentrator timestamp of the uplink, or at which the downlink must be emitted.
+	Timestamp uint32                 `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Time      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=time,proto3" json:"time,omitempty"`
+	// Downlink only settings.
+	Downlink *TxSettings_Downlink `protobuf:"bytes,8,opt,name=downlink,proto3" json:"downlink,omitempty"`
+}
+
+func (x *TxSettings) Reset() {
+	*x = TxSettings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TxSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxSettings) ProtoMessage() {}
+
+func (x *TxSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxSettings.ProtoReflect.Descriptor instead.
+func (*TxSettings) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TxSettings) GetDataRate() *DataRate {
+	if x != nil {
+		return x.DataRate
+	}
+	return nil
+}
+
+func (x *TxSettings) GetCodingRate() string {
+	if x != nil {
+		return x.CodingRate
+	}
+	return ""
+}
+
+func (x *TxSettings) GetFrequency() uint64 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+func (x *TxSettings) GetEnableCrc() bool {
+	if x != nil {
+		return x.EnableCrc
+	}
+	return false
+}
+
+func (x *TxSettings) GetTimestamp() uint32 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *TxSettings) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *TxSettings) GetDownlink() *TxSettings_Downlink {
+	if x != nil {
+		return x.Downlink
+	}
+	return nil
+}
+
+type RxMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GatewayIds   *GatewayIdentifiers    `protobuf:"bytes,1,opt,name=gateway_ids,json=gatewayIds,proto3" json:"gateway_ids,omitempty"`
+	AntennaIndex uint32                 `protobuf:"varint,2,opt,name=antenna_index,json=antennaIndex,proto3" json:"antenna_index,omitempty"`
+	Time         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	// Concentrator timestamp (microseconds).
+	Timestamp    uint32  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Rssi         float32 `protobuf:"fixed32,8,opt,name=rssi,proto3" json:"rssi,omitempty"`
+	ChannelRssi  float32 `protobuf:"fixed32,9,opt,name=channel_rssi,json=channelRssi,proto3" json:"channel_rssi,omitempty"`
+	Snr          float32 `protobuf:"fixed32,11,opt,name=snr,proto3" json:"snr,omitempty"`
+	UplinkToken  []byte  `protobuf:"bytes,15,opt,name=uplink_token,json=uplinkToken,proto3" json:"uplink_token,omitempty"`
+	ChannelIndex uint32  `protobuf:"varint,17,opt,name=channel_index,json=channelIndex,proto3" json:"channel_index,omitempty"`
+}
+
+func (x *RxMetadata) Reset() {
+	*x = RxMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RxMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RxMetadata) ProtoMessage() {}
+
+func (x *RxMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RxMetadata.ProtoReflect.Descriptor instead.
+func (*RxMetadata) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RxMetadata) GetGatewayIds() *GatewayIdentifiers {
+	if x != nil {
+		return x.GatewayIds
+	}
+	return nil
+}
+
+func (x *RxMetadata) GetAntennaIndex() uint32 {
+	if x != nil {
+		return x.AntennaIndex
+	}
+	return 0
+}
+
+func (x *RxMetadata) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *RxMetadata) GetTimestamp() uint32 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *RxMetadata) GetRssi() float32 {
+	if x != nil {
+		return x.Rssi
+	}
+	return 0
+}
+
+func (x *RxMetadata) GetChannelRssi() float32 {
+	if x != nil {
+		return x.ChannelRssi
+	}
+	return 0
+}
+
+func (x *RxMetadata) GetSnr() float32 {
+	if x != nil {
+		return x.Snr
+	}
+	return 0
+}
+
+func (x *RxMetadata) GetUplinkToken() []byte {
+	if x != nil {
+		return x.UplinkToken
+	}
+	return nil
+}
+
+func (x *RxMetadata) GetChannelIndex() uint32 {
+	if x != nil {
+		return x.ChannelIndex
+	}
+	return 0
+}
+
+type UplinkMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RawPayload     []byte                 `protobuf:"bytes,1,opt,name=raw_payload,json=rawPayload,proto3" json:"raw_payload,omitempty"`
+	Settings       *TxSettings            `protobuf:"bytes,4,opt,name=settings,proto3" json:"settings,omitempty"`
+	RxMetadata     []*RxMetadata          `protobuf:"bytes,5,rep,name=rx_metadata,json=rxMetadata,proto3" json:"rx_metadata,omitempty"`
+	ReceivedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"`
+	CorrelationIds []string               `protobuf:"bytes,7,rep,name=correlation_ids,json=correlationIds,proto3" json:"correlation_ids,omitempty"`
+}
+
+func (x *UplinkMessage) Reset() {
+	*x = UplinkMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UplinkMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UplinkMessage) ProtoMessage() {}
+
+func (x *UplinkMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UplinkMessage.ProtoReflect.Descriptor instead.
+func (*UplinkMessage) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UplinkMessage) GetRawPayload() []byte {
+	if x != nil {
+		return x.RawPayload
+	}
+	return nil
+}
+
+func (x *UplinkMessage) GetSettings() *TxSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *UplinkMessage) GetRxMetadata() []*RxMetadata {
+	if x != nil {
+		return x.RxMetadata
+	}
+	return nil
+}
+
+func (x *UplinkMessage) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *UplinkMessage) GetCorrelationIds() []string {
+	if x != nil {
+		return x.CorrelationIds
+	}
+	return nil
+}
+
+type DownlinkMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RawPayload []byte `protobuf:"bytes,1,opt,name=raw_payload,json=rawPayload,proto3" json:"raw_payload,omitempty"`
+	// Types that are assignable to Settings:
+	//	*DownlinkMessage_Scheduled
+	Settings       isDownlinkMessage_Settings `protobuf_oneof:"settings"`
+	CorrelationIds []string                   `protobuf:"bytes,6,rep,name=correlation_ids,json=correlationIds,proto3" json:"correlation_ids,omitempty"`
+}
+
+func (x *DownlinkMessage) Reset() {
+	*x = DownlinkMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DownlinkMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownlinkMessage) ProtoMessage() {}
+
+func (x *DownlinkMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownlinkMessage.ProtoReflect.Descriptor instead.
+func (*DownlinkMessage) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DownlinkMessage) GetRawPayload() []byte {
+	if x != nil {
+		return x.RawPayload
+	}
+	return nil
+}
+
+func (m *DownlinkMessage) GetSettings() isDownlinkMessage_Settings {
+	if m != nil {
+		return m.Settings
+	}
+	return nil
+}
+
+func (x *DownlinkMessage) GetScheduled() *TxSettings {
+	if x, ok := x.GetSettings().(*DownlinkMessage_Scheduled); ok {
+		return x.Scheduled
+	}
+	return nil
+}
+
+func (x *DownlinkMessage) GetCorrelationIds() []string {
+	if x != nil {
+		return x.CorrelationIds
+	}
+	return nil
+}
+
+type isDownlinkMessage_Settings interface {
+	isDownlinkMessage_Settings()
+}
+
+type DownlinkMessage_Scheduled struct {
+	// Settings of a downlink scheduled by the Gateway Server.
+	Scheduled *TxSettings `protobuf:"bytes,5,opt,name=scheduled,proto3,oneof"`
+}
+
+func (*DownlinkMessage_Scheduled) isDownlinkMessage_Settings() {}
+
+type GatewayDown struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DownlinkMessage *DownlinkMessage `protobuf:"bytes,1,opt,name=downlink_message,json=downlinkMessage,proto3" json:"downlink_message,omitempty"`
+}
+
+func (x *GatewayDown) Reset() {
+	*x = GatewayDown{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GatewayDown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GatewayDown) ProtoMessage() {}
+
+func (x *GatewayDown) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GatewayDown.ProtoReflect.Descriptor instead.
+func (*GatewayDown) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GatewayDown) GetDownlinkMessage() *DownlinkMessage {
+	if x != nil {
+		return x.DownlinkMessage
+	}
+	return nil
+}
+
+type GatewayStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Versions map[string]string      `protobuf:"bytes,3,rep,name=versions,proto3" json:"versions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Metrics  map[string]float32     `protobuf:"bytes,6,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed32,2,opt,name=value,proto3"`
+}
+
+func (x *GatewayStatus) Reset() {
+	*x = GatewayStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GatewayStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GatewayStatus) ProtoMessage() {}
+
+func (x *GatewayStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GatewayStatus.ProtoReflect.Descriptor instead.
+func (*GatewayStatus) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GatewayStatus) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *GatewayStatus) GetVersions() map[string]string {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *GatewayStatus) GetMetrics() map[string]float32 {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+type TxAcknowledgment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CorrelationIds  []string                `protobuf:"bytes,1,rep,name=correlation_ids,json=correlationIds,proto3" json:"correlation_ids,omitempty"`
+	Result          TxAcknowledgment_Result `protobuf:"varint,2,opt,name=result,proto3,enum=ttn.lorawan.v3.TxAcknowledgment_Result" json:"result,omitempty"`
+	DownlinkMessage *DownlinkMessage        `protobuf:"bytes,3,opt,name=downlink_message,json=downlinkMessage,proto3" json:"downlink_message,omitempty"`
+}
+
+func (x *TxAcknowledgment) Reset() {
+	*x = TxAcknowledgment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TxAcknowledgment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxAcknowledgment) ProtoMessage() {}
+
+func (x *TxAcknowledgment) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxAcknowledgment.ProtoReflect.Descriptor instead.
+func (*TxAcknowledgment) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TxAcknowledgment) GetCorrelationIds() []string {
+	if x != nil {
+		return x.CorrelationIds
+	}
+	return nil
+}
+
+func (x *TxAcknowledgment) GetResult() TxAcknowledgment_Result {
+	if x != nil {
+		return x.Result
+	}
+	return TxAcknowledgment_SUCCESS
+}
+
+func (x *TxAcknowledgment) GetDownlinkMessage() *DownlinkMessage {
+	if x != nil {
+		return x.DownlinkMessage
+	}
+	return nil
+}
+
+type TxSettings_Downlink struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AntennaIndex uint32 `protobuf:"varint,1,opt,name=antenna_index,json=antennaIndex,proto3" json:"antenna_index,omitempty"`
+	// Transmit power (dBm).
+	TxPower            float32 `protobuf:"fixed32,2,opt,name=tx_power,json=txPower,proto3" json:"tx_power,omitempty"`
+	InvertPolarization bool    `protobuf:"varint,3,opt,name=invert_polarization,json=invertPolarization,proto3" json:"invert_polarization,omitempty"`
+}
+
+func (x *TxSettings_Downlink) Reset() {
+	*x = TxSettings_Downlink{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttnpb_ttn_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TxSettings_Downlink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxSettings_Downlink) ProtoMessage() {}
+
+func (x *TxSettings_Downlink) ProtoReflect() protoreflect.Message {
+	mi := &file_ttnpb_ttn_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxSettings_Downlink.ProtoReflect.Descriptor instead.
+func (*TxSettings_Downlink) Descriptor() ([]byte, []int) {
+	return file_ttnpb_ttn_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *TxSettings_Downlink) GetAntennaIndex() uint32 {
+	if x != nil {
+		return x.AntennaIndex
+	}
+	return 0
+}
+
+func (x *TxSettings_Downlink) GetTxPower() float32 {
+	if x != nil {
+		return x.TxPower
+	}
+	return 0
+}
+
+func (x *TxSettings_Downlink) GetInvertPolarization() bool {
+	if x != nil {
+		return x.InvertPolarization
+	}
+	return false
+}
+
+var File_ttnpb_ttn_proto protoreflect.FileDescriptor
+
+var file_ttnpb_ttn_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x74, 0x74, 0x6e, 0x70, 0x62, 0x2f, 0x74, 0x74, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x0e, 0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72, 0x61, 0x77, 0x61, 0x6e, 0x2e, 0x76,
+	0x33, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0x45, 0x0a, 0x12, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x61, 0x74, 0x65,
+	0x77, 0x61, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x61,
+	0x74, 0x65, 0x77, 0x61, 0x79, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x75, 0x69, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x65, 0x75, 0x69, 0x22, 0x7d, 0x0a, 0x08, 0x44, 0x61, 0x74,
+	0x61, 0x52, 0x61, 0x74, 0x65, 0x12, 0x32, 0x0a, 0x04, 0x6c, 0x6f, 0x72, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72, 0x61, 0x77, 0x61,
+	0x6e, 0x2e, 0x76, 0x33, 0x2e, 0x4c, 0x6f, 0x52, 0x61, 0x44, 0x61, 0x74, 0x61, 0x52, 0x61, 0x74,
+	0x65, 0x48, 0x00, 0x52, 0x04, 0x6c, 0x6f, 0x72, 0x61, 0x12, 0x2f, 0x0a, 0x03, 0x66, 0x73, 0x6b,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72,
+	0x61, 0x77, 0x61, 0x6e, 0x2e, 0x76, 0x33, 0x2e, 0x46, 0x53, 0x4b, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x03, 0x66, 0x73, 0x6b, 0x42, 0x0c, 0x0a, 0x0a, 0x6d, 0x6f,
+	0x64, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x78, 0x0a, 0x0c, 0x4c, 0x6f, 0x52, 0x61,
+	0x44, 0x61, 0x74, 0x61, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x64,
+	0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x61, 0x6e,
+	0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x70, 0x72, 0x65, 0x61, 0x64,
+	0x69, 0x6e, 0x67, 0x5f, 0x66, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0f, 0x73, 0x70, 0x72, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x46, 0x61, 0x63, 0x74, 0x6f,
+	0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x61, 0x74, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x61,
+	0x74, 0x65, 0x22, 0x28, 0x0a, 0x0b, 0x46, 0x53, 0x4b, 0x44, 0x61, 0x74, 0x61, 0x52, 0x61, 0x74,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x69, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x07, 0x62, 0x69, 0x74, 0x52, 0x61, 0x74, 0x65, 0x22, 0xb3, 0x03, 0x0a,
+	0x0a, 0x54, 0x78, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x35, 0x0a, 0x09, 0x64,
+	0x61, 0x74, 0x61, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72, 0x61, 0x77, 0x61, 0x6e, 0x2e, 0x76, 0x33, 0x2e,
+	0x44, 0x61, 0x74, 0x61, 0x52, 0x61, 0x74, 0x65, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x52, 0x61,
+	0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x61, 0x74,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52,
+	0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x63, 0x72, 0x63, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x72, 0x63,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2e,
+	0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x3f,
+	0x0a, 0x08, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x23, 0x2e, 0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72, 0x61, 0x77, 0x61, 0x6e, 0x2e, 0x76,
+	0x33, 0x2e, 0x54, 0x78, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x2e, 0x44, 0x6f, 0x77,
+	0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x08, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x1a,
+	0x7b, 0x0a, 0x08, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x12, 0x23, 0x0a, 0x0d, 0x61,
+	0x6e, 0x74, 0x65, 0x6e, 0x6e, 0x61, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0c, 0x61, 0x6e, 0x74, 0x65, 0x6e, 0x6e, 0x61, 0x49, 0x6e, 0x64, 0x65, 0x78,
+	0x12, 0x19, 0x0a, 0x08, 0x74, 0x78, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x02, 0x52, 0x07, 0x74, 0x78, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x2f, 0x0a, 0x13, 0x69,
+	0x6e, 0x76, 0x65, 0x72, 0x74, 0x5f, 0x70, 0x6f, 0x6c, 0x61, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x69, 0x6e, 0x76, 0x65, 0x72, 0x74,
+	0x50, 0x6f, 0x6c, 0x61, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4a, 0x04, 0x08, 0x02,
+	0x10, 0x03, 0x22, 0xd5, 0x02, 0x0a, 0x0a, 0x52, 0x78, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x43, 0x0a, 0x0b, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x69, 0x64, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72,
+	0x61, 0x77, 0x61, 0x6e, 0x2e, 0x76, 0x33, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x49,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x52, 0x0a, 0x67, 0x61, 0x74, 0x65,
+	0x77, 0x61, 0x79, 0x49, 0x64, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6e, 0x74, 0x65, 0x6e, 0x6e,
+	0x61, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x61,
+	0x6e, 0x74, 0x65, 0x6e, 0x6e, 0x61, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x2e, 0x0a, 0x04, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x73, 0x73,
+	0x69, 0x18, 0x08, 0x20, 0x01, 0x28, 0x02, 0x52, 0x04, 0x72, 0x73, 0x73, 0x69, 0x12, 0x21, 0x0a,
+	0x0c, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x72, 0x73, 0x73, 0x69, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x02, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x73, 0x73, 0x69,
+	0x12, 0x10, 0x0a, 0x03, 0x73, 0x6e, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x02, 0x52, 0x03, 0x73,
+	0x6e, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x75, 0x70, 0x6c, 0x69, 0x6e, 0x6b,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x63, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x8b, 0x02, 0x0a, 0x0d, 0x55,
+	0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0b,
+	0x72, 0x61, 0x77, 0x5f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0a, 0x72, 0x61, 0x77, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x36, 0x0a,
+	0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72, 0x61, 0x77, 0x61, 0x6e, 0x2e, 0x76, 0x33,
+	0x2e, 0x54, 0x78, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x08, 0x73, 0x65, 0x74,
+	0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x3b, 0x0a, 0x0b, 0x72, 0x78, 0x5f, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x74, 0x6e,
+	0x2e, 0x6c, 0x6f, 0x72, 0x61, 0x77, 0x61, 0x6e, 0x2e, 0x76, 0x33, 0x2e, 0x52, 0x78, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x0a, 0x72, 0x78, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x3b, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x41, 0x74, 0x12,
+	0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
+	0x64, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x0f, 0x44, 0x6f, 0x77,
+	0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0b,
+	0x72, 0x61, 0x77, 0x5f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0a, 0x72, 0x61, 0x77, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x3a, 0x0a,
+	0x09, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72, 0x61, 0x77, 0x61, 0x6e, 0x2e, 0x76,
+	0x33, 0x2e, 0x54, 0x78, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x48, 0x00, 0x52, 0x09,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x72,
+	0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x64, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x59,
+	0x0a, 0x0b, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x44, 0x6f, 0x77, 0x6e, 0x12, 0x4a, 0x0a,
+	0x10, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f,
+	0x72, 0x61, 0x77, 0x61, 0x6e, 0x2e, 0x76, 0x33, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e,
+	0x6b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0f, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69,
+	0x6e, 0x6b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xc7, 0x02, 0x0a, 0x0d, 0x47, 0x61,
+	0x74, 0x65, 0x77, 0x61, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2e, 0x0a, 0x04, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x47, 0x0a, 0x08, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e,
+	0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72, 0x61, 0x77, 0x61, 0x6e, 0x2e, 0x76, 0x33, 0x2e, 0x47,
+	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x44, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72, 0x61,
+	0x77, 0x61, 0x6e, 0x2e, 0x76, 0x33, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x1a, 0x3b, 0x0a, 0x0d, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3a, 0x0a, 0x0c, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0xe9, 0x02, 0x0a, 0x10, 0x54, 0x78, 0x41, 0x63, 0x6b, 0x6e, 0x6f, 0x77,
+	0x6c, 0x65, 0x64, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x72, 0x72,
+	0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x73, 0x12, 0x3f, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x27, 0x2e, 0x74, 0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72, 0x61, 0x77, 0x61, 0x6e, 0x2e,
+	0x76, 0x33, 0x2e, 0x54, 0x78, 0x41, 0x63, 0x6b, 0x6e, 0x6f, 0x77, 0x6c, 0x65, 0x64, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x4a, 0x0a, 0x10, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x74,
+	0x74, 0x6e, 0x2e, 0x6c, 0x6f, 0x72, 0x61, 0x77, 0x61, 0x6e, 0x2e, 0x76, 0x33, 0x2e, 0x44, 0x6f,
+	0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0f, 0x64,
+	0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x9e,
+	0x01, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x55, 0x43,
+	0x43, 0x45, 0x53, 0x53, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57,
+	0x4e, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x4f, 0x4f,
+	0x5f, 0x4c, 0x41, 0x54, 0x45, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x4f, 0x4f, 0x5f, 0x45,
+	0x41, 0x52, 0x4c, 0x59, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x4f, 0x4c, 0x4c, 0x49, 0x53,
+	0x49, 0x4f, 0x4e, 0x5f, 0x50, 0x41, 0x43, 0x4b, 0x45, 0x54, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10,
+	0x43, 0x4f, 0x4c, 0x4c, 0x49, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x42, 0x45, 0x41, 0x43, 0x4f, 0x4e,
+	0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x54, 0x58, 0x5f, 0x46, 0x52, 0x45, 0x51, 0x10, 0x06, 0x12,
+	0x0c, 0x0a, 0x08, 0x54, 0x58, 0x5f, 0x50, 0x4f, 0x57, 0x45, 0x52, 0x10, 0x07, 0x12, 0x10, 0x0a,
+	0x0c, 0x47, 0x50, 0x53, 0x5f, 0x55, 0x4e, 0x4c, 0x4f, 0x43, 0x4b, 0x45, 0x44, 0x10, 0x08, 0x42,
+	0x22, 0x5a, 0x20, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x69, 0x65,
+	0x67, 0x6f, 0x6d, 0x65, 0x7a, 0x2f, 0x6c, 0x64, 0x73, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x74, 0x74,
+	0x6e, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_ttnpb_ttn_proto_rawDescOnce sync.Once
+	file_ttnpb_ttn_proto_rawDescData = file_ttnpb_ttn_proto_rawDesc
+)
+
+func file_ttnpb_ttn_proto_rawDescGZIP() []byte {
+	file_ttnpb_ttn_proto_rawDescOnce.Do(func() {
+		file_ttnpb_ttn_proto_rawDescData = protoimpl.X.CompressGZIP(file_ttnpb_ttn_proto_rawDescData)
+	})
+	return file_ttnpb_ttn_proto_rawDescData
+}
+
+var file_ttnpb_ttn_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_ttnpb_ttn_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_ttnpb_ttn_proto_goTypes = []interface{}{
+	(TxAcknowledgment_Result)(0),  // 0: ttn.lorawan.v3.TxAcknowledgment.Result
+	(*GatewayIdentifiers)(nil),    // 1: ttn.lorawan.v3.GatewayIdentifiers
+	(*DataRate)(nil),              // 2: ttn.lorawan.v3.DataRate
+	(*LoRaDataRate)(nil),          // 3: ttn.lorawan.v3.LoRaDataRate
+	(*FSKDataRate)(nil),           // 4: ttn.lorawan.v3.FSKDataRate
+	(*TxSettings)(nil),            // 5: ttn.lorawan.v3.TxSettings
+	(*RxMetadata)(nil),            // 6: ttn.lorawan.v3.RxMetadata
+	(*UplinkMessage)(nil),         // 7: ttn.lorawan.v3.UplinkMessage
+	(*DownlinkMessage)(nil),       // 8: ttn.lorawan.v3.DownlinkMessage
+	(*GatewayDown)(nil),           // 9: ttn.lorawan.v3.GatewayDown
+	(*GatewayStatus)(nil),         // 10: ttn.lorawan.v3.GatewayStatus
+	(*TxAcknowledgment)(nil),      // 11: ttn.lorawan.v3.TxAcknowledgment
+	(*TxSettings_Downlink)(nil),   // 12: ttn.lorawan.v3.TxSettings.Downlink
+	nil,                           // 13: ttn.lorawan.v3.GatewayStatus.VersionsEntry
+	nil,                           // 14: ttn.lorawan.v3.GatewayStatus.MetricsEntry
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+}
+var file_ttnpb_ttn_proto_depIdxs = []int32{
+	3,  // 0: ttn.lorawan.v3.DataRate.lora:type_name -> ttn.lorawan.v3.LoRaDataRate
+	4,  // 1: ttn.lorawan.v3.DataRate.fsk:type_name -> ttn.lorawan.v3.FSKDataRate
+	2,  // 2: ttn.lorawan.v3.TxSettings.data_rate:type_name -> ttn.lorawan.v3.DataRate
+	15, // 3: ttn.lorawan.v3.TxSettings.time:type_name -> google.protobuf.Timestamp
+	12, // 4: ttn.lorawan.v3.TxSettings.downlink:type_name -> ttn.lorawan.v3.TxSettings.Downlink
+	1,  // 5: ttn.lorawan.v3.RxMetadata.gateway_ids:type_name -> ttn.lorawan.v3.GatewayIdentifiers
+	15, // 6: ttn.lorawan.v3.RxMetadata.time:type_name -> google.protobuf.Timestamp
+	5,  // 7: ttn.lorawan.v3.UplinkMessage.settings:type_name -> ttn.lorawan.v3.TxSettings
+	6,  // 8: ttn.lorawan.v3.UplinkMessage.rx_metadata:type_name -> ttn.lorawan.v3.RxMetadata
+	15, // 9: ttn.lorawan.v3.UplinkMessage.received_at:type_name -> google.protobuf.Timestamp
+	5,  // 10: ttn.lorawan.v3.DownlinkMessage.scheduled:type_name -> ttn.lorawan.v3.TxSettings
+	8,  // 11: ttn.lorawan.v3.GatewayDown.downlink_message:type_name -> ttn.lorawan.v3.DownlinkMessage
+	15, // 12: ttn.lorawan.v3.GatewayStatus.time:type_name -> google.protobuf.Timestamp
+	13, // 13: ttn.lorawan.v3.GatewayStatus.versions:type_name -> ttn.lorawan.v3.GatewayStatus.VersionsEntry
+	14, // 14: ttn.lorawan.v3.GatewayStatus.metrics:type_name -> ttn.lorawan.v3.GatewayStatus.MetricsEntry
+	0,  // 15: ttn.lorawan.v3.TxAcknowledgment.result:type_name -> ttn.lorawan.v3.TxAcknowledgment.Result
+	8,  // 16: ttn.lorawan.v3.TxAcknowledgment.downlink_message:type_name -> ttn.lorawan.v3.DownlinkMessage
+	17, // [17:17] is the sub-list for method output_type
+	17, // [17:17] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_ttnpb_ttn_proto_init() }
+func file_ttnpb_ttn_proto_init() {
+	if File_ttnpb_ttn_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_ttnpb_ttn_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GatewayIdentifiers); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttnpb_ttn_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DataRate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttnpb_ttn_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoRaDataRate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttnpb_ttn_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FSKDataRate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttnpb_ttn_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TxSettings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttnpb_ttn_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RxMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttnpb_ttn_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UplinkMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttnpb_ttn_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownlinkMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttnpb_ttn_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GatewayDown); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttnpb_ttn_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GatewayStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttnpb_ttn_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TxAcknowledgment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttnpb_ttn_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TxSettings_Downlink); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_ttnpb_ttn_proto_msgTypes[1].OneofWrappers = []interface{}{
+		(*DataRate_Lora)(nil),
+		(*DataRate_Fsk)(nil),
+	}
+	file_ttnpb_ttn_proto_msgTypes[7].OneofWrappers = []interface{}{
+		(*DownlinkMessage_Scheduled)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_ttnpb_ttn_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_ttnpb_ttn_proto_goTypes,
+		DependencyIndexes: file_ttnpb_ttn_proto_depIdxs,
+		EnumInfos:         file_ttnpb_ttn_proto_enumTypes,
+		MessageInfos:      file_ttnpb_ttn_proto_msgTypes,
+	}.Build()
+	File_ttnpb_ttn_proto = out.File
+	file_ttnpb_ttn_proto_rawDesc = nil
+	file_ttnpb_ttn_proto_goTypes = nil
+	file_ttnpb_ttn_proto_depIdxs = nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	l "gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/iegomez/lds/lds"
+	matx "github.com/scartill/giox/material"
+	log "github.com/sirupsen/logrus"
+)
+
+// mqttBroker is the embedded MQTT broker, when running.
+var mqttBroker *lds.Broker
+
+var (
+	brokerEnabledCheckbox widget.Bool
+	brokerBindEdit        widget.Editor
+	brokerStartButton     widget.Clickable
+	brokerStopButton      widget.Clickable
+)
+
+func brokerResetGuiValues() {
+	brokerEnabledCheckbox.Value = config.Broker.Enabled
+	brokerBindEdit.SetText(config.Broker.Bind)
+}
+
+func brokerForm(th *material.Theme) l.FlexChild {
+
+	config.Broker.Enabled = brokerEnabledCheckbox.Value
+	config.Broker.Bind = brokerBindEdit.Text()
+
+	for brokerStartButton.Clicked() {
+		startBroker()
+	}
+
+	for brokerStopButton.Clicked() {
+		stopBroker()
+	}
+
+	widgets := []l.FlexChild{
+		matx.RigidSection(th, "Embedded broker"),
+		matx.RigidCheckBox(th, "Start on connect", &brokerEnabledCheckbox),
+		matx.RigidEditor(th, "Bind:", ":1883", &brokerBindEdit),
+	}
+
+	if mqttBroker == nil {
+		widgets = append(widgets, matx.RigidButton(th, "Start", &brokerStartButton))
+	} else {
+		widgets = append(widgets, matx.RigidLabel(th, fmt.Sprintf("Listening on %s", mqttBroker.Addr())))
+		for _, c := range mqttBroker.Clients() {
+			state := "disconnected"
+			if c.Connected {
+				state = "connected"
+			}
+			if c.Persistent {
+				state += fmt.Sprintf(", persistent (%d queued)", c.Queued)
+			}
+			widgets = append(widgets, matx.RigidLabel(th, fmt.Sprintf("%s (%s) %s: %s", c.ID, c.Address, state, strings.Join(c.Subscriptions, ", "))))
+		}
+		widgets = append(widgets, matx.RigidButton(th, "Stop", &brokerStopButton))
+	}
+
+	inset := l.Inset{Left: unit.Dp(30)}
+	return l.Rigid(func(gtx l.Context) l.Dimensions {
+		return inset.Layout(gtx, func(gtx l.Context) l.Dimensions {
+			return l.Flex{Axis: l.Vertical}.Layout(gtx, widgets...)
+		})
+	})
+}
+
+func startBroker() error {
+	if mqttBroker != nil {
+		return nil
+	}
+
+	b, err := lds.NewBroker(config.Broker.Bind)
+	if err != nil {
+		log.Errorf("couldn't start the embedded broker: %s", err)
+		return err
+	}
+	mqttBroker = b
+	return nil
+}
+
+func stopBroker() {
+	if mqttBroker == nil {
+		return
+	}
+
+	if err := mqttBroker.Close(); err != nil {
+		log.Errorf("embedded broker close error: %s", err)
+	}
+	mqttBroker = nil
+	log.Infoln("embedded broker stopped")
+}
@@ -0,0 +1,934 @@
+// Command lds is the headless LoRaWAN device simulator. It reads the same toml configuration as the GUI
+// and joins or sends uplinks through MQTT or the packet forwarder UDP protocol, exiting with a status
+// that scripts and CI jobs may check.
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/iegomez/lds/lds"
+	"github.com/iegomez/lds/lds/keys"
+)
+
+// Exit codes.
+const (
+	exitOK         = 0
+	exitFailure    = 1 //A frame couldn't be sent, or the device state couldn't be changed.
+	exitUsage      = 2
+	exitConfig     = 3
+	exitConnection = 4 //Couldn't connect to the broker, the network server or Redis.
+	exitTimeout    = 5 //No join accept was received in time, or a scenario step timed out waiting for a downlink.
+)
+
+const usage = `Usage: lds [options] <command> [command options]
+
+Commands:
+  join     send a join request and wait for the join accept
+  uplink   send an uplink
+  run      send uplinks every interval
+  reset    delete the device session, counters and nonces from Redis
+  status   print the device session
+  scenario run the steps of a scenario file
+  serve    serve the HTTP and gRPC APIs until interrupted
+  journal  print the frames recorded to the journal
+  replay   send the join requests and uplinks of a recorded session again
+  decode   decode a frame, checking its MIC and decrypting it with the given keys
+  keys     derive the session and join server keys of a join (keys derive)
+
+Run "lds <command> -h" for the command options.
+
+Options:
+`
+
+var commands = map[string]func(config *lds.Config, useUDP bool, args []string) int{
+	"join":     joinCommand,
+	"uplink":   uplinkCommand,
+	"run":      runCommand,
+	"reset":    resetCommand,
+	"status":   statusCommand,
+	"scenario": scenarioCommand,
+	"serve":    serveCommand,
+	"journal":  journalCommand,
+	"replay":   replayCommand,
+	"decode":   decodeCommand,
+	"keys":     keysCommand,
+}
+
+// offlineCommands don't connect to anything.
+var offlineCommands = map[string]bool{
+	"journal": true,
+	"decode":  true,
+	"keys":    true,
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	confFile := flag.String("conf", "conf.toml", "path to toml configuration file")
+	useUDP := flag.Bool("udp", false, "use the packet forwarder UDP transport (forwarder section) instead of MQTT")
+	flag.Parse()
+
+	command, ok := commands[flag.Arg(0)]
+	if !ok {
+		flag.Usage()
+		os.Exit(exitUsage)
+	}
+
+	//The frame and key tools run without a configuration file, they only read the device from it.
+	config := lds.NewConfig()
+	if err := config.Load(*confFile); err != nil && !(os.IsNotExist(err) && offlineCommands[flag.Arg(0)]) {
+		log.Errorf("couldn't load the configuration: %s", err)
+		os.Exit(exitConfig)
+	}
+
+	log.SetLevel(log.InfoLevel)
+	if l, err := log.ParseLevel(config.LogLevel); err == nil {
+		log.SetLevel(l)
+	}
+
+	if config.Metrics.Enabled {
+		if _, err := lds.NewMetricsServer(config.Metrics.Bind); err != nil {
+			log.Errorln(err)
+			os.Exit(exitConfig)
+		}
+	}
+
+	if config.JoinServer.Enabled && !offlineCommands[flag.Arg(0)] {
+		if _, err := lds.NewJoinServer(config.JoinServer.Bind, config); err != nil {
+			log.Errorln(err)
+			os.Exit(exitConfig)
+		}
+	}
+
+	if config.Pcap.Enabled {
+		capture, err := config.Pcap.Open()
+		if err != nil {
+			log.Errorln(err)
+			os.Exit(exitConfig)
+		}
+		lds.SetCapture(capture)
+	}
+
+	//UDP is used as well when there's a network server but no broker configured.
+	udp := *useUDP || (config.MQTT.Server == "" && config.Forwarder.Server != "")
+
+	os.Exit(command(config, udp, flag.Args()[1:]))
+}
+
+// connect starts Redis, builds the simulator and connects it, returning the exit code on failure.
+func connect(config *lds.Config, useUDP bool) (*simulator, int) {
+	//The DevNonce and frame counters are kept in Redis, which is required.
+	if err := lds.StartRedis(config.RedisConf.Addr, config.RedisConf.Password, config.RedisConf.DB); err != nil {
+		return nil, exitConnection
+	}
+
+	s, err := newSimulator(config, useUDP)
+	if err != nil {
+		log.Errorf("configuration error: %s", err)
+		return nil, exitConfig
+	}
+
+	if err := s.connect(); err != nil {
+		log.Errorf("connection error: %s", err)
+		s.close()
+		return nil, exitConnection
+	}
+	return s, exitOK
+}
+
+func joinCommand(config *lds.Config, useUDP bool, args []string) int {
+	fs := flag.NewFlagSet("join", flag.ContinueOnError)
+	timeout := fs.Duration("timeout", 10*time.Second, "how long to wait for the join accept, 0 to only send the request")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if config.Device.Profile != "OTAA" {
+		log.Errorf("only OTAA devices join, the device profile is %q", config.Device.Profile)
+		return exitConfig
+	}
+
+	s, code := connect(config, useUDP)
+	if code != exitOK {
+		return code
+	}
+	defer s.close()
+
+	if err := s.Join(); err != nil {
+		log.Errorf("join error: %s", err)
+		return exitFailure
+	}
+	log.Infoln("join sent")
+
+	if *timeout == 0 {
+		return exitOK
+	}
+	if !s.waitJoin(*timeout) {
+		log.Errorf("no join accept received in %s", *timeout)
+		return exitTimeout
+	}
+	device := s.State()
+	log.Infof("joined, dev addr is %s", lds.DevAddressToHex(device.DevAddr))
+	return exitOK
+}
+
+// uplinkFlags are the options shared by the uplink and run commands.
+type uplinkFlags struct {
+	payload   *string
+	fPort     *int
+	confirmed *bool
+}
+
+func addUplinkFlags(fs *flag.FlagSet, config *lds.Config) uplinkFlags {
+	return uplinkFlags{
+		payload:   fs.String("payload", "", "hex encoded payload, instead of the configured data"),
+		fPort:     fs.Int("fport", config.RawPayload.FPort, "fPort"),
+		confirmed: fs.Bool("confirmed", config.Device.MType == lorawan.ConfirmedDataUp, "send confirmed data up"),
+	}
+}
+
+// check validates the options that don't depend on the configuration.
+func (f uplinkFlags) check() error {
+	return lds.CheckFPort(*f.fPort)
+}
+
+// uplinkParams returns the message type, fPort and payload of an uplink.
+func (f uplinkFlags) uplinkParams(config *lds.Config) (lorawan.MType, uint8, []byte, error) {
+	if err := f.check(); err != nil {
+		return 0, 0, nil, err
+	}
+
+	mType := lorawan.UnconfirmedDataUp
+	if *f.confirmed {
+		mType = lorawan.ConfirmedDataUp
+	}
+
+	if *f.payload != "" {
+		payload, err := hex.DecodeString(*f.payload)
+		if err != nil {
+			return mType, 0, nil, fmt.Errorf("couldn't decode hex payload: %s", err)
+		}
+		return mType, uint8(*f.fPort), payload, nil
+	}
+
+	payload, err := config.Payload()
+	return mType, uint8(*f.fPort), payload, err
+}
+
+func uplinkCommand(config *lds.Config, useUDP bool, args []string) int {
+	fs := flag.NewFlagSet("uplink", flag.ContinueOnError)
+	uf := addUplinkFlags(fs, config)
+	wait := fs.Duration("wait", 0, "how long to wait for downlinks after sending")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if err := uf.check(); err != nil {
+		log.Errorln(err)
+		return exitUsage
+	}
+
+	s, code := connect(config, useUDP)
+	if code != exitOK {
+		return code
+	}
+	defer s.close()
+
+	if code := sendUplink(s, uf); code != exitOK {
+		return code
+	}
+	if *wait > 0 {
+		log.Infof("%d downlinks received", s.drain(*wait))
+	}
+	return exitOK
+}
+
+func runCommand(config *lds.Config, useUDP bool, args []string) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	uf := addUplinkFlags(fs, config)
+	interval := fs.Duration("interval", 10*time.Second, "time between uplinks")
+	count := fs.Int("count", 0, "number of uplinks to send, 0 to run until interrupted")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if err := uf.check(); err != nil {
+		log.Errorln(err)
+		return exitUsage
+	}
+
+	s, code := connect(config, useUDP)
+	if code != exitOK {
+		return code
+	}
+	defer s.close()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	failed := 0
+loop:
+	for i := 0; *count == 0 || i < *count; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(*interval):
+			case <-interrupt:
+				log.Infoln("interrupted")
+				break loop
+			}
+		}
+
+		//Keep running after failures, but report them in the exit code.
+		if code := sendUplink(s, uf); code != exitOK {
+			if code != exitFailure {
+				return code
+			}
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		log.Errorf("%d uplinks failed", failed)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// sendUplink sends an uplink, failing when an OTAA device hasn't joined yet.
+func sendUplink(s *simulator, uf uplinkFlags) int {
+	if !s.joined() {
+		log.Errorln("the device hasn't joined, run lds join first")
+		return exitFailure
+	}
+
+	mType, fPort, payload, err := uf.uplinkParams(s.config)
+	if err != nil {
+		log.Errorln(err)
+		return exitConfig
+	}
+
+	fCnt, err := s.Uplink(mType, fPort, payload, nil, lorawan.FCtrl{})
+	if err != nil {
+		log.Errorf("couldn't send uplink: %s", err)
+		return exitFailure
+	}
+	log.Infof("message sent, uplink framecounter is now %d", fCnt)
+	return exitOK
+}
+
+func scenarioCommand(config *lds.Config, useUDP bool, args []string) int {
+	fs := flag.NewFlagSet("scenario", flag.ContinueOnError)
+	report := fs.String("report", "", "write a report of the steps in junit or tap format")
+	out := fs.String("out", "", "report file, stdout when empty")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: lds [options] scenario [scenario options] <file>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 || (*report != "" && *report != "junit" && *report != "tap") {
+		fs.Usage()
+		return exitUsage
+	}
+
+	var scenarios []*lds.Scenario
+	for _, filename := range fs.Args() {
+		scenario, err := lds.LoadScenario(filename)
+		if err != nil {
+			log.Errorf("couldn't load the scenario %s: %s", filename, err)
+			return exitConfig
+		}
+		scenarios = append(scenarios, scenario)
+	}
+
+	s, code := connect(config, useUDP)
+	if code != exitOK {
+		return code
+	}
+	defer s.close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		log.Infoln("interrupted")
+		cancel()
+	}()
+
+	//Every scenario runs, the exit code is the one of the first failure.
+	code = exitOK
+	var results []*lds.ScenarioResult
+	for _, scenario := range scenarios {
+		runner := &lds.ScenarioRunner{Target: s, Config: config}
+		result, err := runner.Run(ctx, scenario)
+		results = append(results, result)
+		if err != nil && code == exitOK {
+			code = exitFailure
+			if errors.Cause(err) == lds.ErrTimeout {
+				code = exitTimeout
+			}
+		}
+	}
+
+	if *report != "" {
+		if err := writeReport(*report, *out, results); err != nil {
+			log.Errorf("couldn't write the report: %s", err)
+			return exitFailure
+		}
+	}
+	return code
+}
+
+func writeReport(format, filename string, results []*lds.ScenarioResult) error {
+	w := os.Stdout
+	if filename != "" {
+		f, err := os.Create(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == "junit" {
+		return lds.WriteJUnit(w, results)
+	}
+	return lds.WriteTAP(w, results)
+}
+
+func resetCommand(config *lds.Config, useUDP bool, args []string) int {
+	fs := flag.NewFlagSet("reset", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	device, code := savedDevice(config)
+	if code != exitOK {
+		return code
+	}
+
+	if err := device.Reset(); err != nil {
+		log.Errorf("couldn't reset the device: %s", err)
+		return exitFailure
+	}
+	log.Warningln("Device was reset")
+	return exitOK
+}
+
+func statusCommand(config *lds.Config, useUDP bool, args []string) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the session as json")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	device, code := savedDevice(config)
+	if code != exitOK {
+		return code
+	}
+
+	status := lds.NewSession(device)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(status)
+		return exitOK
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "DevEUI\t%s\n", status.DevEUI)
+	fmt.Fprintf(w, "Profile\t%s\n", status.Profile)
+	fmt.Fprintf(w, "Joined\t%t\n", status.Joined)
+	fmt.Fprintf(w, "DevAddr\t%s\n", status.DevAddr)
+	fmt.Fprintf(w, "UlFcnt\t%d\n", status.UlFcnt)
+	fmt.Fprintf(w, "DlFcnt\t%d\n", status.DlFcnt)
+	fmt.Fprintf(w, "DevNonce\t%d\n", status.DevNonce)
+	fmt.Fprintf(w, "JoinNonce\t%d\n", status.JoinNonce)
+	fmt.Fprintf(w, "NwkSEncKey\t%s\n", status.NwkSEncKey)
+	fmt.Fprintf(w, "SNwkSIntKey\t%s\n", status.SNwkSIntKey)
+	fmt.Fprintf(w, "FNwkSIntKey\t%s\n", status.FNwkSIntKey)
+	fmt.Fprintf(w, "AppSKey\t%s\n", status.AppSKey)
+	w.Flush()
+	return exitOK
+}
+
+// savedDevice returns the configured device with its session restored from Redis, which is required.
+func savedDevice(config *lds.Config) (*lds.Device, int) {
+	if err := lds.StartRedis(config.RedisConf.Addr, config.RedisConf.Password, config.RedisConf.DB); err != nil {
+		return nil, exitConnection
+	}
+
+	device, err := config.NewDevice()
+	if err != nil {
+		log.Errorf("configuration error: %s", err)
+		return nil, exitConfig
+	}
+	device.GetInfo()
+	return device, exitOK
+}
+
+func journalCommand(config *lds.Config, useUDP bool, args []string) int {
+	fs := flag.NewFlagSet("journal", flag.ContinueOnError)
+	file := fs.String("file", config.Journal.Path, "journal file, its rotated files are read first")
+	devEUI := fs.String("dev-eui", "", "only frames of this DevEUI")
+	devAddr := fs.String("dev-addr", "", "only frames of this DevAddr")
+	gateway := fs.String("gateway", "", "only frames through this gateway MAC")
+	direction := fs.String("direction", "", "only up or down frames")
+	mType := fs.String("mtype", "", "only frames of this message type, e.g. ConfirmedDataUp")
+	fPort := fs.Int("fport", -1, "only frames on this fPort")
+	since := fs.String("since", "", "only frames since this RFC 3339 time, or this duration ago")
+	until := fs.String("until", "", "only frames until this RFC 3339 time, or this duration ago")
+	asJSON := fs.Bool("json", false, "print the entries as json lines")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *direction != "" && *direction != lds.DirectionUp && *direction != lds.DirectionDown {
+		log.Errorf("direction must be %s or %s", lds.DirectionUp, lds.DirectionDown)
+		return exitUsage
+	}
+
+	filter := &lds.JournalFilter{
+		DevEUI:    *devEUI,
+		DevAddr:   *devAddr,
+		Gateway:   *gateway,
+		Direction: *direction,
+		MType:     *mType,
+	}
+	if *fPort >= 0 {
+		filter.FPort = fPort
+	}
+	var err error
+	if filter.Since, err = parseJournalTime(*since); err != nil {
+		log.Errorf("since: %s", err)
+		return exitUsage
+	}
+	if filter.Until, err = parseJournalTime(*until); err != nil {
+		log.Errorf("until: %s", err)
+		return exitUsage
+	}
+
+	var printEntry func(*lds.JournalEntry) error
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		printEntry = func(e *lds.JournalEntry) error {
+			return enc.Encode(e)
+		}
+	} else {
+		fmt.Fprintln(w, "TIME\tDIR\tGATEWAY\tDEVEUI\tDEVADDR\tMTYPE\tFCNT\tFPORT\tFRMPAYLOAD\tMAC COMMANDS\tERROR")
+		printEntry = func(e *lds.JournalEntry) error {
+			fCnt, fPort := "", ""
+			if e.FCnt != nil {
+				fCnt = fmt.Sprint(*e.FCnt)
+			}
+			if e.FPort != nil {
+				fPort = fmt.Sprint(*e.FPort)
+			}
+			_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", e.Time.Format(time.RFC3339Nano), e.Direction,
+				e.Gateway, e.DevEUI, e.DevAddr, e.MType, fCnt, fPort, e.FRMPayload, macCommandNames(e.MACCommands), e.Error)
+			return err
+		}
+	}
+
+	err = lds.ReadJournal(*file, filter, printEntry)
+	w.Flush()
+	if err != nil {
+		log.Errorf("couldn't read the journal: %s", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// parseJournalTime parses an RFC 3339 time or a duration before now, the zero time when empty.
+func parseJournalTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// macCommandNames returns the comma separated CIDs of the journal MAC commands.
+func macCommandNames(macCommands json.RawMessage) string {
+	var cids []struct {
+		CID string `json:"cid"`
+	}
+	if len(macCommands) == 0 || json.Unmarshal(macCommands, &cids) != nil {
+		return ""
+	}
+	names := make([]string, len(cids))
+	for i, c := range cids {
+		names[i] = c.CID
+	}
+	return strings.Join(names, ",")
+}
+
+func replayCommand(config *lds.Config, useUDP bool, args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	speed := fs.Float64("speed", 1, "replay speed, 2 replays twice as fast and 0 sends the frames without waiting")
+	recordedRadio := fs.Bool("recorded-radio", true, "use the recorded frequency, data rate and reception metadata instead of the configured ones")
+	joinTimeout := fs.Duration("join-timeout", 10*time.Second, "how long to wait for the join accept of a join request, 0 to not wait")
+	wait := fs.Duration("wait", 5*time.Second, "how long to wait for downlinks after the last frame")
+	devEUI := fs.String("dev-eui", "", "only replay the frames of this DevEUI")
+	since := fs.String("since", "", "only replay the frames since this RFC 3339 time, or this duration ago")
+	until := fs.String("until", "", "only replay the frames until this RFC 3339 time, or this duration ago")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: lds [options] replay [replay options] [journal file]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 1 || *speed < 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	file := config.Journal.Path
+	if fs.NArg() == 1 {
+		file = fs.Arg(0)
+	}
+	filter := &lds.JournalFilter{DevEUI: *devEUI}
+	var err error
+	if filter.Since, err = parseJournalTime(*since); err != nil {
+		log.Errorf("since: %s", err)
+		return exitUsage
+	}
+	if filter.Until, err = parseJournalTime(*until); err != nil {
+		log.Errorf("until: %s", err)
+		return exitUsage
+	}
+
+	entries, err := lds.LoadRecording(file, filter)
+	if err != nil {
+		log.Errorf("couldn't load the recording %s: %s", file, err)
+		return exitConfig
+	}
+
+	s, code := connect(config, useUDP)
+	if code != exitOK {
+		return code
+	}
+	defer s.close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		log.Infoln("interrupted")
+		cancel()
+	}()
+
+	replayer := &lds.Replayer{
+		Target:        s,
+		Speed:         *speed,
+		RecordedRadio: *recordedRadio,
+		JoinTimeout:   *joinTimeout,
+		Wait:          *wait,
+	}
+	result, err := replayer.Run(ctx, entries)
+	log.Infof("replay: %s", result)
+	if err != nil {
+		log.Errorf("replay failed: %s", err)
+		if errors.Cause(err) == lds.ErrTimeout {
+			return exitTimeout
+		}
+		return exitFailure
+	}
+	return exitOK
+}
+
+func decodeCommand(config *lds.Config, useUDP bool, args []string) int {
+	fs := flag.NewFlagSet("decode", flag.ContinueOnError)
+	device := fs.Bool("device", false, "decode with the keys of the configured device, the other options override them")
+	macVersion := fs.String("mac-version", "", "LoRaWAN version, 1.0 or 1.1 (default the device's with -device, else 1.0)")
+	nwkSKey := fs.String("nwk-s-key", "", "LoRaWAN 1.0 NwkSKey, sets the three network session keys")
+	appSKey := fs.String("app-s-key", "", "AppSKey")
+	nwkSEncKey := fs.String("nwk-s-enc-key", "", "LoRaWAN 1.1 NwkSEncKey")
+	sNwkSIntKey := fs.String("s-nwk-s-int-key", "", "LoRaWAN 1.1 SNwkSIntKey")
+	fNwkSIntKey := fs.String("f-nwk-s-int-key", "", "LoRaWAN 1.1 FNwkSIntKey")
+	nwkKey := fs.String("nwk-key", "", "NwkKey, to decrypt join accepts and check join MICs")
+	appKey := fs.String("app-key", "", "AppKey, to derive the AppSKey of LoRaWAN 1.1 join accepts")
+	devEUI := fs.String("dev-eui", "", "DevEUI of the device a join accept answers")
+	joinEUI := fs.String("join-eui", "", "JoinEUI of the join request a join accept answers")
+	devNonce := fs.Int("dev-nonce", -1, "DevNonce of the join request a join accept answers, to derive the session keys")
+	fCnt := fs.Int64("fcnt", -1, "full frame counter, when it exceeds the 16 bits the frame carries")
+	confFCnt := fs.Uint("conf-fcnt", 0, "LoRaWAN 1.1 counter of the frame acknowledged")
+	txDR := fs.Uint("tx-dr", 0, "LoRaWAN 1.1 data rate of the uplink")
+	txCh := fs.Uint("tx-ch", 0, "LoRaWAN 1.1 channel index of the uplink")
+	asJSON := fs.Bool("json", false, "print the decoded frame as json")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: lds [options] decode [decode options] <hex or base64 frame>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	b, err := lds.ParseFrame(fs.Arg(0))
+	if err != nil {
+		log.Errorln(err)
+		return exitUsage
+	}
+
+	keys := &lds.DecodeKeys{}
+	if *device {
+		d, err := config.NewDevice()
+		if err != nil {
+			log.Errorf("configuration error: %s", err)
+			return exitConfig
+		}
+		keys = d.DecodeKeys()
+		//The DevNonce of the last join is kept in Redis, which isn't read.
+		keys.DevNonce = nil
+	}
+
+	switch *macVersion {
+	case "":
+	case "1.0":
+		keys.MACVersion = lorawan.LoRaWAN1_0
+	case "1.1":
+		keys.MACVersion = lorawan.LoRaWAN1_1
+	default:
+		log.Errorf("unknown mac version %q", *macVersion)
+		return exitUsage
+	}
+
+	keyFlags := []struct {
+		name string
+		hex  string
+		keys []**lorawan.AES128Key
+	}{
+		{"nwk-s-key", *nwkSKey, []**lorawan.AES128Key{&keys.NwkSEncKey, &keys.SNwkSIntKey, &keys.FNwkSIntKey}},
+		{"app-s-key", *appSKey, []**lorawan.AES128Key{&keys.AppSKey}},
+		{"nwk-s-enc-key", *nwkSEncKey, []**lorawan.AES128Key{&keys.NwkSEncKey}},
+		{"s-nwk-s-int-key", *sNwkSIntKey, []**lorawan.AES128Key{&keys.SNwkSIntKey}},
+		{"f-nwk-s-int-key", *fNwkSIntKey, []**lorawan.AES128Key{&keys.FNwkSIntKey}},
+		{"nwk-key", *nwkKey, []**lorawan.AES128Key{&keys.NwkKey}},
+		{"app-key", *appKey, []**lorawan.AES128Key{&keys.AppKey}},
+	}
+	for _, kf := range keyFlags {
+		if kf.hex == "" {
+			continue
+		}
+		var key lorawan.AES128Key
+		if err := key.UnmarshalText([]byte(kf.hex)); err != nil {
+			log.Errorf("%s: %s", kf.name, err)
+			return exitUsage
+		}
+		for _, k := range kf.keys {
+			*k = &key
+		}
+	}
+
+	euiFlags := []struct {
+		name string
+		hex  string
+		eui  *lorawan.EUI64
+	}{
+		{"dev-eui", *devEUI, &keys.DevEUI},
+		{"join-eui", *joinEUI, &keys.JoinEUI},
+	}
+	for _, ef := range euiFlags {
+		if ef.hex == "" {
+			continue
+		}
+		if err := ef.eui.UnmarshalText([]byte(ef.hex)); err != nil {
+			log.Errorf("%s: %s", ef.name, err)
+			return exitUsage
+		}
+	}
+
+	if *devNonce >= 0 {
+		dn := lorawan.DevNonce(*devNonce)
+		keys.DevNonce = &dn
+	}
+	if *fCnt >= 0 {
+		fc := uint32(*fCnt)
+		keys.FCnt = &fc
+	}
+	keys.ConfFCnt = uint32(*confFCnt)
+	keys.TxDR = uint8(*txDR)
+	keys.TxCh = uint8(*txCh)
+
+	frame, err := lds.DecodeFrame(b, keys)
+	if err != nil {
+		log.Errorf("couldn't decode the frame: %s", err)
+		return exitFailure
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(frame); err != nil {
+			log.Errorln(err)
+			return exitFailure
+		}
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, f := range frame.Fields() {
+			fmt.Fprintf(w, "%s\t%s\n", f.Name, f.Value)
+		}
+		w.Flush()
+	}
+
+	if frame.MIC == lds.MICInvalid {
+		return exitFailure
+	}
+	return exitOK
+}
+
+func keysCommand(config *lds.Config, useUDP bool, args []string) int {
+	if len(args) == 0 || args[0] != "derive" {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: lds [options] keys derive [derive options]")
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("keys derive", flag.ContinueOnError)
+	device := fs.Bool("device", false, "derive the keys of the configured device, the other options override its keys and EUIs")
+	macVersion := fs.String("mac-version", "", "LoRaWAN version, 1.0 or 1.1 (default the device's with -device, else 1.0)")
+	optNeg := fs.String("opt-neg", "", "OptNeg bit of the join accept, true or false (default true for LoRaWAN 1.1)")
+	nwkKey := fs.String("nwk-key", "", "NwkKey, the AppKey of LoRaWAN 1.0 devices")
+	appKey := fs.String("app-key", "", "LoRaWAN 1.1 AppKey")
+	devEUI := fs.String("dev-eui", "", "DevEUI")
+	joinEUI := fs.String("join-eui", "", "JoinEUI")
+	netID := fs.String("net-id", "000000", "NetID of the join accept")
+	joinNonce := fs.Uint("join-nonce", 0, "JoinNonce of the join accept")
+	devNonce := fs.Uint("dev-nonce", 0, "DevNonce of the join request")
+	asJSON := fs.Bool("json", false, "print the keys as json")
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 || *joinNonce > 1<<24-1 || *devNonce > 1<<16-1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	var nk, ak lorawan.AES128Key
+	var dEUI, jEUI lorawan.EUI64
+	mv := lorawan.LoRaWAN1_0
+	if *device {
+		d, err := config.NewDevice()
+		if err != nil {
+			log.Errorf("configuration error: %s", err)
+			return exitConfig
+		}
+		nk, ak, dEUI, jEUI, mv = d.NwkKey, d.AppKey, d.DevEUI, d.JoinEUI, d.MACVersion
+	}
+
+	switch *macVersion {
+	case "":
+	case "1.0":
+		mv = lorawan.LoRaWAN1_0
+	case "1.1":
+		mv = lorawan.LoRaWAN1_1
+	default:
+		log.Errorf("unknown mac version %q", *macVersion)
+		return exitUsage
+	}
+
+	values := []struct {
+		name  string
+		text  string
+		value interface{ UnmarshalText([]byte) error }
+	}{
+		{"nwk-key", *nwkKey, &nk},
+		{"app-key", *appKey, &ak},
+		{"dev-eui", *devEUI, &dEUI},
+		{"join-eui", *joinEUI, &jEUI},
+	}
+	for _, v := range values {
+		if v.text == "" {
+			continue
+		}
+		if err := v.value.UnmarshalText([]byte(v.text)); err != nil {
+			log.Errorf("%s: %s", v.name, err)
+			return exitUsage
+		}
+	}
+
+	join := keys.Join{
+		MACVersion: mv,
+		OptNeg:     mv == lorawan.LoRaWAN1_1,
+		JoinEUI:    jEUI,
+		JoinNonce:  lorawan.JoinNonce(*joinNonce),
+		DevNonce:   lorawan.DevNonce(*devNonce),
+	}
+	if err := join.NetID.UnmarshalText([]byte(*netID)); err != nil {
+		log.Errorf("net-id: %s", err)
+		return exitUsage
+	}
+	if *optNeg != "" {
+		var err error
+		if join.OptNeg, err = strconv.ParseBool(*optNeg); err != nil {
+			log.Errorf("opt-neg: %s", err)
+			return exitUsage
+		}
+	}
+
+	sessionKeys, err := keys.GetSessionKeys(nk, ak, join)
+	if err != nil {
+		log.Errorf("couldn't derive the session keys: %s", err)
+		return exitFailure
+	}
+	jsIntKey, err := keys.GetJSIntKey(nk, dEUI)
+	if err != nil {
+		log.Errorf("couldn't derive the JSIntKey: %s", err)
+		return exitFailure
+	}
+	jsEncKey, err := keys.GetJSEncKey(nk, dEUI)
+	if err != nil {
+		log.Errorf("couldn't derive the JSEncKey: %s", err)
+		return exitFailure
+	}
+
+	if *asJSON {
+		out := struct {
+			keys.SessionKeys
+			JSIntKey lorawan.AES128Key `json:"jsIntKey"`
+			JSEncKey lorawan.AES128Key `json:"jsEncKey"`
+		}{sessionKeys, jsIntKey, jsEncKey}
+		if err := json.NewEncoder(os.Stdout).Encode(out); err != nil {
+			log.Errorln(err)
+			return exitFailure
+		}
+		return exitOK
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "FNwkSIntKey\t%s\n", sessionKeys.FNwkSIntKey)
+	fmt.Fprintf(w, "SNwkSIntKey\t%s\n", sessionKeys.SNwkSIntKey)
+	fmt.Fprintf(w, "NwkSEncKey\t%s\n", sessionKeys.NwkSEncKey)
+	fmt.Fprintf(w, "AppSKey\t%s\n", sessionKeys.AppSKey)
+	fmt.Fprintf(w, "JSIntKey\t%s\n", jsIntKey)
+	fmt.Fprintf(w, "JSEncKey\t%s\n", jsEncKey)
+	w.Flush()
+	return exitOK
+}
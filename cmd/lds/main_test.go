@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/brocaar/lorawan"
+
+	"github.com/iegomez/lds/lds"
+)
+
+func TestUplinkParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		mType   lorawan.MType
+		fPort   uint8
+		payload []byte
+		err     bool
+	}{
+		{"configured", nil, lorawan.UnconfirmedDataUp, 10, []byte{1, 2, 3}, false},
+		{"options", []string{"-payload", "0a0b", "-fport", "2", "-confirmed"}, lorawan.ConfirmedDataUp, 2, []byte{10, 11}, false},
+		{"invalid payload", []string{"-payload", "0g"}, lorawan.UnconfirmedDataUp, 0, nil, true},
+		{"fPort 0", []string{"-fport", "0"}, 0, 0, nil, true},
+		{"fPort over 223", []string{"-payload", "0a", "-fport", "256"}, 0, 0, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := lds.NewConfig()
+			config.RawPayload.UseRaw = true
+			config.RawPayload.Payload = "010203"
+			config.RawPayload.FPort = 10
+
+			fs := flag.NewFlagSet("uplink", flag.ContinueOnError)
+			uf := addUplinkFlags(fs, config)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			mType, fPort, payload, err := uf.uplinkParams(config)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if mType != tt.mType || fPort != tt.fPort || !bytes.Equal(payload, tt.payload) {
+				t.Errorf("got %s on fPort %d with payload %x, expected %s on %d with %x", mType, fPort, payload, tt.mType, tt.fPort, tt.payload)
+			}
+		})
+	}
+}
+
+// TestCommandExitCodes checks the exit codes of commands failing before they connect.
+func TestCommandExitCodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		command func(config *lds.Config, useUDP bool, args []string) int
+		profile string
+		args    []string
+		code    int
+	}{
+		{"join of an ABP device", joinCommand, "ABP", nil, exitConfig},
+		{"unknown join option", joinCommand, "OTAA", []string{"-wait"}, exitUsage},
+		{"unknown uplink option", uplinkCommand, "ABP", []string{"-port", "1"}, exitUsage},
+		{"scenario without files", scenarioCommand, "ABP", nil, exitUsage},
+		{"unknown report format", scenarioCommand, "ABP", []string{"-report", "html", "scenario.toml"}, exitUsage},
+		{"missing scenario", scenarioCommand, "ABP", []string{"missing.toml"}, exitConfig},
+		{"uplink fPort out of range", uplinkCommand, "ABP", []string{"-fport", "256"}, exitUsage},
+		{"run fPort out of range", runCommand, "ABP", []string{"-fport", "0"}, exitUsage},
+		{"unreachable Redis", uplinkCommand, "ABP", []string{"-fport", "1"}, exitConnection},
+		{"decode without frame", decodeCommand, "ABP", nil, exitUsage},
+		{"invalid frame", decodeCommand, "ABP", []string{"0x4g!"}, exitUsage},
+		{"decode with unknown mac version", decodeCommand, "ABP", []string{"-mac-version", "1.2", "40"}, exitUsage},
+		{"keys without derive", keysCommand, "OTAA", nil, exitUsage},
+		{"unknown mac version", keysCommand, "OTAA", []string{"derive", "-mac-version", "1.2"}, exitUsage},
+		{"invalid key", keysCommand, "OTAA", []string{"derive", "-nwk-key", "0102"}, exitUsage},
+		{"invalid OptNeg", keysCommand, "OTAA", []string{"derive", "-opt-neg", "maybe"}, exitUsage},
+		{"JoinNonce over 24 bits", keysCommand, "OTAA", []string{"derive", "-join-nonce", "16777216"}, exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := lds.NewConfig()
+			config.Device.Profile = tt.profile
+			config.RedisConf.Addr = "127.0.0.1:1"
+			if code := tt.command(config, false, tt.args); code != tt.code {
+				t.Errorf("got exit code %d, expected %d", code, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/iegomez/lds/lds"
+)
+
+func serveCommand(config *lds.Config, useUDP bool, args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	bind := fs.String("bind", config.API.Bind, "HTTP API bind address")
+	grpcBind := ""
+	if config.GRPC.Enabled {
+		grpcBind = config.GRPC.Bind
+	}
+	fs.StringVar(&grpcBind, "grpc", grpcBind, "gRPC API bind address, the gRPC API is off when empty")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	s, code := connect(config, useUDP)
+	if code != exitOK {
+		return code
+	}
+	defer s.close()
+
+	api, err := lds.NewAPIServer(*bind, s, config)
+	if err != nil {
+		log.Errorln(err)
+		return exitConnection
+	}
+	defer api.Close()
+
+	if grpcBind != "" {
+		grpcAPI, err := lds.NewGRPCServer(grpcBind, s, config)
+		if err != nil {
+			log.Errorln(err)
+			return exitConnection
+		}
+		defer grpcAPI.Close()
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	<-interrupt
+	log.Infoln("interrupted")
+
+	s.Stop()
+	return exitOK
+}
+
+// DeviceConfig returns the device configuration.
+func (s *simulator) DeviceConfig() (lds.DeviceConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.device == nil {
+		return lds.DeviceConfig{}, lds.ErrNoDevice
+	}
+	return s.config.Device, nil
+}
+
+// PutDeviceConfig creates or replaces the device, restoring its session from Redis.
+func (s *simulator) PutDeviceConfig(dc lds.DeviceConfig, create bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if create && s.device != nil {
+		return lds.ErrDeviceExists
+	}
+
+	//The message type isn't part of the device json, keep the configured one.
+	dc.Major = s.config.Device.Major
+	dc.MType = s.config.Device.MType
+	if dc.Marshaler == "" {
+		dc.Marshaler = s.config.Device.Marshaler
+	}
+
+	config := *s.config
+	config.Device = dc
+	device, err := config.NewDevice()
+	if err != nil {
+		return err
+	}
+	device.GetInfo()
+
+	s.config.Device = dc
+	s.setDevice(device)
+	log.Infof("device %s set", dc.DevEUI)
+	return nil
+}
+
+// DeleteDevice resets the device session and removes the device.
+func (s *simulator) DeleteDevice() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.device == nil {
+		return lds.ErrNoDevice
+	}
+	if err := s.device.Reset(); err != nil {
+		return err
+	}
+	s.setDevice(nil)
+	log.Warningln("Device was deleted")
+	return nil
+}
+
+// SetValues sets the frame counters and nonces.
+func (s *simulator) SetValues(ulFcnt, dlFcnt, devNonce, joinNonce int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.device == nil {
+		return lds.ErrNoDevice
+	}
+	return s.device.SetValues(ulFcnt, dlFcnt, devNonce, joinNonce)
+}
+
+// Start sends the configured data every interval until Stop is called.
+func (s *simulator) Start(interval time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.device == nil {
+		return lds.ErrNoDevice
+	}
+	if s.stopRun != nil {
+		return errors.New("already running")
+	}
+
+	stop := make(chan struct{})
+	s.stopRun = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			//The API changes the configuration under the lock.
+			s.mu.Lock()
+			payload, err := s.config.Payload()
+			mType, fPort := s.config.Device.MType, s.config.RawPayload.FPort
+			s.mu.Unlock()
+			if err == nil {
+				err = lds.CheckFPort(fPort)
+			}
+			if err == nil {
+				var fCnt uint32
+				fCnt, err = s.Uplink(mType, uint8(fPort), payload, nil, lorawan.FCtrl{})
+				if err == nil {
+					log.Infof("message sent, uplink framecounter is now %d", fCnt)
+				}
+			}
+			if err != nil {
+				log.Errorf("couldn't send uplink: %s", err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the periodic uplinks.
+func (s *simulator) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopRun != nil {
+		close(s.stopRun)
+		s.stopRun = nil
+	}
+	return nil
+}
+
+// EnqueueDownlink queues a downlink on the embedded network server.
+func (s *simulator) EnqueueDownlink(dl *lds.NSDownlink) (int, error) {
+	if s.ns == nil {
+		return 0, lds.ErrNoNetworkServer
+	}
+	return s.ns.Enqueue(dl), nil
+}
+
+// UplinkRadio returns how the gateway receives the uplinks.
+func (s *simulator) UplinkRadio() lds.UplinkRadio {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return lds.UplinkRadio{Band: s.config.Band.Name, DR: s.config.DR, RXInfo: s.config.RXInfo}
+}
+
+// SetUplinkRadio sets how the gateway receives the next uplinks.
+func (s *simulator) SetUplinkRadio(r lds.UplinkRadio) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := lds.GetGateway(s.config.GW.MAC).SetBand(r.Band); err != nil {
+		return err
+	}
+	s.config.Band.Name = r.Band
+	s.config.DR = r.DR
+	s.config.RXInfo = r.RXInfo
+	return nil
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/iegomez/lds/lds"
+)
+
+// statsInterval is the default stats interval of the packet forwarder.
+const statsInterval = 30 * time.Second
+
+// simulator is a device behind the simulated gateway, connected to the network server through MQTT or the packet forwarder UDP protocol.
+type simulator struct {
+	config *lds.Config
+	useUDP bool
+
+	mu sync.Mutex
+	//device is nil when it was deleted through the API.
+	device *lds.Device
+	//handlers get the events of the current device, even when it's replaced.
+	handlers []*func(*lds.Event)
+	//stopRun stops the periodic uplinks started through the API, it's nil when they aren't running.
+	stopRun chan struct{}
+	//stopStats stops the stats publishing, it's nil when no stats topic is set.
+	stopStats chan struct{}
+
+	mqttClient paho.Client
+	nsClient   lds.NSClient
+	broker     *lds.Broker
+	ns         *lds.NetworkServer
+	journal    *lds.Journal
+
+	//downlinks receives the processed downlink messages.
+	downlinks chan string
+}
+
+// newSimulator builds the device from the configuration and restores its session from Redis.
+func newSimulator(config *lds.Config, useUDP bool) (*simulator, error) {
+	device, err := config.NewDevice()
+	if err != nil {
+		return nil, err
+	}
+	device.GetInfo()
+
+	if config.Channel.Enabled {
+		lds.SetChannelSimulator(config.Channel.CaptureThreshold, config.Channel.Demodulators)
+	}
+	if err := lds.GetGateway(config.GW.MAC).SetBand(config.Band.Name); err != nil {
+		return nil, errors.Wrap(err, "band error")
+	}
+	lds.GetGateway(config.GW.MAC).SetAckPolicy(config.AckPolicy())
+
+	s := &simulator{
+		config:    config,
+		useUDP:    useUDP,
+		downlinks: make(chan string, 16),
+	}
+	if config.Journal.Enabled {
+		journal, err := config.Journal.Open()
+		if err != nil {
+			return nil, err
+		}
+		s.journal = journal
+		record := journal.Record
+		s.handlers = append(s.handlers, &record)
+	}
+	s.setDevice(device)
+	return s, nil
+}
+
+// setDevice replaces the device, which must be called with the lock held once the simulator runs.
+func (s *simulator) setDevice(device *lds.Device) {
+	if device != nil {
+		device.AddEventHandler(s.dispatchEvent)
+	}
+	s.device = device
+}
+
+// dispatchEvent is called by the device with the lock held.
+func (s *simulator) dispatchEvent(e *lds.Event) {
+	for _, h := range s.handlers {
+		(*h)(e)
+	}
+}
+
+// connect connects to the network server with the selected transport.
+func (s *simulator) connect() error {
+	if s.useUDP {
+		port, err := strconv.Atoi(s.config.Forwarder.Port)
+		if err != nil {
+			return errors.Wrap(err, "network server UDP port must be a number")
+		}
+		if err := s.startNetworkServer(); err != nil {
+			return err
+		}
+		s.nsClient.Server = s.config.Forwarder.Server
+		s.nsClient.Port = port
+		return s.nsClient.Connect(s.config.GW.MAC, func(payload []byte) error {
+			return s.onDownlink(payload, false)
+		})
+	}
+
+	if s.config.Broker.Enabled {
+		b, err := lds.NewBroker(s.config.Broker.Bind)
+		if err != nil {
+			return errors.Wrap(err, "couldn't start the embedded broker")
+		}
+		s.broker = b
+	}
+	if err := s.startNetworkServer(); err != nil {
+		return err
+	}
+
+	if err := lds.SetQoS(s.config.MQTT.QoS); err != nil {
+		return err
+	}
+
+	opts, err := s.config.MQTTClientOptions()
+	if err != nil {
+		return err
+	}
+
+	s.mqttClient = paho.NewClient(opts)
+	if token := s.mqttClient.Connect(); token.Wait() && token.Error() != nil {
+		return token.Error()
+	}
+	log.Infoln("connection established")
+
+	topics := s.config.Topics()
+	qos := byte(s.config.MQTT.QoS)
+	if token := s.mqttClient.Subscribe(lds.FormatTopic(topics.Downlink, s.config.GW.MAC), qos, func(c paho.Client, msg paho.Message) {
+		s.onDownlink(msg.Payload(), true)
+	}); token.Wait() && token.Error() != nil {
+		return errors.Wrap(token.Error(), "couldn't subscribe to downlinks")
+	}
+	if topics.Config != "" {
+		s.mqttClient.Subscribe(lds.FormatTopic(topics.Config, s.config.GW.MAC), qos, func(c paho.Client, msg paho.Message) {
+			if err := s.config.MarshalingDevice().ApplyGatewayConfig(msg.Payload(), s.config.GW.MAC); err != nil {
+				log.Errorf("couldn't apply gateway configuration: %s", err)
+			}
+		})
+	}
+	if topics.State != "" {
+		if err := s.config.MarshalingDevice().PublishConnState(s.mqttClient, topics.State, s.config.GW.MAC, true); err != nil {
+			log.Errorf("couldn't publish the online state: %s", err)
+		}
+	}
+	if topics.Stats != "" {
+		s.stopStats = make(chan struct{})
+		go s.publishStats(topics.Stats, s.stopStats)
+	}
+	return nil
+}
+
+// publishStats publishes the gateway stats every statsInterval until stop is closed, skipping the ticks while the client is reconnecting.
+func (s *simulator) publishStats(topic string, stop <-chan struct{}) {
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+
+		if !s.mqttClient.IsConnected() {
+			continue
+		}
+		if err := s.config.MarshalingDevice().PublishStats(s.mqttClient, topic, s.config.GW.MAC); err != nil {
+			log.Errorf("couldn't publish stats: %s", err)
+		}
+	}
+}
+
+// startNetworkServer starts the embedded network server when enabled, before the gateway connects to it.
+func (s *simulator) startNetworkServer() error {
+	if !s.config.NetworkServer.Enabled {
+		return nil
+	}
+	ns, err := lds.NewNetworkServer(s.config)
+	if err != nil {
+		return errors.Wrap(err, "couldn't start the network server")
+	}
+	s.ns = ns
+	return nil
+}
+
+// close publishes the OFFLINE state, disconnects and closes the journal. The UDP client has nothing to close as it stops with the program.
+func (s *simulator) close() {
+	if s.stopStats != nil {
+		close(s.stopStats)
+	}
+	if s.mqttClient != nil && s.mqttClient.IsConnected() {
+		if state := s.config.Topics().State; state != "" {
+			if err := s.config.MarshalingDevice().PublishConnState(s.mqttClient, state, s.config.GW.MAC, false); err != nil {
+				log.Errorf("couldn't publish the offline state: %s", err)
+			}
+		}
+		s.mqttClient.Disconnect(200)
+	}
+	if s.ns != nil {
+		s.ns.Close()
+	}
+	if s.broker != nil {
+		s.broker.Close()
+	}
+	if s.journal != nil {
+		s.journal.Close()
+	}
+}
+
+// Join sends a join request.
+func (s *simulator) Join() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.device == nil {
+		return lds.ErrNoDevice
+	}
+
+	urx, utx, err := s.config.UplinkInfo()
+	if err != nil {
+		return err
+	}
+
+	//A join request starts a new session, so the join accept must be processed as such.
+	s.device.Joined = false
+	if s.useUDP {
+		return s.device.JoinUDP(s.nsClient, s.config.GW.MAC, urx, utx)
+	}
+	return s.device.Join(s.mqttClient, s.config.Topics().Uplink, s.config.GW.MAC, urx, utx)
+}
+
+// Uplink sends the payload with the given MAC commands and FCtrl, and returns the uplink frame counter.
+func (s *simulator) Uplink(mType lorawan.MType, fPort uint8, payload []byte, macCommands []*lorawan.MACCommand, fCtrl lorawan.FCtrl) (uint32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.device == nil {
+		return 0, lds.ErrNoDevice
+	}
+
+	urx, utx, err := s.config.UplinkInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	if s.useUDP {
+		return s.device.UplinkUDP(s.nsClient, mType, fPort, urx, utx, payload, s.config.GW.MAC, s.config.Band.Name, s.config.DataRate(), macCommands, fCtrl)
+	}
+	return s.device.Uplink(s.mqttClient, s.config.Topics().Uplink, mType, fPort, urx, utx, payload, s.config.GW.MAC, s.config.Band.Name, s.config.DataRate(), macCommands, fCtrl)
+}
+
+// Reset deletes the device session, counters and nonces.
+func (s *simulator) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.device == nil {
+		return lds.ErrNoDevice
+	}
+	return s.device.Reset()
+}
+
+// State returns a copy of the device, a zero one when there's no device.
+func (s *simulator) State() lds.Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.device == nil {
+		return lds.Device{}
+	}
+	return *s.device
+}
+
+// AddDownlinkHandler adds a function called with the downlinks processed by the device, and returns the function removing it.
+func (s *simulator) AddDownlinkHandler(h func(*lds.Downlink)) func() {
+	return s.AddEventHandler(lds.DownlinkHandler(h))
+}
+
+// AddEventHandler adds a function called with the device events, and returns the function removing it.
+func (s *simulator) AddEventHandler(h func(*lds.Event)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := &h
+	s.handlers = append(s.handlers, p)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, q := range s.handlers {
+			if q == p {
+				s.handlers = append(s.handlers[:i:i], s.handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// onDownlink processes a downlink and publishes its ack when there's an ack topic.
+func (s *simulator) onDownlink(payload []byte, mqtt bool) error {
+	s.mu.Lock()
+	if s.device == nil {
+		s.mu.Unlock()
+		return lds.ErrNoDevice
+	}
+	message, err := s.device.ProcessDownlink(payload, s.device.MACVersion, mqtt)
+	if mqtt && s.config.Topics().Ack != "" {
+		s.device.PublishAck(s.mqttClient, s.config.Topics().Ack, s.config.GW.MAC)
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		log.Errorf("downlink error: %s", err)
+		return err
+	}
+	log.Infof("received message: %s", message)
+
+	select {
+	case s.downlinks <- message:
+	default:
+	}
+	return nil
+}
+
+// joined tells if the device has a session, either because it's ABP or because it joined.
+func (s *simulator) joined() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.device != nil && (s.device.Profile == "ABP" || s.device.Joined)
+}
+
+// waitJoin waits for the join accept until the timeout expires.
+func (s *simulator) waitJoin(timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for !s.joined() {
+		select {
+		case <-s.downlinks:
+		case <-deadline:
+			return false
+		}
+	}
+	return true
+}
+
+// drain logs the downlinks received during the given duration and returns how many there were.
+func (s *simulator) drain(d time.Duration) int {
+	n := 0
+	deadline := time.After(d)
+	for {
+		select {
+		case <-s.downlinks:
+			n++
+		case <-deadline:
+			return n
+		}
+	}
+}
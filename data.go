@@ -1,53 +1,20 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"reflect"
 	"strconv"
-	"time"
 
 	l "gioui.org/layout"
 	"gioui.org/unit"
 	"gioui.org/widget"
 	"gioui.org/widget/material"
-	"github.com/pkg/errors"
-	"github.com/robertkrimen/otto"
+	"github.com/iegomez/lds/lds"
 	"github.com/scartill/giox"
 	xmat "github.com/scartill/giox/material"
 	log "github.com/sirupsen/logrus"
 )
 
-type encodedType struct {
-	Name     string  `toml:"name"`
-	Value    float64 `toml:"value"`
-	MaxValue float64 `toml:"max_value"`
-	MinValue float64 `toml:"min_value"`
-	IsFloat  bool    `toml:"is_float"`
-	NumBytes int     `toml:"num_bytes"`
-}
-
-//rawPayload holds optional raw bytes payload (hex encoded).
-type rawPayload struct {
-	Payload     string `toml:"payload"`
-	UseRaw      bool   `toml:"use_raw"`
-	Script      string `toml:"script"`
-	UseEncoder  bool   `toml:"use_encoder"`
-	MaxExecTime int    `toml:"max_exec_time"`
-	Obj         string `toml:"js_object"`
-	FPort       int    `toml:"fport"`
-}
-
 var openScript bool
-var defaultScript = `
-// Encode encodes the given object into an array of bytes.
-//  - fPort contains the LoRaWAN fPort number
-//  - obj is an object, e.g. {"temperature": 22.5}
-// The function must return an array of bytes, e.g. [225, 230, 255, 0]
-function Encode(fPort, obj) {
-	return [];
-}
-`
 
 type encodedTypeWidgets struct {
 	Name         widget.Editor
@@ -149,7 +116,7 @@ func dataForm(th *material.Theme) l.FlexChild {
 	}
 
 	for addEncodedType.Clicked() {
-		et := &encodedType{
+		et := &lds.EncodedType{
 			Name:     "New type",
 			Value:    0,
 			MaxValue: 0,
@@ -163,17 +130,17 @@ func dataForm(th *material.Theme) l.FlexChild {
 	for i := 0; i < len(config.EncodedType); i++ {
 		for encodedWidgets[i].DeleteButton.Clicked() {
 			if len(config.EncodedType) == 1 {
-				config.EncodedType = make([]*encodedType, 0)
+				config.EncodedType = make([]*lds.EncodedType, 0)
 			} else {
 				copy(config.EncodedType[i:], config.EncodedType[i+1:])
-				config.EncodedType[len(config.EncodedType)-1] = &encodedType{}
+				config.EncodedType[len(config.EncodedType)-1] = &lds.EncodedType{}
 				config.EncodedType = config.EncodedType[:len(config.EncodedType)-1]
 			}
 		}
 	}
 
 	for clearScriptEditor.Clicked() {
-		config.RawPayload.Script = defaultScript
+		config.RawPayload.Script = lds.DefaultScript
 		funcEditor.SetText(config.RawPayload.Script)
 	}
 
@@ -253,118 +220,3 @@ func dataForm(th *material.Theme) l.FlexChild {
 		})
 	})
 }
-
-// EncodeToBytes encodes the payload to a slice of bytes.
-// Taken from github.com/brocaar/lora-app-server.
-func EncodeToBytes() (b []byte, err error) {
-	defer func() {
-		if caught := recover(); caught != nil {
-			err = fmt.Errorf("%s", caught)
-		}
-	}()
-
-	script := config.RawPayload.Script + "\n\nEncode(fPort, obj);\n"
-
-	vm := otto.New()
-	vm.Interrupt = make(chan func(), 1)
-	vm.SetStackDepthLimit(32)
-	var jsonData interface{}
-	err = json.Unmarshal([]byte(config.RawPayload.Obj), &jsonData)
-	if err != nil {
-		log.Errorf("couldn't unmarshal object: %s", err)
-		return nil, err
-	}
-	log.Debugf("JS object: %v", jsonData)
-	vm.Set("obj", jsonData)
-	vm.Set("fPort", config.RawPayload.FPort)
-
-	go func() {
-		time.Sleep(time.Duration(config.RawPayload.MaxExecTime) * time.Millisecond)
-		vm.Interrupt <- func() {
-			panic(errors.New("execution timeout"))
-		}
-	}()
-
-	var val otto.Value
-	val, err = vm.Run(script)
-	if err != nil {
-		return nil, errors.Wrap(err, "js vm error")
-	}
-	if !val.IsObject() {
-		return nil, errors.New("function must return an array")
-	}
-
-	var out interface{}
-	out, err = val.Export()
-	if err != nil {
-		return nil, errors.Wrap(err, "export error")
-	}
-
-	return interfaceToByteSlice(out)
-}
-
-// Taken from github.com/brocaar/lora-app-server.
-func interfaceToByteSlice(obj interface{}) ([]byte, error) {
-	if obj == nil {
-		return nil, errors.New("value must not be nil")
-	}
-
-	if reflect.TypeOf(obj).Kind() != reflect.Slice {
-		return nil, errors.New("value must be an array")
-	}
-
-	s := reflect.ValueOf(obj)
-	l := s.Len()
-
-	var out []byte
-	for i := 0; i < l; i++ {
-		var b int64
-
-		el := s.Index(i).Interface()
-		switch v := el.(type) {
-		case int:
-			b = int64(v)
-		case uint:
-			b = int64(v)
-		case uint8:
-			b = int64(v)
-		case int8:
-			b = int64(v)
-		case uint16:
-			b = int64(v)
-		case int16:
-			b = int64(v)
-		case uint32:
-			b = int64(v)
-		case int32:
-			b = int64(v)
-		case uint64:
-			b = int64(v)
-			if uint64(b) != v {
-				return nil, fmt.Errorf("array value must be in byte range (0 - 255), got: %d", v)
-			}
-		case int64:
-			b = int64(v)
-		case float32:
-			b = int64(v)
-			if float32(b) != v {
-				return nil, fmt.Errorf("array value must be in byte range (0 - 255), got: %f", v)
-			}
-		case float64:
-			b = int64(v)
-			if float64(b) != v {
-				return nil, fmt.Errorf("array value must be in byte range (0 - 255), got: %f", v)
-			}
-		default:
-			return nil, fmt.Errorf("array value must be an array of ints or floats, got: %T", el)
-		}
-
-		if b < 0 || b > 255 {
-			return nil, fmt.Errorf("array value must be in byte range (0 - 255), got: %d", b)
-		}
-
-		out = append(out, byte(b))
-	}
-
-	return out, nil
-}
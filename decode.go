@@ -0,0 +1,173 @@
+package main
+
+import (
+	"strconv"
+
+	l "gioui.org/layout"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/brocaar/lorawan"
+	xmat "github.com/scartill/giox/material"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/iegomez/lds/lds"
+)
+
+var openDecode bool
+
+// decodeFields are the fields of the last decoded frame.
+var decodeFields []lds.DecodedField
+
+// Widgets
+var (
+	decodeFrameEdit       widget.Editor
+	decodeDeviceChk       widget.Bool
+	decodeMACVersion11Chk widget.Bool
+	decodeNwkSEncKeyEdit  widget.Editor
+	decodeSNwkSIntKeyEdit widget.Editor
+	decodeFNwkSIntKeyEdit widget.Editor
+	decodeAppSKeyEdit     widget.Editor
+	decodeNwkKeyEdit      widget.Editor
+	decodeAppKeyEdit      widget.Editor
+	decodeDevNonceEdit    widget.Editor
+	decodeBtn             widget.Clickable
+	decodeCancelBtn       widget.Clickable
+)
+
+func decodeKeys() (*lds.DecodeKeys, error) {
+	if decodeDeviceChk.Value {
+		if cDevice != nil {
+			return cDevice.DecodeKeys(), nil
+		}
+		d, err := config.NewDevice()
+		if err != nil {
+			return nil, err
+		}
+		return d.DecodeKeys(), nil
+	}
+
+	keys := &lds.DecodeKeys{MACVersion: lorawan.LoRaWAN1_0}
+	if decodeMACVersion11Chk.Value {
+		keys.MACVersion = lorawan.LoRaWAN1_1
+	}
+
+	type keyEditor struct {
+		editor *widget.Editor
+		keys   []**lorawan.AES128Key
+	}
+	//The LoRaWAN 1.0 NwkSKey is the three network session keys.
+	editors := []keyEditor{
+		{&decodeNwkSEncKeyEdit, []**lorawan.AES128Key{&keys.NwkSEncKey, &keys.SNwkSIntKey, &keys.FNwkSIntKey}},
+	}
+	if decodeMACVersion11Chk.Value {
+		editors = []keyEditor{
+			{&decodeNwkSEncKeyEdit, []**lorawan.AES128Key{&keys.NwkSEncKey}},
+			{&decodeSNwkSIntKeyEdit, []**lorawan.AES128Key{&keys.SNwkSIntKey}},
+			{&decodeFNwkSIntKeyEdit, []**lorawan.AES128Key{&keys.FNwkSIntKey}},
+		}
+	}
+	editors = append(editors,
+		keyEditor{&decodeAppSKeyEdit, []**lorawan.AES128Key{&keys.AppSKey}},
+		keyEditor{&decodeNwkKeyEdit, []**lorawan.AES128Key{&keys.NwkKey}},
+		keyEditor{&decodeAppKeyEdit, []**lorawan.AES128Key{&keys.AppKey}},
+	)
+	for _, e := range editors {
+		if e.editor.Text() == "" {
+			continue
+		}
+		key, err := lds.HexToKey(e.editor.Text())
+		if err != nil {
+			return nil, err
+		}
+		k := lorawan.AES128Key(key)
+		for _, dst := range e.keys {
+			*dst = &k
+		}
+	}
+
+	if decodeDevNonceEdit.Text() != "" {
+		dn, err := strconv.ParseUint(decodeDevNonceEdit.Text(), 10, 16)
+		if err != nil {
+			return nil, err
+		}
+		devNonce := lorawan.DevNonce(dn)
+		keys.DevNonce = &devNonce
+	}
+	keys.DevEUI, _ = lds.HexToEUI(config.Device.DevEUI)
+	keys.JoinEUI, _ = lds.HexToEUI(config.Device.JoinEUI)
+
+	return keys, nil
+}
+
+func decodeFrame() {
+	decodeFields = nil
+
+	b, err := lds.ParseFrame(decodeFrameEdit.Text())
+	if err != nil {
+		log.Errorln(err)
+		return
+	}
+	keys, err := decodeKeys()
+	if err != nil {
+		log.Errorf("key error: %s", err)
+		return
+	}
+	frame, err := lds.DecodeFrame(b, keys)
+	if err != nil {
+		log.Errorf("couldn't decode the frame: %s", err)
+		return
+	}
+
+	decodeFields = frame.Fields()
+	for _, f := range decodeFields {
+		log.Infof("decode: %s: %s", f.Name, f.Value)
+	}
+}
+
+func buildDecode(th *material.Theme) (l.FlexChild, bool) {
+
+	for decodeBtn.Clicked() {
+		decodeFrame()
+	}
+
+	for decodeCancelBtn.Clicked() {
+		openDecode = false
+	}
+
+	children := []l.FlexChild{
+		xmat.RigidSection(th, "Decode frame"),
+		xmat.RigidEditor(th, "Frame:", "<hex or base64>", &decodeFrameEdit),
+		xmat.RigidCheckBox(th, "Device keys", &decodeDeviceChk),
+	}
+	if !decodeDeviceChk.Value {
+		children = append(children, xmat.RigidCheckBox(th, "LoRaWAN 1.1", &decodeMACVersion11Chk))
+		if decodeMACVersion11Chk.Value {
+			children = append(children,
+				xmat.RigidEditor(th, "NwkSEncKey:", "<optional>", &decodeNwkSEncKeyEdit),
+				xmat.RigidEditor(th, "SNwkSIntKey:", "<optional>", &decodeSNwkSIntKeyEdit),
+				xmat.RigidEditor(th, "FNwkSIntKey:", "<optional>", &decodeFNwkSIntKeyEdit),
+			)
+		} else {
+			children = append(children, xmat.RigidEditor(th, "NwkSKey:", "<optional>", &decodeNwkSEncKeyEdit))
+		}
+		children = append(children,
+			xmat.RigidEditor(th, "AppSKey:", "<optional>", &decodeAppSKeyEdit),
+			xmat.RigidEditor(th, "NwkKey:", "<optional>", &decodeNwkKeyEdit),
+			xmat.RigidEditor(th, "AppKey:", "<optional>", &decodeAppKeyEdit),
+			xmat.RigidEditor(th, "DevNonce:", "<optional>", &decodeDevNonceEdit),
+		)
+	}
+	children = append(children,
+		xmat.RigidButton(th, "Decode", &decodeBtn),
+		xmat.RigidButton(th, "Cancel", &decodeCancelBtn),
+	)
+	for _, f := range decodeFields {
+		children = append(children, xmat.RigidLabel(th, f.Name+": "+f.Value))
+	}
+
+	widgets := l.Rigid(func(gtx l.Context) l.Dimensions {
+		return l.Flex{Axis: l.Vertical}.Layout(gtx, children...)
+	})
+
+	return widgets, true
+}
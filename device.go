@@ -1,15 +1,12 @@
 package main
 
 import (
-	"encoding/hex"
 	"fmt"
 	"strconv"
 	"time"
 
-	"github.com/brocaar/chirpstack-api/go/gw"
 	"github.com/brocaar/lorawan"
-	lwBand "github.com/brocaar/lorawan/band"
-	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/iegomez/lds/lds"
@@ -25,7 +22,7 @@ import (
 
 // Marshalers, versions, and message types.
 var (
-	marshalers    = []string{"json", "protobuf", "v2_json"}
+	marshalers    = []string{"json", "protobuf", "v2_json", "v4_json", "v4_protobuf"}
 	majorVersions = map[lorawan.Major]string{0: "LoRaWANRev1"}
 	macVersions   = map[lorawan.MACVersion]string{0: "LoRaWAN 1.0", 1: "LoRaWAN 1.1"}
 	mTypes        = map[lorawan.MType]string{lorawan.UnconfirmedDataUp: "UnconfirmedDataUp", lorawan.ConfirmedDataUp: "ConfirmedDataUp"}
@@ -36,25 +33,6 @@ var (
 	cDevice *lds.Device
 )
 
-type device struct {
-	DevEUI        string             `toml:"eui"`
-	DevAddress    string             `toml:"address"`
-	NwkSEncKey    string             `toml:"network_session_encription_key"`
-	SNwkSIntKey   string             `toml:"serving_network_session_integrity_key"`    //For Lorawan 1.0 this is the same as the NwkSEncKey
-	FNwkSIntKey   string             `toml:"forwarding_network_session_integrity_key"` //For Lorawan 1.0 this is the same as the NwkSEncKey
-	AppSKey       string             `toml:"application_session_key"`
-	Marshaler     string             `toml:"marshaler"`
-	NwkKey        string             `toml:"nwk_key"`  //Network key, used to be called application key for Lorawan 1.0
-	AppKey        string             `toml:"app_key"`  //Application key, for Lorawan 1.1
-	JoinEUI       string             `toml:"join_eui"` //JoinEUI for 1.1. (AppEUI on 1.0)
-	Major         lorawan.Major      `toml:"-"`
-	MACVersion    lorawan.MACVersion `toml:"mac_version"` //Lorawan MAC version
-	MType         lorawan.MType      `toml:"-"`
-	Profile       string             `toml:"profile"`
-	Joined        bool               `toml:"joined"`
-	SkipFCntCheck bool               `toml:"skip_fcnt_check"`
-}
-
 // Widgets
 var (
 	deviceEUIEdit      widget.Editor
@@ -407,7 +385,7 @@ func setDevice() {
 		cDevice.MACVersion = lorawan.MACVersion(config.Device.MACVersion)
 		cDevice.SkipFCntCheck = config.Device.SkipFCntCheck
 	}
-	cDevice.SetMarshaler(config.Device.Marshaler)
+	cDevice.SetMarshaler(config.Marshaler())
 }
 
 func resetDeviceSubform(th *material.Theme) (bool, l.FlexChild) {
@@ -498,69 +476,46 @@ func setRedisValuesSubform(th *material.Theme) (bool, layout.FlexChild) {
 }
 
 func join() {
+	if err := sendJoin(); err != nil {
+		log.Errorf("join error: %s", err)
+	} else {
+		log.Println("join sent")
+	}
+}
 
+// checkConnected fails when neither the MQTT nor the UDP client is connected.
+func checkConnected() error {
 	if !cNSClient.IsConnected() {
 		if mqttClient == nil || !mqttClient.IsConnected() {
-			log.Errorln("Neither client is connected")
-			return
+			return errors.New("neither client is connected")
 		}
 	}
+	return nil
+}
 
-	//Always set device to get any changes to the configuration.
-	setDevice()
-
-	gwID, err := lds.MACToGatewayID(config.GW.MAC)
-	if err != nil {
-		log.Errorf("gw mac error: %s", err)
-		return
-	}
-
-	now := time.Now()
-	rxTime := ptypes.TimestampNow()
-	tsge := ptypes.DurationProto(now.Sub(time.Time{}))
-
-	urx := gw.UplinkRXInfo{
-		GatewayId:         gwID,
-		Rssi:              int32(config.RXInfo.Rssi),
-		LoraSnr:           float64(config.RXInfo.LoRaSNR),
-		Channel:           uint32(config.RXInfo.Channel),
-		RfChain:           uint32(config.RXInfo.RfChain),
-		TimeSinceGpsEpoch: tsge,
-		Time:              rxTime,
-		Board:             0,
-		Antenna:           0,
-		Location:          nil,
-		FineTimestamp:     nil,
-		FineTimestampType: gw.FineTimestampType_NONE,
-		Context:           make([]byte, 4),
-	}
+// sendJoin sends a join request through the connected client.
+func sendJoin() error {
 
-	lmi := &gw.LoRaModulationInfo{
-		Bandwidth:       uint32(config.DR.Bandwidth),
-		SpreadingFactor: uint32(config.DR.SpreadFactor),
-		CodeRate:        config.RXInfo.CodeRate,
+	if err := checkConnected(); err != nil {
+		return err
 	}
 
-	umi := &gw.UplinkTXInfo_LoraModulationInfo{
-		LoraModulationInfo: lmi,
-	}
+	//Always set device to get any changes to the configuration.
+	setDevice()
+	setChannelSimulator()
+	lds.GetGateway(config.GW.MAC).SetBand(config.Band.Name)
 
-	utx := gw.UplinkTXInfo{
-		Frequency:      uint32(config.RXInfo.Frequency),
-		ModulationInfo: umi,
+	urx, utx, err := config.UplinkInfo()
+	if err != nil {
+		return err
 	}
 
+	//A join request starts a new session, so the join accept must be processed as such.
+	cDevice.Joined = false
 	if !cNSClient.IsConnected() {
-		err = cDevice.Join(mqttClient, config.MQTT.UplinkTopic, config.GW.MAC, &urx, &utx)
-	} else {
-		err = cDevice.JoinUDP(cNSClient, config.GW.MAC, &urx, &utx)
-	}
-
-	if err != nil {
-		log.Errorf("join error: %s", err)
-	} else {
-		log.Println("join sent")
+		return cDevice.Join(mqttClient, config.Topics().Uplink, config.GW.MAC, urx, utx)
 	}
+	return cDevice.JoinUDP(cNSClient, config.GW.MAC, urx, utx)
 }
 
 func run() {
@@ -573,21 +528,7 @@ func run() {
 	}
 
 	setDevice()
-
-	/*dataRate := &lds.DataRate{
-		Bandwidth:    config.DR.Bandwidth,
-		Modulation:   "LORA",
-		SpreadFactor: config.DR.SpreadFactor,
-		BitRate:      config.DR.BitRate,
-	}*/
-
-	//Get DR index from a dr.
-	dataRate := lwBand.DataRate{
-		Modulation:   lwBand.Modulation("LORA"),
-		SpreadFactor: config.DR.SpreadFactor,
-		Bandwidth:    config.DR.Bandwidth,
-		BitRate:      config.DR.BitRate,
-	}
+	setChannelSimulator()
 
 	running = true
 
@@ -597,75 +538,12 @@ func run() {
 			running = false
 			return
 		}
-		payload := []byte{}
-		var pErr error
-
-		if config.RawPayload.UseRaw {
-			payload, pErr = hex.DecodeString(config.RawPayload.Payload)
-			if pErr != nil {
-				log.Errorf("couldn't decode hex payload: %s", pErr)
-				running = false
-				return
-			}
-		} else if config.RawPayload.UseEncoder {
-			payload, pErr = EncodeToBytes()
-			if pErr != nil {
-				log.Errorf("couldn't encode js object: %s", pErr)
-				running = false
-				return
-			}
-		} else {
-			for _, v := range config.EncodedType {
-				if v.IsFloat {
-					arr := lds.GenerateFloat(float32(v.Value), float32(v.MaxValue), int32(v.NumBytes))
-					payload = append(payload, arr...)
-				} else {
-					arr := lds.GenerateInt(int32(v.Value), int32(v.NumBytes))
-					payload = append(payload, arr...)
-				}
-			}
-		}
-
-		gwID, err := lds.MACToGatewayID(config.GW.MAC)
+		payload, err := config.Payload()
 		if err != nil {
-			log.Errorf("gw mac error: %s", err)
+			log.Errorln(err)
 			running = false
 			return
 		}
-		now := time.Now()
-		rxTime := ptypes.TimestampNow()
-		tsge := ptypes.DurationProto(now.Sub(time.Time{}))
-
-		urx := gw.UplinkRXInfo{
-			GatewayId:         gwID,
-			Rssi:              int32(config.RXInfo.Rssi),
-			LoraSnr:           float64(config.RXInfo.LoRaSNR),
-			Channel:           uint32(config.RXInfo.Channel),
-			RfChain:           uint32(config.RXInfo.RfChain),
-			TimeSinceGpsEpoch: tsge,
-			Time:              rxTime,
-			Board:             0,
-			Antenna:           0,
-			Location:          nil,
-			FineTimestamp:     nil,
-			FineTimestampType: gw.FineTimestampType_NONE,
-			Context:           make([]byte, 4),
-		}
-
-		lmi := &gw.LoRaModulationInfo{
-			Bandwidth:       uint32(config.DR.Bandwidth),
-			SpreadingFactor: uint32(config.DR.SpreadFactor),
-			CodeRate:        config.RXInfo.CodeRate,
-		}
-
-		umi := &gw.UplinkTXInfo_LoraModulationInfo{
-			LoraModulationInfo: lmi,
-		}
-
-		utx := gw.UplinkTXInfo{
-			Frequency:      uint32(config.RXInfo.Frequency),
-			ModulationInfo: umi,
-		}
 
 		var fOpts []*lorawan.MACCommand
 		for i := 0; i < len(macCommands); i++ {
@@ -674,15 +552,14 @@ func run() {
 			}
 		}
 
-		//Now send an uplink
-		var ulfc uint32
-
-		if !cNSClient.IsConnected() {
-			ulfc, err = cDevice.Uplink(mqttClient, config.MQTT.UplinkTopic, config.Device.MType, uint8(config.RawPayload.FPort), &urx, &utx, payload, config.GW.MAC, config.Band.Name, dataRate, fOpts, fCtrl)
-		} else {
-			ulfc, err = cDevice.UplinkUDP(cNSClient, config.Device.MType, uint8(config.RawPayload.FPort), &urx, &utx, payload, config.GW.MAC, config.Band.Name, dataRate, fOpts, fCtrl)
+		if err := lds.CheckFPort(config.RawPayload.FPort); err != nil {
+			log.Errorln(err)
+			running = false
+			return
 		}
 
+		//Now send an uplink
+		ulfc, err := sendUplink(config.Device.MType, uint8(config.RawPayload.FPort), payload, fOpts, fCtrl)
 		if err != nil {
 			log.Errorf("couldn't send uplink: %s", err)
 		} else {
@@ -700,12 +577,35 @@ func run() {
 	}
 }
 
+// sendUplink sends an uplink through the connected client and returns the uplink frame counter.
+func sendUplink(mType lorawan.MType, fPort uint8, payload []byte, fOpts []*lorawan.MACCommand, fCtrl lorawan.FCtrl) (uint32, error) {
+	if err := checkConnected(); err != nil {
+		return 0, err
+	}
+
+	urx, utx, err := config.UplinkInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	if !cNSClient.IsConnected() {
+		return cDevice.Uplink(mqttClient, config.Topics().Uplink, mType, fPort, urx, utx, payload, config.GW.MAC, config.Band.Name, config.DataRate(), fOpts, fCtrl)
+	}
+	return cDevice.UplinkUDP(cNSClient, mType, fPort, urx, utx, payload, config.GW.MAC, config.Band.Name, config.DataRate(), fOpts, fCtrl)
+}
+
 func onIncomingDownlink(payload []byte) error {
 	log.Debugf("Incoming Downlink len=%d", len(payload))
 	err := error(nil)
 	if cDevice != nil {
 		mqtt := mqttClient != nil && mqttClient.IsConnected()
+		if mqtt {
+			lds.GetGateway(config.GW.MAC).SetAckPolicy(config.AckPolicy())
+		}
 		dlMessage, err := cDevice.ProcessDownlink(payload, cDevice.MACVersion, mqtt)
+		if mqtt && config.Topics().Ack != "" {
+			cDevice.PublishAck(mqttClient, config.Topics().Ack, config.GW.MAC)
+		}
 		//Update keys when necessary.
 		config.Device.AppSKey = lds.KeyToHex(cDevice.AppSKey)
 		config.Device.FNwkSIntKey = lds.KeyToHex(cDevice.FNwkSIntKey)
@@ -15,11 +15,6 @@ import (
 // cNSClient is a direct NetworkServer connection handle
 var cNSClient lds.NSClient
 
-type forwarder struct {
-	Server string `toml:"nserver"`
-	Port   string `toml:"nsport"`
-}
-
 var (
 	nserverEdit     widget.Editor
 	nportEdit       widget.Editor
@@ -71,6 +66,12 @@ func forwarderConnect() error {
 		return err
 	}
 
+	if config.NetworkServer.Enabled {
+		if err := startNetServer(); err != nil {
+			return err
+		}
+	}
+
 	cNSClient.Server = config.Forwarder.Server
 	cNSClient.Port = port
 	cNSClient.Connect(config.GW.MAC, onIncomingDownlink)
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+
+	l "gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/iegomez/lds/lds"
+	matx "github.com/scartill/giox/material"
+	log "github.com/sirupsen/logrus"
+)
+
+// grpcServer is the gRPC API, when running.
+var grpcServer *lds.GRPCServer
+
+var (
+	grpcEnabledCheckbox widget.Bool
+	grpcBindEdit        widget.Editor
+	grpcStartButton     widget.Clickable
+	grpcStopButton      widget.Clickable
+)
+
+func grpcResetGuiValues() {
+	grpcEnabledCheckbox.Value = config.GRPC.Enabled
+	grpcBindEdit.SetText(config.GRPC.Bind)
+}
+
+func grpcForm(th *material.Theme) l.FlexChild {
+
+	config.GRPC.Enabled = grpcEnabledCheckbox.Value
+	config.GRPC.Bind = grpcBindEdit.Text()
+
+	for grpcStartButton.Clicked() {
+		startGRPC()
+	}
+
+	for grpcStopButton.Clicked() {
+		stopGRPC()
+	}
+
+	widgets := []l.FlexChild{
+		matx.RigidSection(th, "gRPC API"),
+		matx.RigidCheckBox(th, "Start on launch", &grpcEnabledCheckbox),
+		matx.RigidEditor(th, "Bind:", lds.DefaultGRPCBind, &grpcBindEdit),
+	}
+
+	if grpcServer == nil {
+		widgets = append(widgets, matx.RigidButton(th, "Start", &grpcStartButton))
+	} else {
+		widgets = append(widgets, matx.RigidLabel(th, fmt.Sprintf("Listening on %s", grpcServer.Addr())))
+		widgets = append(widgets, matx.RigidButton(th, "Stop", &grpcStopButton))
+	}
+
+	inset := l.Inset{Left: unit.Dp(30)}
+	return l.Rigid(func(gtx l.Context) l.Dimensions {
+		return inset.Layout(gtx, func(gtx l.Context) l.Dimensions {
+			return l.Flex{Axis: l.Vertical}.Layout(gtx, widgets...)
+		})
+	})
+}
+
+func startGRPC() {
+	if grpcServer != nil {
+		return
+	}
+
+	s, err := lds.NewGRPCServer(config.GRPC.Bind, guiTarget{}, config)
+	if err != nil {
+		log.Errorf("couldn't start the gRPC API: %s", err)
+		return
+	}
+	grpcServer = s
+}
+
+func stopGRPC() {
+	if grpcServer == nil {
+		return
+	}
+
+	grpcServer.Close()
+	grpcServer = nil
+	log.Infoln("gRPC API stopped")
+}
+
+func (guiTarget) AddEventHandler(h func(*lds.Event)) func() {
+	return cDevice.AddEventHandler(h)
+}
+
+func (guiTarget) UplinkRadio() lds.UplinkRadio {
+	return lds.UplinkRadio{Band: config.Band.Name, DR: config.DR, RXInfo: config.RXInfo}
+}
+
+// SetUplinkRadio sets the LoRa tab values.
+func (guiTarget) SetUplinkRadio(r lds.UplinkRadio) error {
+	if err := lds.GetGateway(config.GW.MAC).SetBand(r.Band); err != nil {
+		return err
+	}
+	config.Band.Name = r.Band
+	config.DR = r.DR
+	config.RXInfo = r.RXInfo
+	loraResetGuiValues()
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	l "gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/iegomez/lds/lds"
+	matx "github.com/scartill/giox/material"
+	log "github.com/sirupsen/logrus"
+)
+
+// joinServer is the Backend Interfaces join server, when running.
+var joinServer *lds.JoinServer
+
+var (
+	jsEnabledCheckbox widget.Bool
+	jsBindEdit        widget.Editor
+	jsJoinNonceEdit   widget.Editor
+	jsStartButton     widget.Clickable
+	jsStopButton      widget.Clickable
+)
+
+func joinServerResetGuiValues() {
+	jsEnabledCheckbox.Value = config.JoinServer.Enabled
+	jsBindEdit.SetText(config.JoinServer.Bind)
+	jsJoinNonceEdit.SetText(fmt.Sprintf("%d", config.JoinServer.JoinNonce))
+}
+
+func joinServerForm(th *material.Theme) l.FlexChild {
+
+	config.JoinServer.Enabled = jsEnabledCheckbox.Value
+	config.JoinServer.Bind = jsBindEdit.Text()
+	if jn, err := strconv.ParseUint(jsJoinNonceEdit.Text(), 10, 24); err == nil {
+		config.JoinServer.JoinNonce = uint32(jn)
+	}
+
+	for jsStartButton.Clicked() {
+		startJoinServer()
+	}
+
+	for jsStopButton.Clicked() {
+		stopJoinServer()
+	}
+
+	widgets := []l.FlexChild{
+		matx.RigidSection(th, "Join server"),
+		matx.RigidCheckBox(th, "Start on launch", &jsEnabledCheckbox),
+		matx.RigidEditor(th, "Bind:", lds.DefaultJSBind, &jsBindEdit),
+		matx.RigidEditor(th, "JoinNonce:", "1", &jsJoinNonceEdit),
+	}
+
+	if joinServer == nil {
+		widgets = append(widgets, matx.RigidButton(th, "Start", &jsStartButton))
+	} else {
+		widgets = append(widgets, matx.RigidLabel(th, fmt.Sprintf("Listening on %s", joinServer.Addr())))
+		widgets = append(widgets, matx.RigidButton(th, "Stop", &jsStopButton))
+	}
+
+	inset := l.Inset{Left: unit.Dp(30)}
+	return l.Rigid(func(gtx l.Context) l.Dimensions {
+		return inset.Layout(gtx, func(gtx l.Context) l.Dimensions {
+			return l.Flex{Axis: l.Vertical}.Layout(gtx, widgets...)
+		})
+	})
+}
+
+func startJoinServer() {
+	if joinServer != nil {
+		return
+	}
+
+	js, err := lds.NewJoinServer(config.JoinServer.Bind, config)
+	if err != nil {
+		log.Errorf("couldn't start the join server: %s", err)
+		return
+	}
+	joinServer = js
+}
+
+func stopJoinServer() {
+	if joinServer == nil {
+		return
+	}
+
+	if err := joinServer.Close(); err != nil {
+		log.Errorf("join server close error: %s", err)
+	}
+	joinServer = nil
+	log.Infoln("join server stopped")
+}
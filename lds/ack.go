@@ -0,0 +1,107 @@
+package lds
+
+import (
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/iegomez/lds/api/gwv3"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// AckPolicy sets how a simulated gateway acknowledges the downlinks it receives.
+type AckPolicy struct {
+	// ErrorStatus is returned for the first ErrorItems items of every downlink,
+	// so that the network server has to retry with the next item or downlink.
+	ErrorStatus gwv3.TxAckStatus
+	ErrorItems  int
+	// RejectInvalid rejects items with wrong scheduling or TX parameters with the matching status
+	// instead of only logging them.
+	RejectInvalid bool
+}
+
+// ParseAckErrorStatus returns the ack status with the given name, DefaultAckErrorStatus when it's empty.
+// Only error statuses may be forced, so IGNORED and OK are rejected too.
+func ParseAckErrorStatus(name string) (gwv3.TxAckStatus, error) {
+	if name == "" {
+		name = DefaultAckErrorStatus
+	}
+	status, ok := gwv3.TxAckStatus_value[name]
+	if !ok || status <= int32(gwv3.TxAckStatus_OK) {
+		return gwv3.TxAckStatus_IGNORED, errors.Errorf("unknown ack error status %q", name)
+	}
+	return gwv3.TxAckStatus(status), nil
+}
+
+// SetAckPolicy sets the ack policy of the gateway.
+func (g *Gateway) SetAckPolicy(p AckPolicy) {
+	g.mu.Lock()
+	g.ackPolicy = p
+	g.mu.Unlock()
+}
+
+// emit decides which item of a downlink frame the gateway emits according to its ack policy, checking the items
+// it doesn't force to fail. It returns the index of the emitted item, or -1 if none was, and the ack with one status per item.
+func (g *Gateway) emit(df *gwv3.DownlinkFrame, items []*gwv3.DownlinkFrameItem) (int, *gwv3.DownlinkTXAck) {
+	g.mu.Lock()
+	policy := g.ackPolicy
+	g.mu.Unlock()
+
+	ack := &gwv3.DownlinkTXAck{
+		GatewayId:  df.GetGatewayId(),
+		Token:      df.GetToken(),
+		DownlinkId: df.GetDownlinkId(),
+	}
+
+	emitted := -1
+	for i, item := range items {
+		status := gwv3.TxAckStatus_IGNORED
+		if emitted < 0 {
+			if i < policy.ErrorItems {
+				status = policy.ErrorStatus
+			} else if err := g.CheckDownlinkTXInfo(item.GetTxInfo()); err != nil && policy.RejectInvalid {
+				status = ackStatus(err)
+			} else {
+				status = gwv3.TxAckStatus_OK
+				emitted = i
+			}
+		}
+		ack.Items = append(ack.Items, &gwv3.DownlinkTXAckItem{Status: status})
+	}
+
+	//Network servers that don't know about items only look at the error of the last attempt.
+	if emitted < 0 && len(ack.Items) > 0 {
+		ack.Error = ack.Items[len(ack.Items)-1].GetStatus().String()
+	}
+
+	g.countDownlink(emitted >= 0)
+	return emitted, ack
+}
+
+// ackStatus returns the ack status matching a downlink check error.
+func ackStatus(err error) gwv3.TxAckStatus {
+	switch errors.Cause(err) {
+	case ErrDownlinkScheduling:
+		return gwv3.TxAckStatus_TOO_LATE
+	case ErrTXPower:
+		return gwv3.TxAckStatus_TX_POWER
+	default:
+		return gwv3.TxAckStatus_TX_FREQ
+	}
+}
+
+// PublishAck publishes the TX ack of the last downlink received through MQTT, if it hasn't been published yet.
+func (d *Device) PublishAck(client MQTT.Client, topicTemplate, gwMAC string) error {
+	if d.ack == nil {
+		return nil
+	}
+
+	ack := d.ack
+	d.ack = nil
+
+	b, err := d.marshal(ack)
+	if err != nil {
+		log.Errorf("error marshaling tx ack: %s", err)
+		return err
+	}
+
+	return publish(client, FormatTopic(topicTemplate, gwMAC), b)
+}
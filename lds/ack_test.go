@@ -0,0 +1,257 @@
+package lds
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/iegomez/lds/api/gwv3"
+)
+
+// doneToken is an MQTT token of an operation that already completed.
+type doneToken struct {
+	err error
+}
+
+func (t doneToken) Wait() bool                       { return true }
+func (t doneToken) WaitTimeout(d time.Duration) bool { return true }
+func (t doneToken) Error() error                     { return t.err }
+
+// publishedMessage is a message published through a recordingClient.
+type publishedMessage struct {
+	topic    string
+	qos      byte
+	retained bool
+	payload  []byte
+}
+
+// recordingClient is an MQTT client that keeps the messages it publishes instead of sending them.
+// Other methods aren't implemented.
+type recordingClient struct {
+	MQTT.Client
+
+	mu        sync.Mutex
+	published []publishedMessage
+	err       error
+}
+
+func (c *recordingClient) Publish(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.published = append(c.published, publishedMessage{topic, qos, retained, payload.([]byte)})
+	}
+	return doneToken{c.err}
+}
+
+func (c *recordingClient) messages() []publishedMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]publishedMessage(nil), c.published...)
+}
+
+func TestGatewayEmit(t *testing.T) {
+	valid := func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(868100000, 7, time.Second, c) }
+	rx2 := func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(869525000, 12, 2*time.Second, c) }
+	wrongFrequency := func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(868300000, 7, time.Second, c) }
+	tooLate := func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(868100000, 7, 1500*time.Millisecond, c) }
+	tooPowerful := func(c []byte) *gw.DownlinkTXInfo {
+		txInfo := valid(c)
+		txInfo.Power = 30
+		return txInfo
+	}
+
+	tests := []struct {
+		name     string
+		policy   AckPolicy
+		items    []func(context []byte) *gw.DownlinkTXInfo
+		emitted  int
+		statuses []gwv3.TxAckStatus
+		err      string
+		//paramErrors is how many items have their TX parameters counted as wrong.
+		paramErrors float64
+	}{
+		{
+			name:     "first item emitted",
+			items:    []func([]byte) *gw.DownlinkTXInfo{valid, rx2},
+			emitted:  0,
+			statuses: []gwv3.TxAckStatus{gwv3.TxAckStatus_OK, gwv3.TxAckStatus_IGNORED},
+		},
+		{
+			name:        "invalid items are emitted without RejectInvalid",
+			items:       []func([]byte) *gw.DownlinkTXInfo{wrongFrequency, rx2},
+			emitted:     0,
+			statuses:    []gwv3.TxAckStatus{gwv3.TxAckStatus_OK, gwv3.TxAckStatus_IGNORED},
+			paramErrors: 1,
+		},
+		{
+			name:     "error items",
+			policy:   AckPolicy{ErrorStatus: gwv3.TxAckStatus_COLLISION_PACKET, ErrorItems: 1},
+			items:    []func([]byte) *gw.DownlinkTXInfo{valid, rx2},
+			emitted:  1,
+			statuses: []gwv3.TxAckStatus{gwv3.TxAckStatus_COLLISION_PACKET, gwv3.TxAckStatus_OK},
+		},
+		{
+			name:     "error items aren't checked",
+			policy:   AckPolicy{ErrorStatus: gwv3.TxAckStatus_COLLISION_PACKET, ErrorItems: 1, RejectInvalid: true},
+			items:    []func([]byte) *gw.DownlinkTXInfo{wrongFrequency, rx2},
+			emitted:  1,
+			statuses: []gwv3.TxAckStatus{gwv3.TxAckStatus_COLLISION_PACKET, gwv3.TxAckStatus_OK},
+		},
+		{
+			name:     "every item fails",
+			policy:   AckPolicy{ErrorStatus: gwv3.TxAckStatus_QUEUE_FULL, ErrorItems: 3},
+			items:    []func([]byte) *gw.DownlinkTXInfo{valid, rx2},
+			emitted:  -1,
+			statuses: []gwv3.TxAckStatus{gwv3.TxAckStatus_QUEUE_FULL, gwv3.TxAckStatus_QUEUE_FULL},
+			err:      "QUEUE_FULL",
+		},
+		{
+			name:        "invalid frequency rejected",
+			policy:      AckPolicy{RejectInvalid: true},
+			items:       []func([]byte) *gw.DownlinkTXInfo{wrongFrequency, rx2},
+			emitted:     1,
+			statuses:    []gwv3.TxAckStatus{gwv3.TxAckStatus_TX_FREQ, gwv3.TxAckStatus_OK},
+			paramErrors: 1,
+		},
+		{
+			name:        "invalid power rejected",
+			policy:      AckPolicy{RejectInvalid: true},
+			items:       []func([]byte) *gw.DownlinkTXInfo{tooPowerful},
+			emitted:     -1,
+			statuses:    []gwv3.TxAckStatus{gwv3.TxAckStatus_TX_POWER},
+			err:         "TX_POWER",
+			paramErrors: 1,
+		},
+		{
+			name:     "late item rejected",
+			policy:   AckPolicy{RejectInvalid: true},
+			items:    []func([]byte) *gw.DownlinkTXInfo{tooLate, rx2},
+			emitted:  1,
+			statuses: []gwv3.TxAckStatus{gwv3.TxAckStatus_TOO_LATE, gwv3.TxAckStatus_OK},
+		},
+		{
+			name:    "no items",
+			emitted: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, context := scheduledGateway(t, time.Now().Add(-100*time.Millisecond))
+			g.SetAckPolicy(tt.policy)
+
+			df := &gwv3.DownlinkFrame{GatewayId: testGatewayID, Token: 12, DownlinkId: []byte{1, 2, 3, 4}}
+			var items []*gwv3.DownlinkFrameItem
+			for _, txInfo := range tt.items {
+				items = append(items, &gwv3.DownlinkFrameItem{PhyPayload: []byte{1, 2, 3}, TxInfo: txInfo(context)})
+			}
+
+			paramErrors := testutil.ToFloat64(txParamErrors.WithLabelValues(g.MAC))
+			emitted, ack := g.emit(df, items)
+			if count := testutil.ToFloat64(txParamErrors.WithLabelValues(g.MAC)) - paramErrors; count != tt.paramErrors {
+				t.Errorf("got %v TX parameter errors counted, expected %v", count, tt.paramErrors)
+			}
+			if emitted != tt.emitted {
+				t.Errorf("got item %d emitted, expected %d", emitted, tt.emitted)
+			}
+
+			expected := &gwv3.DownlinkTXAck{GatewayId: testGatewayID, Token: 12, DownlinkId: []byte{1, 2, 3, 4}, Error: tt.err}
+			for _, status := range tt.statuses {
+				expected.Items = append(expected.Items, &gwv3.DownlinkTXAckItem{Status: status})
+			}
+			if !proto.Equal(ack, expected) {
+				t.Errorf("got ack %+v, expected %+v", ack, expected)
+			}
+
+			stats := g.Stats()
+			if stats.GetTxPacketsReceived() != 1 || stats.GetTxPacketsEmitted() != map[bool]uint32{false: 0, true: 1}[tt.emitted >= 0] {
+				t.Errorf("got %d downlinks received and %d emitted", stats.GetTxPacketsReceived(), stats.GetTxPacketsEmitted())
+			}
+		})
+	}
+}
+
+func TestAckStatus(t *testing.T) {
+	tests := []struct {
+		err    error
+		status gwv3.TxAckStatus
+	}{
+		{ErrDownlinkScheduling, gwv3.TxAckStatus_TOO_LATE},
+		{errors.Wrap(ErrDownlinkScheduling, "rx1"), gwv3.TxAckStatus_TOO_LATE},
+		{ErrTXPower, gwv3.TxAckStatus_TX_POWER},
+		{ErrTXParams, gwv3.TxAckStatus_TX_FREQ},
+	}
+
+	for _, tt := range tests {
+		if status := ackStatus(tt.err); status != tt.status {
+			t.Errorf("%s: got %s, expected %s", tt.err, status, tt.status)
+		}
+	}
+}
+
+func TestParseAckErrorStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status gwv3.TxAckStatus
+		err    bool
+	}{
+		{"", gwv3.TxAckStatus_TOO_LATE, false},
+		{"COLLISION_PACKET", gwv3.TxAckStatus_COLLISION_PACKET, false},
+		{"INTERNAL_ERROR", gwv3.TxAckStatus_INTERNAL_ERROR, false},
+		{"OK", gwv3.TxAckStatus_IGNORED, true},
+		{"IGNORED", gwv3.TxAckStatus_IGNORED, true},
+		{"TOO_LTE", gwv3.TxAckStatus_IGNORED, true},
+		{"too_late", gwv3.TxAckStatus_IGNORED, true},
+	}
+
+	for _, tt := range tests {
+		status, err := ParseAckErrorStatus(tt.name)
+		if (err != nil) != tt.err {
+			t.Errorf("%q: got error %v, expected one: %t", tt.name, err, tt.err)
+		}
+		if status != tt.status {
+			t.Errorf("%q: got %s, expected %s", tt.name, status, tt.status)
+		}
+	}
+}
+
+func TestPublishAck(t *testing.T) {
+	d := &Device{}
+	d.SetMarshaler("protobuf")
+	client := &recordingClient{}
+
+	if err := d.PublishAck(client, "gateway/%s/event/ack", "0102030405060708"); err != nil || len(client.messages()) != 0 {
+		t.Fatalf("got error %v and %d messages without a downlink", err, len(client.messages()))
+	}
+
+	ack := &gwv3.DownlinkTXAck{GatewayId: testGatewayID, Token: 12, Items: []*gwv3.DownlinkTXAckItem{{Status: gwv3.TxAckStatus_OK}}}
+	d.ack = ack
+	if err := d.PublishAck(client, "gateway/%s/event/ack", "0102030405060708"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.PublishAck(client, "gateway/%s/event/ack", "0102030405060708"); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := client.messages()
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, expected the ack to be published once", len(messages))
+	}
+	if messages[0].topic != "gateway/0102030405060708/event/ack" {
+		t.Errorf("got topic %s", messages[0].topic)
+	}
+	var published gwv3.DownlinkTXAck
+	if err := proto.Unmarshal(messages[0].payload, &published); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(&published, ack) {
+		t.Errorf("got %+v, expected %+v", &published, ack)
+	}
+}
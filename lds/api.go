@@ -0,0 +1,495 @@
+package lds
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrNoDevice is returned by API backends when the device was deleted and no new one was created.
+var ErrNoDevice = errors.New("no device")
+
+// ErrDeviceExists is returned by API backends when creating a device while there's one already.
+var ErrDeviceExists = errors.New("the device already exists")
+
+// APIBackend is the simulator driven by the HTTP API. The simulator runs one device, so the device endpoints
+// create, read, update or delete that one.
+type APIBackend interface {
+	ScenarioTarget
+	//DeviceConfig returns the device configuration, or ErrNoDevice.
+	DeviceConfig() (DeviceConfig, error)
+	//PutDeviceConfig creates or replaces the device, create fails with ErrDeviceExists when there's one.
+	PutDeviceConfig(dc DeviceConfig, create bool) error
+	//DeleteDevice resets the device session and removes the device.
+	DeleteDevice() error
+	//Start sends the configured data every interval until stopped.
+	Start(interval time.Duration) error
+	//Stop stops the periodic sends.
+	Stop() error
+	//SetValues sets the frame counters and nonces.
+	SetValues(ulFcnt, dlFcnt, devNonce, joinNonce int) error
+	//EnqueueDownlink queues a downlink on the embedded network server and returns the queue length, or ErrNoNetworkServer.
+	EnqueueDownlink(dl *NSDownlink) (int, error)
+}
+
+// UplinkRequest is the body of an uplink call, the configured data, fPort and message type are used for the missing fields.
+type UplinkRequest struct {
+	Payload     string        `json:"payload"`
+	FPort       *int          `json:"fPort"`
+	Confirmed   *bool         `json:"confirmed"`
+	MACCommands []string      `json:"macCommands"`
+	FCtrl       lorawan.FCtrl `json:"fCtrl"`
+}
+
+// StartRequest is the body of a start call.
+type StartRequest struct {
+	Interval Duration `json:"interval"`
+}
+
+// CountersRequest is the body of a counters call.
+type CountersRequest struct {
+	UlFcnt    int `json:"ulFcnt"`
+	DlFcnt    int `json:"dlFcnt"`
+	DevNonce  int `json:"devNonce"`
+	JoinNonce int `json:"joinNonce"`
+}
+
+// NSDownlinkRequest is the body of a network server downlink call, MAC commands are given as ParseDownlinkMACCommands takes them.
+type NSDownlinkRequest struct {
+	FPort       int      `json:"fPort"`
+	Payload     string   `json:"payload"`
+	Confirmed   bool     `json:"confirmed"`
+	MACCommands []string `json:"macCommands"`
+}
+
+// apiDownlinkBuffer is how many downlinks are kept for a slow event stream client before dropping them.
+const apiDownlinkBuffer = 16
+
+// APIServer is the HTTP control API.
+type APIServer struct {
+	backend  APIBackend
+	config   *Config
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewAPIServer starts serving the API on addr. Uplinks that don't give them use the data, fPort and message type of config.
+func NewAPIServer(addr string, backend APIBackend, config *Config) (*APIServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "api listen error")
+	}
+
+	s := &APIServer{
+		backend:  backend,
+		config:   config,
+		listener: listener,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/device", s.device)
+	mux.HandleFunc("/api/device/join", s.post(s.join))
+	mux.HandleFunc("/api/device/uplink", s.post(s.uplink))
+	mux.HandleFunc("/api/device/start", s.post(s.start))
+	mux.HandleFunc("/api/device/stop", s.post(s.stop))
+	mux.HandleFunc("/api/device/reset", s.post(s.reset))
+	mux.HandleFunc("/api/device/counters", s.counters)
+	mux.HandleFunc("/api/device/session", s.session)
+	mux.HandleFunc("/api/downlinks", s.downlinks)
+	mux.HandleFunc("/api/network-server/downlink", s.post(s.enqueueDownlink))
+	mux.Handle("/metrics", promhttp.Handler())
+	s.server = &http.Server{Handler: mux}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Errorf("api server error: %s", err)
+		}
+	}()
+
+	log.Infof("HTTP API listening on %s", listener.Addr())
+	return s, nil
+}
+
+// Addr returns the address the API listens on.
+func (s *APIServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the API, ending the event streams.
+func (s *APIServer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		return s.server.Close()
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Errorf("api response error: %s", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch errors.Cause(err) {
+	case ErrNoDevice:
+		status = http.StatusNotFound
+	case ErrDeviceExists:
+		status = http.StatusConflict
+	case ErrNoNetworkServer:
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func badRequest(w http.ResponseWriter, err error) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed string) {
+	w.Header().Set("Allow", allowed)
+	writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+}
+
+// decode reads the json body into v, an empty body leaves v untouched.
+func decode(r *http.Request, v interface{}) error {
+	if r.ContentLength == 0 {
+		return nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errors.Wrap(err, "invalid body")
+	}
+	return nil
+}
+
+// post only lets POST requests through to h.
+func (s *APIServer) post(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *APIServer) device(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		dc, err := s.backend.DeviceConfig()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, dc)
+	case http.MethodPost, http.MethodPut:
+		var dc DeviceConfig
+		if err := decode(r, &dc); err != nil {
+			badRequest(w, err)
+			return
+		}
+		if err := validateDeviceConfig(&dc); err != nil {
+			badRequest(w, err)
+			return
+		}
+
+		create := r.Method == http.MethodPost
+		if err := s.backend.PutDeviceConfig(dc, create); err != nil {
+			writeError(w, err)
+			return
+		}
+		//Return the device as set, with the defaults the backend filled.
+		dc, err := s.backend.DeviceConfig()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		status := http.StatusOK
+		if create {
+			status = http.StatusCreated
+		}
+		writeJSON(w, status, dc)
+	case http.MethodDelete:
+		if err := s.backend.DeleteDevice(); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, "GET, POST, PUT, DELETE")
+	}
+}
+
+// validateDeviceConfig checks the device identifiers and keys, zero filling the missing ones as the device needs
+// them all, and sets the profile when missing.
+func validateDeviceConfig(dc *DeviceConfig) error {
+	if _, err := HexToEUI(dc.DevEUI); err != nil {
+		return errors.Wrap(err, "devEUI")
+	}
+	if dc.Profile == "" {
+		dc.Profile = "OTAA"
+	}
+	if dc.Profile != "OTAA" && dc.Profile != "ABP" {
+		return fmt.Errorf("unknown profile %q", dc.Profile)
+	}
+
+	fields := []struct {
+		name  string
+		value *string
+		size  int
+	}{
+		{"devAddr", &dc.DevAddress, 4},
+		{"joinEUI", &dc.JoinEUI, 8},
+		{"nwkSEncKey", &dc.NwkSEncKey, 16},
+		{"sNwkSIntKey", &dc.SNwkSIntKey, 16},
+		{"fNwkSIntKey", &dc.FNwkSIntKey, 16},
+		{"appSKey", &dc.AppSKey, 16},
+		{"nwkKey", &dc.NwkKey, 16},
+		{"appKey", &dc.AppKey, 16},
+	}
+	for _, f := range fields {
+		if *f.value == "" {
+			*f.value = hex.EncodeToString(make([]byte, f.size))
+			continue
+		}
+		if b, err := hex.DecodeString(*f.value); err != nil || len(b) != f.size {
+			return fmt.Errorf("%s must be %d hex encoded bytes", f.name, f.size)
+		}
+	}
+	return nil
+}
+
+func (s *APIServer) join(w http.ResponseWriter, r *http.Request) {
+	if err := s.backend.Join(); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *APIServer) uplink(w http.ResponseWriter, r *http.Request) {
+	var req UplinkRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+
+	payload, err := hex.DecodeString(req.Payload)
+	if err != nil {
+		badRequest(w, errors.Wrap(err, "payload"))
+		return
+	}
+	if req.FPort != nil {
+		if err := CheckFPort(*req.FPort); err != nil {
+			badRequest(w, err)
+			return
+		}
+	}
+	payload, fPort, mType, err := uplinkArgs(s.config, payload, req.FPort, req.Confirmed)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	macCommands, err := ParseMACCommands(req.MACCommands)
+	if err != nil {
+		badRequest(w, err)
+		return
+	}
+
+	fCnt, err := s.backend.Uplink(mType, fPort, payload, macCommands, req.FCtrl)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]uint32{"fCnt": fCnt})
+}
+
+// uplinkArgs returns the payload, fPort and message type of an uplink, the configured ones replacing those not given.
+func uplinkArgs(config *Config, payload []byte, fPort *int, confirmed *bool) ([]byte, uint8, lorawan.MType, error) {
+	if len(payload) == 0 {
+		var err error
+		if payload, err = config.Payload(); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	port := config.RawPayload.FPort
+	if fPort != nil {
+		port = *fPort
+	}
+	if err := CheckFPort(port); err != nil {
+		return nil, 0, 0, err
+	}
+
+	mType := config.Device.MType
+	if confirmed != nil {
+		mType = lorawan.UnconfirmedDataUp
+		if *confirmed {
+			mType = lorawan.ConfirmedDataUp
+		}
+	}
+	return payload, uint8(port), mType, nil
+}
+
+// CheckFPort checks that fPort may carry an application payload.
+func CheckFPort(fPort int) error {
+	if fPort < 1 || fPort > 223 {
+		return fmt.Errorf("fPort %d must be between 1 and 223", fPort)
+	}
+	return nil
+}
+
+func (s *APIServer) start(w http.ResponseWriter, r *http.Request) {
+	var req StartRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	if req.Interval.Duration < time.Second {
+		badRequest(w, errors.New("the interval must be at least 1s"))
+		return
+	}
+
+	if err := s.backend.Start(req.Interval.Duration); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *APIServer) stop(w http.ResponseWriter, r *http.Request) {
+	if err := s.backend.Stop(); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *APIServer) reset(w http.ResponseWriter, r *http.Request) {
+	if err := s.backend.Reset(); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *APIServer) counters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		methodNotAllowed(w, http.MethodPut)
+		return
+	}
+
+	var req CountersRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	if err := s.backend.SetValues(req.UlFcnt, req.DlFcnt, req.DevNonce, req.JoinNonce); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *APIServer) session(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	if _, err := s.backend.DeviceConfig(); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	d := s.backend.State()
+	writeJSON(w, http.StatusOK, NewSession(&d))
+}
+
+func (s *APIServer) enqueueDownlink(w http.ResponseWriter, r *http.Request) {
+	var req NSDownlinkRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+
+	dl := &NSDownlink{FPort: uint8(req.FPort), Confirmed: req.Confirmed}
+	var err error
+	if dl.Payload, err = hex.DecodeString(req.Payload); err != nil {
+		badRequest(w, errors.Wrap(err, "payload"))
+		return
+	}
+	if len(dl.Payload) > 0 {
+		if err := CheckFPort(req.FPort); err != nil {
+			badRequest(w, err)
+			return
+		}
+	}
+	if dl.MACCommands, err = ParseDownlinkMACCommands(req.MACCommands); err != nil {
+		badRequest(w, err)
+		return
+	}
+
+	queued, err := s.backend.EnqueueDownlink(dl)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]int{"queued": queued})
+}
+
+// downlinks streams the received downlinks as server-sent events until the client goes away.
+func (s *APIServer) downlinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, errors.New("streaming not supported"))
+		return
+	}
+
+	downlinks := make(chan *Downlink, apiDownlinkBuffer)
+	remove := s.backend.AddDownlinkHandler(func(dl *Downlink) {
+		select {
+		case downlinks <- dl:
+		default:
+			log.Warningln("api downlink stream is full, dropping downlink")
+		}
+	})
+	defer remove()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case dl := <-downlinks:
+			b, err := json.Marshal(dl)
+			if err != nil {
+				log.Errorf("api downlink marshal error: %s", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: downlink\ndata: %s\n\n", b)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
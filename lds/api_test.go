@@ -0,0 +1,290 @@
+package lds
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brocaar/lorawan"
+)
+
+// fakeBackend is an API backend over a fakeTarget, holding a device configuration.
+type fakeBackend struct {
+	*fakeTarget
+	dc       *DeviceConfig
+	interval time.Duration
+	values   [4]int
+	enqueued []*NSDownlink
+	noNS     bool
+}
+
+func (b *fakeBackend) DeviceConfig() (DeviceConfig, error) {
+	if b.dc == nil {
+		return DeviceConfig{}, ErrNoDevice
+	}
+	return *b.dc, nil
+}
+
+func (b *fakeBackend) PutDeviceConfig(dc DeviceConfig, create bool) error {
+	if create && b.dc != nil {
+		return ErrDeviceExists
+	}
+	b.dc = &dc
+	return nil
+}
+
+func (b *fakeBackend) DeleteDevice() error {
+	if b.dc == nil {
+		return ErrNoDevice
+	}
+	b.dc = nil
+	return nil
+}
+
+func (b *fakeBackend) Start(interval time.Duration) error {
+	b.interval = interval
+	return nil
+}
+
+func (b *fakeBackend) Stop() error {
+	b.interval = 0
+	return nil
+}
+
+func (b *fakeBackend) SetValues(ulFcnt, dlFcnt, devNonce, joinNonce int) error {
+	b.values = [4]int{ulFcnt, dlFcnt, devNonce, joinNonce}
+	return nil
+}
+
+func (b *fakeBackend) EnqueueDownlink(dl *NSDownlink) (int, error) {
+	if b.noNS {
+		return 0, ErrNoNetworkServer
+	}
+	b.enqueued = append(b.enqueued, dl)
+	return len(b.enqueued), nil
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		fakeTarget: &fakeTarget{device: Device{Profile: "OTAA", UlFcnt: 4}},
+		dc:         &DeviceConfig{DevEUI: "0102030405060708", Profile: "OTAA"},
+	}
+}
+
+// newTestAPIServer serves the API on the backend, with a configured payload 010203 on fPort 2.
+func newTestAPIServer(t *testing.T, backend APIBackend) *APIServer {
+	config := NewConfig()
+	config.RawPayload.UseRaw = true
+	config.RawPayload.Payload = "010203"
+	config.RawPayload.FPort = 2
+	config.Device.MType = lorawan.UnconfirmedDataUp
+
+	s, err := NewAPIServer("127.0.0.1:0", backend, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// apiCall sends the request, returning the response status and body.
+func apiCall(t *testing.T, s *APIServer, method, path, body string) (int, string) {
+	req, err := http.NewRequest(method, "http://"+s.Addr()+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var b bytes.Buffer
+	if _, err := b.ReadFrom(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, b.String()
+}
+
+func TestAPIUplink(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		mType   lorawan.MType
+		fPort   uint8
+		payload []byte
+	}{
+		{"configured", "", http.StatusOK, lorawan.UnconfirmedDataUp, 2, []byte{1, 2, 3}},
+		{"given", `{"payload": "0a0b", "fPort": 223, "confirmed": true, "macCommands": ["LinkCheckReq"], "fCtrl": {"adr": true}}`, http.StatusOK, lorawan.ConfirmedDataUp, 223, []byte{10, 11}},
+		{"unconfirmed", `{"confirmed": false}`, http.StatusOK, lorawan.UnconfirmedDataUp, 2, []byte{1, 2, 3}},
+		{"fPort 0", `{"fPort": 0}`, http.StatusBadRequest, 0, 0, nil},
+		{"fPort 224", `{"fPort": 224}`, http.StatusBadRequest, 0, 0, nil},
+		{"fPort 256", `{"fPort": 256}`, http.StatusBadRequest, 0, 0, nil},
+		{"invalid payload", `{"payload": "0g"}`, http.StatusBadRequest, 0, 0, nil},
+		{"invalid mac command", `{"macCommands": ["LinkCheck"]}`, http.StatusBadRequest, 0, 0, nil},
+		{"invalid body", `{`, http.StatusBadRequest, 0, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newFakeBackend()
+			s := newTestAPIServer(t, backend)
+			defer s.Close()
+
+			status, body := apiCall(t, s, http.MethodPost, "/api/device/uplink", tt.body)
+			if status != tt.status {
+				t.Fatalf("got status %d with %s, expected %d", status, body, tt.status)
+			}
+			if status != http.StatusOK {
+				if len(backend.uplinks) != 0 {
+					t.Error("the rejected uplink was sent")
+				}
+				return
+			}
+
+			if body != "{\"fCnt\":5}\n" {
+				t.Errorf("got %s", body)
+			}
+			up := backend.uplinks[0]
+			if up.mType != tt.mType || up.fPort != tt.fPort || !bytes.Equal(up.payload, tt.payload) {
+				t.Errorf("got %s on fPort %d with payload %x, expected %s on %d with %x", up.mType, up.fPort, up.payload, tt.mType, tt.fPort, tt.payload)
+			}
+		})
+	}
+}
+
+func TestAPIDevice(t *testing.T) {
+	backend := newFakeBackend()
+	s := newTestAPIServer(t, backend)
+	defer s.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"get", http.MethodGet, "/api/device", "", http.StatusOK},
+		{"create over the device", http.MethodPost, "/api/device", `{"devEUI": "0102030405060708"}`, http.StatusConflict},
+		{"invalid DevEUI", http.MethodPut, "/api/device", `{"devEUI": "0102"}`, http.StatusBadRequest},
+		{"invalid key", http.MethodPut, "/api/device", `{"devEUI": "0102030405060708", "appKey": "0102"}`, http.StatusBadRequest},
+		{"unknown profile", http.MethodPut, "/api/device", `{"devEUI": "0102030405060708", "profile": "OTA"}`, http.StatusBadRequest},
+		{"replace", http.MethodPut, "/api/device", `{"devEUI": "0807060504030201", "profile": "ABP"}`, http.StatusOK},
+		{"delete", http.MethodDelete, "/api/device", "", http.StatusNoContent},
+		{"get deleted", http.MethodGet, "/api/device", "", http.StatusNotFound},
+		{"session of the deleted device", http.MethodGet, "/api/device/session", "", http.StatusNotFound},
+		{"create", http.MethodPost, "/api/device", `{"devEUI": "0102030405060708"}`, http.StatusCreated},
+		{"patch", http.MethodPatch, "/api/device", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		if status, body := apiCall(t, s, tt.method, tt.path, tt.body); status != tt.status {
+			t.Errorf("%s: got status %d with %s, expected %d", tt.name, status, body, tt.status)
+		}
+	}
+
+	//The created device is returned with the missing fields set.
+	var dc DeviceConfig
+	if err := json.Unmarshal([]byte(mustGet(t, s, "/api/device")), &dc); err != nil {
+		t.Fatal(err)
+	}
+	if dc.Profile != "OTAA" || dc.DevAddress != "00000000" || dc.AppKey != "00000000000000000000000000000000" {
+		t.Errorf("got device %+v", dc)
+	}
+}
+
+func mustGet(t *testing.T, s *APIServer, path string) string {
+	status, body := apiCall(t, s, http.MethodGet, path, "")
+	if status != http.StatusOK {
+		t.Fatalf("got status %d with %s", status, body)
+	}
+	return body
+}
+
+func TestAPICommands(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		check  func(b *fakeBackend) bool
+	}{
+		{"join", http.MethodPost, "/api/device/join", "", http.StatusAccepted, func(b *fakeBackend) bool { return b.joins == 1 }},
+		{"join with GET", http.MethodGet, "/api/device/join", "", http.StatusMethodNotAllowed, func(b *fakeBackend) bool { return b.joins == 0 }},
+		{"start", http.MethodPost, "/api/device/start", `{"interval": "30s"}`, http.StatusNoContent, func(b *fakeBackend) bool { return b.interval == 30*time.Second }},
+		{"start too often", http.MethodPost, "/api/device/start", `{"interval": "100ms"}`, http.StatusBadRequest, func(b *fakeBackend) bool { return b.interval == 0 }},
+		{"stop", http.MethodPost, "/api/device/stop", "", http.StatusNoContent, func(b *fakeBackend) bool { return b.interval == 0 }},
+		{"reset", http.MethodPost, "/api/device/reset", "", http.StatusNoContent, func(b *fakeBackend) bool { return b.resets == 1 }},
+		{"counters", http.MethodPut, "/api/device/counters", `{"ulFcnt": 10, "dlFcnt": 2, "devNonce": 5, "joinNonce": 3}`, http.StatusNoContent, func(b *fakeBackend) bool { return b.values == [4]int{10, 2, 5, 3} }},
+		{"counters with POST", http.MethodPost, "/api/device/counters", "", http.StatusMethodNotAllowed, func(b *fakeBackend) bool { return b.values == [4]int{} }},
+		{"downlink", http.MethodPost, "/api/network-server/downlink", `{"payload": "cafe", "fPort": 3, "confirmed": true, "macCommands": ["DevStatusReq"]}`, http.StatusAccepted, func(b *fakeBackend) bool {
+			return len(b.enqueued) == 1 && b.enqueued[0].FPort == 3 && b.enqueued[0].Confirmed && len(b.enqueued[0].MACCommands) == 1
+		}},
+		{"mac commands only", http.MethodPost, "/api/network-server/downlink", `{"macCommands": ["DevStatusReq"]}`, http.StatusAccepted, func(b *fakeBackend) bool { return len(b.enqueued) == 1 }},
+		{"downlink on fPort 0", http.MethodPost, "/api/network-server/downlink", `{"payload": "cafe"}`, http.StatusBadRequest, func(b *fakeBackend) bool { return len(b.enqueued) == 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newFakeBackend()
+			s := newTestAPIServer(t, backend)
+			defer s.Close()
+
+			if status, body := apiCall(t, s, tt.method, tt.path, tt.body); status != tt.status {
+				t.Fatalf("got status %d with %s, expected %d", status, body, tt.status)
+			}
+			if !tt.check(backend) {
+				t.Errorf("got backend %+v", backend)
+			}
+		})
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	backend := newFakeBackend()
+	backend.noNS = true
+	s := newTestAPIServer(t, backend)
+	defer s.Close()
+
+	if status, _ := apiCall(t, s, http.MethodPost, "/api/network-server/downlink", `{"payload": "cafe", "fPort": 3}`); status != http.StatusServiceUnavailable {
+		t.Errorf("got status %d without a network server, expected %d", status, http.StatusServiceUnavailable)
+	}
+	backend.err = ErrNoDevice
+	if status, _ := apiCall(t, s, http.MethodPost, "/api/device/join", ""); status != http.StatusNotFound {
+		t.Errorf("got status %d without a device, expected %d", status, http.StatusNotFound)
+	}
+}
+
+func TestAPIDownlinks(t *testing.T) {
+	backend := newFakeBackend()
+	s := newTestAPIServer(t, backend)
+	defer s.Close()
+
+	resp, err := http.Get("http://" + s.Addr() + "/api/downlinks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("got content type %s", resp.Header.Get("Content-Type"))
+	}
+
+	backend.deliver(&Downlink{MType: lorawan.UnconfirmedDataDown, FCnt: 1, FRMPayload: []byte{0xca, 0xfe}})
+	r := bufio.NewReader(resp.Body)
+	event, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event != "event: downlink\n" || !strings.HasPrefix(data, "data: {") || !strings.Contains(data, `"frmPayload":"cafe"`) {
+		t.Errorf("got %q and %q", event, data)
+	}
+}
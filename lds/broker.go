@@ -0,0 +1,534 @@
+package lds
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// connectTimeout is how long the broker waits for the CONNECT packet of a new connection.
+const connectTimeout = 10 * time.Second
+
+// maxQueued is how many QoS 1 messages are queued for a persistent session while its client is disconnected.
+const maxQueued = 1000
+
+// Broker is a minimal in-process MQTT 3.1.1 broker, so that simulated gateways and the network server under test
+// can exchange messages without an external one. It supports QoS 0 and 1 (QoS 2 publishes are accepted and delivered
+// at QoS 1), wildcard subscriptions, retained messages, last wills and persistent sessions, which keep their
+// subscriptions and queue QoS 1 messages while their client is disconnected. Clients aren't authenticated.
+type Broker struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	sessions map[string]*brokerSession
+	retained map[string]*packets.PublishPacket
+	clientID uint64
+	closed   bool
+}
+
+// BrokerClient describes a client session of the embedded broker.
+type BrokerClient struct {
+	ID            string
+	Address       string
+	Connected     bool
+	Persistent    bool
+	Subscriptions []string
+	Queued        int
+}
+
+type brokerSession struct {
+	id            string
+	clean         bool
+	conn          *brokerConn
+	address       string
+	subscriptions map[string]byte
+	will          *packets.PublishPacket
+	nextID        uint16
+	inflight      map[uint16]*packets.PublishPacket
+	queue         []*packets.PublishPacket
+	//Message IDs of QoS 2 publishes received but not released yet, so that retries aren't routed twice.
+	received map[uint16]bool
+}
+
+// brokerConn serializes the writes to a client connection.
+type brokerConn struct {
+	net.Conn
+	mu sync.Mutex
+}
+
+func (c *brokerConn) write(p packets.ControlPacket) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return p.Write(c.Conn)
+}
+
+// delivery is a packet to be written to a client once the broker lock is released.
+type delivery struct {
+	conn   *brokerConn
+	packet packets.ControlPacket
+}
+
+// NewBroker starts an embedded broker listening on the given address, e.g. ":1883".
+func NewBroker(addr string) (*Broker, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "broker listen error")
+	}
+
+	b := &Broker{
+		listener: listener,
+		sessions: make(map[string]*brokerSession),
+		retained: make(map[string]*packets.PublishPacket),
+	}
+	go b.serve()
+
+	log.Infof("embedded MQTT broker listening on %s", listener.Addr())
+	return b, nil
+}
+
+// Addr returns the address the broker listens on.
+func (b *Broker) Addr() string {
+	return b.listener.Addr().String()
+}
+
+// Close stops the broker and disconnects every client.
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	var conns []*brokerConn
+	for _, s := range b.sessions {
+		if s.conn != nil {
+			conns = append(conns, s.conn)
+			s.conn = nil
+		}
+	}
+	b.sessions = make(map[string]*brokerSession)
+	b.mu.Unlock()
+
+	for _, c := range conns {
+		c.Close()
+	}
+	return b.listener.Close()
+}
+
+// Clients returns the client sessions known by the broker, sorted by client ID.
+func (b *Broker) Clients() []BrokerClient {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	clients := make([]BrokerClient, 0, len(b.sessions))
+	for _, s := range b.sessions {
+		c := BrokerClient{
+			ID:         s.id,
+			Address:    s.address,
+			Connected:  s.conn != nil,
+			Persistent: !s.clean,
+			Queued:     len(s.queue),
+		}
+		for filter := range s.subscriptions {
+			c.Subscriptions = append(c.Subscriptions, filter)
+		}
+		sort.Strings(c.Subscriptions)
+		clients = append(clients, c)
+	}
+
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+	return clients
+}
+
+func (b *Broker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			b.mu.Lock()
+			closed := b.closed
+			b.mu.Unlock()
+			if !closed {
+				log.Errorf("broker accept error: %s", err)
+			}
+			return
+		}
+		go b.handle(&brokerConn{Conn: conn})
+	}
+}
+
+// handle serves a client connection until it disconnects.
+func (b *Broker) handle(conn *brokerConn) {
+	conn.SetReadDeadline(time.Now().Add(connectTimeout))
+	p, err := packets.ReadPacket(conn)
+	if err != nil {
+		log.Debugf("broker: connection from %s closed before connecting: %s", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	cp, ok := p.(*packets.ConnectPacket)
+	if !ok {
+		log.Warningf("broker: connection from %s didn't start with CONNECT", conn.RemoteAddr())
+		conn.Close()
+		return
+	}
+
+	connack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+	if connack.ReturnCode = cp.Validate(); connack.ReturnCode != packets.Accepted {
+		conn.write(connack)
+		conn.Close()
+		return
+	}
+
+	s, pending := b.connect(cp, conn, connack)
+	log.Infof("broker: client %s connected from %s", s.id, conn.RemoteAddr())
+
+	graceful := false
+	defer func() {
+		b.disconnect(s, conn, graceful)
+	}()
+
+	if err := conn.write(connack); err != nil {
+		return
+	}
+	for _, p := range pending {
+		if err := conn.write(p); err != nil {
+			return
+		}
+	}
+
+	for {
+		if cp.Keepalive > 0 {
+			conn.SetReadDeadline(time.Now().Add(time.Duration(cp.Keepalive) * time.Second * 3 / 2))
+		} else {
+			conn.SetReadDeadline(time.Time{})
+		}
+
+		p, err := packets.ReadPacket(conn)
+		if err != nil {
+			log.Debugf("broker: client %s read error: %s", s.id, err)
+			return
+		}
+
+		var deliveries []delivery
+		switch p := p.(type) {
+		case *packets.PublishPacket:
+			deliveries = b.publish(s, conn, p)
+		case *packets.PubrelPacket:
+			b.mu.Lock()
+			delete(s.received, p.MessageID)
+			b.mu.Unlock()
+			pubcomp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			pubcomp.MessageID = p.MessageID
+			deliveries = []delivery{{conn, pubcomp}}
+		case *packets.PubackPacket:
+			b.mu.Lock()
+			delete(s.inflight, p.MessageID)
+			b.mu.Unlock()
+		case *packets.SubscribePacket:
+			deliveries = b.subscribe(s, conn, p)
+		case *packets.UnsubscribePacket:
+			b.mu.Lock()
+			for _, filter := range p.Topics {
+				delete(s.subscriptions, filter)
+			}
+			b.mu.Unlock()
+			unsuback := packets.NewControlPacket(packets.Unsuback).(*packets.UnsubackPacket)
+			unsuback.MessageID = p.MessageID
+			deliveries = []delivery{{conn, unsuback}}
+		case *packets.PingreqPacket:
+			deliveries = []delivery{{conn, packets.NewControlPacket(packets.Pingresp)}}
+		case *packets.DisconnectPacket:
+			graceful = true
+			return
+		default:
+			log.Warningf("broker: unexpected %s from client %s", p, s.id)
+			return
+		}
+
+		if err := deliver(deliveries); err != nil {
+			return
+		}
+	}
+}
+
+// connect sets up the session of a new connection, taking over the connection of a client with the same ID.
+// It returns the session and the messages pending for a resumed persistent session.
+func (b *Broker) connect(cp *packets.ConnectPacket, conn *brokerConn, connack *packets.ConnackPacket) (*brokerSession, []packets.ControlPacket) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := cp.ClientIdentifier
+	if id == "" {
+		b.clientID++
+		id = fmt.Sprintf("lds-broker-%d", b.clientID)
+	}
+
+	s, ok := b.sessions[id]
+	if ok && s.conn != nil {
+		log.Infof("broker: client %s connected again, closing its previous connection", id)
+		old := s.conn
+		s.conn = nil
+		go old.Close()
+	}
+
+	if !ok || cp.CleanSession {
+		s = &brokerSession{
+			id:            id,
+			subscriptions: make(map[string]byte),
+			inflight:      make(map[uint16]*packets.PublishPacket),
+			received:      make(map[uint16]bool),
+		}
+		b.sessions[id] = s
+	} else {
+		connack.SessionPresent = !s.clean
+	}
+
+	s.clean = cp.CleanSession
+	s.conn = conn
+	s.address = conn.RemoteAddr().String()
+	s.will = nil
+	if cp.WillFlag {
+		s.will = packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+		s.will.TopicName = cp.WillTopic
+		s.will.Payload = cp.WillMessage
+		s.will.Qos = cp.WillQos
+		s.will.Retain = cp.WillRetain
+	}
+
+	//Unacknowledged messages are sent again, followed by the ones queued while the client was away.
+	var pending []packets.ControlPacket
+	ids := make([]int, 0, len(s.inflight))
+	for id := range s.inflight {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		p := s.inflight[uint16(id)]
+		p.Dup = true
+		pending = append(pending, p)
+	}
+	for _, p := range s.queue {
+		p.MessageID = s.messageID()
+		s.inflight[p.MessageID] = p
+		pending = append(pending, p)
+	}
+	s.queue = nil
+
+	return s, pending
+}
+
+// disconnect cleans up after a connection is closed, publishing the client's will unless it disconnected gracefully.
+func (b *Broker) disconnect(s *brokerSession, conn *brokerConn, graceful bool) {
+	conn.Close()
+
+	b.mu.Lock()
+	if s.conn != conn {
+		//The connection was taken over by a new one or the broker was closed.
+		b.mu.Unlock()
+		return
+	}
+
+	s.conn = nil
+	will := s.will
+	s.will = nil
+	if s.clean && b.sessions[s.id] == s {
+		delete(b.sessions, s.id)
+	}
+	b.mu.Unlock()
+
+	log.Infof("broker: client %s disconnected", s.id)
+
+	if will != nil && !graceful {
+		deliver(b.route(will))
+	}
+}
+
+// publish handles a message published by a client, acknowledging it as needed.
+func (b *Broker) publish(s *brokerSession, conn *brokerConn, p *packets.PublishPacket) []delivery {
+	if strings.ContainsAny(p.TopicName, "+#") {
+		log.Warningf("broker: client %s published to invalid topic %s", s.id, p.TopicName)
+		return nil
+	}
+
+	var deliveries []delivery
+	switch p.Qos {
+	case 1:
+		puback := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+		puback.MessageID = p.MessageID
+		deliveries = append(deliveries, delivery{conn, puback})
+	case 2:
+		pubrec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+		pubrec.MessageID = p.MessageID
+		deliveries = append(deliveries, delivery{conn, pubrec})
+
+		b.mu.Lock()
+		duplicate := s.received[p.MessageID]
+		s.received[p.MessageID] = true
+		b.mu.Unlock()
+		if duplicate {
+			return deliveries
+		}
+	}
+
+	return append(deliveries, b.route(p)...)
+}
+
+// subscribe handles a subscription request, granting at most QoS 1, and sends the matching retained messages.
+func (b *Broker) subscribe(s *brokerSession, conn *brokerConn, p *packets.SubscribePacket) []delivery {
+	suback := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+	suback.MessageID = p.MessageID
+	deliveries := []delivery{{conn, suback}}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, filter := range p.Topics {
+		if !validTopicFilter(filter) {
+			log.Warningf("broker: client %s subscribed to invalid topic filter %s", s.id, filter)
+			suback.ReturnCodes = append(suback.ReturnCodes, 0x80)
+			continue
+		}
+
+		qos := p.Qoss[i]
+		if qos > 1 {
+			qos = 1
+		}
+		s.subscriptions[filter] = qos
+		suback.ReturnCodes = append(suback.ReturnCodes, qos)
+
+		for topic, r := range b.retained {
+			if !topicMatches(filter, topic) {
+				continue
+			}
+			out := s.outgoing(r, qos)
+			out.Retain = true
+			deliveries = append(deliveries, delivery{conn, out})
+		}
+	}
+
+	return deliveries
+}
+
+// route sends a message to every session subscribed to its topic, queueing QoS 1 messages for disconnected persistent sessions,
+// and keeps it when it's retained.
+func (b *Broker) route(p *packets.PublishPacket) []delivery {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if p.Retain {
+		if len(p.Payload) == 0 {
+			delete(b.retained, p.TopicName)
+		} else {
+			r := p.Copy()
+			r.Qos = p.Qos
+			b.retained[p.TopicName] = r
+		}
+	}
+
+	var deliveries []delivery
+	for _, s := range b.sessions {
+		qos := -1
+		for filter, q := range s.subscriptions {
+			if int(q) > qos && topicMatches(filter, p.TopicName) {
+				qos = int(q)
+			}
+		}
+		if qos < 0 {
+			continue
+		}
+
+		if s.conn == nil {
+			if !s.clean && qos > 0 && p.Qos > 0 && len(s.queue) < maxQueued {
+				out := p.Copy()
+				out.Qos = 1
+				s.queue = append(s.queue, out)
+			}
+			continue
+		}
+
+		deliveries = append(deliveries, delivery{s.conn, s.outgoing(p, byte(qos))})
+	}
+
+	return deliveries
+}
+
+// outgoing returns the copy of a message to be sent to the session at the lowest of both QoS levels, tracking it until acked.
+func (s *brokerSession) outgoing(p *packets.PublishPacket, granted byte) *packets.PublishPacket {
+	out := p.Copy()
+	out.Retain = false
+	out.Qos = p.Qos
+	if out.Qos > granted {
+		out.Qos = granted
+	}
+
+	if out.Qos > 0 {
+		out.Qos = 1
+		out.MessageID = s.messageID()
+		s.inflight[out.MessageID] = out
+	}
+	return out
+}
+
+// messageID returns the next free message ID of the session.
+func (s *brokerSession) messageID() uint16 {
+	for {
+		s.nextID++
+		if _, used := s.inflight[s.nextID]; s.nextID != 0 && !used {
+			return s.nextID
+		}
+	}
+}
+
+func deliver(deliveries []delivery) error {
+	for _, d := range deliveries {
+		if err := d.conn.write(d.packet); err != nil {
+			log.Debugf("broker: write error to %s: %s", d.conn.RemoteAddr(), err)
+			d.conn.Close()
+			return err
+		}
+	}
+	return nil
+}
+
+// validTopicFilter checks that multi-level wildcards are only used as the last level and single level ones take a whole level.
+func validTopicFilter(filter string) bool {
+	if filter == "" {
+		return false
+	}
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || i != len(levels)-1) {
+			return false
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return false
+		}
+	}
+	return true
+}
+
+// topicMatches checks whether a topic matches a subscription filter. Topics starting with $ aren't matched by leading wildcards.
+func topicMatches(filter, topic string) bool {
+	if strings.HasPrefix(topic, "$") && (strings.HasPrefix(filter, "+") || strings.HasPrefix(filter, "#")) {
+		return false
+	}
+
+	f := strings.Split(filter, "/")
+	t := strings.Split(topic, "/")
+	for i, level := range f {
+		if level == "#" {
+			return true
+		}
+		if i >= len(t) {
+			return false
+		}
+		if level != "+" && level != t[i] {
+			return false
+		}
+	}
+	return len(f) == len(t)
+}
@@ -0,0 +1,244 @@
+package lds
+
+import (
+	"testing"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+)
+
+func TestTopicMatches(t *testing.T) {
+	tests := []struct {
+		filter string
+		topic  string
+		match  bool
+	}{
+		{"gateway/0102030405060708/event/up", "gateway/0102030405060708/event/up", true},
+		{"gateway/+/event/up", "gateway/0102030405060708/event/up", true},
+		{"gateway/+/event/up", "gateway/0102030405060708/event/stats", false},
+		{"gateway/#", "gateway/0102030405060708/event/up", true},
+		{"gateway/#", "gateway", true},
+		{"#", "gateway/0102030405060708/event/up", true},
+		{"gateway/+", "gateway/0102030405060708/event/up", false},
+		{"gateway/0102030405060708/event/up/+", "gateway/0102030405060708/event/up", false},
+		{"+/+", "/up", true},
+		{"#", "$SYS/broker/clients", false},
+		{"+/broker/clients", "$SYS/broker/clients", false},
+		{"$SYS/#", "$SYS/broker/clients", true},
+	}
+
+	for _, tt := range tests {
+		if match := topicMatches(tt.filter, tt.topic); match != tt.match {
+			t.Errorf("%s on %s: got %t, expected %t", tt.filter, tt.topic, match, tt.match)
+		}
+	}
+}
+
+func TestValidTopicFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		valid  bool
+	}{
+		{"gateway/+/event/up", true},
+		{"gateway/#", true},
+		{"#", true},
+		{"+", true},
+		{"", false},
+		{"gateway/#/up", false},
+		{"gateway/event#", false},
+		{"gateway/event+/up", false},
+	}
+
+	for _, tt := range tests {
+		if valid := validTopicFilter(tt.filter); valid != tt.valid {
+			t.Errorf("%q: got %t, expected %t", tt.filter, valid, tt.valid)
+		}
+	}
+}
+
+// brokerClient connects an MQTT client to the broker, with the options changed by set if not nil.
+func brokerClient(t *testing.T, b *Broker, clientID string, set func(*MQTT.ClientOptions)) MQTT.Client {
+	opts := MQTT.NewClientOptions().AddBroker("tcp://" + b.Addr()).SetClientID(clientID).SetAutoReconnect(false)
+	if set != nil {
+		set(opts)
+	}
+	client := MQTT.NewClient(opts)
+	if token := client.Connect(); !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Fatalf("couldn't connect %s: %v", clientID, token.Error())
+	}
+	return client
+}
+
+// subscribe subscribes the client to filter, returning the channel its messages are sent to.
+func subscribe(t *testing.T, client MQTT.Client, filter string, qos byte) chan MQTT.Message {
+	messages := make(chan MQTT.Message, 10)
+	token := client.Subscribe(filter, qos, func(_ MQTT.Client, m MQTT.Message) { messages <- m })
+	if !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Fatalf("couldn't subscribe to %s: %v", filter, token.Error())
+	}
+	return messages
+}
+
+func mqttPublish(t *testing.T, client MQTT.Client, topic string, qos byte, retained bool, payload string) {
+	if token := client.Publish(topic, qos, retained, []byte(payload)); !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Fatalf("couldn't publish to %s: %v", topic, token.Error())
+	}
+}
+
+func receive(t *testing.T, messages chan MQTT.Message, topic, payload string) MQTT.Message {
+	select {
+	case m := <-messages:
+		if m.Topic() != topic || string(m.Payload()) != payload {
+			t.Errorf("got %q on %s, expected %q on %s", m.Payload(), m.Topic(), payload, topic)
+		}
+		return m
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no message received on %s", topic)
+		return nil
+	}
+}
+
+func noMessage(t *testing.T, messages chan MQTT.Message) {
+	select {
+	case m := <-messages:
+		t.Errorf("got unexpected %q on %s", m.Payload(), m.Topic())
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// waitDisconnected waits until the broker handled the disconnection of the client.
+func waitDisconnected(t *testing.T, b *Broker, clientID string) {
+	for i := 0; i < 100; i++ {
+		connected := false
+		for _, c := range b.Clients() {
+			connected = connected || (c.ID == clientID && c.Connected)
+		}
+		if !connected {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("client %s is still connected", clientID)
+}
+
+func newTestBroker(t *testing.T) *Broker {
+	b, err := NewBroker("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestBrokerPublish(t *testing.T) {
+	b := newTestBroker(t)
+	defer b.Close()
+
+	sub := brokerClient(t, b, "sub", nil)
+	defer sub.Disconnect(0)
+	pub := brokerClient(t, b, "pub", nil)
+	defer pub.Disconnect(0)
+
+	up := subscribe(t, sub, "gateway/+/event/up", 1)
+	all := subscribe(t, sub, "gateway/#", 0)
+
+	for _, qos := range []byte{0, 1, 2} {
+		mqttPublish(t, pub, "gateway/0102030405060708/event/up", qos, false, "up")
+		if m := receive(t, up, "gateway/0102030405060708/event/up", "up"); m.Qos() > 1 || (qos > 0) != (m.Qos() == 1) {
+			t.Errorf("got a QoS %d publish at QoS %d", qos, m.Qos())
+		}
+		receive(t, all, "gateway/0102030405060708/event/up", "up")
+	}
+
+	mqttPublish(t, pub, "gateway/0102030405060708/event/stats", 0, false, "stats")
+	receive(t, all, "gateway/0102030405060708/event/stats", "stats")
+	noMessage(t, up)
+}
+
+func TestBrokerRetained(t *testing.T) {
+	b := newTestBroker(t)
+	defer b.Close()
+
+	pub := brokerClient(t, b, "pub", nil)
+	defer pub.Disconnect(0)
+	mqttPublish(t, pub, "gateway/0102030405060708/state/conn", 1, true, "ONLINE")
+	mqttPublish(t, pub, "gateway/0102030405060709/state/conn", 1, true, "ONLINE")
+	//An empty retained message clears the retained one.
+	mqttPublish(t, pub, "gateway/0102030405060709/state/conn", 1, true, "")
+
+	sub := brokerClient(t, b, "sub", nil)
+	defer sub.Disconnect(0)
+	state := subscribe(t, sub, "gateway/+/state/conn", 1)
+	if m := receive(t, state, "gateway/0102030405060708/state/conn", "ONLINE"); !m.Retained() {
+		t.Error("the retained message wasn't flagged as retained")
+	}
+	noMessage(t, state)
+}
+
+func TestBrokerWill(t *testing.T) {
+	b := newTestBroker(t)
+	defer b.Close()
+
+	sub := brokerClient(t, b, "sub", nil)
+	defer sub.Disconnect(0)
+	state := subscribe(t, sub, "gateway/+/state/conn", 1)
+
+	setWill := func(opts *MQTT.ClientOptions) {
+		opts.SetWill("gateway/0102030405060708/state/conn", "OFFLINE", 1, true)
+	}
+
+	//No will on a graceful disconnection.
+	graceful := brokerClient(t, b, "graceful", setWill)
+	graceful.Disconnect(100)
+	noMessage(t, state)
+
+	//The will is published when the connection is lost.
+	//The client isn't disconnected afterwards, as paho blocks disconnecting a client that lost its connection.
+	brokerClient(t, b, "lost", setWill)
+	b.mu.Lock()
+	conn := b.sessions["lost"].conn
+	b.mu.Unlock()
+	conn.Close()
+	receive(t, state, "gateway/0102030405060708/state/conn", "OFFLINE")
+}
+
+func TestBrokerPersistentSession(t *testing.T) {
+	b := newTestBroker(t)
+	defer b.Close()
+
+	persistent := func(opts *MQTT.ClientOptions) { opts.SetCleanSession(false) }
+	sub := brokerClient(t, b, "ns", persistent)
+	subscribe(t, sub, "gateway/+/event/up", 1)
+	sub.Disconnect(100)
+	waitDisconnected(t, b, "ns")
+
+	pub := brokerClient(t, b, "pub", nil)
+	defer pub.Disconnect(0)
+	//QoS 0 publishes complete before being sent, the QoS 1 one makes sure both reached the broker.
+	mqttPublish(t, pub, "gateway/0102030405060708/event/up", 0, false, "dropped")
+	mqttPublish(t, pub, "gateway/0102030405060708/event/up", 1, false, "queued")
+
+	clients := b.Clients()
+	if len(clients) != 2 || clients[0].ID != "ns" || clients[0].Connected || !clients[0].Persistent || clients[0].Queued != 1 {
+		t.Fatalf("got clients %+v, expected the disconnected ns session with a queued message", clients)
+	}
+	if len(clients[0].Subscriptions) != 1 || clients[0].Subscriptions[0] != "gateway/+/event/up" {
+		t.Errorf("got subscriptions %v", clients[0].Subscriptions)
+	}
+
+	//The session is resumed with its subscription, and gets the queued message.
+	up := make(chan MQTT.Message, 10)
+	sub = brokerClient(t, b, "ns", func(opts *MQTT.ClientOptions) {
+		persistent(opts)
+		opts.SetDefaultPublishHandler(func(_ MQTT.Client, m MQTT.Message) { up <- m })
+	})
+	defer sub.Disconnect(0)
+	receive(t, up, "gateway/0102030405060708/event/up", "queued")
+	noMessage(t, up)
+
+	//A clean session is forgotten on disconnection.
+	pub.Disconnect(100)
+	waitDisconnected(t, b, "pub")
+	if clients := b.Clients(); len(clients) != 1 || clients[0].ID != "ns" || clients[0].Queued != 0 {
+		t.Errorf("got clients %+v, expected only the ns session", clients)
+	}
+}
@@ -0,0 +1,228 @@
+package lds
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan/airtime"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrFrameLost is returned when an uplink was transmitted but never reached the network server
+// because it collided with another frame or the gateway had no free demodulator.
+var ErrFrameLost = errors.New("frame lost on air")
+
+// keepOnAir is how long finished frames are kept around so that they may be checked against
+// frames that started while they were still on air.
+const keepOnAir = 10 * time.Second
+
+// ChannelStats holds the outcome counters of the channel simulator.
+type ChannelStats struct {
+	Received uint64
+	Collided uint64
+	Dropped  uint64
+}
+
+// ChannelSimulator models the air between simulated devices and gateways.
+// Uplinks on the same gateway, frequency and spreading factor that overlap in time collide,
+// unless one of them is stronger than every other one by at least CaptureThreshold dB (capture effect).
+// Each gateway may only demodulate Demodulators frames at the same time, extra frames are dropped.
+type ChannelSimulator struct {
+	CaptureThreshold float64
+	Demodulators     int
+
+	mu     sync.Mutex
+	frames []*airFrame
+	stats  ChannelStats
+}
+
+type airFrame struct {
+	gateway     string
+	frequency   uint32
+	sf          uint32
+	rssi        int32
+	start       time.Time
+	end         time.Time
+	demodulated bool
+}
+
+var (
+	channelSim   *ChannelSimulator
+	channelSimMu sync.Mutex
+)
+
+// SetChannelSimulator enables the channel simulator for every uplink or updates its settings if already enabled.
+func SetChannelSimulator(captureThreshold float64, demodulators int) {
+	channelSimMu.Lock()
+	defer channelSimMu.Unlock()
+
+	if channelSim == nil {
+		channelSim = &ChannelSimulator{}
+		log.Infof("channel simulator enabled (capture threshold: %.1f dB, demodulators: %d)", captureThreshold, demodulators)
+	}
+
+	channelSim.mu.Lock()
+	channelSim.CaptureThreshold = captureThreshold
+	channelSim.Demodulators = demodulators
+	channelSim.mu.Unlock()
+}
+
+// StopChannelSimulator disables the channel simulator so that every uplink reaches the network server again.
+func StopChannelSimulator() {
+	channelSimMu.Lock()
+	defer channelSimMu.Unlock()
+
+	if channelSim != nil {
+		log.Infoln("channel simulator disabled")
+	}
+	channelSim = nil
+}
+
+// GetChannelStats returns the counters of the channel simulator, or zero values when it's disabled.
+func GetChannelStats() ChannelStats {
+	c := getChannelSimulator()
+	if c == nil {
+		return ChannelStats{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func getChannelSimulator() *ChannelSimulator {
+	channelSimMu.Lock()
+	defer channelSimMu.Unlock()
+	return channelSim
+}
+
+// transmit puts a frame of the given size on air and blocks until the gateway has finished receiving it.
+// It returns ErrFrameLost when the frame collided or found no free demodulator. A nil simulator lets every frame through.
+func (c *ChannelSimulator) transmit(gwMAC string, rxInfo *gw.UplinkRXInfo, txInfo *gw.UplinkTXInfo, size int) error {
+	if c == nil {
+		return nil
+	}
+
+	toa, err := TimeOnAir(txInfo, size)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	f := &airFrame{
+		gateway:   gwMAC,
+		frequency: txInfo.GetFrequency(),
+		sf:        txInfo.GetLoraModulationInfo().GetSpreadingFactor(),
+		rssi:      rxInfo.GetRssi(),
+		start:     now,
+		end:       now.Add(toa),
+	}
+
+	c.mu.Lock()
+	//Forget frames that can't overlap with anything still on air.
+	frames := c.frames[:0]
+	for _, o := range c.frames {
+		if now.Sub(o.end) < keepOnAir {
+			frames = append(frames, o)
+		}
+	}
+	c.frames = frames
+
+	busy := 0
+	for _, o := range c.frames {
+		if o.gateway == f.gateway && o.demodulated && o.end.After(now) {
+			busy++
+		}
+	}
+	f.demodulated = c.Demodulators <= 0 || busy < c.Demodulators
+	c.frames = append(c.frames, f)
+	c.mu.Unlock()
+
+	log.Debugf("frame on air for %s (freq: %d, SF: %d)", toa, f.frequency, f.sf)
+	time.Sleep(toa)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !f.demodulated {
+		c.stats.Dropped++
+		log.Warningf("gateway %s has no free demodulator, frame dropped", gwMAC)
+		return errors.Wrap(ErrFrameLost, "no free demodulator")
+	}
+
+	for _, o := range c.frames {
+		if o == f || o.gateway != f.gateway || o.frequency != f.frequency || o.sf != f.sf {
+			continue
+		}
+
+		if !o.start.Before(f.end) || !f.start.Before(o.end) {
+			continue
+		}
+
+		if float64(f.rssi-o.rssi) < c.CaptureThreshold {
+			c.stats.Collided++
+			log.Warningf("frame collided on %d Hz SF%d (rssi %d dBm vs %d dBm)", f.frequency, f.sf, f.rssi, o.rssi)
+			return errors.Wrap(ErrFrameLost, "collision")
+		}
+	}
+
+	c.stats.Received++
+	return nil
+}
+
+// TimeOnAir returns the duration of a frame of the given size (PHYPayload bytes) sent with the given tx info.
+func TimeOnAir(txInfo *gw.UplinkTXInfo, size int) (time.Duration, error) {
+	if fsk := txInfo.GetFskModulationInfo(); fsk != nil {
+		if fsk.GetBitrate() == 0 {
+			return 0, errors.New("fsk bitrate must be greater than zero")
+		}
+		//Preamble (5), sync word (3), length (1), payload and CRC (2).
+		bits := (5 + 3 + 1 + size + 2) * 8
+		return time.Duration(bits) * time.Second / time.Duration(fsk.GetBitrate()), nil
+	}
+
+	lora := txInfo.GetLoraModulationInfo()
+	if lora == nil {
+		return 0, errors.New("missing modulation info")
+	}
+
+	sf := int(lora.GetSpreadingFactor())
+	bw := int(lora.GetBandwidth())
+	if sf == 0 || bw == 0 {
+		return 0, fmt.Errorf("invalid lora modulation (SF%d, BW%d)", sf, bw)
+	}
+
+	cr, err := parseCodingRate(lora.GetCodeRate())
+	if err != nil {
+		return 0, err
+	}
+
+	//Low data rate optimization is mandated for symbols longer than 16ms.
+	ldro := airtime.CalculateLoRaSymbolDuration(sf, bw) >= 16*time.Millisecond
+
+	return airtime.CalculateLoRaAirtime(size, sf, bw, 8, cr, true, ldro)
+}
+
+// parseCodingRate converts a "4/x" code rate to its airtime representation, defaulting to 4/5.
+func parseCodingRate(codeRate string) (airtime.CodingRate, error) {
+	if codeRate == "" {
+		return airtime.CodingRate45, nil
+	}
+
+	parts := strings.Split(codeRate, "/")
+	if len(parts) != 2 || parts[0] != "4" {
+		return 0, fmt.Errorf("invalid code rate %s", codeRate)
+	}
+
+	d, err := strconv.Atoi(parts[1])
+	if err != nil || d < 5 || d > 8 {
+		return 0, fmt.Errorf("invalid code rate %s", codeRate)
+	}
+
+	return airtime.CodingRate(d - 4), nil
+}
@@ -0,0 +1,166 @@
+package lds
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/common"
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan/airtime"
+	"github.com/pkg/errors"
+)
+
+func loraTXInfo(frequency, sf uint32) *gw.UplinkTXInfo {
+	return &gw.UplinkTXInfo{
+		Frequency:  frequency,
+		Modulation: common.Modulation_LORA,
+		ModulationInfo: &gw.UplinkTXInfo_LoraModulationInfo{
+			LoraModulationInfo: &gw.LoRaModulationInfo{Bandwidth: 125, SpreadingFactor: sf, CodeRate: "4/5"},
+		},
+	}
+}
+
+func TestTimeOnAir(t *testing.T) {
+	tests := []struct {
+		name   string
+		txInfo *gw.UplinkTXInfo
+		size   int
+		toa    time.Duration
+		err    bool
+	}{
+		{"SF7", loraTXInfo(868100000, 7), 13, 46336 * time.Microsecond, false},
+		{"SF12 with low data rate optimization", loraTXInfo(868100000, 12), 13, 1155072 * time.Microsecond, false},
+		{"FSK", &gw.UplinkTXInfo{
+			Modulation:     common.Modulation_FSK,
+			ModulationInfo: &gw.UplinkTXInfo_FskModulationInfo{FskModulationInfo: &gw.FSKModulationInfo{Bitrate: 50000}},
+		}, 13, 3840 * time.Microsecond, false},
+		{"FSK without bitrate", &gw.UplinkTXInfo{
+			ModulationInfo: &gw.UplinkTXInfo_FskModulationInfo{FskModulationInfo: &gw.FSKModulationInfo{}},
+		}, 13, 0, true},
+		{"no modulation", &gw.UplinkTXInfo{}, 13, 0, true},
+		{"no spreading factor", loraTXInfo(868100000, 0), 13, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toa, err := TimeOnAir(tt.txInfo, tt.size)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if toa != tt.toa {
+				t.Errorf("got %s, expected %s", toa, tt.toa)
+			}
+		})
+	}
+}
+
+func TestParseCodingRate(t *testing.T) {
+	tests := []struct {
+		codeRate string
+		cr       airtime.CodingRate
+		err      bool
+	}{
+		{"", airtime.CodingRate45, false},
+		{"4/5", airtime.CodingRate45, false},
+		{"4/8", airtime.CodingRate48, false},
+		{"4/9", 0, true},
+		{"3/5", 0, true},
+		{"4-5", 0, true},
+	}
+
+	for _, tt := range tests {
+		cr, err := parseCodingRate(tt.codeRate)
+		if (err != nil) != tt.err {
+			t.Errorf("%q: got error %v, expected one: %t", tt.codeRate, err, tt.err)
+			continue
+		}
+		if cr != tt.cr {
+			t.Errorf("%q: got %d, expected %d", tt.codeRate, cr, tt.cr)
+		}
+	}
+}
+
+// airUplink is a frame transmitted by TestChannelSimulator, started after the previous one.
+type airUplink struct {
+	gateway   string
+	frequency uint32
+	rssi      int32
+}
+
+func TestChannelSimulator(t *testing.T) {
+	tests := []struct {
+		name         string
+		demodulators int
+		uplinks      []airUplink
+		lost         []bool
+		stats        ChannelStats
+	}{
+		{
+			name:    "same channel and power collide",
+			uplinks: []airUplink{{"gw1", 868100000, -80}, {"gw1", 868100000, -80}},
+			lost:    []bool{true, true},
+			stats:   ChannelStats{Collided: 2},
+		},
+		{
+			name:    "the stronger frame is captured",
+			uplinks: []airUplink{{"gw1", 868100000, -70}, {"gw1", 868100000, -80}},
+			lost:    []bool{false, true},
+			stats:   ChannelStats{Received: 1, Collided: 1},
+		},
+		{
+			name:    "other frequencies don't collide",
+			uplinks: []airUplink{{"gw1", 868100000, -80}, {"gw1", 868300000, -80}},
+			lost:    []bool{false, false},
+			stats:   ChannelStats{Received: 2},
+		},
+		{
+			name:    "other gateways don't collide",
+			uplinks: []airUplink{{"gw1", 868100000, -80}, {"gw2", 868100000, -80}},
+			lost:    []bool{false, false},
+			stats:   ChannelStats{Received: 2},
+		},
+		{
+			name:         "no free demodulator",
+			demodulators: 1,
+			uplinks:      []airUplink{{"gw1", 868100000, -80}, {"gw1", 868300000, -80}},
+			lost:         []bool{false, true},
+			stats:        ChannelStats{Received: 1, Dropped: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ChannelSimulator{CaptureThreshold: DefaultCaptureThreshold, Demodulators: tt.demodulators}
+
+			errs := make([]error, len(tt.uplinks))
+			var wg sync.WaitGroup
+			for i, up := range tt.uplinks {
+				wg.Add(1)
+				go func(i int, up airUplink) {
+					defer wg.Done()
+					errs[i] = c.transmit(up.gateway, &gw.UplinkRXInfo{Rssi: up.rssi}, loraTXInfo(up.frequency, 7), 13)
+				}(i, up)
+				//Let the frame get on air before the next one, which overlaps with it.
+				time.Sleep(5 * time.Millisecond)
+			}
+			wg.Wait()
+
+			for i, err := range errs {
+				if lost := errors.Cause(err) == ErrFrameLost; lost != tt.lost[i] {
+					t.Errorf("uplink %d: got error %v, expected lost: %t", i, err, tt.lost[i])
+				}
+			}
+			if c.stats != tt.stats {
+				t.Errorf("got stats %+v, expected %+v", c.stats, tt.stats)
+			}
+		})
+	}
+}
+
+func TestNilChannelSimulator(t *testing.T) {
+	var c *ChannelSimulator
+	if err := c.transmit("gw1", &gw.UplinkRXInfo{}, loraTXInfo(868100000, 7), 13); err != nil {
+		t.Errorf("a disabled channel simulator dropped a frame: %s", err)
+	}
+}
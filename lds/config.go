@@ -0,0 +1,562 @@
+package lds
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/band"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+)
+
+// MQTT transports.
+const (
+	ChirpStackTransport = "chirpstack"
+	TTNTransport        = "ttn"
+)
+
+// Configuration defaults.
+const (
+	DefaultAPIBind     = ":8080"
+	DefaultGRPCBind    = ":8081"
+	DefaultMetricsBind = ":9110"
+	DefaultJournalPath = "lds.jsonl"
+	// DefaultJournalMaxSize is in megabytes.
+	DefaultJournalMaxSize    = 10
+	DefaultJournalMaxBackups = 3
+	DefaultPcapUDPFile       = "lds-udp.pcap"
+	DefaultPcapLoRaTapFile   = "lds-loratap.pcap"
+	// DefaultBrokerBind is the standard MQTT port on every interface, so that a network server may connect too.
+	DefaultBrokerBind       = ":1883"
+	DefaultCaptureThreshold = 6.0
+	DefaultDemodulators     = 8
+	DefaultMaxExecTime      = 100
+	// DefaultNSBind is the packet forwarder port, where the simulated gateway connects to the embedded network server.
+	DefaultNSBind      = ":1700"
+	DefaultNSNetID     = "000000"
+	DefaultNSJoinNonce = 1
+	DefaultNSRX1Delay  = 1
+	// DefaultJSBind is the port ChirpStack serves its join server on.
+	DefaultJSBind      = ":8003"
+	DefaultJSJoinNonce = 1
+	// DefaultAckErrorStatus is forced when ack_error_items is set without a status.
+	DefaultAckErrorStatus = "TOO_LATE"
+)
+
+// Config is the simulator configuration, shared by the GUI and the lds command and stored as toml.
+type Config struct {
+	MQTT          MQTTConfig          `toml:"mqtt"`
+	Forwarder     ForwarderConfig     `toml:"forwarder"`
+	Broker        BrokerConfig        `toml:"broker"`
+	NetworkServer NetworkServerConfig `toml:"network_server"`
+	JoinServer    JoinServerConfig    `toml:"join_server"`
+	API           APIConfig           `toml:"api"`
+	GRPC          GRPCConfig          `toml:"grpc"`
+	Metrics       MetricsConfig       `toml:"metrics"`
+	Journal       JournalConfig       `toml:"journal"`
+	Pcap          PcapConfig          `toml:"pcap"`
+	Band          BandConfig          `toml:"band"`
+	Device        DeviceConfig        `toml:"device"`
+	GW            GatewayConfig       `toml:"gateway"`
+	DR            DataRateConfig      `toml:"data_rate"`
+	RXInfo        RXInfoConfig        `toml:"rx_info"`
+	Channel       ChannelConfig       `toml:"channel"`
+	RawPayload    RawPayloadConfig    `toml:"raw_payload"`
+	EncodedType   []*EncodedType      `toml:"encoded_type"`
+	LogLevel      string              `toml:"log_level"`
+	RedisConf     RedisConfig         `toml:"redis"`
+	Provisioner   ProvisionerConfig   `toml:"provisioner"`
+}
+
+// MQTTConfig holds the MQTT connection options.
+type MQTTConfig struct {
+	//Transport is either "chirpstack" (the default) or "ttn" for The Things Stack gateway MQTT API.
+	Transport     string `toml:"transport"`
+	GatewayID     string `toml:"gateway_id"`
+	Server        string `toml:"server"`
+	User          string `toml:"user"`
+	Password      string `toml:"password"`
+	DownlinkTopic string `toml:"downlink_topic"`
+	UplinkTopic   string `toml:"uplink_topic"`
+	StatsTopic    string `toml:"stats_topic"`
+	ConfigTopic   string `toml:"config_topic"`
+	//Session options. A random client ID is used when none is set.
+	ClientID     string `toml:"client_id"`
+	QoS          int    `toml:"qos"`
+	CleanSession bool   `toml:"clean_session"`
+	//TLS options, paths point to PEM encoded files.
+	CACert             string   `toml:"ca_cert"`
+	TLSCert            string   `toml:"tls_cert"`
+	TLSKey             string   `toml:"tls_key"`
+	InsecureSkipVerify bool     `toml:"insecure_skip_verify"`
+	ALPN               []string `toml:"alpn"`
+	//Retained gateway connection state, with an OFFLINE last will.
+	StateTopic string `toml:"state_topic"`
+	//TX ack events and the policy for the statuses they carry.
+	AckTopic         string `toml:"ack_topic"`
+	AckErrorStatus   string `toml:"ack_error_status"`
+	AckErrorItems    int    `toml:"ack_error_items"`
+	AckRejectInvalid bool   `toml:"ack_reject_invalid"`
+}
+
+// ForwarderConfig holds the network server address for the packet forwarder UDP transport.
+type ForwarderConfig struct {
+	Server string `toml:"nserver"`
+	Port   string `toml:"nsport"`
+}
+
+// APIConfig holds the HTTP API options.
+type APIConfig struct {
+	//Enabled starts the HTTP API with the simulator.
+	Enabled bool   `toml:"enabled"`
+	Bind    string `toml:"bind"`
+}
+
+// GRPCConfig holds the gRPC API options.
+type GRPCConfig struct {
+	//Enabled starts the gRPC API with the simulator.
+	Enabled bool   `toml:"enabled"`
+	Bind    string `toml:"bind"`
+}
+
+// MetricsConfig holds the Prometheus metrics options. The HTTP API serves them as well.
+type MetricsConfig struct {
+	//Enabled serves the metrics with the simulator.
+	Enabled bool   `toml:"enabled"`
+	Bind    string `toml:"bind"`
+}
+
+// JournalConfig holds the frame journal options.
+type JournalConfig struct {
+	//Enabled records the frames of the device.
+	Enabled bool   `toml:"enabled"`
+	Path    string `toml:"path"`
+	//MaxSize is the size in megabytes at which the journal is rotated, keeping MaxBackups rotated files.
+	MaxSize    int `toml:"max_size"`
+	MaxBackups int `toml:"max_backups"`
+}
+
+// PcapConfig holds the traffic capture options.
+type PcapConfig struct {
+	//Enabled writes the capture files, which are overwritten. An empty file skips that capture.
+	Enabled bool `toml:"enabled"`
+	//UDPFile gets the packet forwarder datagrams, synthesized for MQTT traffic, and LoRaTapFile the LoRaWAN frames.
+	UDPFile     string `toml:"udp_file"`
+	LoRaTapFile string `toml:"loratap_file"`
+}
+
+// BrokerConfig holds the embedded MQTT broker options.
+type BrokerConfig struct {
+	//Enabled starts the embedded broker before connecting to MQTT.
+	Enabled bool   `toml:"enabled"`
+	Bind    string `toml:"bind"`
+}
+
+// NetworkServerConfig holds the embedded network server options.
+type NetworkServerConfig struct {
+	//Enabled starts the network server before connecting the gateway.
+	Enabled bool `toml:"enabled"`
+	//Transport is "udp", listening on Bind for the packet forwarder, or "mqtt", connecting to the MQTT server.
+	Transport string `toml:"transport"`
+	Bind      string `toml:"bind"`
+	NetID     string `toml:"net_id"`
+	//JoinNonce is the one of the first join accept, it's incremented on every join.
+	JoinNonce uint32 `toml:"join_nonce"`
+	//DevAddr is given to the joining devices, a random one within the NetID is when empty.
+	DevAddr string `toml:"dev_addr"`
+	//RX1Delay is in seconds.
+	RX1Delay    int `toml:"rx1_delay"`
+	RX1DROffset int `toml:"rx1_dr_offset"`
+	//CFList are up to five channel frequencies in Hz added to the join accept, for bands with a channel CFList.
+	CFList []int `toml:"cf_list"`
+}
+
+// JoinServerConfig holds the Backend Interfaces join server options.
+type JoinServerConfig struct {
+	//Enabled serves the join server with the simulator.
+	Enabled bool   `toml:"enabled"`
+	Bind    string `toml:"bind"`
+	//JoinNonce is the one of the first join answer, it's incremented on every join.
+	JoinNonce uint32 `toml:"join_nonce"`
+	//Lifetime is the session lifetime in seconds given to the network server, 0 meaning no expiry.
+	Lifetime int `toml:"lifetime"`
+	//The KEKs are hex AES keys wrapping the network and application session keys, which are sent in clear when empty.
+	NSKEKLabel string `toml:"ns_kek_label"`
+	NSKEK      string `toml:"ns_kek"`
+	ASKEKLabel string `toml:"as_kek_label"`
+	ASKEK      string `toml:"as_kek"`
+}
+
+// BandConfig holds the LoRaWAN band.
+type BandConfig struct {
+	Name band.Name `toml:"name"`
+}
+
+// DeviceConfig holds the device keys and LoRaWAN options.
+type DeviceConfig struct {
+	DevEUI        string             `toml:"eui" json:"devEUI"`
+	DevAddress    string             `toml:"address" json:"devAddr"`
+	NwkSEncKey    string             `toml:"network_session_encription_key" json:"nwkSEncKey"`
+	SNwkSIntKey   string             `toml:"serving_network_session_integrity_key" json:"sNwkSIntKey"`    //For Lorawan 1.0 this is the same as the NwkSEncKey
+	FNwkSIntKey   string             `toml:"forwarding_network_session_integrity_key" json:"fNwkSIntKey"` //For Lorawan 1.0 this is the same as the NwkSEncKey
+	AppSKey       string             `toml:"application_session_key" json:"appSKey"`
+	Marshaler     string             `toml:"marshaler" json:"marshaler"`
+	NwkKey        string             `toml:"nwk_key" json:"nwkKey"`   //Network key, used to be called application key for Lorawan 1.0
+	AppKey        string             `toml:"app_key" json:"appKey"`   //Application key, for Lorawan 1.1
+	JoinEUI       string             `toml:"join_eui" json:"joinEUI"` //JoinEUI for 1.1. (AppEUI on 1.0)
+	Major         lorawan.Major      `toml:"-" json:"-"`
+	MACVersion    lorawan.MACVersion `toml:"mac_version" json:"macVersion"` //Lorawan MAC version
+	MType         lorawan.MType      `toml:"-" json:"-"`
+	Profile       string             `toml:"profile" json:"profile"`
+	Joined        bool               `toml:"joined" json:"joined"`
+	SkipFCntCheck bool               `toml:"skip_fcnt_check" json:"skipFCntCheck"`
+}
+
+// GatewayConfig holds the simulated gateway options.
+type GatewayConfig struct {
+	MAC           string `toml:"mac"`
+	BridgeVersion string `toml:"bridge_version"`
+}
+
+// DataRateConfig holds the uplink data rate.
+type DataRateConfig struct {
+	Bandwidth    int `toml:"bandwith"`
+	SpreadFactor int `toml:"spread_factor"`
+	BitRate      int `toml:"bit_rate"`
+}
+
+// RXInfoConfig holds the reception metadata of the uplinks.
+type RXInfoConfig struct {
+	Channel   int     `toml:"channel"`
+	CodeRate  string  `toml:"code_rate"`
+	CrcStatus int     `toml:"crc_status"`
+	Frequency int     `toml:"frequency"`
+	LoRaSNR   float64 `toml:"lora_snr"`
+	RfChain   int     `toml:"rf_chain"`
+	Rssi      int     `toml:"rssi"`
+}
+
+// ChannelConfig holds the channel simulator options.
+type ChannelConfig struct {
+	Enabled          bool    `toml:"enabled"`
+	CaptureThreshold float64 `toml:"capture_threshold"`
+	Demodulators     int     `toml:"demodulators"`
+}
+
+// RawPayloadConfig holds optional raw bytes payload (hex encoded) and the JS encoder.
+type RawPayloadConfig struct {
+	Payload     string `toml:"payload"`
+	UseRaw      bool   `toml:"use_raw"`
+	Script      string `toml:"script"`
+	UseEncoder  bool   `toml:"use_encoder"`
+	MaxExecTime int    `toml:"max_exec_time"`
+	Obj         string `toml:"js_object"`
+	FPort       int    `toml:"fport"`
+}
+
+// EncodedType is a generated value appended to the payload when neither raw bytes nor the encoder are used.
+type EncodedType struct {
+	Name     string  `toml:"name"`
+	Value    float64 `toml:"value"`
+	MaxValue float64 `toml:"max_value"`
+	MinValue float64 `toml:"min_value"`
+	IsFloat  bool    `toml:"is_float"`
+	NumBytes int     `toml:"num_bytes"`
+}
+
+// RedisConfig holds the Redis connection options.
+type RedisConfig struct {
+	Addr     string `toml:"addr"`
+	Password string `toml:"password"`
+	DB       int    `toml:"db"`
+}
+
+// ProvisionerConfig holds the ChirpStack provisioner options.
+type ProvisionerConfig struct {
+	Hostname string `toml:"hostname"`
+	Username string `toml:"username"`
+	Password string `toml:"password"`
+	Path     string `toml:"path"`
+}
+
+// Topics holds the topic templates of the selected transport, %s is replaced with the gateway MAC.
+type Topics struct {
+	Uplink   string
+	Downlink string
+	Stats    string
+	Ack      string
+	State    string
+	Config   string
+}
+
+// NewConfig returns a configuration with the defaults for options that aren't zero values.
+func NewConfig() *Config {
+	return &Config{
+		MQTT:   MQTTConfig{CleanSession: true},
+		Broker: BrokerConfig{Bind: DefaultBrokerBind},
+		NetworkServer: NetworkServerConfig{
+			Transport: NSTransportUDP,
+			Bind:      DefaultNSBind,
+			NetID:     DefaultNSNetID,
+			JoinNonce: DefaultNSJoinNonce,
+			RX1Delay:  DefaultNSRX1Delay,
+		},
+		JoinServer:  JoinServerConfig{Bind: DefaultJSBind, JoinNonce: DefaultJSJoinNonce},
+		API:         APIConfig{Bind: DefaultAPIBind},
+		GRPC:        GRPCConfig{Bind: DefaultGRPCBind},
+		Metrics:     MetricsConfig{Bind: DefaultMetricsBind},
+		Journal:     JournalConfig{Path: DefaultJournalPath, MaxSize: DefaultJournalMaxSize, MaxBackups: DefaultJournalMaxBackups},
+		Pcap:        PcapConfig{UDPFile: DefaultPcapUDPFile, LoRaTapFile: DefaultPcapLoRaTapFile},
+		Device:      DeviceConfig{MType: lorawan.UnconfirmedDataUp},
+		Channel:     ChannelConfig{CaptureThreshold: DefaultCaptureThreshold, Demodulators: DefaultDemodulators},
+		RawPayload:  RawPayloadConfig{MaxExecTime: DefaultMaxExecTime},
+		EncodedType: []*EncodedType{},
+	}
+}
+
+// Load decodes the given toml file over the current values.
+func (c *Config) Load(filename string) error {
+	if _, err := toml.DecodeFile(filename, c); err != nil {
+		return err
+	}
+	if _, err := ParseAckErrorStatus(c.MQTT.AckErrorStatus); err != nil {
+		return err
+	}
+
+	//Set default script when it's not present.
+	if c.RawPayload.Script == "" {
+		c.RawPayload.Script = DefaultScript
+	}
+	return nil
+}
+
+// Topics returns the topic templates of the selected transport. The Things Stack topics are fixed and identify the gateway by its ID,
+// and it has no connection state nor configuration topics. The v2 bridge had no connection state either, so the state topic is
+// ignored with v2_json.
+func (c *Config) Topics() Topics {
+	if c.MQTT.Transport == TTNTransport {
+		prefix := "v3/" + c.MQTT.GatewayID + "/"
+		return Topics{
+			Uplink:   prefix + "up",
+			Downlink: prefix + "down",
+			Stats:    prefix + "status",
+			Ack:      prefix + "down/ack",
+		}
+	}
+
+	topics := Topics{
+		Uplink:   c.MQTT.UplinkTopic,
+		Downlink: c.MQTT.DownlinkTopic,
+		Stats:    c.MQTT.StatsTopic,
+		Ack:      c.MQTT.AckTopic,
+		State:    c.MQTT.StateTopic,
+		Config:   c.MQTT.ConfigTopic,
+	}
+	if c.Marshaler() == "v2_json" {
+		topics.State = ""
+	}
+	return topics
+}
+
+// Marshaler returns the marshaler of the selected transport: The Things Stack only takes its own protobuf messages.
+func (c *Config) Marshaler() string {
+	if c.MQTT.Transport == TTNTransport {
+		return "ttn_protobuf"
+	}
+	return c.Device.Marshaler
+}
+
+// MarshalingDevice returns a device with the configured marshaler, used for the gateway messages that don't depend on the device
+// (e.g. the connection state).
+func (c *Config) MarshalingDevice() *Device {
+	d := &Device{}
+	d.SetMarshaler(c.Marshaler())
+	return d
+}
+
+// AckPolicy returns the configured ack policy. The error status was checked when loading the configuration.
+func (c *Config) AckPolicy() AckPolicy {
+	status, _ := ParseAckErrorStatus(c.MQTT.AckErrorStatus)
+	return AckPolicy{
+		ErrorStatus:   status,
+		ErrorItems:    c.MQTT.AckErrorItems,
+		RejectInvalid: c.MQTT.AckRejectInvalid,
+	}
+}
+
+// MQTTClientOptions returns the client options for the configured broker, session, TLS and last will.
+func (c *Config) MQTTClientOptions() (*MQTT.ClientOptions, error) {
+	tlsConfig, err := NewTLSConfig(c.MQTT.CACert, c.MQTT.TLSCert, c.MQTT.TLSKey, c.MQTT.InsecureSkipVerify, c.MQTT.ALPN)
+	if err != nil {
+		return nil, errors.Wrap(err, "tls config error")
+	}
+
+	opts := MQTT.NewClientOptions()
+	opts.AddBroker(c.MQTT.Server)
+	opts.SetUsername(c.MQTT.User)
+	opts.SetPassword(c.MQTT.Password)
+	opts.SetAutoReconnect(true)
+	//The handler is called on the first connection too, only the following ones are reconnections.
+	connected := false
+	opts.SetOnConnectHandler(func(MQTT.Client) {
+		if connected {
+			transportReconnects.WithLabelValues("mqtt").Inc()
+		}
+		connected = true
+	})
+	opts.SetCleanSession(c.MQTT.CleanSession)
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	//A fixed client ID is needed to resume a persistent session.
+	clientID := c.MQTT.ClientID
+	if clientID == "" {
+		clientID = fmt.Sprintf("lds-%d", time.Now().UnixNano())
+	}
+	opts.SetClientID(clientID)
+
+	//The broker publishes the OFFLINE state for us if the connection is lost.
+	if c.Topics().State != "" {
+		will, err := c.MarshalingDevice().ConnState(c.GW.MAC, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't set the offline last will")
+		}
+		opts.SetBinaryWill(FormatTopic(c.Topics().State, c.GW.MAC), will, byte(c.MQTT.QoS), true)
+	}
+
+	return opts, nil
+}
+
+// DataRate returns the configured uplink data rate.
+func (c *Config) DataRate() band.DataRate {
+	return band.DataRate{
+		Modulation:   band.Modulation("LORA"),
+		SpreadFactor: c.DR.SpreadFactor,
+		Bandwidth:    c.DR.Bandwidth,
+		BitRate:      c.DR.BitRate,
+	}
+}
+
+// UplinkInfo returns the rx and tx info of an uplink. Reception time fields are set by the simulated gateway once the frame is received.
+func (c *Config) UplinkInfo() (*gw.UplinkRXInfo, *gw.UplinkTXInfo, error) {
+	gwID, err := MACToGatewayID(c.GW.MAC)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "gw mac error")
+	}
+
+	urx := &gw.UplinkRXInfo{
+		GatewayId:         gwID,
+		Rssi:              int32(c.RXInfo.Rssi),
+		LoraSnr:           float64(c.RXInfo.LoRaSNR),
+		Channel:           uint32(c.RXInfo.Channel),
+		RfChain:           uint32(c.RXInfo.RfChain),
+		Board:             0,
+		Antenna:           0,
+		Location:          nil,
+		FineTimestamp:     nil,
+		FineTimestampType: gw.FineTimestampType_NONE,
+		Context:           make([]byte, 4),
+	}
+
+	utx := &gw.UplinkTXInfo{
+		Frequency: uint32(c.RXInfo.Frequency),
+		ModulationInfo: &gw.UplinkTXInfo_LoraModulationInfo{
+			LoraModulationInfo: &gw.LoRaModulationInfo{
+				Bandwidth:       uint32(c.DR.Bandwidth),
+				SpreadingFactor: uint32(c.DR.SpreadFactor),
+				CodeRate:        c.RXInfo.CodeRate,
+			},
+		},
+	}
+
+	return urx, utx, nil
+}
+
+// Payload returns the uplink payload: the raw bytes, the encoder output or the generated encoded types, in that order of preference.
+func (c *Config) Payload() ([]byte, error) {
+	if c.RawPayload.UseRaw {
+		payload, err := hex.DecodeString(c.RawPayload.Payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't decode hex payload")
+		}
+		return payload, nil
+	}
+
+	if c.RawPayload.UseEncoder {
+		payload, err := c.RawPayload.EncodeToBytes()
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't encode js object")
+		}
+		return payload, nil
+	}
+
+	payload := []byte{}
+	for _, v := range c.EncodedType {
+		if v.IsFloat {
+			payload = append(payload, GenerateFloat(float32(v.Value), float32(v.MaxValue), int32(v.NumBytes))...)
+		} else {
+			payload = append(payload, GenerateInt(int32(v.Value), int32(v.NumBytes))...)
+		}
+	}
+	return payload, nil
+}
+
+// NewDevice returns a device with the configured keys and marshaler. Session keys may be empty for OTAA devices.
+func (c *Config) NewDevice() (*Device, error) {
+	devAddr, err := HexToDevAddress(c.Device.DevAddress)
+	if err != nil {
+		return nil, errors.Wrap(err, "dev addr error")
+	}
+
+	keys := []struct {
+		name string
+		hex  string
+		key  [16]byte
+	}{
+		{name: "nwkSEncKey", hex: c.Device.NwkSEncKey},
+		{name: "sNwkSIntKey", hex: c.Device.SNwkSIntKey},
+		{name: "fNwkSIntKey", hex: c.Device.FNwkSIntKey},
+		{name: "appSKey", hex: c.Device.AppSKey},
+		{name: "appKey", hex: c.Device.AppKey},
+		{name: "nwkKey", hex: c.Device.NwkKey},
+	}
+	for i := range keys {
+		if keys[i].key, err = HexToKey(keys[i].hex); err != nil {
+			return nil, errors.Wrapf(err, "%s error", keys[i].name)
+		}
+	}
+
+	devEUI, err := HexToEUI(c.Device.DevEUI)
+	if err != nil {
+		return nil, errors.Wrap(err, "devEUI error")
+	}
+
+	joinEUI, err := HexToEUI(c.Device.JoinEUI)
+	if err != nil {
+		return nil, errors.Wrap(err, "joinEUI error")
+	}
+
+	d := &Device{
+		DevEUI:        devEUI,
+		DevAddr:       devAddr,
+		NwkSEncKey:    keys[0].key,
+		SNwkSIntKey:   keys[1].key,
+		FNwkSIntKey:   keys[2].key,
+		AppSKey:       keys[3].key,
+		AppKey:        keys[4].key,
+		NwkKey:        keys[5].key,
+		JoinEUI:       joinEUI,
+		Profile:       c.Device.Profile,
+		Major:         c.Device.Major,
+		MACVersion:    c.Device.MACVersion,
+		SkipFCntCheck: c.Device.SkipFCntCheck,
+	}
+	d.SetMarshaler(c.Marshaler())
+
+	return d, nil
+}
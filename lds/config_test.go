@@ -0,0 +1,301 @@
+package lds
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/iegomez/lds/api/gwv3"
+)
+
+// testConfig returns a configuration of a ChirpStack gateway with every topic set and the given marshaler.
+func testConfig(marshaler string) *Config {
+	c := NewConfig()
+	c.MQTT.Server = "tcp://localhost:1883"
+	c.MQTT.UplinkTopic = "gateway/%s/event/up"
+	c.MQTT.DownlinkTopic = "gateway/%s/command/down"
+	c.MQTT.StatsTopic = "gateway/%s/event/stats"
+	c.MQTT.AckTopic = "gateway/%s/event/ack"
+	c.MQTT.StateTopic = "gateway/%s/state/conn"
+	c.MQTT.ConfigTopic = "gateway/%s/command/config"
+	c.GW.MAC = "0102030405060708"
+	c.Device.Marshaler = marshaler
+	return c
+}
+
+func TestLoadExampleConfig(t *testing.T) {
+	c := NewConfig()
+	if err := c.Load("../example_conf.toml"); err != nil {
+		t.Fatal(err)
+	}
+	if c.MQTT.StateTopic == "" || c.RawPayload.Script == "" {
+		t.Errorf("got state topic %q and script %q", c.MQTT.StateTopic, c.RawPayload.Script)
+	}
+	if _, err := c.MQTTClientOptions(); err != nil {
+		t.Errorf("the example configuration has invalid client options: %s", err)
+	}
+}
+
+func TestLoadAckErrorStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lds-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name   string
+		conf   string
+		status gwv3.TxAckStatus
+		err    bool
+	}{
+		{"default", "[mqtt]\nack_error_items=1\n", gwv3.TxAckStatus_TOO_LATE, false},
+		{"set", "[mqtt]\nack_error_status=\"QUEUE_FULL\"\n", gwv3.TxAckStatus_QUEUE_FULL, false},
+		{"misspelled", "[mqtt]\nack_error_status=\"QUEUE_FUL\"\n", 0, true},
+		{"not an error", "[mqtt]\nack_error_status=\"OK\"\n", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "conf.toml")
+			if err := ioutil.WriteFile(path, []byte(tt.conf), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			c := NewConfig()
+			err := c.Load(path)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if err == nil && c.AckPolicy().ErrorStatus != tt.status {
+				t.Errorf("got %s, expected %s", c.AckPolicy().ErrorStatus, tt.status)
+			}
+		})
+	}
+}
+
+func TestConfigTopics(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    func() *Config
+		topics    Topics
+		marshaler string
+	}{
+		{
+			name:      "chirpstack",
+			config:    func() *Config { return testConfig("json") },
+			marshaler: "json",
+			topics: Topics{
+				Uplink:   "gateway/%s/event/up",
+				Downlink: "gateway/%s/command/down",
+				Stats:    "gateway/%s/event/stats",
+				Ack:      "gateway/%s/event/ack",
+				State:    "gateway/%s/state/conn",
+				Config:   "gateway/%s/command/config",
+			},
+		},
+		{
+			name:      "v2_json has no connection state",
+			config:    func() *Config { return testConfig("v2_json") },
+			marshaler: "v2_json",
+			topics: Topics{
+				Uplink:   "gateway/%s/event/up",
+				Downlink: "gateway/%s/command/down",
+				Stats:    "gateway/%s/event/stats",
+				Ack:      "gateway/%s/event/ack",
+				Config:   "gateway/%s/command/config",
+			},
+		},
+		{
+			name: "ttn",
+			config: func() *Config {
+				c := testConfig("json")
+				c.MQTT.Transport = TTNTransport
+				c.MQTT.GatewayID = "gw@tenant"
+				return c
+			},
+			marshaler: "ttn_protobuf",
+			topics: Topics{
+				Uplink:   "v3/gw@tenant/up",
+				Downlink: "v3/gw@tenant/down",
+				Stats:    "v3/gw@tenant/status",
+				Ack:      "v3/gw@tenant/down/ack",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.config()
+			if topics := c.Topics(); topics != tt.topics {
+				t.Errorf("got topics %+v, expected %+v", topics, tt.topics)
+			}
+			if marshaler := c.Marshaler(); marshaler != tt.marshaler {
+				t.Errorf("got marshaler %s, expected %s", marshaler, tt.marshaler)
+			}
+		})
+	}
+}
+
+func TestMQTTClientOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func() *Config
+		will   bool
+		err    bool
+	}{
+		{"last will", func() *Config { return testConfig("protobuf") }, true, false},
+		{"no state topic", func() *Config {
+			c := testConfig("protobuf")
+			c.MQTT.StateTopic = ""
+			return c
+		}, false, false},
+		{"v2_json with a state topic", func() *Config { return testConfig("v2_json") }, false, false},
+		{"invalid gateway MAC", func() *Config {
+			c := testConfig("protobuf")
+			c.GW.MAC = "gateway"
+			return c
+		}, false, true},
+		{"invalid TLS config", func() *Config {
+			c := testConfig("protobuf")
+			c.MQTT.CACert = "missing.pem"
+			return c
+		}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.config()
+			c.MQTT.QoS = 1
+			opts, err := c.MQTTClientOptions()
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			if opts.WillEnabled != tt.will {
+				t.Fatalf("got will enabled %t, expected %t", opts.WillEnabled, tt.will)
+			}
+			if !tt.will {
+				return
+			}
+			if opts.WillTopic != "gateway/0102030405060708/state/conn" || opts.WillQos != 1 || !opts.WillRetained {
+				t.Errorf("got will on %s with QoS %d, retained: %t", opts.WillTopic, opts.WillQos, opts.WillRetained)
+			}
+			var state gwv3.ConnState
+			if err := proto.Unmarshal(opts.WillPayload, &state); err != nil {
+				t.Fatal(err)
+			}
+			expected := &gwv3.ConnState{GatewayId: testGatewayID, State: gwv3.ConnState_OFFLINE}
+			if !proto.Equal(&state, expected) {
+				t.Errorf("got will %+v, expected %+v", &state, expected)
+			}
+		})
+	}
+}
+
+func TestMQTTClientID(t *testing.T) {
+	c := testConfig("json")
+	c.MQTT.ClientID = "lds-gateway"
+	c.MQTT.CleanSession = false
+	opts, err := c.MQTTClientOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.ClientID != "lds-gateway" || opts.CleanSession {
+		t.Errorf("got client ID %s and clean session %t", opts.ClientID, opts.CleanSession)
+	}
+
+	c.MQTT.ClientID = ""
+	if opts, err = c.MQTTClientOptions(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(opts.ClientID, "lds-") {
+		t.Errorf("got client ID %q, expected a generated one", opts.ClientID)
+	}
+}
+
+func TestConfigPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func(c *Config)
+		payload []byte
+		err     bool
+	}{
+		{"raw", func(c *Config) {
+			c.RawPayload.UseRaw = true
+			c.RawPayload.Payload = "010203"
+		}, []byte{1, 2, 3}, false},
+		{"invalid raw", func(c *Config) {
+			c.RawPayload.UseRaw = true
+			c.RawPayload.Payload = "0g"
+		}, nil, true},
+		{"encoded types", func(c *Config) {
+			c.EncodedType = []*EncodedType{{Value: 258, NumBytes: 2}, {Value: 1, NumBytes: 1}}
+		}, []byte{1, 2, 1}, false},
+		{"empty", func(c *Config) {}, []byte{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testConfig("json")
+			tt.set(c)
+			payload, err := c.Payload()
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if !bytes.Equal(payload, tt.payload) {
+				t.Errorf("got %x, expected %x", payload, tt.payload)
+			}
+		})
+	}
+}
+
+func TestConfigNewDevice(t *testing.T) {
+	valid := func(c *Config) {
+		c.Device.DevEUI = "0102030405060708"
+		c.Device.JoinEUI = "0807060504030201"
+		c.Device.DevAddress = "01020304"
+		c.Device.AppKey = "000102030405060708090a0b0c0d0e0f"
+		c.Device.Profile = "OTAA"
+	}
+
+	tests := []struct {
+		name string
+		set  func(c *Config)
+		err  bool
+	}{
+		{"valid", func(c *Config) {}, false},
+		{"invalid DevEUI", func(c *Config) { c.Device.DevEUI = "0102" }, true},
+		{"invalid DevAddr", func(c *Config) { c.Device.DevAddress = "devaddr" }, true},
+		{"invalid key", func(c *Config) { c.Device.NwkKey = "nwkkey" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testConfig("protobuf")
+			valid(c)
+			tt.set(c)
+			d, err := c.NewDevice()
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if d.DevEUI.String() != "0102030405060708" || d.JoinEUI.String() != "0807060504030201" || d.Profile != "OTAA" {
+				t.Errorf("got device %s %s %s", d.DevEUI, d.JoinEUI, d.Profile)
+			}
+			if d.AppKey[15] != 0x0f || d.marshal == nil {
+				t.Errorf("got app key %x, marshaler set: %t", d.AppKey, d.marshal != nil)
+			}
+		})
+	}
+}
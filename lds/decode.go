@@ -0,0 +1,405 @@
+package lds
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/brocaar/lorawan"
+	"github.com/iegomez/lds/lds/keys"
+	"github.com/pkg/errors"
+)
+
+// DecodeKeys are the keys and session state a frame is decoded with. Nil keys aren't known, and what needs them
+// is left encrypted or unchecked.
+type DecodeKeys struct {
+	MACVersion lorawan.MACVersion
+	//NwkSEncKey, SNwkSIntKey and FNwkSIntKey are the network session keys, all three are the NwkSKey in LoRaWAN 1.0.
+	NwkSEncKey  *lorawan.AES128Key
+	SNwkSIntKey *lorawan.AES128Key
+	FNwkSIntKey *lorawan.AES128Key
+	AppSKey     *lorawan.AES128Key
+	//NwkKey decrypts join accepts and checks join MICs, the AppKey derives the AppSKey of LoRaWAN 1.1 join accepts.
+	NwkKey *lorawan.AES128Key
+	AppKey *lorawan.AES128Key
+	//DevEUI, JoinEUI and DevNonce are those of the join request a join accept answers.
+	DevEUI   lorawan.EUI64
+	JoinEUI  lorawan.EUI64
+	DevNonce *lorawan.DevNonce
+	//FCnt is the full frame counter, the frame only carries its 16 least significant bits.
+	FCnt *uint32
+	//ConfFCnt, TxDR and TxCh enter the LoRaWAN 1.1 MICs: the counter of the acknowledged frame, and the uplink data rate and channel.
+	ConfFCnt uint32
+	TxDR     uint8
+	TxCh     uint8
+}
+
+// DecodeKeys returns the keys of the device to decode its frames.
+func (d *Device) DecodeKeys() *DecodeKeys {
+	nwkSEncKey, sNwkSIntKey, fNwkSIntKey, appSKey := d.NwkSEncKey, d.SNwkSIntKey, d.FNwkSIntKey, d.AppSKey
+	nwkKey, appKey, devNonce := lorawan.AES128Key(d.NwkKey), lorawan.AES128Key(d.AppKey), d.DevNonce
+	return &DecodeKeys{
+		MACVersion:  d.MACVersion,
+		NwkSEncKey:  &nwkSEncKey,
+		SNwkSIntKey: &sNwkSIntKey,
+		FNwkSIntKey: &fNwkSIntKey,
+		AppSKey:     &appSKey,
+		NwkKey:      &nwkKey,
+		AppKey:      &appKey,
+		DevEUI:      d.DevEUI,
+		JoinEUI:     d.JoinEUI,
+		DevNonce:    &devNonce,
+	}
+}
+
+// MIC statuses of a decoded frame.
+const (
+	MICValid      = "valid"
+	MICInvalid    = "invalid"
+	MICNotChecked = "not checked"
+)
+
+// DecodedFrame is a frame with its payload decrypted as far as the keys allowed.
+type DecodedFrame struct {
+	PHYPayload lorawan.PHYPayload `json:"phyPayload"`
+	//MIC is one of MICValid, MICInvalid or MICNotChecked, with the reason it wasn't checked in Notes.
+	MIC string `json:"mic"`
+	//Encrypted tells that the payload, or part of it, couldn't be decrypted.
+	Encrypted bool     `json:"encrypted"`
+	Notes     []string `json:"notes,omitempty"`
+	//SessionKeys are derived from a join accept when the DevNonce is known.
+	SessionKeys *keys.SessionKeys `json:"sessionKeys,omitempty"`
+
+	fOptsLen int
+}
+
+// ParseFrame decodes a hex or base64 encoded frame.
+func ParseFrame(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if b, err := hex.DecodeString(strings.TrimPrefix(s, "0x")); err == nil {
+		return b, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("the frame is neither hex nor base64 encoded")
+	}
+	return b, nil
+}
+
+// DecodeFrame decodes the frame, checking its MIC and decrypting it with the known keys.
+func DecodeFrame(b []byte, k *DecodeKeys) (*DecodedFrame, error) {
+	f := &DecodedFrame{MIC: MICNotChecked}
+	if err := f.PHYPayload.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	var err error
+	switch f.PHYPayload.MHDR.MType {
+	case lorawan.JoinRequest:
+		err = f.decodeJoinRequest(k)
+	case lorawan.JoinAccept:
+		err = f.decodeJoinAccept(k)
+	case lorawan.UnconfirmedDataUp, lorawan.ConfirmedDataUp, lorawan.UnconfirmedDataDown, lorawan.ConfirmedDataDown:
+		f.fOptsLen = int(b[5] & 0x0f)
+		err = f.decodeData(k)
+	default:
+		f.note("%s frames aren't checked", f.PHYPayload.MHDR.MType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *DecodedFrame) note(format string, a ...interface{}) {
+	f.Notes = append(f.Notes, fmt.Sprintf(format, a...))
+}
+
+func (f *DecodedFrame) setMIC(ok bool) {
+	f.MIC = MICInvalid
+	if ok {
+		f.MIC = MICValid
+	}
+}
+
+func (f *DecodedFrame) decodeJoinRequest(k *DecodeKeys) error {
+	if k.NwkKey == nil {
+		f.note("the NwkKey is needed to check the MIC")
+		return nil
+	}
+	ok, err := f.PHYPayload.ValidateUplinkJoinMIC(*k.NwkKey)
+	if err != nil {
+		return err
+	}
+	f.setMIC(ok)
+	return nil
+}
+
+func (f *DecodedFrame) decodeJoinAccept(k *DecodeKeys) error {
+	phy := &f.PHYPayload
+	if k.NwkKey == nil {
+		f.Encrypted = true
+		f.note("the NwkKey is needed to decrypt the join accept")
+		return nil
+	}
+	if err := phy.DecryptJoinAcceptPayload(*k.NwkKey); err != nil {
+		return errors.Wrap(err, "can't decrypt join accept")
+	}
+	jap, ok := phy.MACPayload.(*lorawan.JoinAcceptPayload)
+	if !ok {
+		return errors.New("mac payload is not a join accept payload")
+	}
+
+	//The LoRaWAN 1.1 MIC covers the JoinEUI and DevNonce of the request, and so do all session keys.
+	if k.DevNonce == nil {
+		if jap.DLSettings.OptNeg {
+			f.note("the DevNonce is needed to check the MIC")
+		} else if ok, err := validateJoinAcceptMIC(*phy, *k.NwkKey, k.DevEUI, k.JoinEUI, 0); err != nil {
+			return err
+		} else {
+			f.setMIC(ok)
+		}
+		f.note("the DevNonce is needed to derive the session keys")
+		return nil
+	}
+
+	ok, err := validateJoinAcceptMIC(*phy, *k.NwkKey, k.DevEUI, k.JoinEUI, *k.DevNonce)
+	if err != nil {
+		return err
+	}
+	f.setMIC(ok)
+
+	if jap.DLSettings.OptNeg && k.AppKey == nil {
+		f.note("the AppKey is needed to derive the session keys")
+		return nil
+	}
+	appKey := *k.NwkKey
+	if k.AppKey != nil {
+		appKey = *k.AppKey
+	}
+	sessionKeys, err := keys.GetSessionKeys(*k.NwkKey, appKey, joinParams(jap, k.MACVersion, k.JoinEUI, *k.DevNonce))
+	if err != nil {
+		return errors.Wrap(err, "session keys derivation error")
+	}
+	f.SessionKeys = &sessionKeys
+	return nil
+}
+
+func (f *DecodedFrame) decodeData(k *DecodeKeys) error {
+	phy := &f.PHYPayload
+	mp, ok := phy.MACPayload.(*lorawan.MACPayload)
+	if !ok {
+		return errors.New("can't convert mac payload")
+	}
+
+	if k.FCnt != nil {
+		if uint16(*k.FCnt) != uint16(mp.FHDR.FCnt) {
+			return fmt.Errorf("the frame counter %d doesn't end with the frame's %d", *k.FCnt, mp.FHDR.FCnt)
+		}
+		mp.FHDR.FCnt = *k.FCnt
+	}
+
+	//The MIC is computed over the encrypted frame, so it's checked first.
+	if err := f.validateDataMIC(k); err != nil {
+		return err
+	}
+
+	//Unknown keys are zero, they aren't used.
+	var nwkSEncKey, appSKey lorawan.AES128Key
+	if k.NwkSEncKey != nil {
+		nwkSEncKey = *k.NwkSEncKey
+	}
+	if k.AppSKey != nil {
+		appSKey = *k.AppSKey
+	}
+
+	if mp.FPort != nil {
+		switch {
+		case *mp.FPort == 0 && k.NwkSEncKey == nil:
+			f.Encrypted = true
+			f.note("the NwkSEncKey is needed to decrypt the MAC commands")
+		case *mp.FPort != 0 && k.AppSKey == nil:
+			f.Encrypted = true
+			f.note("the AppSKey is needed to decrypt the frm payload")
+		default:
+			if err := decryptFRMPayload(phy, nwkSEncKey, appSKey); err != nil {
+				return errors.Wrap(err, "can't decrypt the frm payload")
+			}
+		}
+	}
+
+	if len(mp.FHDR.FOpts) > 0 {
+		if k.MACVersion != lorawan.LoRaWAN1_0 && k.NwkSEncKey == nil {
+			f.Encrypted = true
+			f.note("the NwkSEncKey is needed to decrypt the FOpts")
+			return nil
+		}
+		if err := decryptFOpts(phy, k.MACVersion, nwkSEncKey); err != nil {
+			return errors.Wrap(err, "can't decode the FOpts")
+		}
+	}
+	return nil
+}
+
+func (f *DecodedFrame) validateDataMIC(k *DecodeKeys) error {
+	phy := f.PHYPayload
+	var ok bool
+	var err error
+	switch {
+	case phy.MHDR.MType == lorawan.UnconfirmedDataDown || phy.MHDR.MType == lorawan.ConfirmedDataDown:
+		if k.SNwkSIntKey == nil {
+			f.note("the SNwkSIntKey is needed to check the MIC")
+			return nil
+		}
+		ok, err = phy.ValidateDownlinkDataMIC(k.MACVersion, k.ConfFCnt, *k.SNwkSIntKey)
+	case k.FNwkSIntKey == nil:
+		f.note("the FNwkSIntKey is needed to check the MIC")
+		return nil
+	case k.MACVersion == lorawan.LoRaWAN1_0:
+		ok, err = phy.ValidateUplinkDataMIC(k.MACVersion, 0, 0, 0, *k.FNwkSIntKey, *k.FNwkSIntKey)
+	case k.SNwkSIntKey == nil:
+		//Without the SNwkSIntKey only the half of the MIC computed with the FNwkSIntKey is checked.
+		f.note("only the FNwkSIntKey half of the MIC was checked")
+		ok, err = phy.ValidateUplinkDataMICF(*k.FNwkSIntKey)
+	default:
+		ok, err = phy.ValidateUplinkDataMIC(k.MACVersion, k.ConfFCnt, k.TxDR, k.TxCh, *k.FNwkSIntKey, *k.SNwkSIntKey)
+	}
+	if err != nil {
+		return err
+	}
+	f.setMIC(ok)
+	return nil
+}
+
+// decryptFRMPayload decrypts the FRMPayload of a data frame, which carries MAC commands encrypted with the network
+// session key on FPort 0.
+func decryptFRMPayload(phy *lorawan.PHYPayload, nwkSEncKey, appSKey lorawan.AES128Key) error {
+	frmKey := appSKey
+	if mp, ok := phy.MACPayload.(*lorawan.MACPayload); ok && mp.FPort != nil && *mp.FPort == 0 {
+		frmKey = nwkSEncKey
+	}
+	return phy.DecryptFRMPayload(frmKey)
+}
+
+// decryptFOpts decodes the MAC commands of the FOpts, which are encrypted since LoRaWAN 1.1.
+func decryptFOpts(phy *lorawan.PHYPayload, macVersion lorawan.MACVersion, nwkSEncKey lorawan.AES128Key) error {
+	if macVersion == lorawan.LoRaWAN1_0 {
+		return phy.DecodeFOptsToMACCommands()
+	}
+	return phy.DecryptFOpts(nwkSEncKey)
+}
+
+// validateJoinAcceptMIC checks the MIC of a decrypted join accept.
+func validateJoinAcceptMIC(phy lorawan.PHYPayload, nwkKey lorawan.AES128Key, devEUI, joinEUI lorawan.EUI64, devNonce lorawan.DevNonce) (bool, error) {
+	mic, err := keys.JoinAcceptMIC(phy, nwkKey, devEUI, joinEUI, devNonce)
+	if err != nil {
+		return false, err
+	}
+	return mic == phy.MIC, nil
+}
+
+// joinParams returns the parameters of the join a decrypted join accept answers.
+func joinParams(jap *lorawan.JoinAcceptPayload, macVersion lorawan.MACVersion, joinEUI lorawan.EUI64, devNonce lorawan.DevNonce) keys.Join {
+	return keys.Join{
+		MACVersion: macVersion,
+		OptNeg:     jap.DLSettings.OptNeg,
+		NetID:      jap.HomeNetID,
+		JoinEUI:    joinEUI,
+		JoinNonce:  jap.JoinNonce,
+		DevNonce:   devNonce,
+	}
+}
+
+// DecodedField is a named field of a decoded frame.
+type DecodedField struct {
+	Name  string
+	Value string
+}
+
+// Fields lists the header and payload fields of the frame, then its MIC and the derived session keys.
+func (f *DecodedFrame) Fields() []DecodedField {
+	phy := f.PHYPayload
+	fields := []DecodedField{
+		{"MType", phy.MHDR.MType.String()},
+		{"Major", phy.MHDR.Major.String()},
+	}
+	add := func(name, format string, a ...interface{}) {
+		fields = append(fields, DecodedField{name, fmt.Sprintf(format, a...)})
+	}
+
+	switch pl := phy.MACPayload.(type) {
+	case *lorawan.JoinRequestPayload:
+		add("JoinEUI", "%s", pl.JoinEUI)
+		add("DevEUI", "%s", pl.DevEUI)
+		add("DevNonce", "%d", pl.DevNonce)
+	case *lorawan.JoinAcceptPayload:
+		add("JoinNonce", "%d", pl.JoinNonce)
+		add("HomeNetID", "%s", pl.HomeNetID)
+		add("DevAddr", "%s", pl.DevAddr)
+		add("DLSettings", "OptNeg=%t RX1DROffset=%d RX2DataRate=%d", pl.DLSettings.OptNeg, pl.DLSettings.RX1DROffset, pl.DLSettings.RX2DataRate)
+		add("RXDelay", "%d", pl.RXDelay)
+		if pl.CFList != nil {
+			add("CFList", "%s", jsonString(pl.CFList))
+		}
+	case *lorawan.MACPayload:
+		uplink := phy.MHDR.MType == lorawan.UnconfirmedDataUp || phy.MHDR.MType == lorawan.ConfirmedDataUp
+		fc := pl.FHDR.FCtrl
+		add("DevAddr", "%s", pl.FHDR.DevAddr)
+		if uplink {
+			add("FCtrl", "ADR=%t ADRACKReq=%t ACK=%t ClassB=%t FOptsLen=%d", fc.ADR, fc.ADRACKReq, fc.ACK, fc.ClassB, f.fOptsLen)
+		} else {
+			add("FCtrl", "ADR=%t ACK=%t FPending=%t FOptsLen=%d", fc.ADR, fc.ACK, fc.FPending, f.fOptsLen)
+		}
+		add("FCnt", "%d", pl.FHDR.FCnt)
+		for _, p := range pl.FHDR.FOpts {
+			add("FOpts", "%s", payloadString(p))
+		}
+		if pl.FPort != nil {
+			add("FPort", "%d", *pl.FPort)
+		}
+		for _, p := range pl.FRMPayload {
+			add("FRMPayload", "%s", payloadString(p))
+		}
+	default:
+		if b, err := phy.MACPayload.MarshalBinary(); err == nil {
+			add("Payload", "%s", hex.EncodeToString(b))
+		}
+	}
+
+	add("MIC", "%s %s", phy.MIC, f.MIC)
+	for _, n := range f.Notes {
+		add("Note", "%s", n)
+	}
+	if k := f.SessionKeys; k != nil {
+		add("FNwkSIntKey", "%s", k.FNwkSIntKey)
+		add("SNwkSIntKey", "%s", k.SNwkSIntKey)
+		add("NwkSEncKey", "%s", k.NwkSEncKey)
+		add("AppSKey", "%s", k.AppSKey)
+	}
+	return fields
+}
+
+// payloadString returns the hex bytes of a data payload, or the CID of a MAC command with its payload fields.
+func payloadString(p lorawan.Payload) string {
+	switch pl := p.(type) {
+	case *lorawan.DataPayload:
+		return hex.EncodeToString(pl.Bytes)
+	case *lorawan.MACCommand:
+		if pl.Payload == nil {
+			return pl.CID.String()
+		}
+		return pl.CID.String() + " " + jsonString(pl.Payload)
+	}
+	b, _ := p.MarshalBinary()
+	return hex.EncodeToString(b)
+}
+
+func jsonString(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,511 @@
+package lds
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/brocaar/lorawan"
+
+	"github.com/iegomez/lds/lds/keys"
+)
+
+var (
+	testNwkSKey = lorawan.AES128Key{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	testAppSKey = lorawan.AES128Key{8, 7, 6, 5, 4, 3, 2, 1, 8, 7, 6, 5, 4, 3, 2, 1}
+	testNwkKey  = lorawan.AES128Key{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	testAppKey  = lorawan.AES128Key{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2}
+	testJoinEUI = lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1}
+	testDevEUI  = lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+)
+
+func keyPtr(k lorawan.AES128Key) *lorawan.AES128Key {
+	return &k
+}
+
+// dataFrame returns a LoRaWAN 1.0 data frame encrypted and signed with the test session keys.
+func dataFrame(t *testing.T, mType lorawan.MType, fCnt uint32, fPort *uint8, frmPayload []lorawan.Payload, fOpts []lorawan.Payload) []byte {
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{MType: mType, Major: lorawan.LoRaWANR1},
+		MACPayload: &lorawan.MACPayload{
+			FHDR:       lorawan.FHDR{DevAddr: lorawan.DevAddr{1, 2, 3, 4}, FCnt: fCnt, FOpts: fOpts},
+			FPort:      fPort,
+			FRMPayload: frmPayload,
+		},
+	}
+	frmKey := testAppSKey
+	if fPort != nil && *fPort == 0 {
+		frmKey = testNwkSKey
+	}
+	if err := phy.EncryptFRMPayload(frmKey); err != nil {
+		t.Fatal(err)
+	}
+	var err error
+	if mType == lorawan.UnconfirmedDataUp || mType == lorawan.ConfirmedDataUp {
+		err = phy.SetUplinkDataMIC(lorawan.LoRaWAN1_0, 0, 0, 0, testNwkSKey, testNwkSKey)
+	} else {
+		err = phy.SetDownlinkDataMIC(lorawan.LoRaWAN1_0, 0, testNwkSKey)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := phy.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// joinAcceptFrame returns a join accept answering the DevNonce 3 of the test device, encrypted with the test NwkKey.
+func joinAcceptFrame(t *testing.T, optNeg bool) []byte {
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{MType: lorawan.JoinAccept, Major: lorawan.LoRaWANR1},
+		MACPayload: &lorawan.JoinAcceptPayload{
+			JoinNonce:  5,
+			HomeNetID:  lorawan.NetID{0, 0, 1},
+			DevAddr:    lorawan.DevAddr{1, 2, 3, 4},
+			DLSettings: lorawan.DLSettings{OptNeg: optNeg, RX2DataRate: 3},
+			RXDelay:    1,
+		},
+	}
+	var err error
+	if phy.MIC, err = keys.JoinAcceptMIC(phy, testNwkKey, testDevEUI, testJoinEUI, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := phy.EncryptJoinAcceptPayload(testNwkKey); err != nil {
+		t.Fatal(err)
+	}
+	b, err := phy.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestParseFrame(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected []byte
+		err      bool
+	}{
+		{"40010203", []byte{0x40, 1, 2, 3}, false},
+		{" 0x40010203\n", []byte{0x40, 1, 2, 3}, false},
+		{"QAECAw==", []byte{0x40, 1, 2, 3}, false},
+		{"0x4g!", nil, true},
+	}
+
+	for _, tt := range tests {
+		b, err := ParseFrame(tt.s)
+		if (err != nil) != tt.err {
+			t.Errorf("%q: got error %v, expected one: %t", tt.s, err, tt.err)
+		}
+		if !bytes.Equal(b, tt.expected) {
+			t.Errorf("%q: got %x, expected %x", tt.s, b, tt.expected)
+		}
+	}
+}
+
+func TestDecodeJoinRequest(t *testing.T) {
+	phy := lorawan.PHYPayload{
+		MHDR:       lorawan.MHDR{MType: lorawan.JoinRequest, Major: lorawan.LoRaWANR1},
+		MACPayload: &lorawan.JoinRequestPayload{JoinEUI: testJoinEUI, DevEUI: testDevEUI, DevNonce: 3},
+	}
+	if err := phy.SetUplinkJoinMIC(testNwkKey); err != nil {
+		t.Fatal(err)
+	}
+	b, err := phy.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		keys  *DecodeKeys
+		mic   string
+		notes int
+	}{
+		{"valid MIC", &DecodeKeys{NwkKey: keyPtr(testNwkKey)}, MICValid, 0},
+		{"invalid MIC", &DecodeKeys{NwkKey: keyPtr(testAppKey)}, MICInvalid, 0},
+		{"no NwkKey", &DecodeKeys{}, MICNotChecked, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := DecodeFrame(b, tt.keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.MIC != tt.mic || len(f.Notes) != tt.notes {
+				t.Errorf("got MIC %s with notes %q, expected %s with %d", f.MIC, f.Notes, tt.mic, tt.notes)
+			}
+		})
+	}
+
+	f, err := DecodeFrame(b, &DecodeKeys{NwkKey: keyPtr(testNwkKey)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []DecodedField{
+		{"MType", "JoinRequest"},
+		{"Major", "LoRaWANR1"},
+		{"JoinEUI", "0807060504030201"},
+		{"DevEUI", "0102030405060708"},
+		{"DevNonce", "3"},
+		{"MIC", phy.MIC.String() + " valid"},
+	}
+	fields := f.Fields()
+	if len(fields) != len(expected) {
+		t.Fatalf("got fields %v, expected %v", fields, expected)
+	}
+	for i := range fields {
+		if fields[i] != expected[i] {
+			t.Errorf("got field %v, expected %v", fields[i], expected[i])
+		}
+	}
+}
+
+func TestDecodeJoinAccept(t *testing.T) {
+	devNonce := lorawan.DevNonce(3)
+	otherDevNonce := lorawan.DevNonce(4)
+
+	tests := []struct {
+		name        string
+		optNeg      bool
+		keys        *DecodeKeys
+		mic         string
+		encrypted   bool
+		notes       []string
+		sessionKeys bool
+	}{
+		{
+			name:      "no NwkKey",
+			keys:      &DecodeKeys{},
+			mic:       MICNotChecked,
+			encrypted: true,
+			notes:     []string{"the NwkKey is needed to decrypt the join accept"},
+		},
+		{
+			name:  "LoRaWAN 1.0 without DevNonce",
+			keys:  &DecodeKeys{NwkKey: keyPtr(testNwkKey), DevEUI: testDevEUI, JoinEUI: testJoinEUI},
+			mic:   MICValid,
+			notes: []string{"the DevNonce is needed to derive the session keys"},
+		},
+		{
+			name:        "LoRaWAN 1.0",
+			keys:        &DecodeKeys{NwkKey: keyPtr(testNwkKey), DevEUI: testDevEUI, JoinEUI: testJoinEUI, DevNonce: &devNonce},
+			mic:         MICValid,
+			sessionKeys: true,
+		},
+		{
+			name:   "OptNeg without DevNonce",
+			optNeg: true,
+			keys:   &DecodeKeys{MACVersion: lorawan.LoRaWAN1_1, NwkKey: keyPtr(testNwkKey), DevEUI: testDevEUI, JoinEUI: testJoinEUI},
+			mic:    MICNotChecked,
+			notes:  []string{"the DevNonce is needed to check the MIC", "the DevNonce is needed to derive the session keys"},
+		},
+		{
+			name:   "OptNeg with another DevNonce",
+			optNeg: true,
+			keys:   &DecodeKeys{MACVersion: lorawan.LoRaWAN1_1, NwkKey: keyPtr(testNwkKey), AppKey: keyPtr(testAppKey), DevEUI: testDevEUI, JoinEUI: testJoinEUI, DevNonce: &otherDevNonce},
+			mic:    MICInvalid,
+			//The keys of another DevNonce aren't those of the session, but they can't be told apart.
+			sessionKeys: true,
+		},
+		{
+			name:   "OptNeg without AppKey",
+			optNeg: true,
+			keys:   &DecodeKeys{MACVersion: lorawan.LoRaWAN1_1, NwkKey: keyPtr(testNwkKey), DevEUI: testDevEUI, JoinEUI: testJoinEUI, DevNonce: &devNonce},
+			mic:    MICValid,
+			notes:  []string{"the AppKey is needed to derive the session keys"},
+		},
+		{
+			name:        "OptNeg",
+			optNeg:      true,
+			keys:        &DecodeKeys{MACVersion: lorawan.LoRaWAN1_1, NwkKey: keyPtr(testNwkKey), AppKey: keyPtr(testAppKey), DevEUI: testDevEUI, JoinEUI: testJoinEUI, DevNonce: &devNonce},
+			mic:         MICValid,
+			sessionKeys: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := DecodeFrame(joinAcceptFrame(t, tt.optNeg), tt.keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.MIC != tt.mic || f.Encrypted != tt.encrypted {
+				t.Errorf("got MIC %s, encrypted %t, expected %s, %t", f.MIC, f.Encrypted, tt.mic, tt.encrypted)
+			}
+			if strings.Join(f.Notes, "; ") != strings.Join(tt.notes, "; ") {
+				t.Errorf("got notes %q, expected %q", f.Notes, tt.notes)
+			}
+			if (f.SessionKeys != nil) != tt.sessionKeys {
+				t.Fatalf("got session keys %+v, expected some: %t", f.SessionKeys, tt.sessionKeys)
+			}
+			if f.SessionKeys == nil || tt.mic != MICValid {
+				return
+			}
+
+			appKey := testNwkKey
+			if tt.optNeg {
+				appKey = testAppKey
+			}
+			expected, err := keys.GetSessionKeys(testNwkKey, appKey, keys.Join{
+				MACVersion: tt.keys.MACVersion,
+				OptNeg:     tt.optNeg,
+				NetID:      lorawan.NetID{0, 0, 1},
+				JoinEUI:    testJoinEUI,
+				JoinNonce:  5,
+				DevNonce:   devNonce,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *f.SessionKeys != expected {
+				t.Errorf("got session keys %+v, expected %+v", *f.SessionKeys, expected)
+			}
+		})
+	}
+}
+
+func TestDecodeData(t *testing.T) {
+	fPort, macPort := uint8(2), uint8(0)
+	data := []lorawan.Payload{&lorawan.DataPayload{Bytes: []byte{0xca, 0xfe}}}
+	sessionKeys := &DecodeKeys{NwkSEncKey: &testNwkSKey, SNwkSIntKey: &testNwkSKey, FNwkSIntKey: &testNwkSKey, AppSKey: &testAppSKey}
+	fCnt := func(c uint32) *DecodeKeys {
+		k := *sessionKeys
+		k.FCnt = &c
+		return &k
+	}
+
+	tests := []struct {
+		name      string
+		frame     []byte
+		keys      *DecodeKeys
+		mic       string
+		encrypted bool
+		notes     int
+		fields    []string
+		err       bool
+	}{
+		{
+			name:   "uplink",
+			frame:  dataFrame(t, lorawan.UnconfirmedDataUp, 7, &fPort, data, nil),
+			keys:   sessionKeys,
+			mic:    MICValid,
+			fields: []string{"FCtrl ADR=false ADRACKReq=false ACK=false ClassB=false FOptsLen=0", "FCnt 7", "FPort 2", "FRMPayload cafe"},
+		},
+		{
+			name:  "invalid MIC",
+			frame: dataFrame(t, lorawan.UnconfirmedDataUp, 7, &fPort, data, nil),
+			keys:  &DecodeKeys{FNwkSIntKey: &testAppSKey, AppSKey: &testAppSKey},
+			mic:   MICInvalid,
+		},
+		{
+			name:      "no keys",
+			frame:     dataFrame(t, lorawan.ConfirmedDataUp, 7, &fPort, data, nil),
+			keys:      &DecodeKeys{},
+			mic:       MICNotChecked,
+			encrypted: true,
+			notes:     2,
+		},
+		{
+			name:   "MAC commands",
+			frame:  dataFrame(t, lorawan.UnconfirmedDataUp, 7, &macPort, []lorawan.Payload{&lorawan.MACCommand{CID: lorawan.LinkCheckReq}}, nil),
+			keys:   sessionKeys,
+			mic:    MICValid,
+			fields: []string{"FPort 0", "FRMPayload LinkCheckReq"},
+		},
+		{
+			name:      "MAC commands without NwkSEncKey",
+			frame:     dataFrame(t, lorawan.UnconfirmedDataUp, 7, &macPort, []lorawan.Payload{&lorawan.MACCommand{CID: lorawan.LinkCheckReq}}, nil),
+			keys:      &DecodeKeys{FNwkSIntKey: &testNwkSKey, AppSKey: &testAppSKey},
+			mic:       MICValid,
+			encrypted: true,
+			notes:     1,
+		},
+		{
+			name: "downlink with FOpts",
+			frame: dataFrame(t, lorawan.UnconfirmedDataDown, 7, nil, nil, []lorawan.Payload{
+				&lorawan.MACCommand{CID: lorawan.LinkCheckAns, Payload: &lorawan.LinkCheckAnsPayload{Margin: 10, GwCnt: 1}},
+			}),
+			keys: sessionKeys,
+			mic:  MICValid,
+			//The lorawan package names the CIDs after the uplink commands.
+			fields: []string{"FCtrl ADR=false ACK=false FPending=false FOptsLen=3", `FOpts LinkCheckReq {"margin":10,"gwCnt":1}`},
+		},
+		{
+			name:   "full frame counter",
+			frame:  dataFrame(t, lorawan.UnconfirmedDataUp, 0x10007, &fPort, data, nil),
+			keys:   fCnt(0x10007),
+			mic:    MICValid,
+			fields: []string{"FCnt 65543", "FRMPayload cafe"},
+		},
+		{
+			name:  "truncated frame counter",
+			frame: dataFrame(t, lorawan.UnconfirmedDataUp, 0x10007, &fPort, data, nil),
+			keys:  sessionKeys,
+			mic:   MICInvalid,
+		},
+		{
+			name:  "other frame counter",
+			frame: dataFrame(t, lorawan.UnconfirmedDataUp, 7, &fPort, data, nil),
+			keys:  fCnt(8),
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := DecodeFrame(tt.frame, tt.keys)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if f.MIC != tt.mic || f.Encrypted != tt.encrypted || len(f.Notes) != tt.notes {
+				t.Errorf("got MIC %s, encrypted %t with notes %q, expected %s, %t with %d", f.MIC, f.Encrypted, f.Notes, tt.mic, tt.encrypted, tt.notes)
+			}
+
+			fields := map[string]bool{}
+			for _, field := range f.Fields() {
+				fields[field.Name+" "+field.Value] = true
+			}
+			for _, field := range tt.fields {
+				if !fields[field] {
+					t.Errorf("got fields %v, expected %q", f.Fields(), field)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeDataLoRaWAN11(t *testing.T) {
+	fNwkSIntKey, sNwkSIntKey := testNwkSKey, testAppKey
+	fPort := uint8(2)
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{MType: lorawan.UnconfirmedDataUp, Major: lorawan.LoRaWANR1},
+		MACPayload: &lorawan.MACPayload{
+			FHDR:       lorawan.FHDR{DevAddr: lorawan.DevAddr{1, 2, 3, 4}, FCnt: 1, FOpts: []lorawan.Payload{&lorawan.MACCommand{CID: lorawan.LinkCheckReq}}},
+			FPort:      &fPort,
+			FRMPayload: []lorawan.Payload{&lorawan.DataPayload{Bytes: []byte{1}}},
+		},
+	}
+	if err := phy.EncryptFOpts(testNwkSKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := phy.EncryptFRMPayload(testAppSKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := phy.SetUplinkDataMIC(lorawan.LoRaWAN1_1, 0, 5, 2, fNwkSIntKey, sNwkSIntKey); err != nil {
+		t.Fatal(err)
+	}
+	b, err := phy.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		keys      *DecodeKeys
+		mic       string
+		encrypted bool
+		notes     []string
+	}{
+		{
+			name: "every key",
+			keys: &DecodeKeys{MACVersion: lorawan.LoRaWAN1_1, NwkSEncKey: &testNwkSKey, SNwkSIntKey: &sNwkSIntKey, FNwkSIntKey: &fNwkSIntKey, AppSKey: &testAppSKey, TxDR: 5, TxCh: 2},
+			mic:  MICValid,
+		},
+		{
+			name: "other channel",
+			keys: &DecodeKeys{MACVersion: lorawan.LoRaWAN1_1, NwkSEncKey: &testNwkSKey, SNwkSIntKey: &sNwkSIntKey, FNwkSIntKey: &fNwkSIntKey, AppSKey: &testAppSKey, TxDR: 5, TxCh: 3},
+			mic:  MICInvalid,
+		},
+		{
+			name:  "no SNwkSIntKey",
+			keys:  &DecodeKeys{MACVersion: lorawan.LoRaWAN1_1, NwkSEncKey: &testNwkSKey, FNwkSIntKey: &fNwkSIntKey, AppSKey: &testAppSKey},
+			mic:   MICValid,
+			notes: []string{"only the FNwkSIntKey half of the MIC was checked"},
+		},
+		{
+			name:      "no NwkSEncKey",
+			keys:      &DecodeKeys{MACVersion: lorawan.LoRaWAN1_1, FNwkSIntKey: &fNwkSIntKey, AppSKey: &testAppSKey},
+			mic:       MICValid,
+			encrypted: true,
+			notes:     []string{"only the FNwkSIntKey half of the MIC was checked", "the NwkSEncKey is needed to decrypt the FOpts"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := DecodeFrame(b, tt.keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.MIC != tt.mic || f.Encrypted != tt.encrypted {
+				t.Errorf("got MIC %s, encrypted %t, expected %s, %t", f.MIC, f.Encrypted, tt.mic, tt.encrypted)
+			}
+			if strings.Join(f.Notes, "; ") != strings.Join(tt.notes, "; ") {
+				t.Errorf("got notes %q, expected %q", f.Notes, tt.notes)
+			}
+			if tt.encrypted {
+				return
+			}
+
+			mp := f.PHYPayload.MACPayload.(*lorawan.MACPayload)
+			if cmd, ok := mp.FHDR.FOpts[0].(*lorawan.MACCommand); !ok || cmd.CID != lorawan.LinkCheckReq {
+				t.Errorf("got FOpts %v, expected a LinkCheckReq", mp.FHDR.FOpts)
+			}
+			if p, ok := mp.FRMPayload[0].(*lorawan.DataPayload); !ok || !bytes.Equal(p.Bytes, []byte{1}) {
+				t.Errorf("got FRMPayload %v, expected 01", mp.FRMPayload)
+			}
+		})
+	}
+}
+
+func TestDecodeFrameErrors(t *testing.T) {
+	for _, s := range []string{"", "40"} {
+		b, _ := hex.DecodeString(s)
+		if _, err := DecodeFrame(b, &DecodeKeys{}); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+
+	//Proprietary frames are only parsed.
+	f, err := DecodeFrame([]byte{0xe0, 1, 2, 0, 0, 0, 0}, &DecodeKeys{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.MIC != MICNotChecked || len(f.Notes) != 1 {
+		t.Errorf("got MIC %s with notes %q", f.MIC, f.Notes)
+	}
+}
+
+func TestDeviceDecodeKeys(t *testing.T) {
+	d := &Device{
+		MACVersion: lorawan.LoRaWAN1_1,
+		NwkSEncKey: testNwkSKey,
+		AppSKey:    testAppSKey,
+		NwkKey:     testNwkKey,
+		AppKey:     testAppKey,
+		DevEUI:     testDevEUI,
+		JoinEUI:    testJoinEUI,
+		DevNonce:   3,
+	}
+	k := d.DecodeKeys()
+	if k.MACVersion != d.MACVersion || *k.NwkSEncKey != testNwkSKey || *k.AppSKey != testAppSKey || *k.NwkKey != testNwkKey || *k.AppKey != testAppKey {
+		t.Errorf("got keys %+v", k)
+	}
+	if k.DevEUI != testDevEUI || k.JoinEUI != testJoinEUI || *k.DevNonce != 3 || k.FCnt != nil {
+		t.Errorf("got keys %+v", k)
+	}
+
+	//The keys are copies.
+	d.AppSKey = testNwkSKey
+	if *k.AppSKey != testAppSKey {
+		t.Error("the keys changed with the device")
+	}
+}
@@ -0,0 +1,253 @@
+package lds
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan/band"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Errors returned when checking downlinks, which map to the gateway TX ack statuses.
+var (
+	ErrDownlinkScheduling = errors.New("network server scheduling error")
+	ErrTXParams           = errors.New("invalid downlink TX parameters")
+	ErrTXPower            = errors.New("invalid downlink TX power")
+)
+
+// TXPK is the downlink packet of a packet forwarder PULL_RESP.
+type TXPK struct {
+	Imme bool         `json:"imme"`
+	TMST *uint32      `json:"tmst,omitempty"`
+	TMMS *uint64      `json:"tmms,omitempty"`
+	Freq float64      `json:"freq"`
+	RFCH uint32       `json:"rfch"`
+	Powe int32        `json:"powe"`
+	Modu string       `json:"modu"`
+	DatR TXPKDataRate `json:"datr"`
+	CodR string       `json:"codr,omitempty"`
+	FDev uint16       `json:"fdev,omitempty"`
+	IPol bool         `json:"ipol"`
+	Prea uint16       `json:"prea,omitempty"`
+	Size uint16       `json:"size"`
+	NCRC bool         `json:"ncrc,omitempty"`
+	Data string       `json:"data"`
+}
+
+// TXPKDataRate is the data rate of a txpk: a "SFxBWy" string for LoRa or a bitrate for FSK.
+type TXPKDataRate struct {
+	LoRa string
+	FSK  uint32
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d TXPKDataRate) MarshalJSON() ([]byte, error) {
+	if d.LoRa != "" {
+		return json.Marshal(d.LoRa)
+	}
+	return json.Marshal(d.FSK)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *TXPKDataRate) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		d.LoRa = s
+		return nil
+	}
+	return json.Unmarshal(b, &d.FSK)
+}
+
+// dataRate returns the band data rate of the txpk.
+func (t *TXPK) dataRate() (band.DataRate, error) {
+	if t.Modu == string(band.FSKModulation) {
+		return band.DataRate{Modulation: band.FSKModulation, BitRate: int(t.DatR.FSK)}, nil
+	}
+
+	var sf, bw int
+	if _, err := fmt.Sscanf(t.DatR.LoRa, "SF%dBW%d", &sf, &bw); err != nil {
+		return band.DataRate{}, fmt.Errorf("invalid datr %s", t.DatR.LoRa)
+	}
+	return band.DataRate{Modulation: band.LoRaModulation, SpreadFactor: sf, Bandwidth: bw}, nil
+}
+
+// downlinkTXParams are the TX parameters of a downlink, whether scheduled through MQTT or a PULL_RESP.
+type downlinkTXParams struct {
+	frequency int
+	dataRate  band.DataRate
+	power     int
+	iPol      bool
+}
+
+// CheckDownlinkTXInfo checks the scheduling and TX parameters of a downlink received through MQTT.
+// Violations are logged and counted, but the downlink may still be processed.
+func (g *Gateway) CheckDownlinkTXInfo(txInfo *gw.DownlinkTXInfo) error {
+	var uplink *uplinkRecord
+	var delay time.Duration
+	if txInfo.GetTiming() == gw.DownlinkTiming_DELAY {
+		var err error
+		if delay, err = ptypes.Duration(txInfo.GetDelayTimingInfo().GetDelay()); err != nil {
+			delay = 0
+		}
+
+		now := time.Now()
+		if delay == 0 && len(txInfo.GetContext()) == 4 {
+			//Downlinks scheduled at a concentrator counter value (e.g. v2_json) carry it in the context with no delay.
+			uplink, delay, err = g.uplinkForTimestamp(binary.BigEndian.Uint32(txInfo.GetContext()), now)
+		} else {
+			uplink, err = g.uplinkForContext(txInfo.GetContext(), delay, now)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return g.checkTXParams(txInfoParams(txInfo), uplink, delay)
+}
+
+// txInfoParams returns the TX parameters of an MQTT downlink.
+func txInfoParams(txInfo *gw.DownlinkTXInfo) downlinkTXParams {
+	p := downlinkTXParams{
+		frequency: int(txInfo.GetFrequency()),
+		power:     int(txInfo.GetPower()),
+	}
+	if lora := txInfo.GetLoraModulationInfo(); lora != nil {
+		p.dataRate = band.DataRate{
+			Modulation:   band.LoRaModulation,
+			SpreadFactor: int(lora.GetSpreadingFactor()),
+			Bandwidth:    int(lora.GetBandwidth()),
+		}
+		p.iPol = lora.GetPolarizationInversion()
+	} else if fsk := txInfo.GetFskModulationInfo(); fsk != nil {
+		p.dataRate = band.DataRate{
+			Modulation: band.FSKModulation,
+			BitRate:    int(fsk.GetBitrate()),
+		}
+	}
+	return p
+}
+
+// CheckTXPK checks the scheduling and TX parameters of a downlink received in a PULL_RESP.
+// Violations are logged and counted, but the downlink may still be processed.
+func (g *Gateway) CheckTXPK(txpk *TXPK) error {
+	var uplink *uplinkRecord
+	var delay time.Duration
+	if !txpk.Imme && txpk.TMST != nil {
+		var err error
+		if uplink, delay, err = g.uplinkForTimestamp(*txpk.TMST, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	dr, err := txpk.dataRate()
+	if err != nil {
+		return g.txParamError(ErrTXParams, err)
+	}
+
+	return g.checkTXParams(downlinkTXParams{
+		frequency: int(txpk.Freq*1000000 + 0.5),
+		dataRate:  dr,
+		power:     int(txpk.Powe),
+		iPol:      txpk.IPol,
+	}, uplink, delay)
+}
+
+// checkTXParams validates downlink TX parameters against the band's RX1 rules for the given uplink and its RX2 defaults,
+// and the delay after the uplink against the window they match. Downlinks without an uplink (i.e. sent immediately)
+// must use the RX2 parameters.
+func (g *Gateway) checkTXParams(p downlinkTXParams, uplink *uplinkRecord, delay time.Duration) error {
+	g.mu.Lock()
+	b := g.band
+	g.mu.Unlock()
+
+	if b == nil {
+		log.Debugf("gateway %s has no band set, downlink TX parameters not validated", g.MAC)
+		return nil
+	}
+
+	if p.dataRate.Modulation == band.LoRaModulation && !p.iPol {
+		return g.txParamError(ErrTXParams, fmt.Errorf("downlink on %d Hz doesn't invert polarization", p.frequency))
+	}
+
+	dr, err := b.GetDataRateIndex(false, p.dataRate)
+	if err != nil {
+		return g.txParamError(ErrTXParams, fmt.Errorf("invalid downlink data rate %+v", p.dataRate))
+	}
+
+	if maxPower := b.GetDownlinkTXPower(p.frequency); p.power > maxPower {
+		return g.txParamError(ErrTXPower, fmt.Errorf("downlink power %d dBm exceeds the band maximum of %d dBm", p.power, maxPower))
+	}
+
+	defaults := b.GetDefaults()
+	if p.frequency == defaults.RX2Frequency && dr == defaults.RX2DataRate {
+		if uplink == nil {
+			return nil
+		}
+		return g.checkRXDelay("RX2", delay, 2*time.Second)
+	}
+
+	if uplink == nil {
+		return g.txParamError(ErrTXParams, fmt.Errorf("immediate downlink on %d Hz DR%d doesn't use RX2 parameters (%d Hz DR%d)", p.frequency, dr, defaults.RX2Frequency, defaults.RX2DataRate))
+	}
+
+	rx1Freq, err := b.GetRX1FrequencyForUplinkFrequency(int(uplink.txInfo.GetFrequency()))
+	if err != nil {
+		return g.txParamError(ErrTXParams, err)
+	}
+
+	ulDR, err := uplinkDataRateIndex(b, uplink.txInfo)
+	if err != nil {
+		return g.txParamError(ErrTXParams, err)
+	}
+
+	//The RX1 data rate offset is set by the network server, so any valid one is accepted.
+	var rx1DRs []string
+	for offset := 0; ; offset++ {
+		rx1DR, err := b.GetRX1DataRateIndex(ulDR, offset)
+		if err != nil {
+			break
+		}
+		if p.frequency == rx1Freq && dr == rx1DR {
+			return g.checkRXDelay("RX1", delay, time.Second)
+		}
+		rx1DRs = append(rx1DRs, strconv.Itoa(rx1DR))
+	}
+
+	return g.txParamError(ErrTXParams, fmt.Errorf("downlink on %d Hz DR%d matches neither RX1 (%d Hz DR%v) nor RX2 (%d Hz DR%d)", p.frequency, dr, rx1Freq, rx1DRs, defaults.RX2Frequency, defaults.RX2DataRate))
+}
+
+// checkRXDelay checks that a downlink is emitted in the window its TX parameters match: RX1 opens after a RX1 delay of
+// a whole number of seconds up to 15, RX2 one second after RX1, both within the device timing tolerance.
+func (g *Gateway) checkRXDelay(window string, delay, minDelay time.Duration) error {
+	seconds := (delay + rxWindowTolerance) / time.Second * time.Second
+	if offset := delay - seconds; offset < -rxWindowTolerance || offset > rxWindowTolerance || seconds < minDelay || seconds > minDelay+14*time.Second {
+		return g.schedulingError(fmt.Errorf("downlink with %s parameters is emitted %s after the uplink, out of the %s window", window, delay, window))
+	}
+
+	log.Infof("gateway %s: downlink emitted in %s, %s after the uplink", g.MAC, window, delay)
+	return nil
+}
+
+func uplinkDataRateIndex(b band.Band, txInfo *gw.UplinkTXInfo) (int, error) {
+	dr := band.DataRate{Modulation: band.LoRaModulation}
+	if lora := txInfo.GetLoraModulationInfo(); lora != nil {
+		dr.SpreadFactor = int(lora.GetSpreadingFactor())
+		dr.Bandwidth = int(lora.GetBandwidth())
+	} else if fsk := txInfo.GetFskModulationInfo(); fsk != nil {
+		dr = band.DataRate{Modulation: band.FSKModulation, BitRate: int(fsk.GetBitrate())}
+	}
+	return b.GetDataRateIndex(true, dr)
+}
+
+func (g *Gateway) txParamError(cause, err error) error {
+	txParamErrors.WithLabelValues(g.MAC).Inc()
+
+	log.Warningf("downlink TX parameters error on gateway %s: %s", g.MAC, err)
+	return errors.Wrap(cause, err.Error())
+}
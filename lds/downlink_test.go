@@ -0,0 +1,170 @@
+package lds
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/band"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestTXPKJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		dr   band.DataRate
+	}{
+		{
+			name: "LoRa",
+			json: `{"imme":false,"tmst":1000000,"freq":868.1,"rfch":0,"powe":14,"modu":"LORA","datr":"SF7BW125","codr":"4/5","ipol":true,"size":12,"data":"YAQDAgEAAAAB"}`,
+			dr:   band.DataRate{Modulation: band.LoRaModulation, SpreadFactor: 7, Bandwidth: 125},
+		},
+		{
+			name: "FSK",
+			json: `{"imme":true,"freq":868.8,"rfch":0,"powe":14,"modu":"FSK","datr":50000,"fdev":25000,"ipol":false,"prea":5,"size":12,"data":"YAQDAgEAAAAB"}`,
+			dr:   band.DataRate{Modulation: band.FSKModulation, BitRate: 50000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var txpk TXPK
+			if err := json.Unmarshal([]byte(tt.json), &txpk); err != nil {
+				t.Fatal(err)
+			}
+			dr, err := txpk.dataRate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dr != tt.dr {
+				t.Errorf("got data rate %+v, expected %+v", dr, tt.dr)
+			}
+
+			b, err := json.Marshal(txpk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.json {
+				t.Errorf("got %s, expected %s", b, tt.json)
+			}
+		})
+	}
+}
+
+func TestTXPKInvalidDataRate(t *testing.T) {
+	txpk := TXPK{Modu: "LORA", DatR: TXPKDataRate{LoRa: "SF7"}}
+	if _, err := txpk.dataRate(); err == nil {
+		t.Error("expected an error for a datr without bandwidth")
+	}
+}
+
+func TestCheckTXPK(t *testing.T) {
+	now := time.Now()
+	tmst := func(delay time.Duration) func(counter uint32) *uint32 {
+		return func(counter uint32) *uint32 {
+			v := counter + uint32(delay/time.Microsecond)
+			return &v
+		}
+	}
+	immediate := func(counter uint32) *uint32 { return nil }
+
+	tests := []struct {
+		name string
+		tmst func(counter uint32) *uint32
+		freq float64
+		datr string
+		powe int32
+		ipol bool
+		err  error
+	}{
+		{"RX1", tmst(time.Second), 868.1, "SF7BW125", 14, true, nil},
+		{"RX1 with a data rate offset", tmst(time.Second), 868.1, "SF8BW125", 14, true, nil},
+		{"RX2", tmst(2 * time.Second), 869.525, "SF12BW125", 14, true, nil},
+		{"immediate RX2", immediate, 869.525, "SF12BW125", 14, true, nil},
+		{"immediate on RX1 parameters", immediate, 868.1, "SF7BW125", 14, true, ErrTXParams},
+		{"wrong RX1 frequency", tmst(time.Second), 868.3, "SF7BW125", 14, true, ErrTXParams},
+		{"RX1 data rate over the uplink one", tmst(time.Second), 868.1, "SF7BW250", 14, true, ErrTXParams},
+		{"unknown data rate", tmst(time.Second), 868.1, "SF7BW500", 14, true, ErrTXParams},
+		{"polarization not inverted", tmst(time.Second), 868.1, "SF7BW125", 14, false, ErrTXParams},
+		{"power over the band maximum", tmst(time.Second), 868.1, "SF7BW125", 30, true, ErrTXPower},
+		{"RX2 parameters in RX1", tmst(time.Second), 869.525, "SF12BW125", 14, true, ErrDownlinkScheduling},
+		{"no uplink before", tmst(-time.Second), 868.1, "SF7BW125", 14, true, ErrDownlinkScheduling},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, context := scheduledGateway(t, now.Add(-100*time.Millisecond))
+			g.MAC = "txpk " + tt.name
+			txpk := &TXPK{
+				TMST: tt.tmst(binary.BigEndian.Uint32(context)),
+				Freq: tt.freq,
+				Powe: tt.powe,
+				Modu: "LORA",
+				DatR: TXPKDataRate{LoRa: tt.datr},
+				IPol: tt.ipol,
+			}
+			txpk.Imme = txpk.TMST == nil
+
+			err := g.CheckTXPK(txpk)
+			if errors.Cause(err) != tt.err {
+				t.Fatalf("got error %v, expected %v", err, tt.err)
+			}
+
+			expected := 0.0
+			if tt.err == ErrTXParams || tt.err == ErrTXPower {
+				expected = 1
+			}
+			if count := testutil.ToFloat64(txParamErrors.WithLabelValues(g.MAC)); count != expected {
+				t.Errorf("got %v TX parameter errors counted, expected %v", count, expected)
+			}
+		})
+	}
+}
+
+func TestCheckTXParamsWithoutBand(t *testing.T) {
+	g := &Gateway{MAC: "0102030405060708"}
+	if err := g.CheckTXPK(&TXPK{Imme: true, Freq: 915, Modu: "LORA", DatR: TXPKDataRate{LoRa: "SF7BW125"}}); err != nil {
+		t.Errorf("a gateway without a band checked the TX parameters: %s", err)
+	}
+}
+
+func TestProcessUDPDownlinkCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		freq    float64
+		emitted uint32
+	}{
+		{"RX2", 869.525, 1},
+		{"wrong frequency", 868.3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Device{gateway: "udp downlink " + tt.name}
+			if err := GetGateway(d.gateway).SetBand(band.EU868); err != nil {
+				t.Fatal(err)
+			}
+
+			txpk, err := json.Marshal(struct {
+				TXPK TXPK `json:"txpk"`
+			}{TXPK{Imme: true, Freq: tt.freq, Powe: 14, Modu: "LORA", DatR: TXPKDataRate{LoRa: "SF12BW125"}, IPol: true, Data: "AQID"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			//The payload isn't a frame, only the gateway counters matter.
+			before := GetGateway(d.gateway).Stats()
+			d.ProcessDownlink(append([]byte{2, 0, 0, 3}, txpk...), lorawan.LoRaWAN1_0, false)
+
+			stats := GetGateway(d.gateway).Stats()
+			received, emitted := stats.GetTxPacketsReceived()-before.GetTxPacketsReceived(), stats.GetTxPacketsEmitted()-before.GetTxPacketsEmitted()
+			if received != 1 || emitted != tt.emitted {
+				t.Errorf("got %d downlinks received and %d emitted, expected 1 and %d", received, emitted, tt.emitted)
+			}
+		})
+	}
+}
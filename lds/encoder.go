@@ -0,0 +1,138 @@
+package lds
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robertkrimen/otto"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultScript is the encoder script used when none is configured.
+var DefaultScript = `
+// Encode encodes the given object into an array of bytes.
+//  - fPort contains the LoRaWAN fPort number
+//  - obj is an object, e.g. {"temperature": 22.5}
+// The function must return an array of bytes, e.g. [225, 230, 255, 0]
+function Encode(fPort, obj) {
+	return [];
+}
+`
+
+// EncodeToBytes encodes the JS object with the encoder script to a slice of bytes.
+// Taken from github.com/brocaar/lora-app-server.
+func (p *RawPayloadConfig) EncodeToBytes() (b []byte, err error) {
+	defer func() {
+		if caught := recover(); caught != nil {
+			err = fmt.Errorf("%s", caught)
+		}
+	}()
+
+	script := p.Script + "\n\nEncode(fPort, obj);\n"
+
+	vm := otto.New()
+	vm.Interrupt = make(chan func(), 1)
+	vm.SetStackDepthLimit(32)
+	var jsonData interface{}
+	err = json.Unmarshal([]byte(p.Obj), &jsonData)
+	if err != nil {
+		log.Errorf("couldn't unmarshal object: %s", err)
+		return nil, err
+	}
+	log.Debugf("JS object: %v", jsonData)
+	vm.Set("obj", jsonData)
+	vm.Set("fPort", p.FPort)
+
+	go func() {
+		time.Sleep(time.Duration(p.MaxExecTime) * time.Millisecond)
+		vm.Interrupt <- func() {
+			panic(errors.New("execution timeout"))
+		}
+	}()
+
+	var val otto.Value
+	val, err = vm.Run(script)
+	if err != nil {
+		return nil, errors.Wrap(err, "js vm error")
+	}
+	if !val.IsObject() {
+		return nil, errors.New("function must return an array")
+	}
+
+	var out interface{}
+	out, err = val.Export()
+	if err != nil {
+		return nil, errors.Wrap(err, "export error")
+	}
+
+	return interfaceToByteSlice(out)
+}
+
+// Taken from github.com/brocaar/lora-app-server.
+func interfaceToByteSlice(obj interface{}) ([]byte, error) {
+	if obj == nil {
+		return nil, errors.New("value must not be nil")
+	}
+
+	if reflect.TypeOf(obj).Kind() != reflect.Slice {
+		return nil, errors.New("value must be an array")
+	}
+
+	s := reflect.ValueOf(obj)
+	l := s.Len()
+
+	var out []byte
+	for i := 0; i < l; i++ {
+		var b int64
+
+		el := s.Index(i).Interface()
+		switch v := el.(type) {
+		case int:
+			b = int64(v)
+		case uint:
+			b = int64(v)
+		case uint8:
+			b = int64(v)
+		case int8:
+			b = int64(v)
+		case uint16:
+			b = int64(v)
+		case int16:
+			b = int64(v)
+		case uint32:
+			b = int64(v)
+		case int32:
+			b = int64(v)
+		case uint64:
+			b = int64(v)
+			if uint64(b) != v {
+				return nil, fmt.Errorf("array value must be in byte range (0 - 255), got: %d", v)
+			}
+		case int64:
+			b = int64(v)
+		case float32:
+			b = int64(v)
+			if float32(b) != v {
+				return nil, fmt.Errorf("array value must be in byte range (0 - 255), got: %f", v)
+			}
+		case float64:
+			b = int64(v)
+			if float64(b) != v {
+				return nil, fmt.Errorf("array value must be in byte range (0 - 255), got: %f", v)
+			}
+		default:
+			return nil, fmt.Errorf("array value must be an array of ints or floats, got: %T", el)
+		}
+
+		if b < 0 || b > 255 {
+			return nil, fmt.Errorf("array value must be in byte range (0 - 255), got: %d", b)
+		}
+
+		out = append(out, byte(b))
+	}
+
+	return out, nil
+}
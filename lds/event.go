@@ -0,0 +1,144 @@
+package lds
+
+import (
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan"
+)
+
+// EventType tells what happened to a device.
+type EventType string
+
+// Device event types.
+const (
+	EventUplinkSent       EventType = "uplink_sent"
+	EventDownlinkReceived EventType = "downlink_received"
+	EventJoinAccepted     EventType = "join_accepted"
+	EventMICFailure       EventType = "mic_failure"
+)
+
+// Event is something that happened to a device, with the field matching its type set.
+type Event struct {
+	Type   EventType
+	Time   time.Time
+	DevEUI lorawan.EUI64
+	//Gateway is the MAC of the gateway the device last sent through.
+	Gateway string
+	//Uplink is set for EventUplinkSent.
+	Uplink *Uplink
+	//Downlink is set for EventDownlinkReceived and EventJoinAccepted.
+	Downlink *Downlink
+	//MICFailure is set for EventMICFailure.
+	MICFailure *MICFailure
+}
+
+// Uplink is a join request or data uplink sent by the device, with its payload in clear.
+type Uplink struct {
+	MType      lorawan.MType
+	PHYPayload []byte
+	RXInfo     *gw.UplinkRXInfo
+	TXInfo     *gw.UplinkTXInfo
+	//Data uplink fields.
+	DevAddr     lorawan.DevAddr
+	FCtrl       lorawan.FCtrl
+	FCnt        uint32
+	FPort       uint8
+	FRMPayload  []byte
+	MACCommands []lorawan.MACCommand
+	//DevNonce is the nonce of a join request.
+	DevNonce lorawan.DevNonce
+}
+
+// MICFailure is a join accept or data downlink dropped because of its invalid MIC.
+type MICFailure struct {
+	MType      lorawan.MType
+	PHYPayload []byte
+	Err        error
+}
+
+// DownlinkHandler returns an event handler calling h with the received downlinks and join accepts.
+func DownlinkHandler(h func(*Downlink)) func(*Event) {
+	return func(e *Event) {
+		if e.Downlink != nil {
+			h(e.Downlink)
+		}
+	}
+}
+
+// AddEventHandler adds a function called with every event of the device, and returns the function that removes it.
+func (d *Device) AddEventHandler(h func(*Event)) func() {
+	p := &h
+	d.handlers = append(d.handlers, p)
+	return func() {
+		for i, q := range d.handlers {
+			if q == p {
+				d.handlers = append(d.handlers[:i:i], d.handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// AddDownlinkHandler adds a function called with every join accept or data downlink successfully processed by the device.
+// It returns the function that removes it.
+func (d *Device) AddDownlinkHandler(h func(*Downlink)) func() {
+	return d.AddEventHandler(DownlinkHandler(h))
+}
+
+func (d *Device) emit(e *Event) {
+	e.DevEUI = d.DevEUI
+	e.Gateway = d.gateway
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	for _, h := range d.handlers {
+		(*h)(e)
+	}
+}
+
+func (d *Device) handleDownlink(dl *Downlink) {
+	d.countDownlink(dl)
+	t := EventDownlinkReceived
+	if dl.MType == lorawan.JoinAccept {
+		t = EventJoinAccepted
+	}
+	d.emit(&Event{Type: t, Time: dl.Time, Downlink: dl})
+}
+
+// uplinkSent emits the uplink event of a data uplink, before the frame counter is increased.
+func (d *Device) uplinkSent(mType lorawan.MType, fPort uint8, payload []byte, macCommands []*lorawan.MACCommand, fCtrl lorawan.FCtrl, phyPayload []byte, rxInfo *gw.UplinkRXInfo, txInfo *gw.UplinkTXInfo) {
+	ul := &Uplink{
+		MType:      mType,
+		PHYPayload: phyPayload,
+		RXInfo:     rxInfo,
+		TXInfo:     txInfo,
+		DevAddr:    d.DevAddr,
+		FCtrl:      fCtrl,
+		FCnt:       d.UlFcnt,
+		FPort:      fPort,
+		FRMPayload: payload,
+	}
+	for _, mac := range macCommands {
+		ul.MACCommands = append(ul.MACCommands, *mac)
+	}
+	d.countUplink(mType)
+	d.emit(&Event{Type: EventUplinkSent, Uplink: ul})
+}
+
+func (d *Device) joinSent(phyPayload []byte, rxInfo *gw.UplinkRXInfo, txInfo *gw.UplinkTXInfo) {
+	d.countUplink(lorawan.JoinRequest)
+	d.emit(&Event{Type: EventUplinkSent, Uplink: &Uplink{
+		MType:      lorawan.JoinRequest,
+		PHYPayload: phyPayload,
+		RXInfo:     rxInfo,
+		TXInfo:     txInfo,
+		DevNonce:   d.DevNonce,
+	}})
+}
+
+func (d *Device) micFailure(mType lorawan.MType, phyPayload []byte, err error) error {
+	micFailures.WithLabelValues(d.DevEUI.String()).Inc()
+	d.emit(&Event{Type: EventMICFailure, MICFailure: &MICFailure{MType: mType, PHYPayload: phyPayload, Err: err}})
+	return err
+}
@@ -0,0 +1,289 @@
+package lds
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/pkg/errors"
+)
+
+// Downlink is a join accept or data downlink as processed by the device, with its payload decrypted.
+type Downlink struct {
+	Time    time.Time
+	MType   lorawan.MType
+	DevAddr lorawan.DevAddr
+	FCtrl   lorawan.FCtrl
+	FCnt    uint32
+	FPort   *uint8
+	//PHYPayload is the frame as received, encrypted.
+	PHYPayload []byte
+	//FRMPayload is the application payload, it's empty when the frame carries MAC commands on FPort 0.
+	FRMPayload []byte
+	//MACCommands are the commands sent either in FOpts or on FPort 0.
+	MACCommands []lorawan.MACCommand
+}
+
+func newDataDownlink(mType lorawan.MType, mp *lorawan.MACPayload, phyPayload []byte) *Downlink {
+	dl := &Downlink{
+		Time:       time.Now(),
+		MType:      mType,
+		DevAddr:    mp.FHDR.DevAddr,
+		FCtrl:      mp.FHDR.FCtrl,
+		FCnt:       mp.FHDR.FCnt,
+		FPort:      mp.FPort,
+		PHYPayload: phyPayload,
+	}
+
+	for _, pl := range mp.FHDR.FOpts {
+		if mac, ok := pl.(*lorawan.MACCommand); ok {
+			dl.MACCommands = append(dl.MACCommands, *mac)
+		}
+	}
+	for _, pl := range mp.FRMPayload {
+		switch p := pl.(type) {
+		case *lorawan.DataPayload:
+			dl.FRMPayload = append(dl.FRMPayload, p.Bytes...)
+		case *lorawan.MACCommand:
+			dl.MACCommands = append(dl.MACCommands, *p)
+		}
+	}
+	return dl
+}
+
+// MACCommand returns the first MAC command with the given CID, or nil when there's none.
+func (dl *Downlink) MACCommand(cid lorawan.CID) *lorawan.MACCommand {
+	for i := range dl.MACCommands {
+		if dl.MACCommands[i].CID == cid {
+			return &dl.MACCommands[i]
+		}
+	}
+	return nil
+}
+
+// String describes the downlink for errors and logs.
+func (dl *Downlink) String() string {
+	if dl.MType == lorawan.JoinAccept {
+		return fmt.Sprintf("join accept for %s", dl.DevAddr)
+	}
+
+	s := fmt.Sprintf("%s fCnt %d", dl.MType, dl.FCnt)
+	if dl.FPort != nil {
+		s += fmt.Sprintf(" fPort %d payload %x", *dl.FPort, dl.FRMPayload)
+	}
+	for _, mac := range dl.MACCommands {
+		s += fmt.Sprintf(" %s", mac.CID)
+	}
+	return s
+}
+
+// MarshalJSON encodes the downlink with its payload in hex.
+func (dl *Downlink) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Time        time.Time            `json:"time"`
+		MType       lorawan.MType        `json:"mType"`
+		DevAddr     lorawan.DevAddr      `json:"devAddr"`
+		FCtrl       lorawan.FCtrl        `json:"fCtrl"`
+		FCnt        uint32               `json:"fCnt"`
+		FPort       *uint8               `json:"fPort,omitempty"`
+		FRMPayload  string               `json:"frmPayload"`
+		MACCommands []lorawan.MACCommand `json:"macCommands"`
+	}{
+		Time:        dl.Time,
+		MType:       dl.MType,
+		DevAddr:     dl.DevAddr,
+		FCtrl:       dl.FCtrl,
+		FCnt:        dl.FCnt,
+		FPort:       dl.FPort,
+		FRMPayload:  hex.EncodeToString(dl.FRMPayload),
+		MACCommands: dl.MACCommands,
+	})
+}
+
+// DownlinkExpectation describes a downlink, unset fields match anything.
+type DownlinkExpectation struct {
+	Confirmed  *bool
+	ACK        *bool
+	FPending   *bool
+	FPort      *uint8
+	FRMPayload []byte
+	//MACCommand must be present in the downlink, with its payload fields (by their json name) matching MACFields.
+	MACCommand *lorawan.CID
+	MACFields  map[string]interface{}
+}
+
+// Check returns an error describing the first mismatch between the downlink and the expectation.
+func (x *DownlinkExpectation) Check(dl *Downlink) error {
+	if dl.MType == lorawan.JoinAccept {
+		return errors.New("expected a data downlink, got a join accept")
+	}
+	if x.Confirmed != nil && (dl.MType == lorawan.ConfirmedDataDown) != *x.Confirmed {
+		return fmt.Errorf("expected confirmed to be %t, got %s", *x.Confirmed, dl.MType)
+	}
+	if x.ACK != nil && dl.FCtrl.ACK != *x.ACK {
+		return fmt.Errorf("expected ACK to be %t", *x.ACK)
+	}
+	if x.FPending != nil && dl.FCtrl.FPending != *x.FPending {
+		return fmt.Errorf("expected FPending to be %t", *x.FPending)
+	}
+	if x.FPort != nil && (dl.FPort == nil || *dl.FPort != *x.FPort) {
+		return fmt.Errorf("expected fPort %d, got %s", *x.FPort, dl)
+	}
+	if x.FRMPayload != nil && !bytes.Equal(dl.FRMPayload, x.FRMPayload) {
+		return fmt.Errorf("expected payload %x, got %x", x.FRMPayload, dl.FRMPayload)
+	}
+	if x.MACCommand != nil {
+		mac := dl.MACCommand(*x.MACCommand)
+		if mac == nil {
+			return fmt.Errorf("expected %s, got %s", *x.MACCommand, dl)
+		}
+		if err := checkMACFields(mac, x.MACFields); err != nil {
+			return errors.Wrap(err, mac.CID.String())
+		}
+	}
+	return nil
+}
+
+// checkMACFields compares the MAC command payload fields with the expected values, as formatted by fmt.
+func checkMACFields(mac *lorawan.MACCommand, expected map[string]interface{}) error {
+	if len(expected) == 0 {
+		return nil
+	}
+	if mac.Payload == nil {
+		return errors.New("the command has no payload")
+	}
+
+	b, err := json.Marshal(mac.Payload)
+	if err != nil {
+		return err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	for name, want := range expected {
+		got, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("unknown field %s", name)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return fmt.Errorf("expected %s to be %v, got %v", name, want, got)
+		}
+	}
+	return nil
+}
+
+// Expectations keeps the downlinks received by a device so that they may be waited for and checked in order.
+// Record may be given to Device.AddDownlinkHandler.
+type Expectations struct {
+	mu        sync.Mutex
+	downlinks []*Downlink
+	//next is the index of the first downlink not yet consumed by an expectation.
+	next int
+	//received is signaled on every recorded downlink.
+	received chan struct{}
+}
+
+// NewExpectations returns an empty downlink record.
+func NewExpectations() *Expectations {
+	return &Expectations{received: make(chan struct{}, 1)}
+}
+
+// Record adds a received downlink.
+func (e *Expectations) Record(dl *Downlink) {
+	e.mu.Lock()
+	e.downlinks = append(e.downlinks, dl)
+	e.mu.Unlock()
+
+	select {
+	case e.received <- struct{}{}:
+	default:
+	}
+}
+
+// Count returns how many downlinks were recorded.
+func (e *Expectations) Count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.downlinks)
+}
+
+// Skip consumes every downlink received so far, so that the following expectations only see newer ones.
+func (e *Expectations) Skip() {
+	e.mu.Lock()
+	e.next = len(e.downlinks)
+	e.mu.Unlock()
+}
+
+func (e *Expectations) pop() *Downlink {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.next == len(e.downlinks) {
+		return nil
+	}
+	dl := e.downlinks[e.next]
+	e.next++
+	return dl
+}
+
+// Next consumes the next downlink, waiting up to timeout for it.
+func (e *Expectations) Next(ctx context.Context, timeout time.Duration) (*Downlink, error) {
+	deadline := time.After(timeout)
+	for {
+		if dl := e.pop(); dl != nil {
+			return dl, nil
+		}
+		select {
+		case <-e.received:
+		case <-deadline:
+			return nil, errors.Wrapf(ErrTimeout, "no downlink received in %s", timeout)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// ExpectJoinAccept checks that the next downlink is a join accept received within timeout.
+func (e *Expectations) ExpectJoinAccept(ctx context.Context, timeout time.Duration) (*Downlink, error) {
+	dl, err := e.Next(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if dl.MType != lorawan.JoinAccept {
+		return dl, fmt.Errorf("expected a join accept, got %s", dl)
+	}
+	return dl, nil
+}
+
+// ExpectDownlink checks that the next downlink is received within timeout and matches the expectation.
+func (e *Expectations) ExpectDownlink(ctx context.Context, timeout time.Duration, x *DownlinkExpectation) (*Downlink, error) {
+	dl, err := e.Next(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return dl, x.Check(dl)
+}
+
+// ExpectNoDownlink checks that no downlink is received during d, ignoring those received before.
+func (e *Expectations) ExpectNoDownlink(ctx context.Context, d time.Duration) error {
+	e.Skip()
+	deadline := time.After(d)
+	for {
+		if dl := e.pop(); dl != nil {
+			return fmt.Errorf("expected no downlink, got %s", dl)
+		}
+		select {
+		case <-e.received:
+		case <-deadline:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package lds
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/pkg/errors"
+)
+
+func uint8Ptr(v uint8) *uint8             { return &v }
+func cidPtr(cid lorawan.CID) *lorawan.CID { return &cid }
+
+func TestNewDataDownlink(t *testing.T) {
+	mp := &lorawan.MACPayload{
+		FHDR: lorawan.FHDR{
+			DevAddr: lorawan.DevAddr{1, 2, 3, 4},
+			FCtrl:   lorawan.FCtrl{ACK: true},
+			FCnt:    5,
+			FOpts:   []lorawan.Payload{&lorawan.MACCommand{CID: lorawan.DevStatusReq}},
+		},
+		FPort:      uint8Ptr(3),
+		FRMPayload: []lorawan.Payload{&lorawan.DataPayload{Bytes: []byte{1, 2}}},
+	}
+
+	dl := newDataDownlink(lorawan.ConfirmedDataDown, mp, []byte{0xa0})
+	if dl.DevAddr != mp.FHDR.DevAddr || !dl.FCtrl.ACK || dl.FCnt != 5 || *dl.FPort != 3 || !bytes.Equal(dl.PHYPayload, []byte{0xa0}) {
+		t.Errorf("got downlink %+v", dl)
+	}
+	if !bytes.Equal(dl.FRMPayload, []byte{1, 2}) || len(dl.MACCommands) != 1 || dl.MACCommand(lorawan.DevStatusReq) == nil {
+		t.Errorf("got payload %x and mac commands %v", dl.FRMPayload, dl.MACCommands)
+	}
+	if dl.MACCommand(lorawan.LinkADRReq) != nil {
+		t.Error("got a mac command the downlink doesn't have")
+	}
+	if s := dl.String(); s != "ConfirmedDataDown fCnt 5 fPort 3 payload 0102 DevStatusReq" {
+		t.Errorf("got %q", s)
+	}
+
+	//MAC commands on FPort 0 are no application payload.
+	mp.FPort = uint8Ptr(0)
+	mp.FRMPayload = []lorawan.Payload{&lorawan.MACCommand{CID: lorawan.RXParamSetupReq, Payload: &lorawan.RXParamSetupReqPayload{}}}
+	dl = newDataDownlink(lorawan.UnconfirmedDataDown, mp, nil)
+	if len(dl.FRMPayload) != 0 || len(dl.MACCommands) != 2 || dl.MACCommand(lorawan.RXParamSetupReq) == nil {
+		t.Errorf("got payload %x and mac commands %v", dl.FRMPayload, dl.MACCommands)
+	}
+}
+
+func TestDownlinkExpectationCheck(t *testing.T) {
+	linkADR := lorawan.LinkADRReq
+	dl := &Downlink{
+		MType:      lorawan.ConfirmedDataDown,
+		FCtrl:      lorawan.FCtrl{ACK: true},
+		FCnt:       2,
+		FPort:      uint8Ptr(10),
+		FRMPayload: []byte{1, 2, 3},
+		MACCommands: []lorawan.MACCommand{
+			{CID: lorawan.LinkADRReq, Payload: &lorawan.LinkADRReqPayload{DataRate: 5, TXPower: 1}},
+			{CID: lorawan.DevStatusReq},
+		},
+	}
+
+	tests := []struct {
+		name string
+		x    DownlinkExpectation
+		dl   *Downlink
+		err  bool
+	}{
+		{"anything", DownlinkExpectation{}, dl, false},
+		{"every field", DownlinkExpectation{
+			Confirmed:  boolPtr(true),
+			ACK:        boolPtr(true),
+			FPending:   boolPtr(false),
+			FPort:      uint8Ptr(10),
+			FRMPayload: []byte{1, 2, 3},
+			MACCommand: &linkADR,
+			MACFields:  map[string]interface{}{"dataRate": int64(5), "txPower": 1},
+		}, dl, false},
+		{"join accept", DownlinkExpectation{}, &Downlink{MType: lorawan.JoinAccept}, true},
+		{"unconfirmed", DownlinkExpectation{Confirmed: boolPtr(false)}, dl, true},
+		{"no ACK", DownlinkExpectation{ACK: boolPtr(false)}, dl, true},
+		{"pending", DownlinkExpectation{FPending: boolPtr(true)}, dl, true},
+		{"other fPort", DownlinkExpectation{FPort: uint8Ptr(11)}, dl, true},
+		{"no fPort", DownlinkExpectation{FPort: uint8Ptr(10)}, &Downlink{MType: lorawan.UnconfirmedDataDown}, true},
+		{"other payload", DownlinkExpectation{FRMPayload: []byte{1, 2}}, dl, true},
+		{"missing mac command", DownlinkExpectation{MACCommand: cidPtr(lorawan.NewChannelReq)}, dl, true},
+		{"other mac field", DownlinkExpectation{MACCommand: &linkADR, MACFields: map[string]interface{}{"dataRate": 4}}, dl, true},
+		{"unknown mac field", DownlinkExpectation{MACCommand: &linkADR, MACFields: map[string]interface{}{"dr": 5}}, dl, true},
+		{"fields of a command without payload", DownlinkExpectation{MACCommand: cidPtr(lorawan.DevStatusReq), MACFields: map[string]interface{}{"battery": 0}}, dl, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.x.Check(tt.dl); (err != nil) != tt.err {
+				t.Errorf("got error %v, expected one: %t", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestExpectationsNext(t *testing.T) {
+	e := NewExpectations()
+	first := &Downlink{MType: lorawan.UnconfirmedDataDown, FCnt: 1}
+	e.Record(first)
+
+	//A downlink received before waiting is returned right away, a later one once recorded.
+	if dl, err := e.Next(context.Background(), time.Second); err != nil || dl != first {
+		t.Fatalf("got %v, %v, expected the first downlink", dl, err)
+	}
+	second := &Downlink{MType: lorawan.UnconfirmedDataDown, FCnt: 2}
+	time.AfterFunc(10*time.Millisecond, func() { e.Record(second) })
+	if dl, err := e.Next(context.Background(), time.Second); err != nil || dl != second {
+		t.Fatalf("got %v, %v, expected the second downlink", dl, err)
+	}
+	if e.Count() != 2 {
+		t.Errorf("got %d downlinks recorded, expected 2", e.Count())
+	}
+
+	if _, err := e.Next(context.Background(), 10*time.Millisecond); errors.Cause(err) != ErrTimeout {
+		t.Errorf("got error %v, expected a timeout", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := e.Next(ctx, time.Second); err != context.Canceled {
+		t.Errorf("got error %v, expected the context error", err)
+	}
+
+	//Skipped downlinks aren't returned anymore.
+	e.Record(&Downlink{FCnt: 3})
+	e.Skip()
+	if _, err := e.Next(context.Background(), 10*time.Millisecond); errors.Cause(err) != ErrTimeout {
+		t.Errorf("got error %v, expected a timeout", err)
+	}
+}
+
+func TestExpectations(t *testing.T) {
+	joinAccept := &Downlink{MType: lorawan.JoinAccept, DevAddr: lorawan.DevAddr{1, 2, 3, 4}}
+	data := &Downlink{MType: lorawan.UnconfirmedDataDown, FPort: uint8Ptr(1), FRMPayload: []byte{1}}
+
+	tests := []struct {
+		name      string
+		recorded  []*Downlink
+		later     *Downlink
+		expect    func(e *Expectations) error
+		err       bool
+		remaining int
+	}{
+		{"join accept", []*Downlink{joinAccept}, nil, func(e *Expectations) error {
+			_, err := e.ExpectJoinAccept(context.Background(), 10*time.Millisecond)
+			return err
+		}, false, 0},
+		{"data instead of a join accept", []*Downlink{data}, nil, func(e *Expectations) error {
+			_, err := e.ExpectJoinAccept(context.Background(), 10*time.Millisecond)
+			return err
+		}, true, 0},
+		{"no join accept", nil, nil, func(e *Expectations) error {
+			_, err := e.ExpectJoinAccept(context.Background(), 10*time.Millisecond)
+			return err
+		}, true, 0},
+		{"downlinks in order", []*Downlink{data, joinAccept}, nil, func(e *Expectations) error {
+			_, err := e.ExpectDownlink(context.Background(), 10*time.Millisecond, &DownlinkExpectation{FPort: uint8Ptr(1)})
+			return err
+		}, false, 1},
+		{"mismatching downlink", []*Downlink{data}, nil, func(e *Expectations) error {
+			_, err := e.ExpectDownlink(context.Background(), 10*time.Millisecond, &DownlinkExpectation{FPort: uint8Ptr(2)})
+			return err
+		}, true, 0},
+		{"no downlink ignores earlier ones", []*Downlink{data}, nil, func(e *Expectations) error {
+			return e.ExpectNoDownlink(context.Background(), 20*time.Millisecond)
+		}, false, 0},
+		{"unexpected downlink", nil, data, func(e *Expectations) error {
+			return e.ExpectNoDownlink(context.Background(), time.Second)
+		}, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExpectations()
+			for _, dl := range tt.recorded {
+				e.Record(dl)
+			}
+			if tt.later != nil {
+				time.AfterFunc(10*time.Millisecond, func() { e.Record(tt.later) })
+			}
+
+			if err := tt.expect(e); (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			remaining := 0
+			for e.pop() != nil {
+				remaining++
+			}
+			if remaining != tt.remaining {
+				t.Errorf("got %d downlinks left, expected %d", remaining, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestScenarioRunnerExpectations(t *testing.T) {
+	s := &Scenario{Name: "expectations", Steps: []*ScenarioStep{
+		{Action: StepJoin, Timeout: Duration{time.Second}},
+		{Action: StepUplink, Payload: "01", FPort: intPtr(1), Confirmed: true},
+		{Action: StepExpectDownlink, Timeout: Duration{time.Second}, ACK: boolPtr(true), MACCommand: "LinkADRReq", MACFields: map[string]interface{}{"dataRate": int64(5)}},
+		{Action: StepUplink, Payload: "02", FPort: intPtr(1)},
+		{Action: StepExpectNoDownlink, Duration: Duration{20 * time.Millisecond}},
+		{Action: StepUplink, Payload: "03", FPort: intPtr(1)},
+		{Action: StepExpectDownlink, Timeout: Duration{50 * time.Millisecond}},
+		{Action: StepUplink},
+	}}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	target := &fakeTarget{
+		joinAccept: func() *Downlink { return &Downlink{MType: lorawan.JoinAccept} },
+		uplinkAnswers: func(up sentUplink) *Downlink {
+			if up.mType != lorawan.ConfirmedDataUp {
+				return nil
+			}
+			return &Downlink{
+				MType:       lorawan.UnconfirmedDataDown,
+				FCtrl:       lorawan.FCtrl{ACK: true},
+				MACCommands: []lorawan.MACCommand{{CID: lorawan.LinkADRReq, Payload: &lorawan.LinkADRReqPayload{DataRate: 5}}},
+			}
+		},
+	}
+
+	result, err := (&ScenarioRunner{Target: target, Config: NewConfig()}).Run(context.Background(), s)
+	if errors.Cause(err) != ErrTimeout {
+		t.Fatalf("got error %v, expected the last downlink to time out", err)
+	}
+	for i, sr := range result.Steps {
+		if failed := sr.Err != nil; failed != (i == 6) {
+			t.Errorf("step %d: got error %v", i+1, sr.Err)
+		}
+		if sr.Skipped != (i == 7) {
+			t.Errorf("step %d: got skipped %t", i+1, sr.Skipped)
+		}
+	}
+	if len(target.uplinks) != 3 {
+		t.Errorf("got %d uplinks, expected 3", len(target.uplinks))
+	}
+}
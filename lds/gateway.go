@@ -0,0 +1,288 @@
+package lds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/band"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// gpsEpoch is the origin of GPS time.
+var gpsEpoch = time.Date(1980, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+// leapSeconds holds the UTC instants at which a leap second was added since the GPS epoch.
+// GPS time doesn't have leap seconds, so it runs ahead of UTC by one second per entry.
+var leapSeconds = []time.Time{
+	time.Date(1981, time.July, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1982, time.July, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1983, time.July, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1985, time.July, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1988, time.January, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1991, time.January, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1992, time.July, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1993, time.July, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1994, time.July, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1996, time.January, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1997, time.July, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2006, time.January, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2009, time.January, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2012, time.July, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2015, time.July, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+}
+
+// TimeSinceGPSEpoch returns the GPS time at t, i.e. the time elapsed since the GPS epoch including leap seconds.
+func TimeSinceGPSEpoch(t time.Time) time.Duration {
+	d := t.Sub(gpsEpoch)
+	for _, ls := range leapSeconds {
+		if !t.Before(ls) {
+			d += time.Second
+		}
+	}
+	return d
+}
+
+// keepUplinks is how long received uplinks are remembered to check the downlinks scheduled in response to them.
+const keepUplinks = time.Minute
+
+// rxWindowTolerance is how far from the start of a RX window a downlink may be emitted, as devices must open it
+// within 20µs of the RX delay.
+const rxWindowTolerance = 20 * time.Microsecond
+
+// maxRXDelay is the longest delay after an uplink at which a class A downlink may be scheduled (RX2 with a 15s RX1 delay).
+const maxRXDelay = 16 * time.Second
+
+// Gateway holds the state of a simulated gateway.
+type Gateway struct {
+	MAC string
+
+	boot time.Time
+
+	mu               sync.Mutex
+	band             band.Band
+	uplinks          []uplinkRecord
+	schedulingErrors uint64
+	ackPolicy        AckPolicy
+	config           *gw.GatewayConfiguration
+	rxReceived       uint32
+	rxReceivedOK     uint32
+	txReceived       uint32
+	txEmitted        uint32
+}
+
+type uplinkRecord struct {
+	counter uint32
+	time    time.Time
+	txInfo  *gw.UplinkTXInfo
+}
+
+var (
+	gateways   = make(map[string]*Gateway)
+	gatewaysMu sync.Mutex
+)
+
+// GetGateway returns the simulated gateway with the given MAC, booting it on first use.
+func GetGateway(mac string) *Gateway {
+	gatewaysMu.Lock()
+	defer gatewaysMu.Unlock()
+
+	g, ok := gateways[mac]
+	if !ok {
+		g = &Gateway{
+			MAC:  mac,
+			boot: time.Now(),
+		}
+		gateways[mac] = g
+	}
+	return g
+}
+
+// Counter returns the concentrator counter at t: microseconds since the gateway booted, wrapping around every ~71 minutes.
+func (g *Gateway) Counter(t time.Time) uint32 {
+	return uint32(t.Sub(g.boot) / time.Microsecond)
+}
+
+// receive simulates the reception of an uplink of the given size: the channel simulator keeps it on air
+// and the rx info is stamped with the time at which the gateway finished receiving it.
+func (g *Gateway) receive(rxInfo *gw.UplinkRXInfo, txInfo *gw.UplinkTXInfo, size int) error {
+	//The concentrator doesn't listen on channels it isn't configured for, so the uplink isn't even on air for it.
+	if err := g.checkChannel(txInfo); err != nil {
+		log.Warningln(err)
+		return err
+	}
+
+	//Uplinks lost to collisions or noise were still received, but not demodulated.
+	g.mu.Lock()
+	g.rxReceived++
+	g.mu.Unlock()
+
+	if err := getChannelSimulator().transmit(g.MAC, rxInfo, txInfo, size); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	g.rxReceivedOK++
+	g.mu.Unlock()
+
+	now := time.Now()
+	g.setRXTime(rxInfo, now)
+	g.setContext(rxInfo, txInfo, now)
+	return nil
+}
+
+// SetBand sets the band the gateway operates in, which is needed to validate downlink TX parameters.
+func (g *Gateway) SetBand(bandName band.Name) error {
+	b, err := band.GetConfig(bandName, false, lorawan.DwellTimeNoLimit)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	g.band = b
+	g.mu.Unlock()
+	return nil
+}
+
+// setContext stores the concentrator counter at reception in the uplink context, as the packet forwarder
+// based gateway bridge does, and remembers the uplink so that the downlink scheduled for it can be checked.
+func (g *Gateway) setContext(rxInfo *gw.UplinkRXInfo, txInfo *gw.UplinkTXInfo, t time.Time) {
+	counter := g.Counter(t)
+	rxInfo.Context = make([]byte, 4)
+	binary.BigEndian.PutUint32(rxInfo.Context, counter)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	uplinks := g.uplinks[:0]
+	for _, u := range g.uplinks {
+		if t.Sub(u.time) < keepUplinks {
+			uplinks = append(uplinks, u)
+		}
+	}
+	g.uplinks = append(uplinks, uplinkRecord{counter: counter, time: t, txInfo: txInfo})
+}
+
+// uplinkForContext returns the uplink a downlink sent with the given context and delay answers to, checking that
+// the downlink reached the gateway before it had to be emitted.
+func (g *Gateway) uplinkForContext(context []byte, delay time.Duration, now time.Time) (*uplinkRecord, error) {
+	if len(context) != 4 {
+		return nil, g.schedulingError(fmt.Errorf("invalid downlink context %x", context))
+	}
+
+	counter := binary.BigEndian.Uint32(context)
+
+	g.mu.Lock()
+	var uplink *uplinkRecord
+	for i := range g.uplinks {
+		if g.uplinks[i].counter == counter {
+			u := g.uplinks[i]
+			uplink = &u
+			break
+		}
+	}
+	g.mu.Unlock()
+
+	if uplink == nil {
+		return nil, g.schedulingError(fmt.Errorf("downlink context %x doesn't match any uplink", context))
+	}
+
+	if delay <= 0 || delay > maxRXDelay {
+		return uplink, g.schedulingError(fmt.Errorf("downlink delay %s is out of the RX windows", delay))
+	}
+
+	return uplink, g.checkArrival(uplink, delay, now)
+}
+
+// uplinkForTimestamp returns the most recent uplink that a downlink scheduled at the given concentrator counter may
+// answer to, along with the RX delay of the downlink measured from that uplink.
+func (g *Gateway) uplinkForTimestamp(tmst uint32, now time.Time) (*uplinkRecord, time.Duration, error) {
+	g.mu.Lock()
+	var uplink *uplinkRecord
+	var delay time.Duration
+	for i := len(g.uplinks) - 1; i >= 0; i-- {
+		//Unsigned arithmetic takes care of the counter wrapping around.
+		d := time.Duration(tmst-g.uplinks[i].counter) * time.Microsecond
+		if d > 0 && d <= maxRXDelay {
+			u := g.uplinks[i]
+			uplink = &u
+			delay = d
+			break
+		}
+	}
+	g.mu.Unlock()
+
+	if uplink == nil {
+		return nil, 0, g.schedulingError(fmt.Errorf("downlink timestamp %d doesn't follow any uplink within the RX windows", tmst))
+	}
+
+	return uplink, delay, g.checkArrival(uplink, delay, now)
+}
+
+// checkArrival checks that a downlink to be emitted delay after the uplink reached the gateway before that.
+func (g *Gateway) checkArrival(uplink *uplinkRecord, delay time.Duration, now time.Time) error {
+	if late := now.Sub(uplink.time); late >= delay {
+		return g.schedulingError(fmt.Errorf("downlink arrived %s after the uplink, past its %s RX delay", late, delay))
+	}
+	return nil
+}
+
+// Stats returns the gateway stats: the packet counters since the gateway booted, stamped with the current time.
+func (g *Gateway) Stats() *gw.GatewayStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats := &gw.GatewayStats{
+		RxPacketsReceived:   g.rxReceived,
+		RxPacketsReceivedOk: g.rxReceivedOK,
+		TxPacketsReceived:   g.txReceived,
+		TxPacketsEmitted:    g.txEmitted,
+		ConfigVersion:       g.config.GetVersion(),
+	}
+	stats.GatewayId, _ = MACToGatewayID(g.MAC)
+	stats.Time, _ = ptypes.TimestampProto(time.Now())
+	return stats
+}
+
+// countDownlink counts a downlink received from the network server and whether it was emitted.
+func (g *Gateway) countDownlink(emitted bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.txReceived++
+	if emitted {
+		g.txEmitted++
+	}
+}
+
+// SchedulingErrors returns how many downlinks were scheduled by the network server with a wrong context or timing.
+func (g *Gateway) SchedulingErrors() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.schedulingErrors
+}
+
+func (g *Gateway) schedulingError(err error) error {
+	g.mu.Lock()
+	g.schedulingErrors++
+	g.mu.Unlock()
+
+	log.Warningf("network server scheduling error on gateway %s: %s", g.MAC, err)
+	return errors.Wrap(ErrDownlinkScheduling, err.Error())
+}
+
+func (g *Gateway) setRXTime(rxInfo *gw.UplinkRXInfo, t time.Time) {
+	rxTime, err := ptypes.TimestampProto(t)
+	if err == nil {
+		rxInfo.Time = rxTime
+	}
+	rxInfo.TimeSinceGpsEpoch = ptypes.DurationProto(TimeSinceGPSEpoch(t))
+}
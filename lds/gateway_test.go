@@ -0,0 +1,238 @@
+package lds
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/common"
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan/band"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+)
+
+func TestTimeSinceGPSEpoch(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		gps  time.Duration
+	}{
+		{"epoch", gpsEpoch, 0},
+		{"before the first leap second", time.Date(1981, time.June, 30, 23, 59, 59, 0, time.UTC), 46828799 * time.Second},
+		{"first leap second", time.Date(1981, time.July, 1, 0, 0, 0, 0, time.UTC), 46828801 * time.Second},
+		{"2020", time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC), 1261872018 * time.Second},
+		{"other time zone", time.Date(2020, time.January, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600)), 1261872018 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gps := TimeSinceGPSEpoch(tt.t); gps != tt.gps {
+				t.Errorf("got %s, expected %s", gps, tt.gps)
+			}
+		})
+	}
+}
+
+func TestGatewayCounter(t *testing.T) {
+	boot := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	g := &Gateway{MAC: "0102030405060708", boot: boot}
+
+	tests := []struct {
+		name    string
+		t       time.Time
+		counter uint32
+	}{
+		{"boot", boot, 0},
+		{"microseconds", boot.Add(1500 * time.Microsecond), 1500},
+		{"before wrapping around", boot.Add((1<<32 - 1) * time.Microsecond), 1<<32 - 1},
+		{"wrapped around", boot.Add((1<<32 + 5) * time.Microsecond), 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if counter := g.Counter(tt.t); counter != tt.counter {
+				t.Errorf("got %d, expected %d", counter, tt.counter)
+			}
+		})
+	}
+}
+
+func TestGatewayReceive(t *testing.T) {
+	g := &Gateway{MAC: "0102030405060708", boot: time.Now().Add(-time.Second)}
+	rxInfo := &gw.UplinkRXInfo{}
+
+	before := time.Now()
+	if err := g.receive(rxInfo, loraTXInfo(868100000, 7), 13); err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now()
+
+	rxTime, err := ptypes.Timestamp(rxInfo.GetTime())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rxTime.Before(before) || rxTime.After(after) {
+		t.Errorf("rx time %s isn't the reception time", rxTime)
+	}
+
+	gps, err := ptypes.Duration(rxInfo.GetTimeSinceGpsEpoch())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := TimeSinceGPSEpoch(rxTime); gps != expected {
+		t.Errorf("got GPS time %s, expected %s", gps, expected)
+	}
+
+	if len(rxInfo.GetContext()) != 4 {
+		t.Fatalf("got context %x, expected the concentrator counter", rxInfo.GetContext())
+	}
+	if counter := binary.BigEndian.Uint32(rxInfo.GetContext()); counter != g.Counter(rxTime) {
+		t.Errorf("got counter %d in the context, expected %d", counter, g.Counter(rxTime))
+	}
+
+	if stats := g.Stats(); stats.GetRxPacketsReceived() != 1 || stats.GetRxPacketsReceivedOk() != 1 {
+		t.Errorf("got stats %+v, expected one uplink received", stats)
+	}
+}
+
+func TestGatewayReceiveLost(t *testing.T) {
+	channelSimMu.Lock()
+	previous := channelSim
+	channelSim = &ChannelSimulator{CaptureThreshold: DefaultCaptureThreshold, Demodulators: 1}
+	channelSimMu.Unlock()
+	defer func() {
+		channelSimMu.Lock()
+		channelSim = previous
+		channelSimMu.Unlock()
+	}()
+
+	//The second uplink starts while the only demodulator receives the first one.
+	g := &Gateway{MAC: "receive lost", boot: time.Now().Add(-time.Second)}
+	first := make(chan error)
+	go func() {
+		first <- g.receive(&gw.UplinkRXInfo{}, loraTXInfo(868100000, 7), 13)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := g.receive(&gw.UplinkRXInfo{}, loraTXInfo(868300000, 7), 13); errors.Cause(err) != ErrFrameLost {
+		t.Errorf("got error %v, expected %v", err, ErrFrameLost)
+	}
+	if err := <-first; err != nil {
+		t.Fatal(err)
+	}
+
+	if stats := g.Stats(); stats.GetRxPacketsReceived() != 2 || stats.GetRxPacketsReceivedOk() != 1 {
+		t.Errorf("got %d uplinks received and %d received OK, expected 2 and 1", stats.GetRxPacketsReceived(), stats.GetRxPacketsReceivedOk())
+	}
+}
+
+// scheduledGateway returns an EU868 gateway that received an SF7 uplink on 868.1 MHz at the given time,
+// and the context of that uplink.
+func scheduledGateway(t *testing.T, received time.Time) (*Gateway, []byte) {
+	g := &Gateway{MAC: "0102030405060708", boot: received.Add(-time.Minute)}
+	if err := g.SetBand(band.EU868); err != nil {
+		t.Fatal(err)
+	}
+
+	rxInfo := &gw.UplinkRXInfo{}
+	g.setContext(rxInfo, loraTXInfo(868100000, 7), received)
+	return g, rxInfo.GetContext()
+}
+
+func delayTXInfo(frequency, sf uint32, delay time.Duration, context []byte) *gw.DownlinkTXInfo {
+	return &gw.DownlinkTXInfo{
+		Frequency:  frequency,
+		Power:      14,
+		Modulation: common.Modulation_LORA,
+		ModulationInfo: &gw.DownlinkTXInfo_LoraModulationInfo{
+			LoraModulationInfo: &gw.LoRaModulationInfo{Bandwidth: 125, SpreadingFactor: sf, CodeRate: "4/5", PolarizationInversion: true},
+		},
+		Timing:     gw.DownlinkTiming_DELAY,
+		TimingInfo: &gw.DownlinkTXInfo_DelayTimingInfo{DelayTimingInfo: &gw.DelayTimingInfo{Delay: ptypes.DurationProto(delay)}},
+		Context:    context,
+	}
+}
+
+func TestCheckDownlinkContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		received time.Duration
+		txInfo   func(context []byte) *gw.DownlinkTXInfo
+		err      bool
+	}{
+		{"RX1", 100 * time.Millisecond, func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(868100000, 7, time.Second, c) }, false},
+		{"RX1 with a longer RX delay", 100 * time.Millisecond, func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(868100000, 7, 5*time.Second, c) }, false},
+		{"RX2", 100 * time.Millisecond, func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(869525000, 12, 2*time.Second, c) }, false},
+		{"RX1 out of the window", 100 * time.Millisecond, func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(868100000, 7, 1500*time.Millisecond, c) }, true},
+		{"RX2 one second after the uplink", 100 * time.Millisecond, func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(869525000, 12, time.Second, c) }, true},
+		{"delay over the RX windows", 100 * time.Millisecond, func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(868100000, 7, 20*time.Second, c) }, true},
+		{"unknown context", 100 * time.Millisecond, func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(868100000, 7, time.Second, []byte{1, 2, 3, 4}) }, true},
+		{"invalid context", 100 * time.Millisecond, func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(868100000, 7, time.Second, []byte{1}) }, true},
+		{"arrived too late", 1500 * time.Millisecond, func(c []byte) *gw.DownlinkTXInfo { return delayTXInfo(868100000, 7, time.Second, c) }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, context := scheduledGateway(t, time.Now().Add(-tt.received))
+			err := g.CheckDownlinkTXInfo(tt.txInfo(context))
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if err != nil && errors.Cause(err) != ErrDownlinkScheduling {
+				t.Errorf("got error %v, expected a scheduling error", err)
+			}
+			if expected := map[bool]uint64{false: 0, true: 1}[tt.err]; g.SchedulingErrors() != expected {
+				t.Errorf("got %d scheduling errors, expected %d", g.SchedulingErrors(), expected)
+			}
+		})
+	}
+}
+
+func TestUplinkForTimestamp(t *testing.T) {
+	now := time.Now()
+	g, context := scheduledGateway(t, now.Add(-100*time.Millisecond))
+	counter := binary.BigEndian.Uint32(context)
+
+	tests := []struct {
+		name  string
+		tmst  uint32
+		delay time.Duration
+		err   bool
+	}{
+		{"RX1", counter + 1000000, time.Second, false},
+		{"RX2", counter + 2000000, 2 * time.Second, false},
+		{"before the uplink", counter - 1, 0, true},
+		{"after the RX windows", counter + 17000000, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, delay, err := g.uplinkForTimestamp(tt.tmst, now)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if u.counter != counter || delay != tt.delay {
+				t.Errorf("got uplink %d and delay %s, expected %d and %s", u.counter, delay, counter, tt.delay)
+			}
+		})
+	}
+}
+
+func TestUplinkForTimestampWrapAround(t *testing.T) {
+	now := time.Now()
+	g := &Gateway{MAC: "0102030405060708", boot: now.Add(-(1<<32 - 500000) * time.Microsecond)}
+	rxInfo := &gw.UplinkRXInfo{}
+	g.setContext(rxInfo, loraTXInfo(868100000, 7), now)
+
+	//The counter wraps around between the uplink and its RX1 window.
+	tmst := binary.BigEndian.Uint32(rxInfo.GetContext()) + 1000000
+	if tmst > 1000000 {
+		t.Fatalf("tmst %d didn't wrap around", tmst)
+	}
+	if _, delay, err := g.uplinkForTimestamp(tmst, now); err != nil || delay != time.Second {
+		t.Errorf("got delay %s and error %v, expected a second", delay, err)
+	}
+}
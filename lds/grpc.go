@@ -0,0 +1,420 @@
+package lds
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/band"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/iegomez/lds/api/ldspb"
+)
+
+// grpcEventBuffer is how many events are kept for a slow event stream client before dropping them.
+const grpcEventBuffer = 64
+
+// UplinkRadio is how the simulated gateway receives the uplinks.
+type UplinkRadio struct {
+	Band   band.Name
+	DR     DataRateConfig
+	RXInfo RXInfoConfig
+}
+
+// GRPCBackend is the simulator driven by the gRPC API, which adds the gateway and the device events to the HTTP API.
+type GRPCBackend interface {
+	APIBackend
+	//AddEventHandler adds a function called with the device events, and returns the function removing it.
+	AddEventHandler(h func(*Event)) func()
+	//UplinkRadio returns how the gateway receives the uplinks.
+	UplinkRadio() UplinkRadio
+	//SetUplinkRadio sets how the gateway receives the next uplinks.
+	SetUplinkRadio(r UplinkRadio) error
+}
+
+// GRPCServer is the gRPC API.
+type GRPCServer struct {
+	listener net.Listener
+	server   *grpc.Server
+}
+
+// NewGRPCServer starts serving the gRPC API on addr. Uplinks that don't give them use the data, fPort and message type of config.
+func NewGRPCServer(addr string, backend GRPCBackend, config *Config) (*GRPCServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "grpc listen error")
+	}
+
+	s := &GRPCServer{
+		listener: listener,
+		server:   grpc.NewServer(),
+	}
+	ldspb.RegisterSimulatorServer(s.server, &simulatorService{backend: backend, config: config})
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			log.Errorf("grpc server error: %s", err)
+		}
+	}()
+
+	log.Infof("gRPC API listening on %s", listener.Addr())
+	return s, nil
+}
+
+// Addr returns the address the API listens on.
+func (s *GRPCServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the API, ending the event streams.
+func (s *GRPCServer) Close() {
+	s.server.Stop()
+}
+
+// simulatorService implements the Simulator service on the backend.
+type simulatorService struct {
+	backend GRPCBackend
+	config  *Config
+}
+
+func grpcError(err error) error {
+	switch errors.Cause(err) {
+	case ErrNoDevice:
+		return status.Error(codes.NotFound, err.Error())
+	case ErrDeviceExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func (s *simulatorService) GetDevice(ctx context.Context, req *emptypb.Empty) (*ldspb.Device, error) {
+	dc, err := s.backend.DeviceConfig()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return deviceToProto(&dc), nil
+}
+
+func (s *simulatorService) CreateDevice(ctx context.Context, req *ldspb.Device) (*ldspb.Device, error) {
+	return s.putDevice(req, true)
+}
+
+func (s *simulatorService) UpdateDevice(ctx context.Context, req *ldspb.Device) (*ldspb.Device, error) {
+	return s.putDevice(req, false)
+}
+
+func (s *simulatorService) putDevice(req *ldspb.Device, create bool) (*ldspb.Device, error) {
+	dc := deviceFromProto(req)
+	if err := validateDeviceConfig(&dc); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if err := s.backend.PutDeviceConfig(dc, create); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.GetDevice(context.Background(), nil)
+}
+
+func (s *simulatorService) DeleteDevice(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
+	if err := s.backend.DeleteDevice(); err != nil {
+		return nil, grpcError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *simulatorService) GetSession(ctx context.Context, req *emptypb.Empty) (*ldspb.Session, error) {
+	if _, err := s.backend.DeviceConfig(); err != nil {
+		return nil, grpcError(err)
+	}
+
+	d := s.backend.State()
+	return &ldspb.Session{
+		DevEui:      d.DevEUI[:],
+		Profile:     ldspb.Profile(ldspb.Profile_value[d.Profile]),
+		Joined:      d.Joined,
+		DevAddr:     d.DevAddr[:],
+		UlFCnt:      d.UlFcnt,
+		DlFCnt:      d.DlFcnt,
+		DevNonce:    uint32(d.DevNonce),
+		JoinNonce:   uint32(d.JoinNonce),
+		NwkSEncKey:  d.NwkSEncKey[:],
+		SNwkSIntKey: d.SNwkSIntKey[:],
+		FNwkSIntKey: d.FNwkSIntKey[:],
+		AppSKey:     d.AppSKey[:],
+	}, nil
+}
+
+func (s *simulatorService) SetCounters(ctx context.Context, req *ldspb.Counters) (*emptypb.Empty, error) {
+	if err := s.backend.SetValues(int(req.UlFCnt), int(req.DlFCnt), int(req.DevNonce), int(req.JoinNonce)); err != nil {
+		return nil, grpcError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *simulatorService) ResetDevice(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
+	if err := s.backend.Reset(); err != nil {
+		return nil, grpcError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *simulatorService) GetGateway(ctx context.Context, req *emptypb.Empty) (*ldspb.Gateway, error) {
+	r := s.backend.UplinkRadio()
+	g := GetGateway(s.config.GW.MAC)
+
+	resp := &ldspb.Gateway{
+		Band:      string(r.Band),
+		Frequency: uint32(r.RXInfo.Frequency),
+		LoraModulationInfo: &gw.LoRaModulationInfo{
+			Bandwidth:       uint32(r.DR.Bandwidth),
+			SpreadingFactor: uint32(r.DR.SpreadFactor),
+			CodeRate:        r.RXInfo.CodeRate,
+		},
+		Rssi:             int32(r.RXInfo.Rssi),
+		LoraSnr:          r.RXInfo.LoRaSNR,
+		Channel:          uint32(r.RXInfo.Channel),
+		RfChain:          uint32(r.RXInfo.RfChain),
+		CrcStatus:        int32(r.RXInfo.CrcStatus),
+		Stats:            g.Stats(),
+		SchedulingErrors: g.SchedulingErrors(),
+	}
+	resp.GatewayId, _ = MACToGatewayID(s.config.GW.MAC)
+	return resp, nil
+}
+
+func (s *simulatorService) UpdateGateway(ctx context.Context, req *ldspb.Gateway) (*ldspb.Gateway, error) {
+	r := s.backend.UplinkRadio()
+	if req.Band != "" {
+		if _, err := band.GetConfig(band.Name(req.Band), false, lorawan.DwellTimeNoLimit); err != nil {
+			return nil, invalidArgument(err)
+		}
+		r.Band = band.Name(req.Band)
+	}
+	if mi := req.LoraModulationInfo; mi != nil {
+		r.DR.Bandwidth = int(mi.Bandwidth)
+		r.DR.SpreadFactor = int(mi.SpreadingFactor)
+		r.RXInfo.CodeRate = mi.CodeRate
+	}
+	r.RXInfo.Frequency = int(req.Frequency)
+	r.RXInfo.Rssi = int(req.Rssi)
+	r.RXInfo.LoRaSNR = req.LoraSnr
+	r.RXInfo.Channel = int(req.Channel)
+	r.RXInfo.RfChain = int(req.RfChain)
+	r.RXInfo.CrcStatus = int(req.CrcStatus)
+
+	if err := s.backend.SetUplinkRadio(r); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.GetGateway(ctx, nil)
+}
+
+func (s *simulatorService) Join(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
+	if err := s.backend.Join(); err != nil {
+		return nil, grpcError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *simulatorService) Uplink(ctx context.Context, req *ldspb.UplinkRequest) (*ldspb.UplinkResponse, error) {
+	var fPort *int
+	if req.FPort != nil {
+		p := int(req.FPort.Value)
+		if err := CheckFPort(p); err != nil {
+			return nil, invalidArgument(err)
+		}
+		fPort = &p
+	}
+	var confirmed *bool
+	if req.Confirmed != nil {
+		confirmed = &req.Confirmed.Value
+	}
+
+	payload, port, mType, err := uplinkArgs(s.config, req.FrmPayload, fPort, confirmed)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	var macCommands []*lorawan.MACCommand
+	for _, c := range req.MacCommands {
+		var mac lorawan.MACCommand
+		if err := mac.UnmarshalBinary(true, append([]byte{byte(c.Cid)}, c.Payload...)); err != nil {
+			return nil, invalidArgument(errors.Wrapf(err, "mac command %d", c.Cid))
+		}
+		macCommands = append(macCommands, &mac)
+	}
+
+	var fCtrl lorawan.FCtrl
+	if c := req.FCtrl; c != nil {
+		fCtrl = lorawan.FCtrl{ADR: c.Adr, ADRACKReq: c.AdrAckReq, ACK: c.Ack, FPending: c.FPending, ClassB: c.ClassB}
+	}
+
+	fCnt, err := s.backend.Uplink(mType, port, payload, macCommands, fCtrl)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &ldspb.UplinkResponse{FCnt: fCnt}, nil
+}
+
+func (s *simulatorService) Start(ctx context.Context, req *ldspb.StartRequest) (*emptypb.Empty, error) {
+	interval, err := ptypes.Duration(req.Interval)
+	if err != nil {
+		return nil, invalidArgument(errors.Wrap(err, "interval"))
+	}
+	if interval < time.Second {
+		return nil, invalidArgument(errors.New("the interval must be at least 1s"))
+	}
+
+	if err := s.backend.Start(interval); err != nil {
+		return nil, grpcError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *simulatorService) Stop(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
+	if err := s.backend.Stop(); err != nil {
+		return nil, grpcError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *simulatorService) SubscribeEvents(req *ldspb.SubscribeEventsRequest, stream ldspb.Simulator_SubscribeEventsServer) error {
+	events := make(chan *Event, grpcEventBuffer)
+	remove := s.backend.AddEventHandler(func(e *Event) {
+		select {
+		case events <- e:
+		default:
+			log.Warningln("grpc event stream is full, dropping event")
+		}
+	})
+	defer remove()
+
+	for {
+		select {
+		case e := <-events:
+			if err := stream.Send(eventToProto(e)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// deviceFromProto returns the device configuration with hex encoded keys, as the HTTP API and toml files give it.
+func deviceFromProto(d *ldspb.Device) DeviceConfig {
+	return DeviceConfig{
+		DevEUI:        hex.EncodeToString(d.DevEui),
+		DevAddress:    hex.EncodeToString(d.DevAddr),
+		NwkSEncKey:    hex.EncodeToString(d.NwkSEncKey),
+		SNwkSIntKey:   hex.EncodeToString(d.SNwkSIntKey),
+		FNwkSIntKey:   hex.EncodeToString(d.FNwkSIntKey),
+		AppSKey:       hex.EncodeToString(d.AppSKey),
+		Marshaler:     d.Marshaler,
+		NwkKey:        hex.EncodeToString(d.NwkKey),
+		AppKey:        hex.EncodeToString(d.AppKey),
+		JoinEUI:       hex.EncodeToString(d.JoinEui),
+		MACVersion:    lorawan.MACVersion(d.MacVersion),
+		Profile:       d.Profile.String(),
+		Joined:        d.Joined,
+		SkipFCntCheck: d.SkipFCntCheck,
+	}
+}
+
+func deviceToProto(dc *DeviceConfig) *ldspb.Device {
+	//The configuration was validated when set, a wrong key in a toml file is returned empty.
+	decode := func(s string) []byte {
+		b, _ := hex.DecodeString(s)
+		return b
+	}
+	return &ldspb.Device{
+		DevEui:        decode(dc.DevEUI),
+		JoinEui:       decode(dc.JoinEUI),
+		NwkKey:        decode(dc.NwkKey),
+		AppKey:        decode(dc.AppKey),
+		Profile:       ldspb.Profile(ldspb.Profile_value[dc.Profile]),
+		MacVersion:    ldspb.MACVersion(dc.MACVersion),
+		DevAddr:       decode(dc.DevAddress),
+		NwkSEncKey:    decode(dc.NwkSEncKey),
+		SNwkSIntKey:   decode(dc.SNwkSIntKey),
+		FNwkSIntKey:   decode(dc.FNwkSIntKey),
+		AppSKey:       decode(dc.AppSKey),
+		Joined:        dc.Joined,
+		SkipFCntCheck: dc.SkipFCntCheck,
+		Marshaler:     dc.Marshaler,
+	}
+}
+
+func fCtrlToProto(c lorawan.FCtrl) *ldspb.FCtrl {
+	return &ldspb.FCtrl{Adr: c.ADR, AdrAckReq: c.ADRACKReq, Ack: c.ACK, FPending: c.FPending, ClassB: c.ClassB}
+}
+
+func macCommandsToProto(commands []lorawan.MACCommand) []*ldspb.MACCommand {
+	var pb []*ldspb.MACCommand
+	for _, mac := range commands {
+		c := &ldspb.MACCommand{Cid: uint32(mac.CID)}
+		if mac.Payload != nil {
+			c.Payload, _ = mac.Payload.MarshalBinary()
+		}
+		pb = append(pb, c)
+	}
+	return pb
+}
+
+func eventToProto(e *Event) *ldspb.Event {
+	pb := &ldspb.Event{DevEui: e.DevEUI[:]}
+	pb.Time, _ = ptypes.TimestampProto(e.Time)
+
+	switch e.Type {
+	case EventUplinkSent:
+		ul := e.Uplink
+		pb.Event = &ldspb.Event_UplinkSent{UplinkSent: &ldspb.UplinkSentEvent{
+			MType:       ldspb.MType(ul.MType),
+			PhyPayload:  ul.PHYPayload,
+			RxInfo:      ul.RXInfo,
+			TxInfo:      ul.TXInfo,
+			DevAddr:     ul.DevAddr[:],
+			FCtrl:       fCtrlToProto(ul.FCtrl),
+			FCnt:        ul.FCnt,
+			FPort:       uint32(ul.FPort),
+			FrmPayload:  ul.FRMPayload,
+			MacCommands: macCommandsToProto(ul.MACCommands),
+			DevNonce:    uint32(ul.DevNonce),
+		}}
+	case EventDownlinkReceived:
+		dl := e.Downlink
+		ev := &ldspb.DownlinkReceivedEvent{
+			MType:       ldspb.MType(dl.MType),
+			DevAddr:     dl.DevAddr[:],
+			FCtrl:       fCtrlToProto(dl.FCtrl),
+			FCnt:        dl.FCnt,
+			FrmPayload:  dl.FRMPayload,
+			MacCommands: macCommandsToProto(dl.MACCommands),
+		}
+		if dl.FPort != nil {
+			ev.FPort = &wrapperspb.UInt32Value{Value: uint32(*dl.FPort)}
+		}
+		pb.Event = &ldspb.Event_DownlinkReceived{DownlinkReceived: ev}
+	case EventJoinAccepted:
+		pb.Event = &ldspb.Event_JoinAccepted{JoinAccepted: &ldspb.JoinAcceptedEvent{DevAddr: e.Downlink.DevAddr[:]}}
+	case EventMICFailure:
+		pb.Event = &ldspb.Event_MicFailure{MicFailure: &ldspb.MICFailureEvent{
+			MType:      ldspb.MType(e.MICFailure.MType),
+			PhyPayload: e.MICFailure.PHYPayload,
+			Error:      e.MICFailure.Err.Error(),
+		}}
+	}
+	return pb
+}
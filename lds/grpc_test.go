@@ -0,0 +1,331 @@
+package lds
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/band"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/iegomez/lds/api/ldspb"
+)
+
+// fakeGRPCBackend adds the events and the uplink radio to a fakeBackend.
+type fakeGRPCBackend struct {
+	*fakeBackend
+	mu       sync.Mutex
+	handlers []func(*Event)
+	radio    UplinkRadio
+}
+
+func (b *fakeGRPCBackend) AddEventHandler(h func(*Event)) func() {
+	b.mu.Lock()
+	b.handlers = append(b.handlers, h)
+	b.mu.Unlock()
+	return func() {}
+}
+
+func (b *fakeGRPCBackend) UplinkRadio() UplinkRadio {
+	return b.radio
+}
+
+func (b *fakeGRPCBackend) SetUplinkRadio(r UplinkRadio) error {
+	b.radio = r
+	return nil
+}
+
+func (b *fakeGRPCBackend) emit(e *Event) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, h := range b.handlers {
+		h(e)
+	}
+	return len(b.handlers)
+}
+
+func newTestSimulatorService() (*simulatorService, *fakeGRPCBackend) {
+	backend := &fakeGRPCBackend{
+		fakeBackend: newFakeBackend(),
+		radio: UplinkRadio{
+			Band:   band.EU868,
+			DR:     DataRateConfig{Bandwidth: 125, SpreadFactor: 7},
+			RXInfo: RXInfoConfig{Frequency: 868100000, CodeRate: "4/5", Rssi: -50, LoRaSNR: 7, CrcStatus: 1},
+		},
+	}
+	config := NewConfig()
+	config.GW.MAC = "0102030405060708"
+	config.RawPayload.UseRaw = true
+	config.RawPayload.Payload = "010203"
+	config.RawPayload.FPort = 2
+	config.Device.MType = lorawan.UnconfirmedDataUp
+	return &simulatorService{backend: backend, config: config}, backend
+}
+
+func TestGRPCUplink(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *ldspb.UplinkRequest
+		code    codes.Code
+		mType   lorawan.MType
+		fPort   uint8
+		payload []byte
+	}{
+		{"configured", &ldspb.UplinkRequest{}, codes.OK, lorawan.UnconfirmedDataUp, 2, []byte{1, 2, 3}},
+		{"given", &ldspb.UplinkRequest{
+			FrmPayload:  []byte{10, 11},
+			FPort:       &wrapperspb.UInt32Value{Value: 223},
+			Confirmed:   &wrapperspb.BoolValue{Value: true},
+			MacCommands: []*ldspb.MACCommand{{Cid: uint32(lorawan.LinkCheckReq)}},
+			FCtrl:       &ldspb.FCtrl{Adr: true},
+		}, codes.OK, lorawan.ConfirmedDataUp, 223, []byte{10, 11}},
+		{"fPort 0", &ldspb.UplinkRequest{FPort: &wrapperspb.UInt32Value{Value: 0}}, codes.InvalidArgument, 0, 0, nil},
+		{"fPort 224", &ldspb.UplinkRequest{FPort: &wrapperspb.UInt32Value{Value: 224}}, codes.InvalidArgument, 0, 0, nil},
+		{"fPort 256", &ldspb.UplinkRequest{FPort: &wrapperspb.UInt32Value{Value: 256}}, codes.InvalidArgument, 0, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, backend := newTestSimulatorService()
+			resp, err := s.Uplink(context.Background(), tt.req)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("got code %s (%v), expected %s", code, err, tt.code)
+			}
+			if err != nil {
+				if len(backend.uplinks) != 0 {
+					t.Error("the rejected uplink was sent")
+				}
+				return
+			}
+
+			if resp.GetFCnt() != 5 {
+				t.Errorf("got fCnt %d, expected 5", resp.GetFCnt())
+			}
+			up := backend.uplinks[0]
+			if up.mType != tt.mType || up.fPort != tt.fPort || !bytes.Equal(up.payload, tt.payload) {
+				t.Errorf("got %s on fPort %d with payload %x, expected %s on %d with %x", up.mType, up.fPort, up.payload, tt.mType, tt.fPort, tt.payload)
+			}
+			if tt.req.FCtrl != nil && (!up.fCtrl.ADR || len(up.macCommands) != 1 || up.macCommands[0].CID != lorawan.LinkCheckReq) {
+				t.Errorf("got FCtrl %+v and mac commands %v", up.fCtrl, up.macCommands)
+			}
+		})
+	}
+}
+
+func TestGRPCDevice(t *testing.T) {
+	s, backend := newTestSimulatorService()
+	ctx := context.Background()
+
+	device := &ldspb.Device{DevEui: []byte{8, 7, 6, 5, 4, 3, 2, 1}, Profile: ldspb.Profile_ABP, MacVersion: ldspb.MACVersion(lorawan.LoRaWAN1_1), AppKey: bytes.Repeat([]byte{1}, 16)}
+	if _, err := s.CreateDevice(ctx, device); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("got error %v creating over the device, expected %s", err, codes.AlreadyExists)
+	}
+	if _, err := s.UpdateDevice(ctx, &ldspb.Device{DevEui: []byte{1, 2}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got error %v for an invalid DevEUI, expected %s", err, codes.InvalidArgument)
+	}
+
+	updated, err := s.UpdateDevice(ctx, device)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(updated.GetDevEui(), device.DevEui) || updated.GetProfile() != ldspb.Profile_ABP || updated.GetMacVersion() != device.MacVersion {
+		t.Errorf("got device %+v", updated)
+	}
+	if !bytes.Equal(updated.GetAppKey(), device.AppKey) || !bytes.Equal(updated.GetNwkKey(), make([]byte, 16)) || !bytes.Equal(updated.GetDevAddr(), make([]byte, 4)) {
+		t.Errorf("got keys %x and %x and DevAddr %x", updated.GetAppKey(), updated.GetNwkKey(), updated.GetDevAddr())
+	}
+	if backend.dc.AppKey != "01010101010101010101010101010101" {
+		t.Errorf("got app key %s set on the backend", backend.dc.AppKey)
+	}
+
+	if _, err := s.DeleteDevice(ctx, &emptypb.Empty{}); err != nil {
+		t.Fatal(err)
+	}
+	for name, call := range map[string]func() error{
+		"get":     func() error { _, err := s.GetDevice(ctx, &emptypb.Empty{}); return err },
+		"session": func() error { _, err := s.GetSession(ctx, &emptypb.Empty{}); return err },
+		"delete":  func() error { _, err := s.DeleteDevice(ctx, &emptypb.Empty{}); return err },
+	} {
+		if err := call(); status.Code(err) != codes.NotFound {
+			t.Errorf("%s: got error %v without a device, expected %s", name, err, codes.NotFound)
+		}
+	}
+
+	if _, err := s.CreateDevice(ctx, device); err != nil {
+		t.Fatal(err)
+	}
+	session, err := s.GetSession(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.GetProfile() != ldspb.Profile_OTAA || session.GetUlFCnt() != 4 || len(session.GetAppSKey()) != 16 {
+		t.Errorf("got session %+v", session)
+	}
+}
+
+func TestGRPCCommands(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		call  func(s *simulatorService) error
+		code  codes.Code
+		check func(b *fakeGRPCBackend) bool
+	}{
+		{"join", func(s *simulatorService) error {
+			_, err := s.Join(ctx, &emptypb.Empty{})
+			return err
+		}, codes.OK, func(b *fakeGRPCBackend) bool { return b.joins == 1 }},
+		{"start", func(s *simulatorService) error {
+			_, err := s.Start(ctx, &ldspb.StartRequest{Interval: ptypes.DurationProto(30 * time.Second)})
+			return err
+		}, codes.OK, func(b *fakeGRPCBackend) bool { return b.interval == 30*time.Second }},
+		{"start too often", func(s *simulatorService) error {
+			_, err := s.Start(ctx, &ldspb.StartRequest{Interval: ptypes.DurationProto(100 * time.Millisecond)})
+			return err
+		}, codes.InvalidArgument, func(b *fakeGRPCBackend) bool { return b.interval == 0 }},
+		{"start without interval", func(s *simulatorService) error {
+			_, err := s.Start(ctx, &ldspb.StartRequest{})
+			return err
+		}, codes.InvalidArgument, func(b *fakeGRPCBackend) bool { return b.interval == 0 }},
+		{"stop", func(s *simulatorService) error {
+			_, err := s.Stop(ctx, &emptypb.Empty{})
+			return err
+		}, codes.OK, func(b *fakeGRPCBackend) bool { return b.interval == 0 }},
+		{"reset", func(s *simulatorService) error {
+			_, err := s.ResetDevice(ctx, &emptypb.Empty{})
+			return err
+		}, codes.OK, func(b *fakeGRPCBackend) bool { return b.resets == 1 }},
+		{"counters", func(s *simulatorService) error {
+			_, err := s.SetCounters(ctx, &ldspb.Counters{UlFCnt: 10, DlFCnt: 2, DevNonce: 5, JoinNonce: 3})
+			return err
+		}, codes.OK, func(b *fakeGRPCBackend) bool { return b.values == [4]int{10, 2, 5, 3} }},
+		{"join error", func(s *simulatorService) error {
+			s.backend.(*fakeGRPCBackend).err = ErrNoDevice
+			_, err := s.Join(ctx, &emptypb.Empty{})
+			return err
+		}, codes.NotFound, func(b *fakeGRPCBackend) bool { return b.joins == 1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, backend := newTestSimulatorService()
+			if err := tt.call(s); status.Code(err) != tt.code {
+				t.Fatalf("got error %v, expected %s", err, tt.code)
+			}
+			if !tt.check(backend) {
+				t.Errorf("got backend %+v", backend)
+			}
+		})
+	}
+}
+
+func TestGRPCGateway(t *testing.T) {
+	s, backend := newTestSimulatorService()
+	ctx := context.Background()
+
+	g, err := s.GetGateway(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(g.GetGatewayId(), testGatewayID) || g.GetBand() != string(band.EU868) || g.GetFrequency() != 868100000 {
+		t.Errorf("got gateway %+v", g)
+	}
+	if mi := g.GetLoraModulationInfo(); mi.GetBandwidth() != 125 || mi.GetSpreadingFactor() != 7 || mi.GetCodeRate() != "4/5" {
+		t.Errorf("got modulation %+v", mi)
+	}
+	if g.GetRssi() != -50 || g.GetLoraSnr() != 7 || g.GetCrcStatus() != 1 {
+		t.Errorf("got gateway %+v", g)
+	}
+
+	if _, err := s.UpdateGateway(ctx, &ldspb.Gateway{Band: "XX000"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got error %v for an unknown band, expected %s", err, codes.InvalidArgument)
+	}
+
+	g, err = s.UpdateGateway(ctx, &ldspb.Gateway{
+		Band:      string(band.US915),
+		Frequency: 902300000,
+		Rssi:      -80,
+		LoraSnr:   -2.5,
+		Channel:   3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := UplinkRadio{
+		Band:   band.US915,
+		DR:     DataRateConfig{Bandwidth: 125, SpreadFactor: 7},
+		RXInfo: RXInfoConfig{Frequency: 902300000, CodeRate: "4/5", Rssi: -80, LoRaSNR: -2.5, Channel: 3},
+	}
+	if backend.radio != expected {
+		t.Errorf("got radio %+v, expected %+v", backend.radio, expected)
+	}
+	if g.GetBand() != string(band.US915) || g.GetFrequency() != 902300000 || g.GetChannel() != 3 {
+		t.Errorf("got gateway %+v", g)
+	}
+}
+
+func TestGRPCSubscribeEvents(t *testing.T) {
+	_, backend := newTestSimulatorService()
+	server, err := NewGRPCServer("127.0.0.1:0", backend, NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	conn, err := grpc.Dial(server.Addr(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := ldspb.NewSimulatorClient(conn).SubscribeEvents(ctx, &ldspb.SubscribeEventsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fPort := uint8(3)
+	events := []*Event{
+		{Type: EventUplinkSent, Uplink: &Uplink{MType: lorawan.UnconfirmedDataUp, FCnt: 1, FPort: 2, FRMPayload: []byte{1}, MACCommands: []lorawan.MACCommand{{CID: lorawan.LinkCheckReq}}}},
+		{Type: EventDownlinkReceived, Downlink: &Downlink{MType: lorawan.UnconfirmedDataDown, FCnt: 1, FPort: &fPort, FCtrl: lorawan.FCtrl{ACK: true}}},
+		{Type: EventJoinAccepted, Downlink: &Downlink{MType: lorawan.JoinAccept, DevAddr: lorawan.DevAddr{1, 2, 3, 4}}},
+	}
+	//The stream handler may not be registered yet when the call returns.
+	for i := 0; i < 100; i++ {
+		if backend.emit(events[0]) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, e := range events[1:] {
+		backend.emit(e)
+	}
+
+	e, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if up := e.GetUplinkSent(); up.GetFCnt() != 1 || up.GetFPort() != 2 || len(up.GetMacCommands()) != 1 || up.GetMacCommands()[0].GetCid() != uint32(lorawan.LinkCheckReq) {
+		t.Errorf("got uplink event %+v", e)
+	}
+	if e, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if dl := e.GetDownlinkReceived(); dl.GetFPort().GetValue() != 3 || !dl.GetFCtrl().GetAck() {
+		t.Errorf("got downlink event %+v", e)
+	}
+	if e, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if ja := e.GetJoinAccepted(); !bytes.Equal(ja.GetDevAddr(), []byte{1, 2, 3, 4}) {
+		t.Errorf("got join accepted event %+v", e)
+	}
+}
@@ -0,0 +1,126 @@
+package lds
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brocaar/chirpstack-api/go/common"
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrChannelNotConfigured is returned when an uplink is sent on a channel the gateway isn't configured to receive on.
+var ErrChannelNotConfigured = errors.New("channel not configured at the gateway")
+
+// ApplyGatewayConfig decodes a gateway configuration command sent by the network server and applies it to the given gateway.
+func (d *Device) ApplyGatewayConfig(payload []byte, gwMAC string) error {
+	var c gw.GatewayConfiguration
+	if err := d.unmarshal(payload, &c); err != nil {
+		return err
+	}
+
+	g := GetGateway(gwMAC)
+	if gatewayID, err := MACToGatewayID(gwMAC); err == nil && len(c.GetGatewayId()) != 0 && !bytes.Equal(gatewayID, c.GetGatewayId()) {
+		return fmt.Errorf("gateway configuration for %x received by gateway %s", c.GetGatewayId(), gwMAC)
+	}
+
+	g.ApplyConfiguration(&c)
+	return nil
+}
+
+// ApplyConfiguration sets the channels the gateway receives on. Until a configuration is applied, every uplink is received.
+func (g *Gateway) ApplyConfiguration(c *gw.GatewayConfiguration) {
+	g.mu.Lock()
+	g.config = c
+	g.mu.Unlock()
+
+	log.Infof("gateway %s configuration version %s applied: %d channels", g.MAC, c.GetVersion(), len(c.GetChannels()))
+}
+
+// Configuration returns the configuration applied to the gateway, or nil if it hasn't received one.
+func (g *Gateway) Configuration() *gw.GatewayConfiguration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.config
+}
+
+// checkChannel returns ErrChannelNotConfigured when the gateway has a configuration and none of its channels matches the uplink's frequency and data rate.
+func (g *Gateway) checkChannel(txInfo *gw.UplinkTXInfo) error {
+	c := g.Configuration()
+	if c == nil {
+		return nil
+	}
+
+	for _, ch := range c.GetChannels() {
+		if channelMatches(ch, txInfo) {
+			return nil
+		}
+	}
+
+	return errors.Wrapf(ErrChannelNotConfigured, "uplink on %d Hz %s dropped by gateway %s", txInfo.GetFrequency(), uplinkDataRate(txInfo), g.MAC)
+}
+
+func channelMatches(ch *gw.ChannelConfiguration, txInfo *gw.UplinkTXInfo) bool {
+	if ch.GetFrequency() != txInfo.GetFrequency() {
+		return false
+	}
+
+	switch ch.GetModulation() {
+	case common.Modulation_LORA:
+		lora := txInfo.GetLoraModulationInfo()
+		conf := ch.GetLoraModulationConfig()
+		if lora == nil || conf.GetBandwidth() != lora.GetBandwidth() {
+			return false
+		}
+		for _, sf := range conf.GetSpreadingFactors() {
+			if sf == lora.GetSpreadingFactor() {
+				return true
+			}
+		}
+		return false
+	case common.Modulation_FSK:
+		fsk := txInfo.GetFskModulationInfo()
+		return fsk != nil && ch.GetFskModulationConfig().GetBitrate() == fsk.GetBitrate()
+	default:
+		return false
+	}
+}
+
+func uplinkDataRate(txInfo *gw.UplinkTXInfo) string {
+	if fsk := txInfo.GetFskModulationInfo(); fsk != nil {
+		return fmt.Sprintf("FSK %d bps", fsk.GetBitrate())
+	}
+	lora := txInfo.GetLoraModulationInfo()
+	return fmt.Sprintf("SF%dBW%d", lora.GetSpreadingFactor(), lora.GetBandwidth())
+}
+
+// FormatChannel returns a human readable description of a gateway channel, e.g. "868100000 Hz LoRa BW125 SF7-12".
+func FormatChannel(ch *gw.ChannelConfiguration) string {
+	switch ch.GetModulation() {
+	case common.Modulation_FSK:
+		conf := ch.GetFskModulationConfig()
+		return fmt.Sprintf("%d Hz FSK BW%d %d bps", ch.GetFrequency(), conf.GetBandwidth(), conf.GetBitrate())
+	default:
+		conf := ch.GetLoraModulationConfig()
+		sfs := append([]uint32{}, conf.GetSpreadingFactors()...)
+		sort.Slice(sfs, func(i, j int) bool { return sfs[i] < sfs[j] })
+
+		var sf string
+		switch {
+		case len(sfs) == 0:
+			sf = "SF-"
+		case int(sfs[len(sfs)-1]-sfs[0]) == len(sfs)-1 && len(sfs) > 1:
+			sf = fmt.Sprintf("SF%d-%d", sfs[0], sfs[len(sfs)-1])
+		default:
+			s := make([]string, len(sfs))
+			for i, v := range sfs {
+				s[i] = fmt.Sprint(v)
+			}
+			sf = "SF" + strings.Join(s, ",")
+		}
+		return fmt.Sprintf("%d Hz LoRa BW%d %s", ch.GetFrequency(), conf.GetBandwidth(), sf)
+	}
+}
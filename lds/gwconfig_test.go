@@ -0,0 +1,151 @@
+package lds
+
+import (
+	"testing"
+
+	"github.com/brocaar/chirpstack-api/go/common"
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/pkg/errors"
+)
+
+func loraChannel(frequency uint32, bandwidth uint32, sfs ...uint32) *gw.ChannelConfiguration {
+	return &gw.ChannelConfiguration{
+		Frequency:  frequency,
+		Modulation: common.Modulation_LORA,
+		ModulationConfig: &gw.ChannelConfiguration_LoraModulationConfig{
+			LoraModulationConfig: &gw.LoRaModulationConfig{Bandwidth: bandwidth, SpreadingFactors: sfs},
+		},
+	}
+}
+
+func fskChannel(frequency, bitrate uint32) *gw.ChannelConfiguration {
+	return &gw.ChannelConfiguration{
+		Frequency:  frequency,
+		Modulation: common.Modulation_FSK,
+		ModulationConfig: &gw.ChannelConfiguration_FskModulationConfig{
+			FskModulationConfig: &gw.FSKModulationConfig{Bandwidth: 125, Bitrate: bitrate},
+		},
+	}
+}
+
+func TestCheckChannel(t *testing.T) {
+	config := &gw.GatewayConfiguration{
+		Version: "1",
+		Channels: []*gw.ChannelConfiguration{
+			loraChannel(868100000, 125, 7, 8, 9, 10, 11, 12),
+			loraChannel(868300000, 250, 7),
+			fskChannel(868800000, 50000),
+		},
+	}
+	fsk := func(frequency, bitrate uint32) *gw.UplinkTXInfo {
+		return &gw.UplinkTXInfo{
+			Frequency:      frequency,
+			Modulation:     common.Modulation_FSK,
+			ModulationInfo: &gw.UplinkTXInfo_FskModulationInfo{FskModulationInfo: &gw.FSKModulationInfo{Bitrate: bitrate}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		config *gw.GatewayConfiguration
+		txInfo *gw.UplinkTXInfo
+		err    bool
+	}{
+		{"not configured", nil, loraTXInfo(867100000, 7), false},
+		{"LoRa channel", config, loraTXInfo(868100000, 12), false},
+		{"FSK channel", config, fsk(868800000, 50000), false},
+		{"unknown frequency", config, loraTXInfo(867100000, 7), true},
+		{"other bandwidth", config, loraTXInfo(868300000, 7), true},
+		{"spreading factor not configured", config, loraTXInfo(868300000, 12), true},
+		{"other bitrate", config, fsk(868800000, 100000), true},
+		{"FSK on a LoRa channel", config, fsk(868100000, 50000), true},
+		{"no channels", &gw.GatewayConfiguration{Version: "2"}, loraTXInfo(868100000, 7), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Gateway{MAC: "0102030405060708"}
+			if tt.config != nil {
+				g.ApplyConfiguration(tt.config)
+			}
+
+			err := g.checkChannel(tt.txInfo)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if err != nil && errors.Cause(err) != ErrChannelNotConfigured {
+				t.Errorf("got error %v, expected ErrChannelNotConfigured", err)
+			}
+		})
+	}
+}
+
+func TestApplyGatewayConfig(t *testing.T) {
+	d := &Device{}
+	d.SetMarshaler("v2_json")
+
+	tests := []struct {
+		name    string
+		gwMAC   string
+		payload string
+		err     bool
+	}{
+		{"configuration", "0a0b0c0d0e0f0001", `{"mac":"0a0b0c0d0e0f0001","version":"7","channels":[{"modulation":"LORA","frequency":868100000,"bandwidth":125,"spreadingFactors":[7]}]}`, false},
+		{"other gateway", "0a0b0c0d0e0f0002", `{"mac":"0a0b0c0d0e0f0001","version":"7","channels":[]}`, true},
+		{"invalid payload", "0a0b0c0d0e0f0003", `{"mac":`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := d.ApplyGatewayConfig([]byte(tt.payload), tt.gwMAC)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+
+			c := GetGateway(tt.gwMAC).Configuration()
+			if tt.err {
+				if c != nil {
+					t.Errorf("a rejected configuration was applied: %+v", c)
+				}
+				return
+			}
+			if c.GetVersion() != "7" || len(c.GetChannels()) != 1 {
+				t.Errorf("got configuration %+v", c)
+			}
+		})
+	}
+}
+
+func TestGatewayReceiveOnConfiguredChannels(t *testing.T) {
+	g := &Gateway{MAC: "0102030405060708"}
+	g.ApplyConfiguration(&gw.GatewayConfiguration{Channels: []*gw.ChannelConfiguration{loraChannel(868100000, 125, 7)}})
+
+	if err := g.receive(&gw.UplinkRXInfo{}, loraTXInfo(868100000, 7), 13); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.receive(&gw.UplinkRXInfo{}, loraTXInfo(868300000, 7), 13); errors.Cause(err) != ErrChannelNotConfigured {
+		t.Fatalf("got error %v, expected ErrChannelNotConfigured", err)
+	}
+	if stats := g.Stats(); stats.GetRxPacketsReceived() != 1 {
+		t.Errorf("got %d uplinks received, expected the dropped one not to be counted", stats.GetRxPacketsReceived())
+	}
+}
+
+func TestFormatChannel(t *testing.T) {
+	tests := []struct {
+		ch  *gw.ChannelConfiguration
+		out string
+	}{
+		{loraChannel(868100000, 125, 12, 11, 10, 9, 8, 7), "868100000 Hz LoRa BW125 SF7-12"},
+		{loraChannel(868300000, 250, 7), "868300000 Hz LoRa BW250 SF7"},
+		{loraChannel(868500000, 125, 7, 9, 12), "868500000 Hz LoRa BW125 SF7,9,12"},
+		{loraChannel(868500000, 125), "868500000 Hz LoRa BW125 SF-"},
+		{fskChannel(868800000, 50000), "868800000 Hz FSK BW125 50000 bps"},
+	}
+
+	for _, tt := range tests {
+		if out := FormatChannel(tt.ch); out != tt.out {
+			t.Errorf("got %q, expected %q", out, tt.out)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package lds
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Journal directions.
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+)
+
+// JournalEntry is a frame sent or received by a device, as a line of the journal.
+type JournalEntry struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"direction"`
+	Gateway   string    `json:"gateway,omitempty"`
+	DevEUI    string    `json:"devEUI"`
+	DevAddr   string    `json:"devAddr,omitempty"`
+	MType     string    `json:"mType"`
+	FCnt      *uint32   `json:"fCnt,omitempty"`
+	FPort     *uint8    `json:"fPort,omitempty"`
+	//PHYPayload is the hex encoded frame as sent or received, FRMPayload the hex encoded application payload in clear.
+	PHYPayload string `json:"phyPayload"`
+	FRMPayload string `json:"frmPayload,omitempty"`
+	//MACCommands are the lorawan MAC commands, with their payload fields.
+	MACCommands json.RawMessage `json:"macCommands,omitempty"`
+	//RXInfo and TXInfo are the uplink metadata as given to the network server.
+	RXInfo json.RawMessage `json:"rxInfo,omitempty"`
+	TXInfo json.RawMessage `json:"txInfo,omitempty"`
+	//Error tells why a received frame was dropped.
+	Error string `json:"error,omitempty"`
+}
+
+// NewJournalEntry returns the entry of a frame event, or nil for other events.
+func NewJournalEntry(e *Event) *JournalEntry {
+	entry := &JournalEntry{
+		Time:    e.Time,
+		Gateway: e.Gateway,
+		DevEUI:  e.DevEUI.String(),
+	}
+
+	switch {
+	case e.Uplink != nil:
+		ul := e.Uplink
+		entry.Direction = DirectionUp
+		entry.MType = ul.MType.String()
+		entry.PHYPayload = hex.EncodeToString(ul.PHYPayload)
+		entry.RXInfo = protoJSON(ul.RXInfo)
+		entry.TXInfo = protoJSON(ul.TXInfo)
+		if ul.MType != lorawan.JoinRequest {
+			fCnt, fPort := ul.FCnt, ul.FPort
+			entry.DevAddr = ul.DevAddr.String()
+			entry.FCnt = &fCnt
+			entry.FPort = &fPort
+			entry.FRMPayload = hex.EncodeToString(ul.FRMPayload)
+			entry.MACCommands = macCommandsJSON(ul.MACCommands)
+		}
+	case e.Downlink != nil:
+		dl := e.Downlink
+		entry.Direction = DirectionDown
+		entry.MType = dl.MType.String()
+		entry.DevAddr = dl.DevAddr.String()
+		entry.PHYPayload = hex.EncodeToString(dl.PHYPayload)
+		if dl.MType != lorawan.JoinAccept {
+			fCnt := dl.FCnt
+			entry.FCnt = &fCnt
+			entry.FPort = dl.FPort
+			entry.FRMPayload = hex.EncodeToString(dl.FRMPayload)
+			entry.MACCommands = macCommandsJSON(dl.MACCommands)
+		}
+	case e.MICFailure != nil:
+		entry.Direction = DirectionDown
+		entry.MType = e.MICFailure.MType.String()
+		entry.PHYPayload = hex.EncodeToString(e.MICFailure.PHYPayload)
+		entry.Error = e.MICFailure.Err.Error()
+	default:
+		return nil
+	}
+	return entry
+}
+
+func macCommandsJSON(macCommands []lorawan.MACCommand) json.RawMessage {
+	if len(macCommands) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(macCommands)
+	if err != nil {
+		log.Errorf("journal MAC commands marshal error: %s", err)
+		return nil
+	}
+	return b
+}
+
+// protoJSON marshals the message with the field names of the gateway bridge json marshaler.
+func protoJSON(msg proto.Message) json.RawMessage {
+	if msg == nil {
+		return nil
+	}
+	var b strings.Builder
+	if err := (&jsonpb.Marshaler{}).Marshal(&b, msg); err != nil {
+		log.Errorf("journal metadata marshal error: %s", err)
+		return nil
+	}
+	return json.RawMessage(b.String())
+}
+
+// Journal writes the frames of the devices as JSON lines, rotating the file when it reaches its maximum size.
+type Journal struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// OpenJournal opens the journal at path, appending to it. The file is rotated to path.1, path.2 and so on when
+// it would exceed maxSize bytes, keeping maxBackups rotated files.
+func OpenJournal(path string, maxSize int64, maxBackups int) (*Journal, error) {
+	j := &Journal{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *Journal) open() error {
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "couldn't open the journal")
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return errors.Wrap(err, "couldn't open the journal")
+	}
+	j.file = f
+	j.size = info.Size()
+	return nil
+}
+
+// rotate renames the file to path.1, shifting the older backups and removing the last one.
+func (j *Journal) rotate() error {
+	if err := j.file.Close(); err != nil {
+		return err
+	}
+	os.Remove(backupPath(j.path, j.maxBackups))
+	for i := j.maxBackups - 1; i >= 1; i-- {
+		os.Rename(backupPath(j.path, i), backupPath(j.path, i+1))
+	}
+	if j.maxBackups > 0 {
+		if err := os.Rename(j.path, backupPath(j.path, 1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(j.path); err != nil {
+		return err
+	}
+	return j.open()
+}
+
+func backupPath(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+// Write appends the entry.
+func (j *Journal) Write(entry *JournalEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return errors.New("the journal is closed")
+	}
+	if j.size > 0 && j.maxSize > 0 && j.size+int64(len(b)) > j.maxSize {
+		if err := j.rotate(); err != nil {
+			return errors.Wrap(err, "couldn't rotate the journal")
+		}
+	}
+	n, err := j.file.Write(b)
+	j.size += int64(n)
+	return err
+}
+
+// Record writes the frame events, it may be given to Device.AddEventHandler.
+func (j *Journal) Record(e *Event) {
+	entry := NewJournalEntry(e)
+	if entry == nil {
+		return
+	}
+	if err := j.Write(entry); err != nil {
+		log.Errorf("journal error: %s", err)
+	}
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// JournalFilter selects journal entries, empty fields match anything.
+type JournalFilter struct {
+	DevEUI    string
+	DevAddr   string
+	Gateway   string
+	Direction string
+	MType     string
+	FPort     *int
+	Since     time.Time
+	Until     time.Time
+}
+
+// Match tells if the entry is selected by the filter.
+func (f *JournalFilter) Match(e *JournalEntry) bool {
+	switch {
+	case f.DevEUI != "" && !strings.EqualFold(f.DevEUI, e.DevEUI),
+		f.DevAddr != "" && !strings.EqualFold(f.DevAddr, e.DevAddr),
+		f.Gateway != "" && !strings.EqualFold(f.Gateway, e.Gateway),
+		f.Direction != "" && f.Direction != e.Direction,
+		f.MType != "" && !strings.EqualFold(f.MType, e.MType),
+		f.FPort != nil && (e.FPort == nil || int(*e.FPort) != *f.FPort),
+		!f.Since.IsZero() && e.Time.Before(f.Since),
+		!f.Until.IsZero() && e.Time.After(f.Until):
+		return false
+	}
+	return true
+}
+
+// ReadJournal calls fn with the entries matching the filter, oldest first, reading the rotated files before path.
+func ReadJournal(path string, filter *JournalFilter, fn func(*JournalEntry) error) error {
+	var files []string
+	for i := 1; ; i++ {
+		if _, err := os.Stat(backupPath(path, i)); err != nil {
+			break
+		}
+		files = append([]string{backupPath(path, i)}, files...)
+	}
+	files = append(files, path)
+
+	for _, name := range files {
+		if err := readJournalFile(name, filter, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readJournalFile(name string, filter *JournalFilter, fn func(*JournalEntry) error) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return errors.Wrapf(err, "%s:%d", name, line)
+		}
+		if !filter.Match(&entry) {
+			continue
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// Open opens the configured journal.
+func (c JournalConfig) Open() (*Journal, error) {
+	return OpenJournal(c.Path, int64(c.MaxSize)<<20, c.MaxBackups)
+}
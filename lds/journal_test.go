@@ -0,0 +1,277 @@
+package lds
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan"
+	"github.com/pkg/errors"
+)
+
+func TestNewJournalEntry(t *testing.T) {
+	eventTime := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	devEUI := lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+	fPort := uint8(3)
+
+	tests := []struct {
+		name     string
+		event    *Event
+		expected string
+	}{
+		{
+			name: "join request",
+			event: &Event{Type: EventUplinkSent, Uplink: &Uplink{
+				MType:      lorawan.JoinRequest,
+				PHYPayload: []byte{0, 1},
+				RXInfo:     &gw.UplinkRXInfo{Rssi: -50},
+				DevNonce:   3,
+			}},
+			expected: `{"time":"2020-01-01T00:00:00Z","direction":"up","gateway":"0a0b0c0d0e0f0001","devEUI":"0102030405060708","mType":"JoinRequest","phyPayload":"0001","rxInfo":{"rssi":-50}}`,
+		},
+		{
+			name: "uplink",
+			event: &Event{Type: EventUplinkSent, Uplink: &Uplink{
+				MType:       lorawan.UnconfirmedDataUp,
+				PHYPayload:  []byte{0x40},
+				DevAddr:     lorawan.DevAddr{1, 2, 3, 4},
+				FCnt:        0,
+				FPort:       2,
+				FRMPayload:  []byte{1, 2},
+				MACCommands: []lorawan.MACCommand{{CID: lorawan.LinkCheckReq}},
+			}},
+			expected: `{"time":"2020-01-01T00:00:00Z","direction":"up","gateway":"0a0b0c0d0e0f0001","devEUI":"0102030405060708","devAddr":"01020304","mType":"UnconfirmedDataUp","fCnt":0,"fPort":2,"phyPayload":"40","frmPayload":"0102","macCommands":[{"cid":"LinkCheckReq","payload":null}]}`,
+		},
+		{
+			name:     "join accept",
+			event:    &Event{Type: EventJoinAccepted, Downlink: &Downlink{MType: lorawan.JoinAccept, DevAddr: lorawan.DevAddr{1, 2, 3, 4}, PHYPayload: []byte{0x20}}},
+			expected: `{"time":"2020-01-01T00:00:00Z","direction":"down","gateway":"0a0b0c0d0e0f0001","devEUI":"0102030405060708","devAddr":"01020304","mType":"JoinAccept","phyPayload":"20"}`,
+		},
+		{
+			name:     "downlink",
+			event:    &Event{Type: EventDownlinkReceived, Downlink: &Downlink{MType: lorawan.ConfirmedDataDown, DevAddr: lorawan.DevAddr{1, 2, 3, 4}, FCnt: 7, FPort: &fPort, PHYPayload: []byte{0xa0}, FRMPayload: []byte{0xca, 0xfe}}},
+			expected: `{"time":"2020-01-01T00:00:00Z","direction":"down","gateway":"0a0b0c0d0e0f0001","devEUI":"0102030405060708","devAddr":"01020304","mType":"ConfirmedDataDown","fCnt":7,"fPort":3,"phyPayload":"a0","frmPayload":"cafe"}`,
+		},
+		{
+			name:     "MIC failure",
+			event:    &Event{Type: EventMICFailure, MICFailure: &MICFailure{MType: lorawan.UnconfirmedDataDown, PHYPayload: []byte{0x60}, Err: errors.New("invalid MIC")}},
+			expected: `{"time":"2020-01-01T00:00:00Z","direction":"down","gateway":"0a0b0c0d0e0f0001","devEUI":"0102030405060708","mType":"UnconfirmedDataDown","phyPayload":"60","error":"invalid MIC"}`,
+		},
+		{
+			name:  "no frame",
+			event: &Event{Type: EventJoinAccepted},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.event.Time = eventTime
+			tt.event.DevEUI = devEUI
+			tt.event.Gateway = "0a0b0c0d0e0f0001"
+			entry := NewJournalEntry(tt.event)
+			if tt.expected == "" {
+				if entry != nil {
+					t.Errorf("got entry %+v, expected none", entry)
+				}
+				return
+			}
+
+			b, err := json.Marshal(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.expected {
+				t.Errorf("got %s, expected %s", b, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJournalFilter(t *testing.T) {
+	fPort := uint8(2)
+	entry := &JournalEntry{
+		Time:      time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC),
+		Direction: DirectionUp,
+		Gateway:   "0a0b0c0d0e0f0001",
+		DevEUI:    "0102030405060708",
+		DevAddr:   "01020304",
+		MType:     "UnconfirmedDataUp",
+		FPort:     &fPort,
+	}
+	port := func(p int) *int { return &p }
+
+	tests := []struct {
+		name   string
+		filter JournalFilter
+		match  bool
+	}{
+		{"everything", JournalFilter{}, true},
+		{"every field", JournalFilter{
+			DevEUI:    "0102030405060708",
+			DevAddr:   "01020304",
+			Gateway:   "0A0B0C0D0E0F0001",
+			Direction: DirectionUp,
+			MType:     "unconfirmeddataup",
+			FPort:     port(2),
+			Since:     entry.Time.Add(-time.Hour),
+			Until:     entry.Time,
+		}, true},
+		{"other device", JournalFilter{DevEUI: "0102030405060709"}, false},
+		{"other address", JournalFilter{DevAddr: "01020305"}, false},
+		{"other gateway", JournalFilter{Gateway: "0a0b0c0d0e0f0002"}, false},
+		{"downlinks", JournalFilter{Direction: DirectionDown}, false},
+		{"other message type", JournalFilter{MType: "ConfirmedDataUp"}, false},
+		{"other fPort", JournalFilter{FPort: port(3)}, false},
+		{"later", JournalFilter{Since: entry.Time.Add(time.Second)}, false},
+		{"earlier", JournalFilter{Until: entry.Time.Add(-time.Second)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if match := tt.filter.Match(entry); match != tt.match {
+				t.Errorf("got %t, expected %t", match, tt.match)
+			}
+		})
+	}
+
+	if (&JournalFilter{FPort: port(2)}).Match(&JournalEntry{MType: "JoinRequest"}) {
+		t.Error("an fPort filter matched a frame without fPort")
+	}
+}
+
+// journalFCnts reads the journal, returning the frame counters of the entries matching filter.
+func journalFCnts(t *testing.T, path string, filter *JournalFilter) []uint32 {
+	var fCnts []uint32
+	if err := ReadJournal(path, filter, func(e *JournalEntry) error {
+		fCnts = append(fCnts, *e.FCnt)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return fCnts
+}
+
+func TestJournalRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lds-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	entry := func(fCnt uint32) *JournalEntry {
+		return &JournalEntry{Direction: DirectionUp, DevEUI: "0102030405060708", MType: "UnconfirmedDataUp", FCnt: &fCnt}
+	}
+	b, _ := json.Marshal(entry(0))
+	//Every file holds two entries.
+	size := int64(2 * (len(b) + 1))
+
+	tests := []struct {
+		name       string
+		maxSize    int64
+		maxBackups int
+		files      []string
+		fCnts      []uint32
+	}{
+		{"not rotated", 0, 2, []string{"journal"}, []uint32{0, 1, 2, 3, 4, 5, 6}},
+		{"backups", size, 2, []string{"journal", "journal.1", "journal.2"}, []uint32{2, 3, 4, 5, 6}},
+		{"no backups", size, 0, []string{"journal"}, []uint32{6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name, "journal")
+			if err := os.Mkdir(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			//The journal is reopened after a few entries, appending to the file.
+			j, err := OpenJournal(path, tt.maxSize, tt.maxBackups)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := uint32(0); i < 7; i++ {
+				if i == 3 {
+					j.Close()
+					if j, err = OpenJournal(path, tt.maxSize, tt.maxBackups); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := j.Write(entry(i)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := j.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			files, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != len(tt.files) {
+				t.Errorf("got files %v, expected %v", files, tt.files)
+			}
+			for i := range files {
+				if i < len(tt.files) && filepath.Base(files[i]) != tt.files[i] {
+					t.Errorf("got files %v, expected %v", files, tt.files)
+				}
+			}
+
+			fCnts := journalFCnts(t, path, &JournalFilter{})
+			if len(fCnts) != len(tt.fCnts) {
+				t.Fatalf("got fCnts %v, expected %v", fCnts, tt.fCnts)
+			}
+			for i := range fCnts {
+				if fCnts[i] != tt.fCnts[i] {
+					t.Fatalf("got fCnts %v, expected %v", fCnts, tt.fCnts)
+				}
+			}
+		})
+	}
+}
+
+func TestJournalRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lds-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "journal")
+
+	j, err := OpenJournal(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &Device{DevEUI: lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}}
+	d.AddEventHandler(j.Record)
+	d.uplinkSent(lorawan.UnconfirmedDataUp, 1, []byte{1}, nil, lorawan.FCtrl{}, []byte{0x40}, nil, nil)
+	d.handleDownlink(&Downlink{MType: lorawan.UnconfirmedDataDown, FCnt: 4})
+	d.emit(&Event{Type: EventMICFailure})
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Write(&JournalEntry{}); err == nil {
+		t.Error("expected an error writing to a closed journal")
+	}
+
+	if fCnts := journalFCnts(t, path, &JournalFilter{Direction: DirectionDown}); len(fCnts) != 1 || fCnts[0] != 4 {
+		t.Errorf("got downlink fCnts %v, expected [4]", fCnts)
+	}
+	if fCnts := journalFCnts(t, path, &JournalFilter{Direction: DirectionUp, FPort: new(int)}); len(fCnts) != 0 {
+		t.Errorf("got uplink fCnts %v on fPort 0, expected none", fCnts)
+	}
+
+	//An invalid line is an error with the file and line.
+	if err := ioutil.WriteFile(path, []byte("{}\n\n{\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = ReadJournal(path, &JournalFilter{}, func(*JournalEntry) error { return nil })
+	if err == nil || !strings.HasPrefix(err.Error(), path+":3:") {
+		t.Errorf("got error %v, expected one at line 3", err)
+	}
+}
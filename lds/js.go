@@ -0,0 +1,280 @@
+package lds
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/backend"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/iegomez/lds/lds/keys"
+)
+
+// JoinServer is a LoRaWAN Backend Interfaces (TS002) join server for the device of the configuration, so that the join
+// server client of a network server may be tested with the simulator. It answers JoinReq messages with a JoinAns
+// carrying the join accept and the session keys, the AppSKey in its own envelope for the application server.
+type JoinServer struct {
+	config   *Config
+	listener net.Listener
+	server   *http.Server
+
+	nsKEK []byte
+	asKEK []byte
+
+	mu        sync.Mutex
+	joinNonce lorawan.JoinNonce
+	devNonces map[lorawan.EUI64]map[lorawan.DevNonce]bool
+}
+
+// jsError is a failed JoinReq with the result code to answer.
+type jsError struct {
+	code backend.ResultCode
+	err  error
+}
+
+func (e *jsError) Error() string {
+	return e.err.Error()
+}
+
+func jsErrorf(code backend.ResultCode, format string, args ...interface{}) error {
+	return &jsError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// NewJoinServer starts serving the join server on addr, at any path.
+func NewJoinServer(addr string, config *Config) (*JoinServer, error) {
+	c := config.JoinServer
+	js := &JoinServer{
+		config:    config,
+		joinNonce: lorawan.JoinNonce(c.JoinNonce),
+		devNonces: make(map[lorawan.EUI64]map[lorawan.DevNonce]bool),
+	}
+
+	var err error
+	if js.nsKEK, err = parseKEK(c.NSKEKLabel, c.NSKEK); err != nil {
+		return nil, errors.Wrap(err, "ns kek error")
+	}
+	if js.asKEK, err = parseKEK(c.ASKEKLabel, c.ASKEK); err != nil {
+		return nil, errors.Wrap(err, "as kek error")
+	}
+
+	js.listener, err = net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "join server listen error")
+	}
+
+	js.server = &http.Server{Handler: http.HandlerFunc(js.handle)}
+	go func() {
+		if err := js.server.Serve(js.listener); err != nil && err != http.ErrServerClosed {
+			log.Errorf("join server error: %s", err)
+		}
+	}()
+
+	log.Infof("join server listening on %s", js.listener.Addr())
+	return js, nil
+}
+
+// parseKEK decodes a hex KEK, which is only used along with a label.
+func parseKEK(label, kek string) ([]byte, error) {
+	if label == "" {
+		return nil, nil
+	}
+	b, err := hex.DecodeString(kek)
+	if err != nil {
+		return nil, err
+	}
+	switch len(b) {
+	case 16, 24, 32:
+		return b, nil
+	}
+	return nil, fmt.Errorf("the kek of %s must be an AES key of 16, 24 or 32 bytes", label)
+}
+
+// Addr returns the address the join server listens on.
+func (js *JoinServer) Addr() string {
+	return js.listener.Addr().String()
+}
+
+// Close stops the join server.
+func (js *JoinServer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return js.server.Shutdown(ctx)
+}
+
+// handle answers a Backend Interfaces request. Only JoinReq is supported, other messages get an Other result.
+func (js *JoinServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	var req backend.JoinReqPayload
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, backend.BasePayloadResult{
+			BasePayload: backend.BasePayload{ProtocolVersion: backend.ProtocolVersion1_0},
+			Result:      backend.Result{ResultCode: backend.MalformedRequest, Description: err.Error()},
+		})
+		return
+	}
+
+	base := backend.BasePayload{
+		ProtocolVersion: backend.ProtocolVersion1_0,
+		SenderID:        req.ReceiverID,
+		ReceiverID:      req.SenderID,
+		TransactionID:   req.TransactionID,
+		MessageType:     backend.JoinAns,
+		ReceiverToken:   req.SenderToken,
+	}
+
+	if req.MessageType != backend.JoinReq {
+		base.MessageType = req.MessageType
+		log.Warnf("join server: unsupported %s message from %s", req.MessageType, req.SenderID)
+		writeJSON(w, http.StatusOK, backend.BasePayloadResult{
+			BasePayload: base,
+			Result:      backend.Result{ResultCode: backend.Other, Description: fmt.Sprintf("unsupported message type %s", req.MessageType)},
+		})
+		return
+	}
+
+	ans, err := js.joinAns(req)
+	if err != nil {
+		code := backend.Other
+		if jsErr, ok := err.(*jsError); ok {
+			code = jsErr.code
+		}
+		log.Warnf("join server: JoinReq %d from %s rejected: %s", req.TransactionID, req.SenderID, err)
+		ans = backend.JoinAnsPayload{
+			BasePayloadResult: backend.BasePayloadResult{Result: backend.Result{ResultCode: code, Description: err.Error()}},
+		}
+	}
+	ans.BasePayload = base
+	writeJSON(w, http.StatusOK, ans)
+}
+
+// joinAns checks a JoinReq of the device and builds the join accept, encrypted with the NwkKey, along with the
+// session keys derived as the device does.
+func (js *JoinServer) joinAns(req backend.JoinReqPayload) (backend.JoinAnsPayload, error) {
+	var ans backend.JoinAnsPayload
+
+	var phy lorawan.PHYPayload
+	if err := phy.UnmarshalBinary(req.PHYPayload); err != nil {
+		return ans, jsErrorf(backend.MalformedRequest, "phy payload error: %s", err)
+	}
+	jr, ok := phy.MACPayload.(*lorawan.JoinRequestPayload)
+	if !ok {
+		return ans, jsErrorf(backend.MalformedRequest, "the phy payload is a %s instead of a join request", phy.MHDR.MType)
+	}
+	var netID lorawan.NetID
+	if err := netID.UnmarshalText([]byte(req.SenderID)); err != nil {
+		return ans, jsErrorf(backend.UnknownSender, "the sender %q isn't a NetID", req.SenderID)
+	}
+
+	d, err := js.config.NewDevice()
+	if err != nil {
+		return ans, errors.Wrap(err, "device error")
+	}
+	if req.DevEUI != d.DevEUI || jr.DevEUI != d.DevEUI || d.Profile != "OTAA" {
+		return ans, jsErrorf(backend.UnknownDevEUI, "unknown device %s", req.DevEUI)
+	}
+	if !strings.EqualFold(req.ReceiverID, d.JoinEUI.String()) || jr.JoinEUI != d.JoinEUI {
+		return ans, jsErrorf(backend.UnknownReceiver, "the join server is %s instead of %s", d.JoinEUI, req.ReceiverID)
+	}
+
+	mic, err := keys.JoinRequestMIC(phy, d.NwkKey)
+	if err != nil {
+		return ans, err
+	}
+	if mic != phy.MIC {
+		return ans, jsErrorf(backend.MICFailed, "invalid join request mic from %s", jr.DevEUI)
+	}
+
+	jap := &lorawan.JoinAcceptPayload{
+		HomeNetID:  netID,
+		DevAddr:    req.DevAddr,
+		DLSettings: req.DLSettings,
+		RXDelay:    uint8(req.RxDelay),
+	}
+	if len(req.CFList) > 0 {
+		jap.CFList = &lorawan.CFList{}
+		if err := jap.CFList.UnmarshalBinary(req.CFList); err != nil {
+			return ans, jsErrorf(backend.MalformedRequest, "cf list error: %s", err)
+		}
+	}
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	if js.devNonces[jr.DevEUI][jr.DevNonce] {
+		return ans, jsErrorf(backend.JoinReqFailed, "DevNonce %d of %s was already used", jr.DevNonce, jr.DevEUI)
+	}
+	jap.JoinNonce = js.joinNonce
+
+	accept := lorawan.PHYPayload{
+		MHDR:       lorawan.MHDR{MType: lorawan.JoinAccept, Major: lorawan.LoRaWANR1},
+		MACPayload: jap,
+	}
+	if accept.MIC, err = keys.JoinAcceptMIC(accept, d.NwkKey, d.DevEUI, d.JoinEUI, jr.DevNonce); err != nil {
+		return ans, err
+	}
+	//The network server sets OptNeg when it speaks LoRaWAN 1.1, the keys are derived the 1.0 way otherwise.
+	macVersion := lorawan.LoRaWAN1_0
+	if req.DLSettings.OptNeg {
+		macVersion = lorawan.LoRaWAN1_1
+	}
+	sessionKeys, err := keys.GetSessionKeys(d.NwkKey, d.AppKey, joinParams(jap, macVersion, jr.JoinEUI, jr.DevNonce))
+	if err != nil {
+		return ans, errors.Wrap(err, "session keys derivation error")
+	}
+	if err := accept.EncryptJoinAcceptPayload(d.NwkKey); err != nil {
+		return ans, err
+	}
+	if ans.PHYPayload, err = accept.MarshalBinary(); err != nil {
+		return ans, err
+	}
+
+	if err := js.envelopes(&ans, sessionKeys, req.DLSettings.OptNeg); err != nil {
+		return ans, err
+	}
+	lifetime := js.config.JoinServer.Lifetime
+	ans.Lifetime = &lifetime
+	ans.Result = backend.Result{ResultCode: backend.Success}
+
+	if js.devNonces[jr.DevEUI] == nil {
+		js.devNonces[jr.DevEUI] = make(map[lorawan.DevNonce]bool)
+	}
+	js.devNonces[jr.DevEUI][jr.DevNonce] = true
+	js.joinNonce++
+	log.Infof("join server: %s joined through %s with DevAddr %s (JoinNonce %d)", d.DevEUI, netID, req.DevAddr, jap.JoinNonce)
+	return ans, nil
+}
+
+// envelopes sets the session keys of an answer, wrapped with the KEKs when there are.
+func (js *JoinServer) envelopes(ans *backend.JoinAnsPayload, sessionKeys keys.SessionKeys, optNeg bool) error {
+	c := js.config.JoinServer
+
+	var err error
+	if ans.AppSKey, err = backend.NewKeyEnvelope(c.ASKEKLabel, js.asKEK, sessionKeys.AppSKey); err != nil {
+		return err
+	}
+	if !optNeg {
+		ans.NwkSKey, err = backend.NewKeyEnvelope(c.NSKEKLabel, js.nsKEK, sessionKeys.FNwkSIntKey)
+		return err
+	}
+	if ans.FNwkSIntKey, err = backend.NewKeyEnvelope(c.NSKEKLabel, js.nsKEK, sessionKeys.FNwkSIntKey); err != nil {
+		return err
+	}
+	if ans.SNwkSIntKey, err = backend.NewKeyEnvelope(c.NSKEKLabel, js.nsKEK, sessionKeys.SNwkSIntKey); err != nil {
+		return err
+	}
+	ans.NwkSEncKey, err = backend.NewKeyEnvelope(c.NSKEKLabel, js.nsKEK, sessionKeys.NwkSEncKey)
+	return err
+}
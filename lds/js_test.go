@@ -0,0 +1,331 @@
+package lds
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/backend"
+)
+
+// newTestJoinServer starts a join server on a random port for the OTAA test device.
+func newTestJoinServer(t *testing.T, nsKEK, asKEK string) *JoinServer {
+	config := NewConfig()
+	config.Device.Profile = "OTAA"
+	config.Device.DevEUI = testDevEUI.String()
+	config.Device.JoinEUI = testJoinEUI.String()
+	config.Device.NwkKey = testNwkKey.String()
+	config.Device.AppKey = testAppKey.String()
+	config.JoinServer.JoinNonce = 7
+	config.JoinServer.Lifetime = 3600
+	if nsKEK != "" {
+		config.JoinServer.NSKEKLabel, config.JoinServer.NSKEK = "ns", nsKEK
+	}
+	if asKEK != "" {
+		config.JoinServer.ASKEKLabel, config.JoinServer.ASKEK = "as", asKEK
+	}
+
+	js, err := NewJoinServer("127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return js
+}
+
+// joinReq returns a JoinReq of the network server 000013 for a join request.
+func joinReq(phyPayload []byte, devEUI lorawan.EUI64, optNeg bool) backend.JoinReqPayload {
+	return backend.JoinReqPayload{
+		BasePayload: backend.BasePayload{
+			ProtocolVersion: backend.ProtocolVersion1_0,
+			SenderID:        "000013",
+			ReceiverID:      testJoinEUI.String(),
+			TransactionID:   1234,
+			MessageType:     backend.JoinReq,
+		},
+		MACVersion: "1.0.3",
+		PHYPayload: phyPayload,
+		DevEUI:     devEUI,
+		DevAddr:    lorawan.DevAddr{0x26, 1, 2, 3},
+		DLSettings: lorawan.DLSettings{OptNeg: optNeg, RX2DataRate: 3},
+		RxDelay:    1,
+	}
+}
+
+// postJoinServer posts a request to the join server and decodes its answer.
+func postJoinServer(t *testing.T, js *JoinServer, req interface{}) (int, backend.JoinAnsPayload) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post("http://"+js.Addr()+"/", "application/json", bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var ans backend.JoinAnsPayload
+	if err := json.NewDecoder(resp.Body).Decode(&ans); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, ans
+}
+
+// envelopeKey returns the key of an envelope, unwrapping it with kek when it has a label.
+func envelopeKey(t *testing.T, e *backend.KeyEnvelope, kek []byte) lorawan.AES128Key {
+	var key lorawan.AES128Key
+	if e == nil {
+		t.Fatal("missing key envelope")
+	}
+	if e.KEKLabel == "" {
+		copy(key[:], e.AESKey)
+		return key
+	}
+	key, err := e.Unwrap(kek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestJoinAns(t *testing.T) {
+	js := newTestJoinServer(t, "", "")
+	defer js.Close()
+
+	dataUp := func() []byte {
+		fPort := uint8(1)
+		return dataFrame(t, lorawan.UnconfirmedDataUp, 0, &fPort, nil, nil)
+	}
+
+	//The requests are answered in order by the same join server.
+	tests := []struct {
+		name      string
+		req       func() backend.JoinReqPayload
+		code      backend.ResultCode
+		joinNonce lorawan.JoinNonce
+	}{
+		{"join", func() backend.JoinReqPayload {
+			return joinReq(joinRequestFrame(t, testDevEUI, testJoinEUI, 1, testNwkKey), testDevEUI, false)
+		}, backend.Success, 7},
+		{"replayed DevNonce", func() backend.JoinReqPayload {
+			return joinReq(joinRequestFrame(t, testDevEUI, testJoinEUI, 1, testNwkKey), testDevEUI, true)
+		}, backend.JoinReqFailed, 0},
+		{"invalid MIC", func() backend.JoinReqPayload {
+			return joinReq(joinRequestFrame(t, testDevEUI, testJoinEUI, 2, testAppKey), testDevEUI, false)
+		}, backend.MICFailed, 0},
+		{"unknown device", func() backend.JoinReqPayload {
+			return joinReq(joinRequestFrame(t, testJoinEUI, testJoinEUI, 2, testNwkKey), testJoinEUI, false)
+		}, backend.UnknownDevEUI, 0},
+		{"other DevEUI in the request", func() backend.JoinReqPayload {
+			return joinReq(joinRequestFrame(t, testDevEUI, testJoinEUI, 2, testNwkKey), testJoinEUI, false)
+		}, backend.UnknownDevEUI, 0},
+		{"other receiver", func() backend.JoinReqPayload {
+			req := joinReq(joinRequestFrame(t, testDevEUI, testJoinEUI, 2, testNwkKey), testDevEUI, false)
+			req.ReceiverID = testDevEUI.String()
+			return req
+		}, backend.UnknownReceiver, 0},
+		{"sender without NetID", func() backend.JoinReqPayload {
+			req := joinReq(joinRequestFrame(t, testDevEUI, testJoinEUI, 2, testNwkKey), testDevEUI, false)
+			req.SenderID = "ns"
+			return req
+		}, backend.UnknownSender, 0},
+		{"data uplink", func() backend.JoinReqPayload {
+			return joinReq(dataUp(), testDevEUI, false)
+		}, backend.MalformedRequest, 0},
+		{"invalid CFList", func() backend.JoinReqPayload {
+			req := joinReq(joinRequestFrame(t, testDevEUI, testJoinEUI, 2, testNwkKey), testDevEUI, false)
+			req.CFList = []byte{1, 2}
+			return req
+		}, backend.MalformedRequest, 0},
+		{"OptNeg", func() backend.JoinReqPayload {
+			return joinReq(joinRequestFrame(t, testDevEUI, testJoinEUI, 2, testNwkKey), testDevEUI, true)
+		}, backend.Success, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req()
+			status, ans := postJoinServer(t, js, req)
+			if status != http.StatusOK || ans.Result.ResultCode != tt.code {
+				t.Fatalf("got status %d with result %+v, expected %s", status, ans.Result, tt.code)
+			}
+			if ans.MessageType != backend.JoinAns || ans.SenderID != req.ReceiverID || ans.ReceiverID != req.SenderID || ans.TransactionID != req.TransactionID {
+				t.Errorf("got base payload %+v", ans.BasePayload)
+			}
+			if tt.code != backend.Success {
+				if ans.PHYPayload != nil || ans.AppSKey != nil {
+					t.Errorf("got a join accept %x with a failure", ans.PHYPayload)
+				}
+				return
+			}
+
+			var jr lorawan.PHYPayload
+			if err := jr.UnmarshalBinary(req.PHYPayload); err != nil {
+				t.Fatal(err)
+			}
+			devNonce := jr.MACPayload.(*lorawan.JoinRequestPayload).DevNonce
+			macVersion := lorawan.LoRaWAN1_0
+			if req.DLSettings.OptNeg {
+				macVersion = lorawan.LoRaWAN1_1
+			}
+			f, err := DecodeFrame(ans.PHYPayload, &DecodeKeys{MACVersion: macVersion, NwkKey: &testNwkKey, AppKey: &testAppKey, DevEUI: testDevEUI, JoinEUI: testJoinEUI, DevNonce: &devNonce})
+			if err != nil {
+				t.Fatal(err)
+			}
+			jap := f.PHYPayload.MACPayload.(*lorawan.JoinAcceptPayload)
+			if f.MIC != MICValid || jap.JoinNonce != tt.joinNonce || jap.HomeNetID != (lorawan.NetID{0, 0, 0x13}) || jap.DevAddr != req.DevAddr || jap.DLSettings != req.DLSettings || jap.RXDelay != 1 {
+				t.Errorf("got join accept %+v with MIC %s", jap, f.MIC)
+			}
+			if ans.Lifetime == nil || *ans.Lifetime != 3600 {
+				t.Errorf("got lifetime %v, expected 3600", ans.Lifetime)
+			}
+
+			if key := envelopeKey(t, ans.AppSKey, nil); key != f.SessionKeys.AppSKey {
+				t.Errorf("got AppSKey %s, expected %s", key, f.SessionKeys.AppSKey)
+			}
+			if !req.DLSettings.OptNeg {
+				if key := envelopeKey(t, ans.NwkSKey, nil); key != f.SessionKeys.FNwkSIntKey || ans.FNwkSIntKey != nil {
+					t.Errorf("got NwkSKey %s and FNwkSIntKey %v, expected %s alone", key, ans.FNwkSIntKey, f.SessionKeys.FNwkSIntKey)
+				}
+				return
+			}
+			if ans.NwkSKey != nil {
+				t.Errorf("got NwkSKey %v for a LoRaWAN 1.1 network server", ans.NwkSKey)
+			}
+			if envelopeKey(t, ans.FNwkSIntKey, nil) != f.SessionKeys.FNwkSIntKey || envelopeKey(t, ans.SNwkSIntKey, nil) != f.SessionKeys.SNwkSIntKey || envelopeKey(t, ans.NwkSEncKey, nil) != f.SessionKeys.NwkSEncKey {
+				t.Errorf("got envelopes %+v, %+v and %+v, expected the keys %+v", ans.FNwkSIntKey, ans.SNwkSIntKey, ans.NwkSEncKey, f.SessionKeys)
+			}
+		})
+	}
+}
+
+func TestJoinAnsEnvelopes(t *testing.T) {
+	nsKEK, asKEK := "000102030405060708090a0b0c0d0e0f", "0f0e0d0c0b0a090807060504030201000f0e0d0c0b0a0908"
+	js := newTestJoinServer(t, nsKEK, asKEK)
+	defer js.Close()
+	nsKEKB, _ := hex.DecodeString(nsKEK)
+	asKEKB, _ := hex.DecodeString(asKEK)
+
+	for i, optNeg := range []bool{false, true} {
+		devNonce := lorawan.DevNonce(i + 1)
+		status, ans := postJoinServer(t, js, joinReq(joinRequestFrame(t, testDevEUI, testJoinEUI, devNonce, testNwkKey), testDevEUI, optNeg))
+		if status != http.StatusOK || ans.Result.ResultCode != backend.Success {
+			t.Fatalf("OptNeg %t: got status %d with result %+v", optNeg, status, ans.Result)
+		}
+
+		macVersion := lorawan.LoRaWAN1_0
+		if optNeg {
+			macVersion = lorawan.LoRaWAN1_1
+		}
+		f, err := DecodeFrame(ans.PHYPayload, &DecodeKeys{MACVersion: macVersion, NwkKey: &testNwkKey, AppKey: &testAppKey, DevEUI: testDevEUI, JoinEUI: testJoinEUI, DevNonce: &devNonce})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		type envelope struct {
+			name     string
+			envelope *backend.KeyEnvelope
+			label    string
+			kek      []byte
+			key      lorawan.AES128Key
+		}
+		envelopes := []envelope{
+			{"AppSKey", ans.AppSKey, "as", asKEKB, f.SessionKeys.AppSKey},
+			{"NwkSKey", ans.NwkSKey, "ns", nsKEKB, f.SessionKeys.FNwkSIntKey},
+		}
+		if optNeg {
+			envelopes = []envelope{
+				envelopes[0],
+				{"FNwkSIntKey", ans.FNwkSIntKey, "ns", nsKEKB, f.SessionKeys.FNwkSIntKey},
+				{"SNwkSIntKey", ans.SNwkSIntKey, "ns", nsKEKB, f.SessionKeys.SNwkSIntKey},
+				{"NwkSEncKey", ans.NwkSEncKey, "ns", nsKEKB, f.SessionKeys.NwkSEncKey},
+			}
+		}
+		for _, e := range envelopes {
+			if e.envelope == nil || e.envelope.KEKLabel != e.label || len(e.envelope.AESKey) != 24 {
+				t.Fatalf("OptNeg %t: got %s envelope %+v, expected one wrapped with %s", optNeg, e.name, e.envelope, e.label)
+			}
+			if key := envelopeKey(t, e.envelope, e.kek); key != e.key {
+				t.Errorf("OptNeg %t: got %s %s, expected %s", optNeg, e.name, key, e.key)
+			}
+			//The network keys can't be unwrapped with the application server KEK.
+			if e.label == "ns" {
+				if _, err := e.envelope.Unwrap(asKEKB); err == nil {
+					t.Errorf("OptNeg %t: %s unwrapped with the AS KEK", optNeg, e.name)
+				}
+			}
+		}
+	}
+}
+
+func TestJoinServerRequests(t *testing.T) {
+	js := newTestJoinServer(t, "", "")
+	defer js.Close()
+
+	resp, err := http.Get("http://" + js.Addr() + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != http.MethodPost {
+		t.Errorf("got status %d allowing %q, expected %d", resp.StatusCode, resp.Header.Get("Allow"), http.StatusMethodNotAllowed)
+	}
+
+	resp, err = http.Post("http://"+js.Addr()+"/", "application/json", strings.NewReader("{"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result backend.BasePayloadResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest || result.Result.ResultCode != backend.MalformedRequest {
+		t.Errorf("got status %d with result %+v, expected %d", resp.StatusCode, result.Result, http.StatusBadRequest)
+	}
+
+	req := joinReq(nil, testDevEUI, false)
+	req.MessageType = backend.RejoinReq
+	status, ans := postJoinServer(t, js, req)
+	if status != http.StatusOK || ans.Result.ResultCode != backend.Other || ans.MessageType != backend.RejoinReq {
+		t.Errorf("got status %d with %s result %+v, expected %s", status, ans.MessageType, ans.Result, backend.Other)
+	}
+}
+
+func TestParseKEK(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+		kek   string
+		size  int
+		err   bool
+	}{
+		{"no label", "", "0102", 0, false},
+		{"AES-128", "ns", "000102030405060708090a0b0c0d0e0f", 16, false},
+		{"AES-256", "ns", strings.Repeat("ab", 32), 32, false},
+		{"invalid hex", "ns", "0g", 0, true},
+		{"invalid size", "ns", "0102", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kek, err := parseKEK(tt.label, tt.kek)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if len(kek) != tt.size {
+				t.Errorf("got kek %x, expected %d bytes", kek, tt.size)
+			}
+		})
+	}
+
+	config := NewConfig()
+	config.JoinServer.NSKEKLabel = "ns"
+	config.JoinServer.NSKEK = "0102"
+	if _, err := NewJoinServer("127.0.0.1:0", config); err == nil {
+		t.Error("expected an error starting a join server with an invalid kek")
+	}
+}
@@ -0,0 +1,161 @@
+// Package keys derives the LoRaWAN 1.0 and 1.1 session and join server keys, computes the join and data MICs and
+// encrypts FRMPayloads, as devices and network servers do.
+package keys
+
+import (
+	"crypto/aes"
+	"fmt"
+
+	"github.com/brocaar/lorawan"
+	"github.com/pkg/errors"
+)
+
+// Join holds the parameters of a join the session keys are derived from: those of the join request and its accept.
+type Join struct {
+	MACVersion lorawan.MACVersion
+	//OptNeg is set on the join accept by LoRaWAN 1.1 network servers, the keys are derived the 1.0 way when it isn't.
+	OptNeg    bool
+	NetID     lorawan.NetID
+	JoinEUI   lorawan.EUI64
+	JoinNonce lorawan.JoinNonce
+	DevNonce  lorawan.DevNonce
+}
+
+// SessionKeys are the keys of a session. In LoRaWAN 1.0, and in 1.1 without OptNeg, the three network keys are the NwkSKey.
+type SessionKeys struct {
+	FNwkSIntKey lorawan.AES128Key `json:"fNwkSIntKey"`
+	SNwkSIntKey lorawan.AES128Key `json:"sNwkSIntKey"`
+	NwkSEncKey  lorawan.AES128Key `json:"nwkSEncKey"`
+	AppSKey     lorawan.AES128Key `json:"appSKey"`
+}
+
+// GetSessionKeys derives the session keys of a join. The AppSKey is derived from the AppKey when the OptNeg bit is set,
+// and from the NwkKey otherwise.
+func GetSessionKeys(nwkKey, appKey lorawan.AES128Key, j Join) (SessionKeys, error) {
+	var keys SessionKeys
+	var err error
+
+	keys.FNwkSIntKey, err = GetFNwkSIntKey(j.OptNeg, nwkKey, j.NetID, j.JoinEUI, j.JoinNonce, j.DevNonce)
+	if err != nil {
+		return keys, err
+	}
+	if j.MACVersion == lorawan.LoRaWAN1_0 || !j.OptNeg {
+		keys.NwkSEncKey = keys.FNwkSIntKey
+		keys.SNwkSIntKey = keys.FNwkSIntKey
+	} else {
+		if keys.NwkSEncKey, err = GetNwkSEncKey(j.OptNeg, nwkKey, j.NetID, j.JoinEUI, j.JoinNonce, j.DevNonce); err != nil {
+			return keys, err
+		}
+		if keys.SNwkSIntKey, err = GetSNwkSIntKey(j.OptNeg, nwkKey, j.NetID, j.JoinEUI, j.JoinNonce, j.DevNonce); err != nil {
+			return keys, err
+		}
+	}
+
+	rootKey := nwkKey
+	if j.OptNeg {
+		rootKey = appKey
+	}
+	keys.AppSKey, err = GetAppSKey(j.OptNeg, rootKey, j.NetID, j.JoinEUI, j.JoinNonce, j.DevNonce)
+	return keys, err
+}
+
+// GetFNwkSIntKey returns the FNwkSIntKey.
+// For LoRaWAN 1.0: SNwkSIntKey = NwkSEncKey = FNwkSIntKey = NwkSKey
+func GetFNwkSIntKey(optNeg bool, nwkKey lorawan.AES128Key, netID lorawan.NetID, joinEUI lorawan.EUI64, joinNonce lorawan.JoinNonce, devNonce lorawan.DevNonce) (lorawan.AES128Key, error) {
+	return getSKey(optNeg, 0x01, nwkKey, netID, joinEUI, joinNonce, devNonce)
+}
+
+// GetAppSKey returns the AppSKey, rootKey being the AppKey when optNeg is set and the NwkKey otherwise.
+func GetAppSKey(optNeg bool, rootKey lorawan.AES128Key, netID lorawan.NetID, joinEUI lorawan.EUI64, joinNonce lorawan.JoinNonce, devNonce lorawan.DevNonce) (lorawan.AES128Key, error) {
+	return getSKey(optNeg, 0x02, rootKey, netID, joinEUI, joinNonce, devNonce)
+}
+
+// GetSNwkSIntKey returns the SNwkSIntKey.
+func GetSNwkSIntKey(optNeg bool, nwkKey lorawan.AES128Key, netID lorawan.NetID, joinEUI lorawan.EUI64, joinNonce lorawan.JoinNonce, devNonce lorawan.DevNonce) (lorawan.AES128Key, error) {
+	return getSKey(optNeg, 0x03, nwkKey, netID, joinEUI, joinNonce, devNonce)
+}
+
+// GetNwkSEncKey returns the NwkSEncKey.
+func GetNwkSEncKey(optNeg bool, nwkKey lorawan.AES128Key, netID lorawan.NetID, joinEUI lorawan.EUI64, joinNonce lorawan.JoinNonce, devNonce lorawan.DevNonce) (lorawan.AES128Key, error) {
+	return getSKey(optNeg, 0x04, nwkKey, netID, joinEUI, joinNonce, devNonce)
+}
+
+// GetJSIntKey returns the JSIntKey.
+func GetJSIntKey(nwkKey lorawan.AES128Key, devEUI lorawan.EUI64) (lorawan.AES128Key, error) {
+	return getJSKey(0x06, devEUI, nwkKey)
+}
+
+// GetJSEncKey returns the JSEncKey.
+func GetJSEncKey(nwkKey lorawan.AES128Key, devEUI lorawan.EUI64) (lorawan.AES128Key, error) {
+	return getJSKey(0x05, devEUI, nwkKey)
+}
+
+func getSKey(optNeg bool, typ byte, nwkKey lorawan.AES128Key, netID lorawan.NetID, joinEUI lorawan.EUI64, joinNonce lorawan.JoinNonce, devNonce lorawan.DevNonce) (lorawan.AES128Key, error) {
+	var key lorawan.AES128Key
+	b := make([]byte, 16)
+	b[0] = typ
+
+	netIDB, err := netID.MarshalBinary()
+	if err != nil {
+		return key, errors.Wrap(err, "marshal binary error")
+	}
+
+	joinEUIB, err := joinEUI.MarshalBinary()
+	if err != nil {
+		return key, errors.Wrap(err, "marshal binary error")
+	}
+
+	joinNonceB, err := joinNonce.MarshalBinary()
+	if err != nil {
+		return key, errors.Wrap(err, "marshal binary error")
+	}
+
+	devNonceB, err := devNonce.MarshalBinary()
+	if err != nil {
+		return key, errors.Wrap(err, "marshal binary error")
+	}
+
+	if optNeg {
+		copy(b[1:4], joinNonceB)
+		copy(b[4:12], joinEUIB)
+		copy(b[12:14], devNonceB)
+	} else {
+		copy(b[1:4], joinNonceB)
+		copy(b[4:7], netIDB)
+		copy(b[7:9], devNonceB)
+	}
+
+	block, err := aes.NewCipher(nwkKey[:])
+	if err != nil {
+		return key, err
+	}
+	if block.BlockSize() != len(b) {
+		return key, fmt.Errorf("block-size of %d bytes is expected", len(b))
+	}
+	block.Encrypt(key[:], b)
+
+	return key, nil
+}
+
+func getJSKey(typ byte, devEUI lorawan.EUI64, nwkKey lorawan.AES128Key) (lorawan.AES128Key, error) {
+	var key lorawan.AES128Key
+	b := make([]byte, 16)
+
+	b[0] = typ
+
+	devB, err := devEUI.MarshalBinary()
+	if err != nil {
+		return key, err
+	}
+	copy(b[1:9], devB[:])
+
+	block, err := aes.NewCipher(nwkKey[:])
+	if err != nil {
+		return key, err
+	}
+	if block.BlockSize() != len(b) {
+		return key, fmt.Errorf("block-size of %d bytes is expected", len(b))
+	}
+	block.Encrypt(key[:], b)
+	return key, nil
+}
@@ -0,0 +1,142 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/brocaar/lorawan"
+)
+
+func key(t *testing.T, s string) lorawan.AES128Key {
+	var k lorawan.AES128Key
+	if err := k.UnmarshalText([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// The expected keys are aes128_encrypt(key, type | JoinNonce | NetID | DevNonce | pad16) and, with OptNeg,
+// aes128_encrypt(key, type | JoinNonce | JoinEUI | DevNonce | pad16), the fields being little endian as sent.
+func TestGetSessionKeys(t *testing.T) {
+	nwkKey := key(t, "000102030405060708090a0b0c0d0e0f")
+	appKey := key(t, "0f0e0d0c0b0a09080706050403020100")
+	nwkSKey, appSKey := "31b9ffcbb363ecf0aa503dd44f4df627", "ff7b1d259a194ec1ddb0a60ba53639d2"
+
+	tests := []struct {
+		name        string
+		macVersion  lorawan.MACVersion
+		optNeg      bool
+		fNwkSIntKey string
+		sNwkSIntKey string
+		nwkSEncKey  string
+		appSKey     string
+	}{
+		{"LoRaWAN 1.0", lorawan.LoRaWAN1_0, false, nwkSKey, nwkSKey, nwkSKey, appSKey},
+		{"LoRaWAN 1.1 without OptNeg", lorawan.LoRaWAN1_1, false, nwkSKey, nwkSKey, nwkSKey, appSKey},
+		{"LoRaWAN 1.1", lorawan.LoRaWAN1_1, true, "a2e9d4f7c8856184ef51466fa234aa60", "c06d79639e2637f4cf93c365c77bf5d0", "95a2c5a638149b55eaf7086783d42ea2", "e117859dd703ad57b38b1169ac80eea1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := GetSessionKeys(nwkKey, appKey, Join{
+				MACVersion: tt.macVersion,
+				OptNeg:     tt.optNeg,
+				NetID:      lorawan.NetID{1, 2, 3},
+				JoinEUI:    lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+				JoinNonce:  0x010203,
+				DevNonce:   0x0102,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if keys.FNwkSIntKey.String() != tt.fNwkSIntKey {
+				t.Errorf("got FNwkSIntKey %s, expected %s", keys.FNwkSIntKey, tt.fNwkSIntKey)
+			}
+			if keys.SNwkSIntKey.String() != tt.sNwkSIntKey {
+				t.Errorf("got SNwkSIntKey %s, expected %s", keys.SNwkSIntKey, tt.sNwkSIntKey)
+			}
+			if keys.NwkSEncKey.String() != tt.nwkSEncKey {
+				t.Errorf("got NwkSEncKey %s, expected %s", keys.NwkSEncKey, tt.nwkSEncKey)
+			}
+			if keys.AppSKey.String() != tt.appSKey {
+				t.Errorf("got AppSKey %s, expected %s", keys.AppSKey, tt.appSKey)
+			}
+		})
+	}
+
+	if _, err := GetSessionKeys(nwkKey, appKey, Join{JoinNonce: 1 << 24}); err == nil {
+		t.Error("expected an error with a JoinNonce over 24 bits")
+	}
+}
+
+// The expected keys are aes128_encrypt(NwkKey, type | DevEUI | pad16).
+func TestGetJSKeys(t *testing.T) {
+	nwkKey := key(t, "000102030405060708090a0b0c0d0e0f")
+	devEUI := lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1}
+
+	tests := []struct {
+		name     string
+		get      func(lorawan.AES128Key, lorawan.EUI64) (lorawan.AES128Key, error)
+		expected string
+	}{
+		{"JSIntKey", GetJSIntKey, "9c66a4d1260f3d78d41c3db7decc62ad"},
+		{"JSEncKey", GetJSEncKey, "386a412d7777e3b15050ba7d6b7345b6"},
+	}
+
+	for _, tt := range tests {
+		k, err := tt.get(nwkKey, devEUI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k.String() != tt.expected {
+			t.Errorf("got %s %s, expected %s", tt.name, k, tt.expected)
+		}
+	}
+}
+
+func TestJoinAcceptMIC(t *testing.T) {
+	nwkKey := key(t, "000102030405060708090a0b0c0d0e0f")
+	devEUI, joinEUI := lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1}, lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	for _, optNeg := range []bool{false, true} {
+		phy := lorawan.PHYPayload{
+			MHDR:       lorawan.MHDR{MType: lorawan.JoinAccept, Major: lorawan.LoRaWANR1},
+			MACPayload: &lorawan.JoinAcceptPayload{JoinNonce: 1, DLSettings: lorawan.DLSettings{OptNeg: optNeg}},
+		}
+		mic, err := JoinAcceptMIC(phy, nwkKey, devEUI, joinEUI, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		other, err := JoinAcceptMIC(phy, nwkKey, devEUI, joinEUI, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		//Only the LoRaWAN 1.1 MIC covers the DevNonce.
+		if (mic != other) != optNeg {
+			t.Errorf("OptNeg %t: got MICs %s and %s for two DevNonces", optNeg, mic, other)
+		}
+	}
+
+	if _, err := JoinAcceptMIC(lorawan.PHYPayload{MACPayload: &lorawan.MACPayload{}}, nwkKey, devEUI, joinEUI, 2); err == nil {
+		t.Error("expected an error with a data frame")
+	}
+}
+
+func TestEncryptFRMPayload(t *testing.T) {
+	appSKey := key(t, "0f0e0d0c0b0a09080706050403020100")
+	data, _ := hex.DecodeString("0102030405060708090a0b0c0d0e0f1011")
+
+	b, err := EncryptFRMPayload(appSKey, true, lorawan.DevAddr{1, 2, 3, 4}, 0x10001, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(b) == hex.EncodeToString(data) {
+		t.Errorf("got %x, expected it encrypted", b)
+	}
+	if b, err = DecryptFRMPayload(appSKey, true, lorawan.DevAddr{1, 2, 3, 4}, 0x10001, b); err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(b) != hex.EncodeToString(data) {
+		t.Errorf("got %x, expected %x", b, data)
+	}
+}
@@ -0,0 +1,56 @@
+package keys
+
+import (
+	"github.com/brocaar/lorawan"
+	"github.com/pkg/errors"
+)
+
+// JoinRequestMIC computes the MIC of a join request.
+func JoinRequestMIC(phy lorawan.PHYPayload, nwkKey lorawan.AES128Key) (lorawan.MIC, error) {
+	err := phy.SetUplinkJoinMIC(nwkKey)
+	return phy.MIC, err
+}
+
+// JoinAcceptMIC computes the MIC of a decrypted join accept answering a join request. It's computed with the JSIntKey
+// over the JoinEUI and DevNonce of the request too when the OptNeg bit is set, and with the NwkKey otherwise.
+func JoinAcceptMIC(phy lorawan.PHYPayload, nwkKey lorawan.AES128Key, devEUI, joinEUI lorawan.EUI64, devNonce lorawan.DevNonce) (lorawan.MIC, error) {
+	jap, ok := phy.MACPayload.(*lorawan.JoinAcceptPayload)
+	if !ok {
+		return phy.MIC, errors.New("mac payload is not a join accept payload")
+	}
+
+	key := nwkKey
+	if jap.DLSettings.OptNeg {
+		var err error
+		if key, err = GetJSIntKey(nwkKey, devEUI); err != nil {
+			return phy.MIC, err
+		}
+	}
+	err := phy.SetDownlinkJoinMIC(lorawan.JoinRequestType, joinEUI, devNonce, key)
+	return phy.MIC, err
+}
+
+// UplinkDataMIC computes the MIC of a data uplink as sent, with encrypted FOpts and FRMPayload, and the full frame counter
+// in the FHDR. The LoRaWAN 1.1 MIC also covers the counter of the acknowledged downlink, and the data rate and channel
+// the uplink is sent on. In LoRaWAN 1.0 only the FNwkSIntKey, the NwkSKey, is used.
+func UplinkDataMIC(phy lorawan.PHYPayload, macVersion lorawan.MACVersion, confFCnt uint32, txDR, txCh uint8, fNwkSIntKey, sNwkSIntKey lorawan.AES128Key) (lorawan.MIC, error) {
+	err := phy.SetUplinkDataMIC(macVersion, confFCnt, txDR, txCh, fNwkSIntKey, sNwkSIntKey)
+	return phy.MIC, err
+}
+
+// DownlinkDataMIC computes the MIC of a data downlink as sent, like UplinkDataMIC. The LoRaWAN 1.1 MIC also covers
+// the counter of the acknowledged uplink.
+func DownlinkDataMIC(phy lorawan.PHYPayload, macVersion lorawan.MACVersion, confFCnt uint32, sNwkSIntKey lorawan.AES128Key) (lorawan.MIC, error) {
+	err := phy.SetDownlinkDataMIC(macVersion, confFCnt, sNwkSIntKey)
+	return phy.MIC, err
+}
+
+// EncryptFRMPayload encrypts the FRMPayload of a frame with the AppSKey, or with the NwkSEncKey on FPort 0.
+func EncryptFRMPayload(key lorawan.AES128Key, uplink bool, devAddr lorawan.DevAddr, fCnt uint32, data []byte) ([]byte, error) {
+	return lorawan.EncryptFRMPayload(key, uplink, devAddr, fCnt, data)
+}
+
+// DecryptFRMPayload decrypts the FRMPayload of a frame, the cipher being symmetric.
+func DecryptFRMPayload(key lorawan.AES128Key, uplink bool, devAddr lorawan.DevAddr, fCnt uint32, data []byte) ([]byte, error) {
+	return lorawan.EncryptFRMPayload(key, uplink, devAddr, fCnt, data)
+}
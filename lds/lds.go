@@ -2,12 +2,14 @@ package lds
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/brocaar/chirpstack-api/go/gw"
@@ -17,6 +19,8 @@ import (
 	"github.com/go-redis/redis"
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
+	"github.com/iegomez/lds/api/gwv3"
+	"github.com/iegomez/lds/lds/keys"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
@@ -38,6 +42,13 @@ type Device struct {
 	DlFcnt        uint32             `json:"dlFcnt"`
 	marshal       func(msg proto.Message) ([]byte, error)
 	unmarshal     func(b []byte, msg proto.Message) error
+	gateway       string
+	ack           *gwv3.DownlinkTXAck
+	handlers      []*func(*Event)
+	lastUplink    time.Time
+	lastMType     lorawan.MType
+	lastFCnt      uint32
+	confFCnt      uint32 //Counter of the last confirmed downlink, acknowledged with the LoRaWAN 1.1 MIC.
 	Profile       string            `json:"profile"`
 	Joined        bool              `json:"joined"`
 	DevNonce      lorawan.DevNonce  `json:"devNonce"`
@@ -47,15 +58,28 @@ type Device struct {
 
 var redisClient *redis.Client
 
+// mqttQoS is the QoS of the messages published to the MQTT broker.
+var mqttQoS byte
+
 //StartRedis tries to connect to Redis (used for DevNonce and JoinNonce).
 func StartRedis(addr, password string, db int) error {
-	log.Debugf("Connecting to redis %s %s %s", addr, password, db)
+	log.Debugf("Connecting to redis %s %s %d", addr, password, db)
 	redisClient = redis.NewClient(&redis.Options{
 		Addr:     addr,
 		Password: password,
 		DB:       db,
 	})
 
+	redisClient.WrapProcess(func(process func(cmd redis.Cmder) error) func(cmd redis.Cmder) error {
+		return func(cmd redis.Cmder) error {
+			err := process(cmd)
+			if err != nil && err != redis.Nil {
+				storageErrors.WithLabelValues(cmd.Name()).Inc()
+			}
+			return err
+		}
+	})
+
 	_, err := redisClient.Ping().Result()
 	if err != nil {
 		log.Errorf("couldn't start Redis, only ABP profile will work. error: %s", err)
@@ -67,7 +91,7 @@ func StartRedis(addr, password string, db int) error {
 //SetMarshaler sets marshaling and unmarshaling functions according to the given option.
 func (d *Device) SetMarshaler(opt string) {
 	switch opt {
-	case "json":
+	case "json", "v4_json":
 		d.marshal = func(msg proto.Message) ([]byte, error) {
 			marshaler := &jsonpb.Marshaler{
 				EnumsAsInts:  false,
@@ -84,7 +108,7 @@ func (d *Device) SetMarshaler(opt string) {
 			return unmarshaler.Unmarshal(bytes.NewReader(b), msg)
 		}
 
-	case "protobuf":
+	case "protobuf", "v4_protobuf":
 		d.marshal = func(msg proto.Message) ([]byte, error) {
 			return proto.Marshal(msg)
 		}
@@ -92,6 +116,12 @@ func (d *Device) SetMarshaler(opt string) {
 		d.unmarshal = func(b []byte, msg proto.Message) error {
 			return proto.Unmarshal(b, msg)
 		}
+	case "v2_json":
+		d.marshal = marshalV2
+		d.unmarshal = unmarshalV2
+	case "ttn_protobuf":
+		d.marshal = marshalTTN
+		d.unmarshal = unmarshalTTN
 	default:
 		//Plain old json.
 		d.marshal = func(msg proto.Message) ([]byte, error) {
@@ -102,6 +132,12 @@ func (d *Device) SetMarshaler(opt string) {
 			return json.Unmarshal(b, msg)
 		}
 	}
+
+	//ChirpStack v4 messages are encoded in the same way, but need to be converted first.
+	if strings.HasPrefix(opt, "v4_") {
+		d.marshal = marshalV4(d.marshal)
+		d.unmarshal = unmarshalV4(d.unmarshal)
+	}
 }
 
 // RedisSet is wrapper around redis client
@@ -163,6 +199,11 @@ func (d *Device) Join(client MQTT.Client, topicTemplate, gwMac string, rxInfo *g
 		return err
 	}
 
+	d.gateway = gwMac
+	if err := GetGateway(gwMac).receive(rxInfo, txInfo, len(joinStr)); err != nil {
+		return err
+	}
+
 	message := &gw.UplinkFrame{
 		PhyPayload: joinStr,
 		RxInfo:     rxInfo,
@@ -171,7 +212,7 @@ func (d *Device) Join(client MQTT.Client, topicTemplate, gwMac string, rxInfo *g
 
 	log.Infof("frame: %+v\n", message)
 
-	topic := fmt.Sprintf(topicTemplate, gwMac)
+	topic := FormatTopic(topicTemplate, gwMac)
 
 	b, err := d.marshal(message)
 	if err != nil {
@@ -179,9 +220,13 @@ func (d *Device) Join(client MQTT.Client, topicTemplate, gwMac string, rxInfo *g
 		return err
 	}
 
-	pErr := publish(client, topic, b)
+	if err := publish(client, topic, b); err != nil {
+		return err
+	}
 
-	return pErr
+	captureUplink(joinStr, gwMac, rxInfo, txInfo, true)
+	d.joinSent(joinStr, rxInfo, txInfo)
+	return nil
 }
 
 // JoinUDP sends a join request for a given device (OTAA) and rxInfo via raw packet_forwarder protocol
@@ -194,6 +239,11 @@ func (d *Device) JoinUDP(cClient NSClient, gwMac string, rxInfo *gw.UplinkRXInfo
 		return err
 	}
 
+	d.gateway = gwMac
+	if err := GetGateway(gwMac).receive(rxInfo, txInfo, len(phyBytes)); err != nil {
+		return err
+	}
+
 	log.Debugln("Sending UDP join payload")
 	err = cClient.sendWithPayload(phyBytes, gwMac, rxInfo, txInfo)
 
@@ -202,6 +252,8 @@ func (d *Device) JoinUDP(cClient NSClient, gwMac string, rxInfo *gw.UplinkRXInfo
 		return err
 	}
 
+	captureUplink(phyBytes, gwMac, rxInfo, txInfo, false)
+	d.joinSent(phyBytes, rxInfo, txInfo)
 	return nil
 }
 
@@ -282,7 +334,7 @@ func (d *Device) marshalPhyPayload(mType lorawan.MType, fPort uint8, rxInfo *gw.
 		}
 
 		//Now set the MIC.
-		if err := phy.SetUplinkDataMIC(lorawan.LoRaWAN1_1, d.UlFcnt, uint8(txDR), uint8(txCh), d.FNwkSIntKey, d.SNwkSIntKey); err != nil {
+		if err := phy.SetUplinkDataMIC(lorawan.LoRaWAN1_1, d.confFCnt, uint8(txDR), uint8(txCh), d.FNwkSIntKey, d.SNwkSIntKey); err != nil {
 			log.Errorf("set uplink mic error: %s", err)
 			return nil, err
 		}
@@ -317,6 +369,17 @@ func (d *Device) Uplink(client MQTT.Client, topicTemplate string, mType lorawan.
 		}
 	}
 
+	d.gateway = gwMAC
+	if err := GetGateway(gwMAC).SetBand(bandName); err != nil {
+		return d.UlFcnt, err
+	}
+	if err := GetGateway(gwMAC).receive(rxInfo, txInfo, len(phyBytes)); err != nil {
+		//The device did transmit, so the frame counter must be increased anyway.
+		d.UlFcnt++
+		d.RedisSet(ulFcntKey, d.UlFcnt, 0)
+		return d.UlFcnt, err
+	}
+
 	message := gw.UplinkFrame{
 		PhyPayload: phyBytes,
 		RxInfo:     rxInfo,
@@ -333,12 +396,11 @@ func (d *Device) Uplink(client MQTT.Client, topicTemplate string, mType lorawan.
 
 	log.Debugf("marshaled message: %v\n", string(bytes))
 
-	topic := fmt.Sprintf(topicTemplate, gwMAC)
-
-	if token := client.Publish(topic, 0, false, bytes); token.Wait() && token.Error() != nil {
-		log.Errorf("publish error: %s", token.Error())
-		return d.UlFcnt, token.Error()
+	if err := publish(client, FormatTopic(topicTemplate, gwMAC), bytes); err != nil {
+		return d.UlFcnt, err
 	}
+	captureUplink(phyBytes, gwMAC, rxInfo, txInfo, true)
+	d.uplinkSent(mType, fPort, payload, macCommands, fCtrl, phyBytes, rxInfo, txInfo)
 
 	//Message was sent, UlFcnt can be set.
 	d.UlFcnt++
@@ -367,11 +429,24 @@ func (d *Device) UplinkUDP(cClient NSClient, mType lorawan.MType, fPort uint8, r
 		return d.UlFcnt, err
 	}
 
+	d.gateway = gwMAC
+	if err := GetGateway(gwMAC).SetBand(bandName); err != nil {
+		return d.UlFcnt, err
+	}
+	if err := GetGateway(gwMAC).receive(rxInfo, txInfo, len(phyBytes)); err != nil {
+		//The device did transmit, so the frame counter must be increased anyway.
+		d.UlFcnt++
+		d.RedisSet(ulFcntKey, d.UlFcnt, 0)
+		return d.UlFcnt, err
+	}
+
 	err = cClient.sendWithPayload(phyBytes, gwMAC, rxInfo, txInfo)
 	if err != nil {
 		log.Debugf("Unable to send UDP datagram: %s\n", err)
 		return d.UlFcnt, err
 	}
+	captureUplink(phyBytes, gwMAC, rxInfo, txInfo, false)
+	d.uplinkSent(mType, fPort, payload, macCommands, fCtrl, phyBytes, rxInfo, txInfo)
 
 	//Message was sent, UlFcnt can be set.
 	d.UlFcnt++
@@ -380,24 +455,41 @@ func (d *Device) UplinkUDP(cClient NSClient, mType lorawan.MType, fPort uint8, r
 	return d.UlFcnt, nil
 }
 
+// PublishStats publishes the stats of the given gateway.
+func (d *Device) PublishStats(client MQTT.Client, topicTemplate, gwMAC string) error {
+	b, err := d.marshal(GetGateway(gwMAC).Stats())
+	if err != nil {
+		log.Errorf("error marshaling gateway stats: %s", err)
+		return err
+	}
+
+	return publish(client, FormatTopic(topicTemplate, gwMAC), b)
+}
+
 //ProcessDownlink processes a downlink message from the loraserver.
 func (d *Device) ProcessDownlink(dlMessage []byte, mv lorawan.MACVersion, mqtt bool) (string, error) {
 	log.Debugf("original dlmessage: %s", string(dlMessage))
 
 	var payload []byte = nil
 	if mqtt {
-		var df map[string]interface{}
+		var df gwv3.DownlinkFrame
 
-		err := json.Unmarshal(dlMessage, &df)
+		err := d.unmarshal(dlMessage, &df)
 		if err != nil {
 			return "", err
 		}
 
-		payload = []byte(df["phyPayload"].(string))
+		item := d.emitDownlink(&df)
+		if item == nil {
+			return "Downlink not emitted", nil
+		}
+		captureMQTTDownlink(item)
+
+		payload = item.GetPhyPayload()
 	} else {
-		var contents map[string]interface{}
+		var txpk TXPK
 
-		result, payloadBase, err := UDPParsePacket(dlMessage, &contents)
+		result, err := UDPParsePacket(dlMessage, &txpk)
 
 		if err != nil {
 			return "", err
@@ -407,13 +499,23 @@ func (d *Device) ProcessDownlink(dlMessage []byte, mv lorawan.MACVersion, mqtt b
 			return "Service (non-PULL_RESP) ignored", nil
 		}
 
-		payload = []byte(payloadBase)
+		//Downlinks with wrong TX parameters are still processed, but not counted as emitted.
+		err = GetGateway(d.gateway).CheckTXPK(&txpk)
+		GetGateway(d.gateway).countDownlink(err == nil)
+
+		payload, err = base64.StdEncoding.DecodeString(txpk.Data)
+		if err != nil {
+			return "", err
+		}
+		if dr, err := txpk.dataRate(); err == nil {
+			captureFrame(payload, int(txpk.Freq*1000000+0.5), dr, 0, 0)
+		}
 	}
 
 	var phy lorawan.PHYPayload
-	log.Debugf("encrypted payload: %s", string(payload))
+	log.Debugf("encrypted payload: %x", payload)
 
-	if err := phy.UnmarshalText(payload); err != nil {
+	if err := phy.UnmarshalBinary(payload); err != nil {
 		log.Error("failed at unmarshal")
 		return "", err
 	}
@@ -428,14 +530,32 @@ func (d *Device) ProcessDownlink(dlMessage []byte, mv lorawan.MACVersion, mqtt b
 
 }
 
+// emitDownlink returns the item of a downlink frame that the gateway emits, or nil if none was, and keeps
+// the ack to be published. Frames sent by network servers that don't know about items carry the payload and
+// tx info at the top level.
+func (d *Device) emitDownlink(df *gwv3.DownlinkFrame) *gwv3.DownlinkFrameItem {
+	items := df.GetItems()
+	if len(items) == 0 && len(df.GetPhyPayload()) > 0 {
+		items = []*gwv3.DownlinkFrameItem{{PhyPayload: df.GetPhyPayload(), TxInfo: df.GetTxInfo()}}
+	}
+
+	i, ack := GetGateway(d.gateway).emit(df, items)
+	d.ack = ack
+
+	if i < 0 {
+		return nil
+	}
+	return items[i]
+}
+
 func (d *Device) processJoinResponse(phy lorawan.PHYPayload, payload []byte, mv lorawan.MACVersion) (string, error) {
 	log.Infoln("processing join response")
 
-	log.Debugln("Network key on join: %s", KeyToHex(d.NwkKey))
+	log.Debugf("Network key on join: %s", KeyToHex(d.NwkKey))
 	err := phy.DecryptJoinAcceptPayload(d.NwkKey)
 	if err != nil {
 		log.Errorf("can't decrypt join accept: %s", err)
-		return "", err
+		return "", d.decryptFailure(err)
 	}
 
 	jap, ok := phy.MACPayload.(*lorawan.JoinAcceptPayload)
@@ -443,26 +563,12 @@ func (d *Device) processJoinResponse(phy lorawan.PHYPayload, payload []byte, mv
 		return "", errors.New("mac payload is not a join accept payload")
 	}
 
-	if jap.DLSettings.OptNeg {
-		jsIntKey, err := getJSIntKey(d.NwkKey, d.DevEUI)
-		if err != nil {
-			return "", err
-		}
-		ok, err := phy.ValidateDownlinkJoinMIC(0xFF, d.JoinEUI, d.DevNonce, jsIntKey)
-		if err != nil {
-			return "", err
-		}
-		if !ok {
-			return "", errors.New("validate downlink join mic not ok")
-		}
-	} else {
-		ok, err := phy.ValidateDownlinkJoinMIC(0xFF, d.JoinEUI, d.DevNonce, d.NwkKey)
-		if err != nil {
-			return "", err
-		}
-		if !ok {
-			return "", errors.New("validate downlink join mic not ok")
-		}
+	ok, err = validateJoinAcceptMIC(phy, d.NwkKey, d.DevEUI, d.JoinEUI, d.DevNonce)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", d.micFailure(lorawan.JoinAccept, payload, errors.New("validate downlink join mic not ok"))
 	}
 
 	phyJSON, err := phy.MarshalJSON()
@@ -491,19 +597,14 @@ func (d *Device) processJoinResponse(phy lorawan.PHYPayload, payload []byte, mv
 	log.Infof("setting join nonce: %d", d.JoinNonce)
 	d.RedisSet(joinNonceKey, uint16(jap.JoinNonce), 0)
 
-	d.FNwkSIntKey, err = getFNwkSIntKey(jap.DLSettings.OptNeg, d.NwkKey, jap.HomeNetID, d.JoinEUI, jap.JoinNonce, d.DevNonce)
-	if d.MACVersion == 0 {
-		d.NwkSEncKey = d.FNwkSIntKey
-		d.SNwkSIntKey = d.FNwkSIntKey
-	} else {
-		d.NwkSEncKey, err = getNwkSEncKey(jap.DLSettings.OptNeg, d.NwkKey, jap.HomeNetID, d.JoinEUI, jap.JoinNonce, d.DevNonce)
-		d.SNwkSIntKey, err = getSNwkSIntKey(jap.DLSettings.OptNeg, d.NwkKey, jap.HomeNetID, d.JoinEUI, jap.JoinNonce, d.DevNonce)
-	}
-	if jap.DLSettings.OptNeg {
-		d.AppSKey, err = getAppSKey(jap.DLSettings.OptNeg, d.AppKey, jap.HomeNetID, d.JoinEUI, jap.JoinNonce, d.DevNonce)
-	} else {
-		d.AppSKey, err = getAppSKey(jap.DLSettings.OptNeg, d.NwkKey, jap.HomeNetID, d.JoinEUI, jap.JoinNonce, d.DevNonce)
+	sessionKeys, err := keys.GetSessionKeys(d.NwkKey, d.AppKey, joinParams(jap, d.MACVersion, d.JoinEUI, d.DevNonce))
+	if err != nil {
+		return "", errors.Wrap(err, "session keys derivation error")
 	}
+	d.FNwkSIntKey = sessionKeys.FNwkSIntKey
+	d.NwkSEncKey = sessionKeys.NwkSEncKey
+	d.SNwkSIntKey = sessionKeys.SNwkSIntKey
+	d.AppSKey = sessionKeys.AppSKey
 
 	d.DevAddr = jap.DevAddr
 	d.Joined = true
@@ -534,6 +635,8 @@ func (d *Device) processJoinResponse(phy lorawan.PHYPayload, payload []byte, mv
 
 	log.Infoln("Join successful!")
 
+	d.handleDownlink(&Downlink{Time: time.Now(), MType: lorawan.JoinAccept, DevAddr: jap.DevAddr, PHYPayload: payload})
+
 	return string(phyJSON), nil
 }
 
@@ -560,25 +663,18 @@ func (d *Device) processDownlink(phy lorawan.PHYPayload, payload []byte, mv lora
 			return "", err
 		}
 		if !ok {
-			return "", errors.New("downlink error: invalid mic")
+			return "", d.micFailure(phy.MHDR.MType, payload, errors.New("downlink error: invalid mic"))
 		}
 	}
 
-	if err := phy.DecryptFRMPayload(d.AppSKey); err != nil {
+	if err := decryptFRMPayload(&phy, d.NwkSEncKey, d.AppSKey); err != nil {
 		log.Error("failed at downlink frm payload decryption")
-		return "", err
+		return "", d.decryptFailure(err)
 	}
 
-	if d.MACVersion == lorawan.LoRaWAN1_0 {
-		if err := phy.DecodeFOptsToMACCommands(); err != nil {
-			log.Error("failed at downlink opts to mac commands decoding")
-			return "", err
-		}
-	} else {
-		if err := phy.DecryptFOpts(d.NwkSEncKey); err != nil {
-			log.Error("failed at downlink opts decryption")
-			return "", err
-		}
+	if err := decryptFOpts(&phy, d.MACVersion, d.NwkSEncKey); err != nil {
+		log.Error("failed at downlink opts decryption")
+		return "", d.decryptFailure(err)
 	}
 
 	phyJSON, err := phy.MarshalJSON()
@@ -604,6 +700,11 @@ func (d *Device) processDownlink(phy lorawan.PHYPayload, payload []byte, mv lora
 	}
 
 	log.Infof("dlFcnt: %d / received Fcnt: %d", d.DlFcnt, macPayload.FHDR.FCnt)
+	if phy.MHDR.MType == lorawan.ConfirmedDataDown {
+		d.confFCnt = macPayload.FHDR.FCnt
+	}
+
+	d.handleDownlink(newDataDownlink(phy.MHDR.MType, macPayload, payload))
 
 	return string(phyJSON), nil
 }
@@ -896,12 +997,34 @@ func testMIC(appKey [16]byte, appEUI, devEUI [8]byte) error {
 	return nil
 }
 
+// SetQoS sets the QoS of the messages published to the MQTT broker.
+func SetQoS(qos int) error {
+	if qos < 0 || qos > 2 {
+		return fmt.Errorf("invalid mqtt qos %d", qos)
+	}
+	mqttQoS = byte(qos)
+	return nil
+}
+
+// FormatTopic replaces %s in the topic template with the gateway MAC. Templates without it are used as is,
+// e.g. for The Things Stack topics, which carry the gateway ID instead.
+func FormatTopic(topicTemplate, gwMAC string) string {
+	if !strings.Contains(topicTemplate, "%s") {
+		return topicTemplate
+	}
+	return fmt.Sprintf(topicTemplate, gwMAC)
+}
+
 //publish publishes a message to the broker.
 func publish(client MQTT.Client, topic string, bytes []byte) error {
+	return publishWith(client, topic, mqttQoS, false, bytes)
+}
+
+func publishWith(client MQTT.Client, topic string, qos byte, retained bool, bytes []byte) error {
 
 	log.Infof("sending to topic %s", topic)
 
-	if token := client.Publish(topic, 0, false, bytes); token.Wait() && token.Error() != nil {
+	if token := client.Publish(topic, qos, retained, bytes); token.Wait() && token.Error() != nil {
 		log.Errorf("publish error: %s", token.Error())
 		return token.Error()
 	}
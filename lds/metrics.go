@@ -0,0 +1,148 @@
+package lds
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	uplinksSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lds_uplinks_sent_total",
+		Help: "Data uplinks sent.",
+	}, []string{"dev_eui", "gateway"})
+
+	joinsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lds_joins_sent_total",
+		Help: "Join requests sent.",
+	}, []string{"dev_eui", "gateway"})
+
+	joinAcceptsReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lds_join_accepts_received_total",
+		Help: "Join accepts processed.",
+	}, []string{"dev_eui", "gateway"})
+
+	downlinksReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lds_downlinks_received_total",
+		Help: "Join accepts and data downlinks processed, by message type.",
+	}, []string{"dev_eui", "gateway", "m_type"})
+
+	micFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lds_mic_failures_total",
+		Help: "Join accepts and data downlinks dropped because of an invalid MIC.",
+	}, []string{"dev_eui"})
+
+	decryptFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lds_decrypt_failures_total",
+		Help: "Join accepts and data downlinks that couldn't be decrypted.",
+	}, []string{"dev_eui"})
+
+	retransmissions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lds_retransmissions_total",
+		Help: "Data uplinks sent again with the frame counter of the previous one, as after a failed send.",
+	}, []string{"dev_eui"})
+
+	storageErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lds_storage_errors_total",
+		Help: "Redis command errors, by command.",
+	}, []string{"command"})
+
+	transportReconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lds_transport_reconnects_total",
+		Help: "Reconnections to the network server, by transport.",
+	}, []string{"transport"})
+
+	txParamErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lds_downlink_tx_param_errors_total",
+		Help: "Downlinks sent by the network server with TX parameters that violate the band rules.",
+	}, []string{"gateway"})
+
+	downlinkLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "lds_uplink_downlink_latency_seconds",
+		Help:    "Time from the last uplink of the device to a processed join accept or data downlink.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 1.5, 2, 3, 5, 6, 8, 10, 16},
+	}, []string{"m_type"})
+)
+
+// countUplink counts a sent uplink and whether it's a retransmission, keeping its time for the downlink latency.
+func (d *Device) countUplink(mType lorawan.MType) {
+	devEUI := d.DevEUI.String()
+	if mType == lorawan.JoinRequest {
+		joinsSent.WithLabelValues(devEUI, d.gateway).Inc()
+	} else {
+		if !d.lastUplink.IsZero() && d.lastMType != lorawan.JoinRequest && d.UlFcnt == d.lastFCnt {
+			retransmissions.WithLabelValues(devEUI).Inc()
+		}
+		uplinksSent.WithLabelValues(devEUI, d.gateway).Inc()
+		d.lastFCnt = d.UlFcnt
+	}
+	d.lastUplink = time.Now()
+	d.lastMType = mType
+}
+
+func (d *Device) countDownlink(dl *Downlink) {
+	devEUI := d.DevEUI.String()
+	if dl.MType == lorawan.JoinAccept {
+		joinAcceptsReceived.WithLabelValues(devEUI, d.gateway).Inc()
+	}
+	downlinksReceived.WithLabelValues(devEUI, d.gateway, dl.MType.String()).Inc()
+	if !d.lastUplink.IsZero() {
+		downlinkLatency.WithLabelValues(dl.MType.String()).Observe(dl.Time.Sub(d.lastUplink).Seconds())
+	}
+}
+
+// decryptFailure counts the error of a downlink decryption.
+func (d *Device) decryptFailure(err error) error {
+	decryptFailures.WithLabelValues(d.DevEUI.String()).Inc()
+	return err
+}
+
+// MetricsServer serves the Prometheus metrics at /metrics.
+type MetricsServer struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewMetricsServer starts serving the metrics on addr.
+func NewMetricsServer(addr string) (*MetricsServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "metrics listen error")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	s := &MetricsServer{
+		listener: listener,
+		server:   &http.Server{Handler: mux},
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Errorf("metrics server error: %s", err)
+		}
+	}()
+
+	log.Infof("metrics listening on %s", listener.Addr())
+	return s, nil
+}
+
+// Addr returns the address the metrics are served on.
+func (s *MetricsServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops serving the metrics.
+func (s *MetricsServer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
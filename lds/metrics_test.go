@@ -0,0 +1,148 @@
+package lds
+
+import (
+	"bufio"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// scrape returns the value of the series served by the metrics server, or -1 when it's missing.
+func scrape(t *testing.T, s *MetricsServer, series string) float64 {
+	resp, err := http.Get("http://" + s.Addr() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, series+" ") {
+			v, err := strconv.ParseFloat(strings.TrimPrefix(line, series+" "), 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return -1
+}
+
+func TestCountUplink(t *testing.T) {
+	tests := []struct {
+		name            string
+		devEUI          lorawan.EUI64
+		mTypes          []lorawan.MType
+		fCnts           []uint32
+		joins           float64
+		uplinks         float64
+		retransmissions float64
+	}{
+		{"join", lorawan.EUI64{0x43, 0, 0, 0, 0, 0, 0, 1}, []lorawan.MType{lorawan.JoinRequest, lorawan.JoinRequest}, []uint32{0, 0}, 2, 0, 0},
+		{"uplinks", lorawan.EUI64{0x43, 0, 0, 0, 0, 0, 0, 2}, []lorawan.MType{lorawan.UnconfirmedDataUp, lorawan.ConfirmedDataUp}, []uint32{0, 1}, 0, 2, 0},
+		{"retransmission", lorawan.EUI64{0x43, 0, 0, 0, 0, 0, 0, 3}, []lorawan.MType{lorawan.ConfirmedDataUp, lorawan.ConfirmedDataUp, lorawan.ConfirmedDataUp}, []uint32{4, 4, 5}, 0, 3, 1},
+		//The counter of a session is reset by the join, the first uplink after it isn't a retransmission.
+		{"uplink after a join", lorawan.EUI64{0x43, 0, 0, 0, 0, 0, 0, 4}, []lorawan.MType{lorawan.UnconfirmedDataUp, lorawan.JoinRequest, lorawan.UnconfirmedDataUp}, []uint32{0, 0, 0}, 1, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Device{DevEUI: tt.devEUI, gateway: "0102030405060708"}
+			for i, mType := range tt.mTypes {
+				d.UlFcnt = tt.fCnts[i]
+				d.countUplink(mType)
+			}
+
+			devEUI := tt.devEUI.String()
+			if count := testutil.ToFloat64(joinsSent.WithLabelValues(devEUI, d.gateway)); count != tt.joins {
+				t.Errorf("got %v joins, expected %v", count, tt.joins)
+			}
+			if count := testutil.ToFloat64(uplinksSent.WithLabelValues(devEUI, d.gateway)); count != tt.uplinks {
+				t.Errorf("got %v uplinks, expected %v", count, tt.uplinks)
+			}
+			if count := testutil.ToFloat64(retransmissions.WithLabelValues(devEUI)); count != tt.retransmissions {
+				t.Errorf("got %v retransmissions, expected %v", count, tt.retransmissions)
+			}
+			if d.lastUplink.IsZero() || d.lastMType != tt.mTypes[len(tt.mTypes)-1] {
+				t.Errorf("got last uplink %s at %s", d.lastMType, d.lastUplink)
+			}
+		})
+	}
+}
+
+func TestCountDownlink(t *testing.T) {
+	s, err := NewMetricsServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	d := &Device{DevEUI: lorawan.EUI64{0x43, 0, 0, 0, 0, 0, 1, 0}, gateway: "0102030405060708"}
+	devEUI := d.DevEUI.String()
+	latency := `lds_uplink_downlink_latency_seconds_count{m_type="ConfirmedDataDown"}`
+	before := scrape(t, s, latency)
+	if before < 0 {
+		before = 0
+	}
+
+	//No latency without an uplink.
+	d.countDownlink(&Downlink{MType: lorawan.ConfirmedDataDown, Time: time.Now()})
+	if count := scrape(t, s, latency); count > before {
+		t.Errorf("got %v latencies, expected %v", count, before)
+	}
+
+	d.countUplink(lorawan.JoinRequest)
+	d.countDownlink(&Downlink{MType: lorawan.JoinAccept, Time: d.lastUplink.Add(5 * time.Second)})
+	d.countUplink(lorawan.ConfirmedDataUp)
+	d.countDownlink(&Downlink{MType: lorawan.ConfirmedDataDown, Time: d.lastUplink.Add(time.Second)})
+
+	if count := testutil.ToFloat64(joinAcceptsReceived.WithLabelValues(devEUI, d.gateway)); count != 1 {
+		t.Errorf("got %v join accepts, expected 1", count)
+	}
+	if count := testutil.ToFloat64(downlinksReceived.WithLabelValues(devEUI, d.gateway, "ConfirmedDataDown")); count != 2 {
+		t.Errorf("got %v confirmed downlinks, expected 2", count)
+	}
+	if count := scrape(t, s, latency); count != before+1 {
+		t.Errorf("got %v latencies, expected %v", count, before+1)
+	}
+	series := `lds_downlinks_received_total{dev_eui="` + devEUI + `",gateway="0102030405060708",m_type="JoinAccept"}`
+	if count := scrape(t, s, series); count != 1 {
+		t.Errorf("got %s %v served, expected 1", series, count)
+	}
+}
+
+func TestDownlinkFailures(t *testing.T) {
+	d := &Device{DevEUI: lorawan.EUI64{0x43, 0, 0, 0, 0, 1, 0, 0}}
+	events := 0
+	d.AddEventHandler(func(e *Event) {
+		if e.Type == EventMICFailure && e.MICFailure.MType == lorawan.UnconfirmedDataDown {
+			events++
+		}
+	})
+
+	failure := errors.New("invalid MIC")
+	if err := d.micFailure(lorawan.UnconfirmedDataDown, []byte{0x60}, failure); err != failure {
+		t.Errorf("got error %v, expected %v", err, failure)
+	}
+	if err := d.decryptFailure(failure); err != failure {
+		t.Errorf("got error %v, expected %v", err, failure)
+	}
+	d.decryptFailure(failure)
+
+	devEUI := d.DevEUI.String()
+	if count := testutil.ToFloat64(micFailures.WithLabelValues(devEUI)); count != 1 || events != 1 {
+		t.Errorf("got %v MIC failures and %d events, expected 1", count, events)
+	}
+	if count := testutil.ToFloat64(decryptFailures.WithLabelValues(devEUI)); count != 2 {
+		t.Errorf("got %v decrypt failures, expected 2", count)
+	}
+}
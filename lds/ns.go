@@ -0,0 +1,883 @@
+package lds
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/common"
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/band"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/iegomez/lds/api/gwv3"
+	"github.com/iegomez/lds/lds/keys"
+)
+
+// Network server transports.
+const (
+	NSTransportUDP  = "udp"
+	NSTransportMQTT = "mqtt"
+)
+
+// ErrNoNetworkServer is returned by API backends when the embedded network server isn't running.
+var ErrNoNetworkServer = errors.New("the network server isn't running")
+
+// maxFOptsLen is the size of the FOpts field, MAC commands that don't fit are sent on FPort 0.
+const maxFOptsLen = 15
+
+// NetworkServer is a minimal network server for the device of the configuration, so that devices and codecs may be
+// developed without a network server install. It answers join requests, checks the MIC and frame counter of uplinks,
+// answers LinkCheckReq and DeviceTimeReq, and sends the queued downlinks in the RX1 window of the following uplinks.
+// It talks to the simulated gateway with the packet forwarder UDP protocol or through MQTT.
+type NetworkServer struct {
+	config *Config
+	band   band.Band
+	netID  lorawan.NetID
+	//devAddr is given to the joining devices, a random one within the NetID is when nil.
+	devAddr *lorawan.DevAddr
+
+	conn       *net.UDPConn
+	mqttClient MQTT.Client
+	marshaling *Device
+
+	mu        sync.Mutex
+	closed    bool
+	joinNonce lorawan.JoinNonce
+	devNonces map[lorawan.EUI64]map[lorawan.DevNonce]bool
+	session   *nsSession
+	queue     []*NSDownlink
+	//pullAddrs are the addresses the gateways sent their PULL_DATA from.
+	pullAddrs map[lorawan.EUI64]*net.UDPAddr
+}
+
+// nsSession is the session of the device on the network server.
+type nsSession struct {
+	devEUI     lorawan.EUI64
+	devAddr    lorawan.DevAddr
+	macVersion lorawan.MACVersion
+	keys       keys.SessionKeys
+	//fCntUp is the next expected uplink counter, which isn't checked when skipFCntCheck is set.
+	fCntUp        uint32
+	skipFCntCheck bool
+	//LoRaWAN 1.1 counts the downlinks with application data on the AFCntDown, 1.0 only uses the NFCntDown.
+	nFCntDown uint32
+	aFCntDown uint32
+	//confFCnt is the counter of the last confirmed downlink, acknowledged by the next uplink.
+	confFCnt uint32
+}
+
+// NSDownlink is a downlink queued on the network server. MAC commands are sent in the FOpts, or on FPort 0 when
+// there's no payload and they don't fit.
+type NSDownlink struct {
+	FPort       uint8
+	Payload     []byte
+	Confirmed   bool
+	MACCommands []*lorawan.MACCommand
+}
+
+// nsUplink is an uplink received by the network server with the gateway metadata needed to answer it.
+type nsUplink struct {
+	phyPayload []byte
+	gatewayMAC lorawan.EUI64
+	//gateway is the MAC of an MQTT uplink as written in its topic.
+	gateway   string
+	frequency int
+	dataRate  band.DataRate
+	snr       float64
+	time      time.Time
+	//tmst is the concentrator counter of a packet forwarder uplink, context the one of an MQTT uplink.
+	tmst    uint32
+	context []byte
+}
+
+// nsTX is an answer to an uplink, sent delay after it.
+type nsTX struct {
+	phyPayload []byte
+	delay      time.Duration
+	frequency  int
+	dataRate   band.DataRate
+	power      int
+}
+
+// NewNetworkServer starts the network server with the configured transport.
+func NewNetworkServer(config *Config) (*NetworkServer, error) {
+	c := config.NetworkServer
+	ns := &NetworkServer{
+		config:    config,
+		joinNonce: lorawan.JoinNonce(c.JoinNonce),
+		devNonces: make(map[lorawan.EUI64]map[lorawan.DevNonce]bool),
+		pullAddrs: make(map[lorawan.EUI64]*net.UDPAddr),
+	}
+
+	var err error
+	if ns.band, err = band.GetConfig(config.Band.Name, false, lorawan.DwellTime400ms); err != nil {
+		return nil, errors.Wrap(err, "band error")
+	}
+	if err := ns.netID.UnmarshalText([]byte(c.NetID)); err != nil {
+		return nil, errors.Wrap(err, "net id error")
+	}
+	if c.DevAddr != "" {
+		var devAddr lorawan.DevAddr
+		if err := devAddr.UnmarshalText([]byte(c.DevAddr)); err != nil {
+			return nil, errors.Wrap(err, "dev addr error")
+		}
+		ns.devAddr = &devAddr
+	}
+	if len(c.CFList) > len(lorawan.CFListChannelPayload{}.Channels) {
+		return nil, fmt.Errorf("the cf list has %d channels, at most %d fit", len(c.CFList), len(lorawan.CFListChannelPayload{}.Channels))
+	}
+
+	switch c.Transport {
+	case NSTransportUDP, "":
+		err = ns.listenUDP(c.Bind)
+	case NSTransportMQTT:
+		err = ns.connectMQTT()
+	default:
+		err = fmt.Errorf("unknown network server transport %q", c.Transport)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// Close stops the network server.
+func (ns *NetworkServer) Close() error {
+	ns.mu.Lock()
+	ns.closed = true
+	ns.mu.Unlock()
+
+	if ns.mqttClient != nil {
+		ns.mqttClient.Disconnect(200)
+		return nil
+	}
+	return ns.conn.Close()
+}
+
+// Addr returns the UDP address the network server listens on, or the MQTT server it's connected to.
+func (ns *NetworkServer) Addr() string {
+	if ns.conn != nil {
+		return ns.conn.LocalAddr().String()
+	}
+	return ns.config.MQTT.Server
+}
+
+// Enqueue queues a downlink, sent in answer to the next uplink of the device.
+func (ns *NetworkServer) Enqueue(dl *NSDownlink) int {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	ns.queue = append(ns.queue, dl)
+	log.Infof("network server: downlink queued (%d in the queue)", len(ns.queue))
+	return len(ns.queue)
+}
+
+func (ns *NetworkServer) listenUDP(bind string) error {
+	addr, err := net.ResolveUDPAddr("udp", bind)
+	if err != nil {
+		return errors.Wrap(err, "network server bind error")
+	}
+	if ns.conn, err = net.ListenUDP("udp", addr); err != nil {
+		return errors.Wrap(err, "network server listen error")
+	}
+
+	go ns.serveUDP()
+	log.Infof("network server listening on %s", ns.conn.LocalAddr())
+	return nil
+}
+
+func (ns *NetworkServer) serveUDP() {
+	buffer := make([]byte, 65535)
+	for {
+		size, addr, err := ns.conn.ReadFromUDP(buffer)
+		if err != nil {
+			ns.mu.Lock()
+			closed := ns.closed
+			ns.mu.Unlock()
+			if closed {
+				return
+			}
+			log.Errorf("network server read error: %s", err)
+			continue
+		}
+		ns.handleDatagram(append([]byte(nil), buffer[:size]...), addr)
+	}
+}
+
+// handleDatagram acknowledges the PUSH_DATA and PULL_DATA of a gateway and answers the uplinks of its PUSH_DATA.
+func (ns *NetworkServer) handleDatagram(packet []byte, addr *net.UDPAddr) {
+	if len(packet) < 12 || packet[0] != 0x02 {
+		log.Warningf("network server: bad datagram from %s", addr)
+		return
+	}
+
+	var mac lorawan.EUI64
+	copy(mac[:], packet[4:12])
+	switch packet[3] {
+	case 0x00: //PUSH_DATA
+		ns.sendUDP([]byte{0x02, packet[1], packet[2], 0x01}, addr)
+
+		var push pfproto
+		if err := json.Unmarshal(packet[12:], &push); err != nil {
+			log.Warningf("network server: bad PUSH_DATA from gateway %s: %s", mac, err)
+			return
+		}
+		for _, rxpk := range push.RXPK {
+			up, err := rxpkUplink(mac, rxpk)
+			if err != nil {
+				log.Warningf("network server: uplink from gateway %s ignored: %s", mac, err)
+				continue
+			}
+			if tx := ns.answer(up); tx != nil {
+				ns.sendTXPK(up, tx)
+			}
+		}
+	case 0x02: //PULL_DATA
+		ns.mu.Lock()
+		ns.pullAddrs[mac] = addr
+		ns.mu.Unlock()
+		ns.sendUDP([]byte{0x02, packet[1], packet[2], 0x04}, addr)
+	}
+}
+
+// rxpkUplink returns the uplink of a PUSH_DATA rxpk.
+func rxpkUplink(mac lorawan.EUI64, rxpk pfpacket) (nsUplink, error) {
+	if rxpk.Stat != 1 {
+		return nsUplink{}, fmt.Errorf("crc status %d", rxpk.Stat)
+	}
+	if rxpk.Modu != string(band.LoRaModulation) {
+		return nsUplink{}, fmt.Errorf("%s modulation", rxpk.Modu)
+	}
+
+	up := nsUplink{
+		gatewayMAC: mac,
+		//The frequency is a float32 in MHz, channels are on a 100 Hz grid.
+		frequency: int(math.Round(float64(rxpk.Freq)*10000)) * 100,
+		dataRate:  band.DataRate{Modulation: band.LoRaModulation},
+		snr:       rxpk.LSNR,
+		tmst:      rxpk.TMST,
+	}
+	if _, err := fmt.Sscanf(rxpk.DatR, "SF%dBW%d", &up.dataRate.SpreadFactor, &up.dataRate.Bandwidth); err != nil {
+		return up, fmt.Errorf("invalid datr %s", rxpk.DatR)
+	}
+	var err error
+	if up.time, err = time.Parse(pfTimeFormat, rxpk.Time); err != nil {
+		up.time = time.Now()
+	}
+	if up.phyPayload, err = base64.StdEncoding.DecodeString(rxpk.Data); err != nil {
+		return up, errors.Wrap(err, "invalid data")
+	}
+	return up, nil
+}
+
+// sendTXPK sends the answer to an uplink in a PULL_RESP, scheduled at the concentrator counter of the RX window.
+func (ns *NetworkServer) sendTXPK(up nsUplink, tx *nsTX) {
+	ns.mu.Lock()
+	addr := ns.pullAddrs[up.gatewayMAC]
+	ns.mu.Unlock()
+	if addr == nil {
+		log.Warningf("network server: gateway %s didn't send PULL_DATA yet, the downlink is dropped", up.gatewayMAC)
+		return
+	}
+
+	tmst := up.tmst + uint32(tx.delay/time.Microsecond)
+	datagram, err := pullResp(&TXPK{
+		TMST: &tmst,
+		Freq: float64(tx.frequency) / 1000000,
+		Powe: int32(tx.power),
+		Modu: string(band.LoRaModulation),
+		DatR: TXPKDataRate{LoRa: fmt.Sprintf("SF%dBW%d", tx.dataRate.SpreadFactor, tx.dataRate.Bandwidth)},
+		CodR: "4/5",
+		IPol: true,
+		Size: uint16(len(tx.phyPayload)),
+		Data: base64.StdEncoding.EncodeToString(tx.phyPayload),
+	})
+	if err != nil {
+		log.Errorf("network server: PULL_RESP error: %s", err)
+		return
+	}
+	ns.sendUDP(datagram, addr)
+}
+
+func (ns *NetworkServer) sendUDP(datagram []byte, addr *net.UDPAddr) {
+	if _, err := ns.conn.WriteToUDP(datagram, addr); err != nil {
+		log.Errorf("network server write error: %s", err)
+	}
+}
+
+// connectMQTT connects to the configured MQTT server as a network server, subscribing to the uplinks of every gateway.
+// Only the ChirpStack json and protobuf marshalers carry the uplinks and downlinks both ways.
+func (ns *NetworkServer) connectMQTT() error {
+	marshaler := ns.config.Marshaler()
+	if marshaler != "json" && marshaler != "protobuf" {
+		return fmt.Errorf("the network server doesn't support the %q marshaler, use json or protobuf", marshaler)
+	}
+	ns.marshaling = ns.config.MarshalingDevice()
+
+	tlsConfig, err := NewTLSConfig(ns.config.MQTT.CACert, ns.config.MQTT.TLSCert, ns.config.MQTT.TLSKey, ns.config.MQTT.InsecureSkipVerify, ns.config.MQTT.ALPN)
+	if err != nil {
+		return errors.Wrap(err, "tls config error")
+	}
+
+	opts := MQTT.NewClientOptions()
+	opts.AddBroker(ns.config.MQTT.Server)
+	opts.SetUsername(ns.config.MQTT.User)
+	opts.SetPassword(ns.config.MQTT.Password)
+	opts.SetAutoReconnect(true)
+	opts.SetClientID(fmt.Sprintf("lds-ns-%d", time.Now().UnixNano()))
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	ns.mqttClient = MQTT.NewClient(opts)
+	if token := ns.mqttClient.Connect(); token.Wait() && token.Error() != nil {
+		return errors.Wrap(token.Error(), "network server connection error")
+	}
+
+	topic := FormatTopic(ns.config.Topics().Uplink, "+")
+	if token := ns.mqttClient.Subscribe(topic, byte(ns.config.MQTT.QoS), func(c MQTT.Client, msg MQTT.Message) {
+		ns.handleUplinkFrame(msg.Topic(), msg.Payload())
+	}); token.Wait() && token.Error() != nil {
+		ns.mqttClient.Disconnect(200)
+		return errors.Wrap(token.Error(), "network server subscription error")
+	}
+
+	log.Infof("network server subscribed to %s", topic)
+	return nil
+}
+
+// handleUplinkFrame answers an uplink frame published by a gateway.
+func (ns *NetworkServer) handleUplinkFrame(topic string, payload []byte) {
+	var uf gw.UplinkFrame
+	if err := ns.marshaling.unmarshal(payload, &uf); err != nil {
+		log.Warningf("network server: bad uplink frame: %s", err)
+		return
+	}
+
+	lora := uf.GetTxInfo().GetLoraModulationInfo()
+	if lora == nil {
+		log.Warningln("network server: uplink frame without LoRa modulation info ignored")
+		return
+	}
+
+	up := nsUplink{
+		phyPayload: uf.GetPhyPayload(),
+		frequency:  int(uf.GetTxInfo().GetFrequency()),
+		dataRate: band.DataRate{
+			Modulation:   band.LoRaModulation,
+			SpreadFactor: int(lora.GetSpreadingFactor()),
+			Bandwidth:    int(lora.GetBandwidth()),
+		},
+		snr:     uf.GetRxInfo().GetLoraSnr(),
+		context: uf.GetRxInfo().GetContext(),
+	}
+	copy(up.gatewayMAC[:], uf.GetRxInfo().GetGatewayId())
+	up.gateway = topicGateway(ns.config.Topics().Uplink, topic)
+	if up.gateway == "" {
+		up.gateway = up.gatewayMAC.String()
+	}
+	var err error
+	if up.time, err = ptypes.Timestamp(uf.GetRxInfo().GetTime()); err != nil {
+		up.time = time.Now()
+	}
+
+	if tx := ns.answer(up); tx != nil {
+		ns.publishDownlink(up, tx)
+	}
+}
+
+// publishDownlink publishes the answer to an uplink, scheduled with a delay after it.
+func (ns *NetworkServer) publishDownlink(up nsUplink, tx *nsTX) {
+	downlinkID := make([]byte, 16)
+	rand.Read(downlinkID)
+
+	df := &gwv3.DownlinkFrame{
+		Token:      uint32(binary.BigEndian.Uint16(downlinkID)),
+		DownlinkId: downlinkID,
+		GatewayId:  up.gatewayMAC[:],
+		Items: []*gwv3.DownlinkFrameItem{{
+			PhyPayload: tx.phyPayload,
+			TxInfo: &gw.DownlinkTXInfo{
+				GatewayId:  up.gatewayMAC[:],
+				Frequency:  uint32(tx.frequency),
+				Power:      int32(tx.power),
+				Modulation: common.Modulation_LORA,
+				ModulationInfo: &gw.DownlinkTXInfo_LoraModulationInfo{
+					LoraModulationInfo: &gw.LoRaModulationInfo{
+						Bandwidth:             uint32(tx.dataRate.Bandwidth),
+						SpreadingFactor:       uint32(tx.dataRate.SpreadFactor),
+						CodeRate:              "4/5",
+						PolarizationInversion: true,
+					},
+				},
+				Timing: gw.DownlinkTiming_DELAY,
+				TimingInfo: &gw.DownlinkTXInfo_DelayTimingInfo{
+					DelayTimingInfo: &gw.DelayTimingInfo{Delay: ptypes.DurationProto(tx.delay)},
+				},
+				Context: up.context,
+			},
+		}},
+	}
+
+	b, err := ns.marshaling.marshal(df)
+	if err != nil {
+		log.Errorf("network server: error marshaling the downlink frame: %s", err)
+		return
+	}
+	if err := publish(ns.mqttClient, FormatTopic(ns.config.Topics().Downlink, up.gateway), b); err != nil {
+		log.Errorf("network server: couldn't publish the downlink: %s", err)
+	}
+}
+
+// topicGateway returns the gateway MAC of a topic, or an empty string when the template doesn't have one.
+func topicGateway(topicTemplate, topic string) string {
+	i := strings.Index(topicTemplate, "%s")
+	if i < 0 {
+		return ""
+	}
+	prefix, suffix := topicTemplate[:i], topicTemplate[i+2:]
+	if !strings.HasPrefix(topic, prefix) || !strings.HasSuffix(topic, suffix) || len(topic) < len(prefix)+len(suffix) {
+		return ""
+	}
+	return topic[len(prefix) : len(topic)-len(suffix)]
+}
+
+// answer handles an uplink and returns the downlink to send in its RX1 window, if any.
+func (ns *NetworkServer) answer(up nsUplink) *nsTX {
+	var phy lorawan.PHYPayload
+	if err := phy.UnmarshalBinary(up.phyPayload); err != nil {
+		log.Warningf("network server: bad uplink: %s", err)
+		return nil
+	}
+
+	var tx *nsTX
+	var err error
+	switch phy.MHDR.MType {
+	case lorawan.JoinRequest:
+		tx, err = ns.handleJoinRequest(phy, up)
+	case lorawan.UnconfirmedDataUp, lorawan.ConfirmedDataUp:
+		tx, err = ns.handleDataUp(phy, up)
+	default:
+		err = fmt.Errorf("unexpected %s", phy.MHDR.MType)
+	}
+	if err != nil {
+		log.Warningf("network server: uplink rejected: %s", err)
+		return nil
+	}
+	return tx
+}
+
+// handleJoinRequest accepts the join request of the configured device, starting a new session.
+func (ns *NetworkServer) handleJoinRequest(phy lorawan.PHYPayload, up nsUplink) (*nsTX, error) {
+	jr, ok := phy.MACPayload.(*lorawan.JoinRequestPayload)
+	if !ok {
+		return nil, errors.New("mac payload is not a join request payload")
+	}
+
+	d, err := ns.config.NewDevice()
+	if err != nil {
+		return nil, errors.Wrap(err, "device error")
+	}
+	if jr.DevEUI != d.DevEUI || d.Profile != "OTAA" {
+		return nil, fmt.Errorf("join request from unknown device %s", jr.DevEUI)
+	}
+	if jr.JoinEUI != d.JoinEUI {
+		return nil, fmt.Errorf("join request of %s for JoinEUI %s instead of %s", jr.DevEUI, jr.JoinEUI, d.JoinEUI)
+	}
+
+	mic, err := keys.JoinRequestMIC(phy, d.NwkKey)
+	if err != nil {
+		return nil, err
+	}
+	if mic != phy.MIC {
+		return nil, fmt.Errorf("invalid join request mic from %s", jr.DevEUI)
+	}
+
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	if ns.devNonces[jr.DevEUI][jr.DevNonce] {
+		return nil, fmt.Errorf("DevNonce %d of %s was already used", jr.DevNonce, jr.DevEUI)
+	}
+
+	devAddr := ns.randomDevAddr()
+	if ns.devAddr != nil {
+		devAddr = *ns.devAddr
+	}
+	jap := &lorawan.JoinAcceptPayload{
+		JoinNonce: ns.joinNonce,
+		HomeNetID: ns.netID,
+		DevAddr:   devAddr,
+		DLSettings: lorawan.DLSettings{
+			OptNeg:      d.MACVersion == lorawan.LoRaWAN1_1,
+			RX2DataRate: uint8(ns.band.GetDefaults().RX2DataRate),
+			RX1DROffset: uint8(ns.config.NetworkServer.RX1DROffset),
+		},
+		RXDelay: uint8(ns.config.NetworkServer.RX1Delay),
+	}
+	if len(ns.config.NetworkServer.CFList) > 0 {
+		var channels lorawan.CFListChannelPayload
+		for i, f := range ns.config.NetworkServer.CFList {
+			channels.Channels[i] = uint32(f)
+		}
+		jap.CFList = &lorawan.CFList{CFListType: lorawan.CFListChannel, Payload: &channels}
+	}
+
+	accept := lorawan.PHYPayload{
+		MHDR:       lorawan.MHDR{MType: lorawan.JoinAccept, Major: lorawan.LoRaWANR1},
+		MACPayload: jap,
+	}
+	if accept.MIC, err = keys.JoinAcceptMIC(accept, d.NwkKey, d.DevEUI, d.JoinEUI, jr.DevNonce); err != nil {
+		return nil, err
+	}
+	sessionKeys, err := keys.GetSessionKeys(d.NwkKey, d.AppKey, joinParams(jap, d.MACVersion, jr.JoinEUI, jr.DevNonce))
+	if err != nil {
+		return nil, errors.Wrap(err, "session keys derivation error")
+	}
+	if err := accept.EncryptJoinAcceptPayload(d.NwkKey); err != nil {
+		return nil, err
+	}
+
+	//The RX1DROffset of the join accept only applies to the following downlinks.
+	tx, err := ns.rx1(up, ns.band.GetDefaults().JoinAcceptDelay1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if tx.phyPayload, err = accept.MarshalBinary(); err != nil {
+		return nil, err
+	}
+
+	if ns.devNonces[jr.DevEUI] == nil {
+		ns.devNonces[jr.DevEUI] = make(map[lorawan.DevNonce]bool)
+	}
+	ns.devNonces[jr.DevEUI][jr.DevNonce] = true
+	ns.joinNonce++
+	ns.session = &nsSession{
+		devEUI:        d.DevEUI,
+		devAddr:       devAddr,
+		macVersion:    d.MACVersion,
+		keys:          sessionKeys,
+		skipFCntCheck: d.SkipFCntCheck,
+	}
+
+	log.Infof("network server: %s joined with DevAddr %s (JoinNonce %d)", jr.DevEUI, devAddr, jap.JoinNonce)
+	return tx, nil
+}
+
+// handleDataUp checks a data uplink and returns the next queued downlink, or an empty one when the uplink needs an answer.
+func (ns *NetworkServer) handleDataUp(phy lorawan.PHYPayload, up nsUplink) (*nsTX, error) {
+	mp, ok := phy.MACPayload.(*lorawan.MACPayload)
+	if !ok {
+		return nil, errors.New("can't convert mac payload")
+	}
+
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	s, err := ns.sessionFor(mp.FHDR.DevAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	fCnt := fullFCnt(s.fCntUp, mp.FHDR.FCnt)
+	if !s.skipFCntCheck {
+		if fCnt < s.fCntUp {
+			return nil, fmt.Errorf("frame counter %d of %s was already used, %d or more was expected", fCnt, s.devAddr, s.fCntUp)
+		}
+		if gap := ns.band.GetDefaults().MaxFCntGap; fCnt-s.fCntUp > gap {
+			return nil, fmt.Errorf("frame counter %d of %s is more than %d ahead of %d", fCnt, s.devAddr, gap, s.fCntUp)
+		}
+	}
+	mp.FHDR.FCnt = fCnt
+
+	//The MIC is computed over the encrypted frame, so it's checked first.
+	var valid bool
+	if s.macVersion == lorawan.LoRaWAN1_0 {
+		valid, err = phy.ValidateUplinkDataMIC(s.macVersion, 0, 0, 0, s.keys.FNwkSIntKey, s.keys.FNwkSIntKey)
+	} else {
+		txDR, drErr := ns.band.GetDataRateIndex(true, up.dataRate)
+		if drErr != nil {
+			return nil, drErr
+		}
+		txCh, chErr := ns.band.GetUplinkChannelIndexForFrequencyDR(up.frequency, txDR)
+		if chErr != nil {
+			return nil, chErr
+		}
+		valid, err = phy.ValidateUplinkDataMIC(s.macVersion, s.confFCnt, uint8(txDR), uint8(txCh), s.keys.FNwkSIntKey, s.keys.SNwkSIntKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("invalid mic on uplink %d of %s", fCnt, s.devAddr)
+	}
+	s.fCntUp = fCnt + 1
+
+	if err := decryptFOpts(&phy, s.macVersion, s.keys.NwkSEncKey); err != nil {
+		return nil, errors.Wrap(err, "can't decode the FOpts")
+	}
+	if err := decryptFRMPayload(&phy, s.keys.NwkSEncKey, s.keys.AppSKey); err != nil {
+		return nil, errors.Wrap(err, "can't decrypt the frm payload")
+	}
+
+	confirmed := phy.MHDR.MType == lorawan.ConfirmedDataUp
+	log.Infof("network server: %s uplink %d from %s (ack: %t)", phy.MHDR.MType, fCnt, s.devAddr, mp.FHDR.FCtrl.ACK)
+	var answers []lorawan.Payload
+	macCommands := mp.FHDR.FOpts
+	for _, p := range mp.FRMPayload {
+		if mp.FPort != nil && *mp.FPort == 0 {
+			macCommands = append(macCommands, p)
+		} else if dp, ok := p.(*lorawan.DataPayload); ok {
+			log.Infof("network server: fPort %d payload %x", *mp.FPort, dp.Bytes)
+		}
+	}
+	for _, p := range macCommands {
+		mac, ok := p.(*lorawan.MACCommand)
+		if !ok {
+			continue
+		}
+		log.Infof("network server: mac command %s", payloadString(mac))
+		if answer := ns.answerMACCommand(mac, up); answer != nil {
+			answers = append(answers, answer)
+		}
+	}
+
+	dl := ns.dequeue()
+	if dl == nil && !confirmed && !mp.FHDR.FCtrl.ADRACKReq && len(answers) == 0 {
+		return nil, nil
+	}
+
+	tx, err := ns.rx1(up, time.Duration(ns.config.NetworkServer.RX1Delay)*time.Second, ns.config.NetworkServer.RX1DROffset)
+	if err != nil {
+		return nil, err
+	}
+	if tx.phyPayload, err = s.dataDown(dl, confirmed, fCnt, answers, len(ns.queue) > 0); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// sessionFor returns the session of a DevAddr, which for ABP devices is the one of the configuration.
+func (ns *NetworkServer) sessionFor(devAddr lorawan.DevAddr) (*nsSession, error) {
+	if ns.session != nil && ns.session.devAddr == devAddr {
+		return ns.session, nil
+	}
+
+	d, err := ns.config.NewDevice()
+	if err != nil {
+		return nil, errors.Wrap(err, "device error")
+	}
+	if d.Profile != "ABP" || d.DevAddr != devAddr {
+		return nil, fmt.Errorf("unknown DevAddr %s", devAddr)
+	}
+
+	s := &nsSession{
+		devEUI:     d.DevEUI,
+		devAddr:    devAddr,
+		macVersion: d.MACVersion,
+		keys: keys.SessionKeys{
+			FNwkSIntKey: d.FNwkSIntKey,
+			SNwkSIntKey: d.SNwkSIntKey,
+			NwkSEncKey:  d.NwkSEncKey,
+			AppSKey:     d.AppSKey,
+		},
+		skipFCntCheck: d.SkipFCntCheck,
+	}
+	//ABP devices set the NwkSKey as the NwkSEncKey.
+	if d.MACVersion == lorawan.LoRaWAN1_0 {
+		s.keys.FNwkSIntKey = d.NwkSEncKey
+		s.keys.SNwkSIntKey = d.NwkSEncKey
+	}
+	ns.session = s
+	return s, nil
+}
+
+// answerMACCommand returns the answer to the MAC commands the network server handles.
+func (ns *NetworkServer) answerMACCommand(mac *lorawan.MACCommand, up nsUplink) *lorawan.MACCommand {
+	switch mac.CID {
+	case lorawan.LinkCheckReq:
+		//The margin is over the demodulation floor of the spreading factor: -7.5 dB for SF7, 2.5 dB lower for every step.
+		margin := up.snr + 7.5 + 2.5*float64(up.dataRate.SpreadFactor-7)
+		if margin < 0 {
+			margin = 0
+		}
+		return &lorawan.MACCommand{CID: lorawan.LinkCheckAns, Payload: &lorawan.LinkCheckAnsPayload{Margin: uint8(margin), GwCnt: 1}}
+	case lorawan.DeviceTimeReq:
+		return &lorawan.MACCommand{CID: lorawan.DeviceTimeAns, Payload: &lorawan.DeviceTimeAnsPayload{TimeSinceGPSEpoch: TimeSinceGPSEpoch(up.time)}}
+	}
+	return nil
+}
+
+func (ns *NetworkServer) dequeue() *NSDownlink {
+	if len(ns.queue) == 0 {
+		return nil
+	}
+	dl := ns.queue[0]
+	ns.queue = ns.queue[1:]
+	return dl
+}
+
+// rx1 returns the RX1 parameters of an uplink, the data rate being the uplink one lowered by drOffset.
+func (ns *NetworkServer) rx1(up nsUplink, delay time.Duration, drOffset int) (*nsTX, error) {
+	frequency, err := ns.band.GetRX1FrequencyForUplinkFrequency(up.frequency)
+	if err != nil {
+		return nil, err
+	}
+	ulDR, err := ns.band.GetDataRateIndex(true, up.dataRate)
+	if err != nil {
+		return nil, err
+	}
+	dr, err := ns.band.GetRX1DataRateIndex(ulDR, drOffset)
+	if err != nil {
+		return nil, err
+	}
+	dataRate, err := ns.band.GetDataRate(dr)
+	if err != nil {
+		return nil, err
+	}
+	return &nsTX{
+		delay:     delay,
+		frequency: frequency,
+		dataRate:  dataRate,
+		power:     ns.band.GetDownlinkTXPower(frequency),
+	}, nil
+}
+
+// randomDevAddr returns a random DevAddr with the NwkID of the NetID.
+func (ns *NetworkServer) randomDevAddr() lorawan.DevAddr {
+	var devAddr lorawan.DevAddr
+	rand.Read(devAddr[:])
+	devAddr.SetAddrPrefix(ns.netID)
+	return devAddr
+}
+
+// dataDown returns the downlink answering an uplink, with the queued downlink if any, and counts it.
+func (s *nsSession) dataDown(dl *NSDownlink, ack bool, confFCnt uint32, answers []lorawan.Payload, fPending bool) ([]byte, error) {
+	mType := lorawan.UnconfirmedDataDown
+	if dl != nil && dl.Confirmed {
+		mType = lorawan.ConfirmedDataDown
+	}
+
+	fOpts := answers
+	mp := &lorawan.MACPayload{
+		FHDR: lorawan.FHDR{
+			DevAddr: s.devAddr,
+			FCtrl:   lorawan.FCtrl{ACK: ack, FPending: fPending},
+		},
+	}
+	if dl != nil {
+		for _, mac := range dl.MACCommands {
+			fOpts = append(fOpts, mac)
+		}
+		if len(dl.Payload) > 0 {
+			fPort := dl.FPort
+			mp.FPort = &fPort
+			mp.FRMPayload = []lorawan.Payload{&lorawan.DataPayload{Bytes: dl.Payload}}
+		}
+	}
+
+	size := 0
+	for _, mac := range fOpts {
+		b, err := mac.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		size += len(b)
+	}
+	if size > maxFOptsLen {
+		if mp.FPort != nil {
+			return nil, fmt.Errorf("%d bytes of mac commands don't fit in the FOpts of a downlink with a payload", size)
+		}
+		fPort := uint8(0)
+		mp.FPort = &fPort
+		mp.FRMPayload = fOpts
+	} else {
+		mp.FHDR.FOpts = fOpts
+	}
+
+	fCnt := &s.nFCntDown
+	if s.macVersion == lorawan.LoRaWAN1_1 && mp.FPort != nil && *mp.FPort > 0 {
+		fCnt = &s.aFCntDown
+	}
+	mp.FHDR.FCnt = *fCnt
+
+	phy := lorawan.PHYPayload{
+		MHDR:       lorawan.MHDR{MType: mType, Major: lorawan.LoRaWANR1},
+		MACPayload: mp,
+	}
+	if mp.FPort != nil {
+		key := s.keys.AppSKey
+		if *mp.FPort == 0 {
+			key = s.keys.NwkSEncKey
+		}
+		if err := phy.EncryptFRMPayload(key); err != nil {
+			return nil, err
+		}
+	}
+	if s.macVersion == lorawan.LoRaWAN1_1 {
+		if err := phy.EncryptFOpts(s.keys.NwkSEncKey); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if phy.MIC, err = keys.DownlinkDataMIC(phy, s.macVersion, confFCnt, s.keys.SNwkSIntKey); err != nil {
+		return nil, err
+	}
+
+	b, err := phy.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if mType == lorawan.ConfirmedDataDown {
+		s.confFCnt = *fCnt
+	}
+	*fCnt++
+	log.Infof("network server: %s %d to %s", mType, mp.FHDR.FCnt, s.devAddr)
+	return b, nil
+}
+
+// fullFCnt returns the 32 bits frame counter of a 16 bits one, given the next expected counter.
+func fullFCnt(next, fCnt uint32) uint32 {
+	full := next&^0xffff | fCnt&0xffff
+	if full < next && next-full > 1<<15 {
+		full += 1 << 16
+	}
+	return full
+}
+
+// ParseDownlinkMACCommands parses downlink MAC commands given hex encoded, or by name when they have no payload.
+func ParseDownlinkMACCommands(commands []string) ([]*lorawan.MACCommand, error) {
+	var parsed []*lorawan.MACCommand
+	for _, c := range commands {
+		if cid, ok := cidByName(c); ok {
+			if _, size, err := lorawan.GetMACPayloadAndSize(false, cid); err == nil && size > 0 {
+				return nil, fmt.Errorf("mac command %s has a payload, give it hex encoded", c)
+			}
+			parsed = append(parsed, &lorawan.MACCommand{CID: cid})
+			continue
+		}
+
+		b, err := hex.DecodeString(c)
+		if err != nil {
+			return nil, fmt.Errorf("unknown mac command %q", c)
+		}
+		var mac lorawan.MACCommand
+		if err := mac.UnmarshalBinary(false, b); err != nil {
+			return nil, errors.Wrapf(err, "mac command %s", c)
+		}
+		parsed = append(parsed, &mac)
+	}
+	return parsed, nil
+}
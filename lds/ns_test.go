@@ -0,0 +1,302 @@
+package lds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/band"
+)
+
+// newTestNetworkServer starts a network server on a random port for the test device, OTAA with the test root keys or
+// LoRaWAN 1.0 ABP with the test session keys.
+func newTestNetworkServer(t *testing.T, profile string, drOffset int) *NetworkServer {
+	config := NewConfig()
+	config.Band.Name = band.EU868
+	config.NetworkServer.Bind = "127.0.0.1:0"
+	config.NetworkServer.NetID = "000013"
+	config.NetworkServer.RX1DROffset = drOffset
+	config.Device.Profile = profile
+	config.Device.MACVersion = lorawan.LoRaWAN1_0
+	config.Device.DevEUI = testDevEUI.String()
+	config.Device.JoinEUI = testJoinEUI.String()
+	config.Device.NwkKey = testNwkKey.String()
+	config.Device.AppKey = testAppKey.String()
+	config.Device.DevAddress = "01020304"
+	config.Device.NwkSEncKey = testNwkSKey.String()
+	config.Device.AppSKey = testAppSKey.String()
+
+	ns, err := NewNetworkServer(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ns
+}
+
+// nsUp returns an uplink received on 868.1 MHz.
+func nsUp(phyPayload []byte, sf int) nsUplink {
+	return nsUplink{
+		phyPayload: phyPayload,
+		frequency:  868100000,
+		dataRate:   band.DataRate{Modulation: band.LoRaModulation, SpreadFactor: sf, Bandwidth: 125},
+		snr:        5,
+		time:       time.Now(),
+	}
+}
+
+// joinRequestFrame returns a join request signed with nwkKey.
+func joinRequestFrame(t *testing.T, devEUI, joinEUI lorawan.EUI64, devNonce lorawan.DevNonce, nwkKey lorawan.AES128Key) []byte {
+	phy := lorawan.PHYPayload{
+		MHDR:       lorawan.MHDR{MType: lorawan.JoinRequest, Major: lorawan.LoRaWANR1},
+		MACPayload: &lorawan.JoinRequestPayload{JoinEUI: joinEUI, DevEUI: devEUI, DevNonce: devNonce},
+	}
+	if err := phy.SetUplinkJoinMIC(nwkKey); err != nil {
+		t.Fatal(err)
+	}
+	b, err := phy.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestNSJoin(t *testing.T) {
+	ns := newTestNetworkServer(t, "OTAA", 2)
+	defer ns.Close()
+
+	//The steps run in order on the same network server.
+	tests := []struct {
+		name      string
+		frame     []byte
+		accepted  bool
+		joinNonce lorawan.JoinNonce
+	}{
+		{"join", joinRequestFrame(t, testDevEUI, testJoinEUI, 1, testNwkKey), true, 1},
+		{"replayed DevNonce", joinRequestFrame(t, testDevEUI, testJoinEUI, 1, testNwkKey), false, 0},
+		{"invalid MIC", joinRequestFrame(t, testDevEUI, testJoinEUI, 2, testAppKey), false, 0},
+		{"unknown device", joinRequestFrame(t, testJoinEUI, testJoinEUI, 2, testNwkKey), false, 0},
+		{"other JoinEUI", joinRequestFrame(t, testDevEUI, testDevEUI, 2, testNwkKey), false, 0},
+		{"new DevNonce", joinRequestFrame(t, testDevEUI, testJoinEUI, 2, testNwkKey), true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := ns.answer(nsUp(tt.frame, 7))
+			if (tx != nil) != tt.accepted {
+				t.Fatalf("got answer %+v, expected one: %t", tx, tt.accepted)
+			}
+			if tx == nil {
+				return
+			}
+
+			//The join accept is sent at the uplink data rate, the RX1DROffset it carries applies afterwards.
+			if tx.delay != 5*time.Second || tx.frequency != 868100000 || tx.dataRate.SpreadFactor != 7 {
+				t.Errorf("got answer after %s on %d Hz at SF%d, expected after 5s on 868100000 Hz at SF7", tx.delay, tx.frequency, tx.dataRate.SpreadFactor)
+			}
+
+			var jr lorawan.PHYPayload
+			if err := jr.UnmarshalBinary(tt.frame); err != nil {
+				t.Fatal(err)
+			}
+			devNonce := jr.MACPayload.(*lorawan.JoinRequestPayload).DevNonce
+			f, err := DecodeFrame(tx.phyPayload, &DecodeKeys{NwkKey: &testNwkKey, DevEUI: testDevEUI, JoinEUI: testJoinEUI, DevNonce: &devNonce})
+			if err != nil {
+				t.Fatal(err)
+			}
+			jap := f.PHYPayload.MACPayload.(*lorawan.JoinAcceptPayload)
+			if f.MIC != MICValid || jap.JoinNonce != tt.joinNonce || jap.DLSettings.RX1DROffset != 2 || jap.RXDelay != 1 {
+				t.Errorf("got join accept %+v with MIC %s", jap, f.MIC)
+			}
+			if !jap.DevAddr.IsNetID(ns.netID) {
+				t.Errorf("got DevAddr %s out of NetID %s", jap.DevAddr, ns.netID)
+			}
+			if ns.session == nil || ns.session.devAddr != jap.DevAddr || ns.session.keys != *f.SessionKeys {
+				t.Errorf("got session %+v, expected the keys %+v", ns.session, f.SessionKeys)
+			}
+		})
+	}
+}
+
+func TestNSDataUp(t *testing.T) {
+	ns := newTestNetworkServer(t, "ABP", 2)
+	defer ns.Close()
+
+	fPort, macPort := uint8(2), uint8(0)
+	data := []lorawan.Payload{&lorawan.DataPayload{Bytes: []byte{1}}}
+	invalidMIC := dataFrame(t, lorawan.UnconfirmedDataUp, 2, &fPort, data, nil)
+	invalidMIC[len(invalidMIC)-1]++
+
+	//The steps run in order on the same session.
+	tests := []struct {
+		name     string
+		frame    []byte
+		answered bool
+		ack      bool
+		fOpts    []lorawan.CID
+		fCntUp   uint32
+	}{
+		{"unconfirmed", dataFrame(t, lorawan.UnconfirmedDataUp, 0, &fPort, data, nil), false, false, nil, 1},
+		{"confirmed", dataFrame(t, lorawan.ConfirmedDataUp, 1, &fPort, data, nil), true, true, nil, 2},
+		{"replayed frame counter", dataFrame(t, lorawan.ConfirmedDataUp, 1, &fPort, data, nil), false, false, nil, 2},
+		{"invalid MIC", invalidMIC, false, false, nil, 2},
+		{"frame counter gap", dataFrame(t, lorawan.UnconfirmedDataUp, 2+16385, &fPort, data, nil), false, false, nil, 2},
+		{"skipped frame counters", dataFrame(t, lorawan.UnconfirmedDataUp, 10, &fPort, data, nil), false, false, nil, 11},
+		{"MAC commands in the FOpts", dataFrame(t, lorawan.UnconfirmedDataUp, 11, &fPort, data, []lorawan.Payload{&lorawan.MACCommand{CID: lorawan.LinkCheckReq}}), true, false, []lorawan.CID{lorawan.LinkCheckAns}, 12},
+		{"MAC commands on FPort 0", dataFrame(t, lorawan.UnconfirmedDataUp, 12, &macPort, []lorawan.Payload{&lorawan.MACCommand{CID: lorawan.LinkCheckReq}, &lorawan.MACCommand{CID: lorawan.DeviceTimeReq}}, nil), true, false, []lorawan.CID{lorawan.LinkCheckAns, lorawan.DeviceTimeAns}, 13},
+		{"unknown DevAddr", func() []byte {
+			b := dataFrame(t, lorawan.UnconfirmedDataUp, 13, &fPort, data, nil)
+			b[1]++
+			return b
+		}(), false, false, nil, 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := ns.answer(nsUp(tt.frame, 7))
+			if (tx != nil) != tt.answered {
+				t.Fatalf("got answer %+v, expected one: %t", tx, tt.answered)
+			}
+			if ns.session.fCntUp != tt.fCntUp {
+				t.Errorf("got next frame counter %d, expected %d", ns.session.fCntUp, tt.fCntUp)
+			}
+			if tx == nil {
+				return
+			}
+
+			//SF7 is DR5 in EU868, lowered to DR3 by the offset.
+			if tx.delay != time.Second || tx.frequency != 868100000 || tx.dataRate.SpreadFactor != 9 {
+				t.Errorf("got answer after %s on %d Hz at SF%d, expected after 1s on 868100000 Hz at SF9", tx.delay, tx.frequency, tx.dataRate.SpreadFactor)
+			}
+			f, err := DecodeFrame(tx.phyPayload, &DecodeKeys{NwkSEncKey: &testNwkSKey, SNwkSIntKey: &testNwkSKey, FNwkSIntKey: &testNwkSKey, AppSKey: &testAppSKey})
+			if err != nil {
+				t.Fatal(err)
+			}
+			mp := f.PHYPayload.MACPayload.(*lorawan.MACPayload)
+			if f.MIC != MICValid || mp.FHDR.FCtrl.ACK != tt.ack || len(mp.FHDR.FOpts) != len(tt.fOpts) {
+				t.Fatalf("got downlink %+v with MIC %s", mp.FHDR, f.MIC)
+			}
+			for i, cid := range tt.fOpts {
+				if mac, ok := mp.FHDR.FOpts[i].(*lorawan.MACCommand); !ok || mac.CID != cid {
+					t.Errorf("got FOpts %v, expected %v", mp.FHDR.FOpts, tt.fOpts)
+				}
+			}
+		})
+	}
+}
+
+func TestNSDataDown(t *testing.T) {
+	linkCheckAns := func() lorawan.Payload {
+		return &lorawan.MACCommand{CID: lorawan.LinkCheckAns, Payload: &lorawan.LinkCheckAnsPayload{Margin: 10, GwCnt: 1}}
+	}
+	devStatusReqs := func(n int) []*lorawan.MACCommand {
+		var macs []*lorawan.MACCommand
+		for i := 0; i < n; i++ {
+			macs = append(macs, &lorawan.MACCommand{CID: lorawan.DevStatusReq})
+		}
+		return macs
+	}
+
+	tests := []struct {
+		name       string
+		macVersion lorawan.MACVersion
+		dl         *NSDownlink
+		answers    []lorawan.Payload
+		fPort      *uint8
+		fOpts      int
+		frmPayload int
+		nFCntDown  uint32
+		aFCntDown  uint32
+		err        bool
+	}{
+		{"MAC answers", lorawan.LoRaWAN1_0, nil, []lorawan.Payload{linkCheckAns()}, nil, 1, 0, 1, 0, false},
+		{"payload", lorawan.LoRaWAN1_0, &NSDownlink{FPort: 3, Payload: []byte{1}}, nil, uint8Ptr(3), 0, 1, 1, 0, false},
+		{"FOpts full", lorawan.LoRaWAN1_0, &NSDownlink{MACCommands: devStatusReqs(12)}, []lorawan.Payload{linkCheckAns()}, nil, 13, 0, 1, 0, false},
+		{"FOpts overflow on FPort 0", lorawan.LoRaWAN1_0, &NSDownlink{MACCommands: devStatusReqs(13)}, []lorawan.Payload{linkCheckAns()}, uint8Ptr(0), 0, 14, 1, 0, false},
+		{"FOpts overflow with a payload", lorawan.LoRaWAN1_0, &NSDownlink{FPort: 3, Payload: []byte{1}, MACCommands: devStatusReqs(16)}, nil, nil, 0, 0, 0, 0, true},
+		{"LoRaWAN 1.1 payload", lorawan.LoRaWAN1_1, &NSDownlink{FPort: 3, Payload: []byte{1}, MACCommands: devStatusReqs(1)}, nil, uint8Ptr(3), 1, 1, 0, 1, false},
+		{"LoRaWAN 1.1 MAC commands", lorawan.LoRaWAN1_1, &NSDownlink{MACCommands: devStatusReqs(16)}, nil, uint8Ptr(0), 0, 16, 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &nsSession{
+				devAddr:    lorawan.DevAddr{1, 2, 3, 4},
+				macVersion: tt.macVersion,
+			}
+			s.keys.FNwkSIntKey, s.keys.SNwkSIntKey, s.keys.NwkSEncKey, s.keys.AppSKey = testNwkSKey, testNwkSKey, testNwkSKey, testAppSKey
+
+			b, err := s.dataDown(tt.dl, false, 0, tt.answers, false)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if s.nFCntDown != tt.nFCntDown || s.aFCntDown != tt.aFCntDown {
+				t.Errorf("got NFCntDown %d and AFCntDown %d, expected %d and %d", s.nFCntDown, s.aFCntDown, tt.nFCntDown, tt.aFCntDown)
+			}
+			if err != nil {
+				return
+			}
+
+			f, err := DecodeFrame(b, &DecodeKeys{MACVersion: tt.macVersion, NwkSEncKey: &testNwkSKey, SNwkSIntKey: &testNwkSKey, AppSKey: &testAppSKey})
+			if err != nil {
+				t.Fatal(err)
+			}
+			mp := f.PHYPayload.MACPayload.(*lorawan.MACPayload)
+			if f.MIC != MICValid || f.Encrypted {
+				t.Errorf("got MIC %s, encrypted %t", f.MIC, f.Encrypted)
+			}
+			if (mp.FPort == nil) != (tt.fPort == nil) || mp.FPort != nil && *mp.FPort != *tt.fPort {
+				t.Errorf("got fPort %v, expected %v", mp.FPort, tt.fPort)
+			}
+			if len(mp.FHDR.FOpts) != tt.fOpts || len(mp.FRMPayload) != tt.frmPayload {
+				t.Errorf("got %d FOpts and %d FRMPayload items, expected %d and %d", len(mp.FHDR.FOpts), len(mp.FRMPayload), tt.fOpts, tt.frmPayload)
+			}
+		})
+	}
+}
+
+func TestFullFCnt(t *testing.T) {
+	tests := []struct {
+		name string
+		next uint32
+		fCnt uint32
+		full uint32
+	}{
+		{"first", 0, 0, 0},
+		{"next", 5, 5, 5},
+		{"ahead", 5, 9, 9},
+		{"replayed", 5, 4, 4},
+		{"wraparound", 0xffff, 0, 0x10000},
+		{"after the wraparound", 0x10000, 3, 0x10003},
+		{"replayed before the wraparound", 0x10001, 0xfff0, 0xfff0 + 0x10000},
+		{"late wraparound", 0x1fff0, 5, 0x20005},
+		{"replayed after a wraparound", 0x20005, 4, 0x20004},
+		{"far ahead", 0x20005, 0x8000, 0x28000},
+	}
+
+	for _, tt := range tests {
+		if full := fullFCnt(tt.next, tt.fCnt); full != tt.full {
+			t.Errorf("%s: got %#x, expected %#x", tt.name, full, tt.full)
+		}
+	}
+}
+
+func TestRandomDevAddr(t *testing.T) {
+	for _, netID := range []string{"000013", "20002a", "4001ff", "600123", "800abc", "e0abcd"} {
+		ns := &NetworkServer{}
+		if err := ns.netID.UnmarshalText([]byte(netID)); err != nil {
+			t.Fatal(err)
+		}
+
+		seen := map[lorawan.DevAddr]bool{}
+		for i := 0; i < 10; i++ {
+			devAddr := ns.randomDevAddr()
+			if !devAddr.IsNetID(ns.netID) || devAddr.NetIDType() != ns.netID.Type() {
+				t.Errorf("NetID %s: got DevAddr %s of type %d", netID, devAddr, devAddr.NetIDType())
+			}
+			seen[devAddr] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("NetID %s: got the same DevAddr every time", netID)
+		}
+	}
+}
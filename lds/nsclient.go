@@ -3,6 +3,7 @@ package lds
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -12,7 +13,7 @@ import (
 	"time"
 
 	"github.com/brocaar/chirpstack-api/go/gw"
-	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -42,6 +43,9 @@ type pfpacket struct {
 	Data string  `json:"data"`
 }
 
+// pfTimeFormat is the compact ISO 8601 format with microseconds precision used by the packet forwarder.
+const pfTimeFormat = "2006-01-02T15:04:05.000000Z"
+
 type pfproto struct {
 	RXPK []pfpacket `json:"rxpk"`
 }
@@ -73,8 +77,11 @@ func (client *NSClient) Connect(gwMAC string, onReceive udpPacketCallback) error
 		return err
 	}
 	client.connexion = conn
+	if client.connected {
+		transportReconnects.WithLabelValues("udp").Inc()
+	}
 
-	log.Infoln("UDP listening bindpoint=%s", conn.LocalAddr())
+	log.Infof("UDP listening bindpoint=%s", conn.LocalAddr())
 	go client.receiveUDP(onReceive)
 	go client.sendPullData(gwMAC)
 
@@ -100,6 +107,7 @@ func (client *NSClient) receiveUDP(onReceive udpPacketCallback) {
 		}
 
 		message := buffer[0:size]
+		captureDatagram(client.connexion.RemoteAddr().(*net.UDPAddr), client.connexion.LocalAddr().(*net.UDPAddr), message)
 		onReceive(message)
 	}
 }
@@ -139,25 +147,48 @@ func (client *NSClient) sendPullData(gwMAC string) {
 
 func (client *NSClient) send(bytes []byte) error {
 	_, err := client.connexion.Write(bytes)
+	if err == nil {
+		captureDatagram(client.connexion.LocalAddr().(*net.UDPAddr), client.connexion.RemoteAddr().(*net.UDPAddr), bytes)
+	}
 	return err
 }
 
-func toMilliseconds(d *duration.Duration) uint64 {
-	return uint64(d.Seconds)*1000 + uint64(d.Nanos)/1000
+func (client *NSClient) sendWithPayload(payload []byte, gwMAC string, rxInfo *gw.UplinkRXInfo, txInfo *gw.UplinkTXInfo) error {
+
+	datagram, err := pushData(payload, gwMAC, rxInfo, txInfo)
+
+	if err != nil {
+		return err
+	}
+
+	client.send(datagram)
+
+	return nil
 }
 
-func (client *NSClient) sendWithPayload(payload []byte, gwMAC string, rxInfo *gw.UplinkRXInfo, txInfo *gw.UplinkTXInfo) error {
+// pushData returns the PUSH_DATA datagram of an uplink.
+func pushData(payload []byte, gwMAC string, rxInfo *gw.UplinkRXInfo, txInfo *gw.UplinkTXInfo) ([]byte, error) {
 
 	phyBase := base64.StdEncoding.EncodeToString(payload)
 
-	gps := rxInfo.GetTimeSinceGpsEpoch()
-	utc := time.Now().Format(time.RFC3339)
+	rxTime, err := ptypes.Timestamp(rxInfo.GetTime())
+	if err != nil {
+		rxTime = time.Now()
+	}
+	gps, err := ptypes.Duration(rxInfo.GetTimeSinceGpsEpoch())
+	if err != nil {
+		gps = TimeSinceGPSEpoch(rxTime)
+	}
 	mod := txInfo.GetLoraModulationInfo()
 
 	packet := pfpacket{}
-	packet.Time = utc
-	packet.TMMS = toMilliseconds(gps) / 1000
-	packet.TMST = uint32(toMilliseconds(gps) / 1000 / 1000)
+	packet.Time = rxTime.UTC().Format(pfTimeFormat)
+	packet.TMMS = uint64(gps / time.Millisecond)
+	if ctx := rxInfo.GetContext(); len(ctx) == 4 {
+		packet.TMST = binary.BigEndian.Uint32(ctx)
+	} else {
+		packet.TMST = GetGateway(gwMAC).Counter(rxTime)
+	}
 	packet.Chan = rxInfo.GetChannel()
 	packet.RFCH = rxInfo.GetRfChain()
 	packet.Freq = float32(txInfo.GetFrequency()) / 1000000.0
@@ -177,36 +208,48 @@ func (client *NSClient) sendWithPayload(payload []byte, gwMAC string, rxInfo *gw
 	log.Debugf("Marshalled upstream JSON %s", packetJSON)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	gwheader, err := createGWHeader(0x00, gwMAC)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	jsonbytes := []byte(packetJSON)
 	datagram := bytes.Join([][]byte{gwheader, jsonbytes}, []byte{})
 
-	client.send(datagram)
+	return datagram, nil
+}
 
-	return nil
+// pullResp returns the PULL_RESP datagram of a downlink, as sent by a network server.
+func pullResp(txpk *TXPK) ([]byte, error) {
+	packetJSON, err := json.Marshal(struct {
+		TXPK *TXPK `json:"txpk"`
+	}{txpk})
+	if err != nil {
+		return nil, err
+	}
+
+	token := rand.Int()
+	header := []byte{0x02, byte(token >> 8), byte(token), 0x03}
+	return append(header, packetJSON...), nil
 }
 
 // UDPParsePacket extract metadata and physial payload from a packet
-func UDPParsePacket(packet []byte, result *map[string]interface{}) (bool, string, error) {
+func UDPParsePacket(packet []byte, txpk *TXPK) (bool, error) {
 
 	if len(packet) < 4 {
 		log.Warningf("Bad incoming packet len %d", len(packet))
-		return false, "", nil
+		return false, nil
 	}
 
 	version := int8(packet[0])
 
 	if version != 0x02 {
 		log.Warningf("Bad incoming version %d", version)
-		return false, "", nil
+		return false, nil
 	}
 
 	token := int16(packet[1]) + int16(packet[2])<<8
@@ -216,28 +259,26 @@ func UDPParsePacket(packet []byte, result *map[string]interface{}) (bool, string
 
 	// PULL_RESP == 0x03
 	if id != 0x03 {
-		return false, "", nil
+		return false, nil
 	}
 
 	jsonBytes := packet[4:]
 	log.Debugf("Incoming JSON %s", string(jsonBytes))
 
-	*result = make(map[string]interface{})
-	json.Unmarshal(jsonBytes, result)
-
-	if (*result)["txpk"] == nil {
-		log.Warningf("BAD JSON 'txpk'")
-		return false, "", nil
+	var contents struct {
+		TXPK *TXPK `json:"txpk"`
 	}
+	contents.TXPK = txpk
 
-	txpk := (*result)["txpk"].(map[string]interface{})
+	if err := json.Unmarshal(jsonBytes, &contents); err != nil {
+		log.Warningf("BAD JSON 'txpk': %s", err)
+		return false, nil
+	}
 
-	if txpk["data"] == nil {
+	if txpk.Data == "" {
 		log.Warningf("BAD JSON 'data'")
-		return false, "", nil
+		return false, nil
 	}
 
-	phyBase := txpk["data"].(string)
-
-	return true, phyBase, nil
+	return true, nil
 }
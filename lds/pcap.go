@@ -0,0 +1,318 @@
+package lds
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan/band"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/iegomez/lds/api/gwv3"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// pcap link types of the capture files.
+const (
+	linkTypeRaw     = 101
+	linkTypeLoRaTap = 270
+)
+
+// loRaWANSyncWord is the LoRaTap sync word of public LoRaWAN networks, which makes Wireshark hand the frame to its LoRaWAN dissector.
+const loRaWANSyncWord = 0x34
+
+// Synthetic packet forwarder addresses of the datagrams captured when the transport is MQTT.
+var (
+	pcapGatewayAddr = &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 1700}
+	pcapServerAddr  = &net.UDPAddr{IP: net.IPv4(192, 0, 2, 2), Port: 1700}
+)
+
+// pcapWriter writes packets to a pcap file, unbuffered so that the file is complete when the simulator is killed.
+type pcapWriter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func createPcap(path string, linkType uint32) (*pcapWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint32(header[0:], 0xa1b2c3d4)
+	binary.LittleEndian.PutUint16(header[4:], 2)
+	binary.LittleEndian.PutUint16(header[6:], 4)
+	binary.LittleEndian.PutUint32(header[16:], 65535)
+	binary.LittleEndian.PutUint32(header[20:], linkType)
+	if _, err := f.Write(header); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &pcapWriter{file: f}, nil
+}
+
+func (w *pcapWriter) write(t time.Time, packet []byte) error {
+	record := make([]byte, 16, 16+len(packet))
+	binary.LittleEndian.PutUint32(record[0:], uint32(t.Unix()))
+	binary.LittleEndian.PutUint32(record[4:], uint32(t.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(record[8:], uint32(len(packet)))
+	binary.LittleEndian.PutUint32(record[12:], uint32(len(packet)))
+	record = append(record, packet...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := w.file.Write(record)
+	return err
+}
+
+func (w *pcapWriter) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+// Capture writes the packet forwarder datagrams, with IP and UDP headers, and the LoRaWAN frames, LoRaTap encapsulated,
+// to pcap files. Either file may be missing.
+type Capture struct {
+	udp     *pcapWriter
+	loRaTap *pcapWriter
+}
+
+// OpenCapture creates the capture files, an empty path skips that capture.
+func OpenCapture(udpPath, loRaTapPath string) (*Capture, error) {
+	c := &Capture{}
+	if udpPath != "" {
+		w, err := createPcap(udpPath, linkTypeRaw)
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't create the UDP capture")
+		}
+		c.udp = w
+	}
+	if loRaTapPath != "" {
+		w, err := createPcap(loRaTapPath, linkTypeLoRaTap)
+		if err != nil {
+			c.Close()
+			return nil, errors.Wrap(err, "couldn't create the LoRaTap capture")
+		}
+		c.loRaTap = w
+	}
+	return c, nil
+}
+
+// Close closes the capture files.
+func (c *Capture) Close() error {
+	var err error
+	for _, w := range []*pcapWriter{c.udp, c.loRaTap} {
+		if w == nil {
+			continue
+		}
+		if e := w.close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Open creates the configured capture files.
+func (c PcapConfig) Open() (*Capture, error) {
+	return OpenCapture(c.UDPFile, c.LoRaTapFile)
+}
+
+var (
+	captureMu sync.RWMutex
+	capture   *Capture
+)
+
+// SetCapture sets the capture of the traffic, nil stops capturing. The previous capture isn't closed.
+func SetCapture(c *Capture) {
+	captureMu.Lock()
+	capture = c
+	captureMu.Unlock()
+}
+
+func getCapture() *Capture {
+	captureMu.RLock()
+	defer captureMu.RUnlock()
+	return capture
+}
+
+// captureDatagram writes a packet forwarder datagram sent from src to dst.
+func captureDatagram(src, dst *net.UDPAddr, datagram []byte) {
+	c := getCapture()
+	if c == nil || c.udp == nil {
+		return
+	}
+	if err := c.udp.write(time.Now(), ipPacket(src, dst, datagram)); err != nil {
+		log.Errorf("UDP capture error: %s", err)
+	}
+}
+
+// captureFrame writes a LoRaWAN frame with its radio parameters.
+func captureFrame(phyPayload []byte, frequency int, dr band.DataRate, rssi int32, snr float64) {
+	c := getCapture()
+	if c == nil || c.loRaTap == nil {
+		return
+	}
+	if err := c.loRaTap.write(time.Now(), loRaTapPacket(phyPayload, frequency, dr, rssi, snr)); err != nil {
+		log.Errorf("LoRaTap capture error: %s", err)
+	}
+}
+
+// captureUplink writes the frame of an uplink. When it was published to MQTT, the PUSH_DATA a packet forwarder
+// would have sent is written too, the UDP client captures the actual one.
+func captureUplink(phyPayload []byte, gwMAC string, rxInfo *gw.UplinkRXInfo, txInfo *gw.UplinkTXInfo, mqtt bool) {
+	if getCapture() == nil {
+		return
+	}
+
+	mod := txInfo.GetLoraModulationInfo()
+	dr := band.DataRate{
+		Modulation:   band.LoRaModulation,
+		SpreadFactor: int(mod.GetSpreadingFactor()),
+		Bandwidth:    int(mod.GetBandwidth()),
+	}
+	captureFrame(phyPayload, int(txInfo.GetFrequency()), dr, rxInfo.GetRssi(), rxInfo.GetLoraSnr())
+
+	if mqtt {
+		datagram, err := pushData(phyPayload, gwMAC, rxInfo, txInfo)
+		if err != nil {
+			log.Errorf("UDP capture error: %s", err)
+			return
+		}
+		captureDatagram(pcapGatewayAddr, pcapServerAddr, datagram)
+	}
+}
+
+// captureMQTTDownlink writes the frame of an emitted MQTT downlink, and the PULL_RESP a packet forwarder would have received.
+func captureMQTTDownlink(item *gwv3.DownlinkFrameItem) {
+	if getCapture() == nil {
+		return
+	}
+
+	txInfo := item.GetTxInfo()
+	p := txInfoParams(txInfo)
+	captureFrame(item.GetPhyPayload(), p.frequency, p.dataRate, 0, 0)
+
+	txpk := TXPK{
+		Imme: txInfo.GetTiming() == gw.DownlinkTiming_IMMEDIATELY,
+		Freq: float64(txInfo.GetFrequency()) / 1000000,
+		Powe: txInfo.GetPower(),
+		IPol: p.iPol,
+		Size: uint16(len(item.GetPhyPayload())),
+		Data: base64.StdEncoding.EncodeToString(item.GetPhyPayload()),
+	}
+	if p.dataRate.Modulation == band.FSKModulation {
+		txpk.Modu = string(band.FSKModulation)
+		txpk.DatR.FSK = uint32(p.dataRate.BitRate)
+	} else {
+		txpk.Modu = string(band.LoRaModulation)
+		txpk.DatR.LoRa = fmt.Sprintf("SF%dBW%d", p.dataRate.SpreadFactor, p.dataRate.Bandwidth)
+		txpk.CodR = txInfo.GetLoraModulationInfo().GetCodeRate()
+	}
+	if txInfo.GetTiming() == gw.DownlinkTiming_DELAY && len(txInfo.GetContext()) == 4 {
+		delay, _ := ptypes.Duration(txInfo.GetDelayTimingInfo().GetDelay())
+		tmst := binary.BigEndian.Uint32(txInfo.GetContext()) + uint32(delay/time.Microsecond)
+		txpk.TMST = &tmst
+	}
+
+	datagram, err := pullResp(&txpk)
+	if err != nil {
+		log.Errorf("UDP capture error: %s", err)
+		return
+	}
+	captureDatagram(pcapServerAddr, pcapGatewayAddr, datagram)
+}
+
+// ipPacket prepends the IPv4 or IPv6 and UDP headers to the payload.
+func ipPacket(src, dst *net.UDPAddr, payload []byte) []byte {
+	udp := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint16(udp[0:], uint16(src.Port))
+	binary.BigEndian.PutUint16(udp[2:], uint16(dst.Port))
+	binary.BigEndian.PutUint16(udp[4:], uint16(8+len(payload)))
+	udp = append(udp, payload...)
+
+	src4, dst4 := src.IP.To4(), dst.IP.To4()
+	if src4 != nil && dst4 != nil {
+		ip := make([]byte, 20, 20+len(udp))
+		ip[0] = 0x45
+		binary.BigEndian.PutUint16(ip[2:], uint16(20+len(udp)))
+		ip[6] = 0x40 //Don't fragment.
+		ip[8] = 64
+		ip[9] = 17
+		copy(ip[12:], src4)
+		copy(ip[16:], dst4)
+		binary.BigEndian.PutUint16(ip[10:], checksum(ip, 0))
+		binary.BigEndian.PutUint16(udp[6:], udpChecksum(src4, dst4, udp))
+		return append(ip, udp...)
+	}
+
+	src16, dst16 := src.IP.To16(), dst.IP.To16()
+	ip := make([]byte, 40, 40+len(udp))
+	ip[0] = 0x60
+	binary.BigEndian.PutUint16(ip[4:], uint16(len(udp)))
+	ip[6] = 17
+	ip[7] = 64
+	copy(ip[8:], src16)
+	copy(ip[24:], dst16)
+	binary.BigEndian.PutUint16(udp[6:], udpChecksum(src16, dst16, udp))
+	return append(ip, udp...)
+}
+
+// udpChecksum computes the checksum of the UDP segment with its pseudo header.
+func udpChecksum(src, dst net.IP, udp []byte) uint16 {
+	var sum uint32
+	for _, b := range [][]byte{src, dst} {
+		for i := 0; i < len(b); i += 2 {
+			sum += uint32(b[i])<<8 | uint32(b[i+1])
+		}
+	}
+	sum += 17 + uint32(len(udp))
+	c := checksum(udp, sum)
+	if c == 0 {
+		return 0xffff
+	}
+	return c
+}
+
+// checksum is the internet checksum of b, starting with the given partial sum.
+func checksum(b []byte, sum uint32) uint16 {
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
+
+// loRaTapPacket prepends a LoRaTap version 0 header to the frame.
+func loRaTapPacket(phyPayload []byte, frequency int, dr band.DataRate, rssi int32, snr float64) []byte {
+	h := make([]byte, 15, 15+len(phyPayload))
+	binary.BigEndian.PutUint16(h[2:], 15)
+	binary.BigEndian.PutUint32(h[4:], uint32(frequency))
+	h[8] = uint8(dr.Bandwidth / 125)
+	h[9] = uint8(dr.SpreadFactor)
+	//RSSI is stored as dBm + 139 and SNR in quarters of dB.
+	if rssi != 0 {
+		r := rssi + 139
+		if r < 0 {
+			r = 0
+		} else if r > 255 {
+			r = 255
+		}
+		h[10] = uint8(r)
+		h[11] = uint8(r)
+	}
+	h[13] = uint8(int8(snr * 4))
+	h[14] = loRaWANSyncWord
+	return append(h, phyPayload...)
+}
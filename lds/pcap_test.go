@@ -0,0 +1,230 @@
+package lds
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan/band"
+
+	"github.com/iegomez/lds/api/gwv3"
+)
+
+// readPcap returns the link type and the packets of a pcap file.
+func readPcap(t *testing.T, path string) (uint32, [][]byte) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) < 24 || binary.LittleEndian.Uint32(b) != 0xa1b2c3d4 || binary.LittleEndian.Uint16(b[4:]) != 2 || binary.LittleEndian.Uint16(b[6:]) != 4 {
+		t.Fatalf("invalid pcap header %x", b[:24])
+	}
+
+	var packets [][]byte
+	for r := b[24:]; len(r) > 0; {
+		if len(r) < 16 {
+			t.Fatalf("truncated record header %x", r)
+		}
+		n := binary.LittleEndian.Uint32(r[8:])
+		if binary.LittleEndian.Uint32(r[12:]) != n || uint32(len(r)) < 16+n {
+			t.Fatalf("invalid record header %x", r[:16])
+		}
+		packets = append(packets, r[16:16+n])
+		r = r[16+n:]
+	}
+	return binary.LittleEndian.Uint32(b[20:]), packets
+}
+
+func TestIPPacket(t *testing.T) {
+	payload := []byte{1, 2, 3}
+
+	tests := []struct {
+		name   string
+		src    *net.UDPAddr
+		dst    *net.UDPAddr
+		header int
+	}{
+		{"IPv4", &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 1700}, &net.UDPAddr{IP: net.IPv4(192, 0, 2, 2), Port: 1701}, 20},
+		{"IPv6", &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 1700}, &net.UDPAddr{IP: net.ParseIP("2001:db8::2"), Port: 1701}, 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ipPacket(tt.src, tt.dst, payload)
+			if len(p) != tt.header+8+len(payload) || !bytes.Equal(p[tt.header+8:], payload) {
+				t.Fatalf("got packet %x", p)
+			}
+
+			var src, dst net.IP
+			if tt.header == 20 {
+				if p[0] != 0x45 || p[9] != 17 || binary.BigEndian.Uint16(p[2:]) != uint16(len(p)) {
+					t.Errorf("got IPv4 header %x", p[:20])
+				}
+				//The checksum of a header with its checksum is zero.
+				if c := checksum(p[:20], 0); c != 0 {
+					t.Errorf("got header checksum %x", c)
+				}
+				src, dst = p[12:16], p[16:20]
+			} else {
+				if p[0] != 0x60 || p[6] != 17 || binary.BigEndian.Uint16(p[4:]) != uint16(8+len(payload)) {
+					t.Errorf("got IPv6 header %x", p[:40])
+				}
+				src, dst = p[8:24], p[24:40]
+			}
+			if !src.Equal(tt.src.IP) || !dst.Equal(tt.dst.IP) {
+				t.Errorf("got %s to %s, expected %s to %s", src, dst, tt.src.IP, tt.dst.IP)
+			}
+
+			udp := p[tt.header:]
+			if binary.BigEndian.Uint16(udp) != 1700 || binary.BigEndian.Uint16(udp[2:]) != 1701 || binary.BigEndian.Uint16(udp[4:]) != uint16(len(udp)) {
+				t.Errorf("got UDP header %x", udp[:8])
+			}
+			//The checksum over the pseudo header and the segment with its checksum is zero.
+			if c := udpChecksum(src, dst, udp); c != 0xffff {
+				t.Errorf("got UDP checksum %x over the segment", c)
+			}
+		})
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		b   []byte
+		sum uint16
+	}{
+		{[]byte{}, 0xffff},
+		{[]byte{0x00, 0x01, 0xf2, 0x03, 0xf4, 0xf5, 0xf6, 0xf7}, 0x220d},
+		//An odd length is padded with a zero byte.
+		{[]byte{0x00, 0x01, 0xf2}, 0x0dfe},
+	}
+
+	for _, tt := range tests {
+		if sum := checksum(tt.b, 0); sum != tt.sum {
+			t.Errorf("%x: got %x, expected %x", tt.b, sum, tt.sum)
+		}
+	}
+}
+
+func TestLoRaTapPacket(t *testing.T) {
+	dr := band.DataRate{Modulation: band.LoRaModulation, SpreadFactor: 9, Bandwidth: 250}
+
+	tests := []struct {
+		name string
+		rssi int32
+		snr  float64
+		r    uint8
+		s    uint8
+	}{
+		{"no RSSI", 0, 0, 0, 0},
+		{"RSSI and SNR", -60, 7.5, 79, 30},
+		{"negative SNR", -120, -2.5, 19, 0xf6},
+		{"RSSI below the range", -150, 0, 0, 0},
+		{"RSSI above the range", 120, 0, 255, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := loRaTapPacket([]byte{0x40, 1}, 868300000, dr, tt.rssi, tt.snr)
+			if len(p) != 17 || !bytes.Equal(p[15:], []byte{0x40, 1}) {
+				t.Fatalf("got packet %x", p)
+			}
+			if p[0] != 0 || binary.BigEndian.Uint16(p[2:]) != 15 || binary.BigEndian.Uint32(p[4:]) != 868300000 {
+				t.Errorf("got header %x", p[:15])
+			}
+			if p[8] != 2 || p[9] != 9 || p[14] != loRaWANSyncWord {
+				t.Errorf("got bandwidth %d, SF %d and sync word %x", p[8], p[9], p[14])
+			}
+			if p[10] != tt.r || p[11] != tt.r || p[13] != tt.s {
+				t.Errorf("got RSSI %d and %d and SNR %x, expected %d and %x", p[10], p[11], p[13], tt.r, tt.s)
+			}
+		})
+	}
+}
+
+func TestCapture(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lds-pcap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	udpPath, loRaTapPath := filepath.Join(dir, "udp.pcap"), filepath.Join(dir, "loratap.pcap")
+
+	if _, err := OpenCapture(filepath.Join(dir, "missing", "udp.pcap"), ""); err == nil {
+		t.Error("expected an error creating a capture in a missing directory")
+	}
+
+	c, err := PcapConfig{UDPFile: udpPath, LoRaTapFile: loRaTapPath}.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	//Nothing is captured before the capture is set.
+	captureFrame([]byte{0x40}, 868100000, band.DataRate{}, 0, 0)
+	SetCapture(c)
+
+	rxInfo := &gw.UplinkRXInfo{GatewayId: testGatewayID, Rssi: -50, LoraSnr: 5, Context: []byte{0, 0x0f, 0x42, 0x40}}
+	captureUplink([]byte{0x40, 1}, "0102030405060708", rxInfo, loraTXInfo(868100000, 7), true)
+	captureUplink([]byte{0x40, 2}, "0102030405060708", rxInfo, loraTXInfo(868100000, 7), false)
+	captureMQTTDownlink(&gwv3.DownlinkFrameItem{PhyPayload: []byte{0x60, 3}, TxInfo: delayTXInfo(868100000, 7, time.Second, []byte{0, 0x0f, 0x42, 0x40})})
+
+	SetCapture(nil)
+	captureFrame([]byte{0x40}, 868100000, band.DataRate{}, 0, 0)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	linkType, frames := readPcap(t, loRaTapPath)
+	if linkType != linkTypeLoRaTap || len(frames) != 3 {
+		t.Fatalf("got link type %d with %d frames", linkType, len(frames))
+	}
+	for i, phyPayload := range [][]byte{{0x40, 1}, {0x40, 2}, {0x60, 3}} {
+		if !bytes.Equal(frames[i][15:], phyPayload) || binary.BigEndian.Uint32(frames[i][4:]) != 868100000 || frames[i][9] != 7 {
+			t.Errorf("frame %d: got %x", i, frames[i])
+		}
+	}
+
+	//Only the uplink published to MQTT and the downlink get a synthetic datagram.
+	linkType, packets := readPcap(t, udpPath)
+	if linkType != linkTypeRaw || len(packets) != 2 {
+		t.Fatalf("got link type %d with %d packets", linkType, len(packets))
+	}
+	push, pull := packets[0][28:], packets[1][28:]
+	if !net.IP(packets[0][12:16]).Equal(pcapGatewayAddr.IP) || !net.IP(packets[1][12:16]).Equal(pcapServerAddr.IP) {
+		t.Errorf("got packets from %s and %s", net.IP(packets[0][12:16]), net.IP(packets[1][12:16]))
+	}
+	if push[0] != 2 || push[3] != 0 || !bytes.Equal(push[4:12], testGatewayID) {
+		t.Errorf("got PUSH_DATA header %x", push[:12])
+	}
+	var rxpk struct {
+		RXPK []struct {
+			TMST uint32 `json:"tmst"`
+			Data string `json:"data"`
+		} `json:"rxpk"`
+	}
+	if err := json.Unmarshal(push[12:], &rxpk); err != nil {
+		t.Fatal(err)
+	}
+	if len(rxpk.RXPK) != 1 || rxpk.RXPK[0].TMST != 1000000 || rxpk.RXPK[0].Data != base64.StdEncoding.EncodeToString([]byte{0x40, 1}) {
+		t.Errorf("got rxpk %+v", rxpk)
+	}
+
+	if pull[0] != 2 || pull[3] != 3 {
+		t.Errorf("got PULL_RESP header %x", pull[:4])
+	}
+	var txpk struct {
+		TXPK TXPK `json:"txpk"`
+	}
+	if err := json.Unmarshal(pull[4:], &txpk); err != nil {
+		t.Fatal(err)
+	}
+	if txpk.TXPK.TMST == nil || *txpk.TXPK.TMST != 2000000 || txpk.TXPK.DatR.LoRa != "SF7BW125" || !txpk.TXPK.IPol || txpk.TXPK.Powe != 14 {
+		t.Errorf("got txpk %+v", txpk.TXPK)
+	}
+}
@@ -0,0 +1,280 @@
+package lds
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ReplayTarget is the device a recorded session is replayed on.
+type ReplayTarget interface {
+	ScenarioTarget
+	//UplinkRadio returns how the gateway receives the uplinks.
+	UplinkRadio() UplinkRadio
+	//SetUplinkRadio sets how the gateway receives the next uplinks.
+	SetUplinkRadio(r UplinkRadio) error
+}
+
+// LoadRecording reads the frames of a recorded session from a journal, oldest first.
+func LoadRecording(path string, filter *JournalFilter) ([]*JournalEntry, error) {
+	var entries []*JournalEntry
+	err := ReadJournal(path, filter, func(e *JournalEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("no frames recorded")
+	}
+	return entries, nil
+}
+
+// Replayer sends the join requests and uplinks of a recorded session again, with the counters, nonces and MICs
+// of the target device's session. The payloads, FCtrl bits and MAC commands are the recorded ones.
+type Replayer struct {
+	Target ReplayTarget
+	//Speed divides the recorded time between frames, 2 replays twice as fast and 0 doesn't wait.
+	Speed float64
+	//RecordedRadio sets the recorded frequency, data rate and reception metadata on every uplink instead of keeping the configured ones.
+	RecordedRadio bool
+	//JoinTimeout is how long to wait for the join accept of a replayed join request, it isn't awaited when zero.
+	JoinTimeout time.Duration
+	//Wait is how long to wait for downlinks after the last frame.
+	Wait time.Duration
+}
+
+// ReplayResult counts the replayed frames, along with the downlinks received while replaying and those recorded.
+type ReplayResult struct {
+	Joins             int
+	Uplinks           int
+	Downlinks         int
+	RecordedDownlinks int
+	Duration          time.Duration
+}
+
+// String summarizes the result for logs.
+func (r *ReplayResult) String() string {
+	return fmt.Sprintf("%d joins and %d uplinks replayed in %s, %d downlinks received (%d recorded)",
+		r.Joins, r.Uplinks, r.Duration.Round(time.Millisecond), r.Downlinks, r.RecordedDownlinks)
+}
+
+// Run replays the entries in order, stopping at the first failing frame or when the context is done.
+// The configured radio is restored afterwards.
+func (r *Replayer) Run(ctx context.Context, entries []*JournalEntry) (*ReplayResult, error) {
+	downlinks := NewExpectations()
+	defer r.Target.AddDownlinkHandler(downlinks.Record)()
+
+	if r.RecordedRadio {
+		radio := r.Target.UplinkRadio()
+		defer func() {
+			if err := r.Target.SetUplinkRadio(radio); err != nil {
+				log.Errorf("couldn't restore the uplink radio: %s", err)
+			}
+		}()
+	}
+
+	result := &ReplayResult{}
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+		result.Downlinks = downlinks.Count()
+	}()
+
+	first := entries[0].Time
+	for i, e := range entries {
+		if e.Direction != DirectionUp {
+			if e.Error == "" {
+				result.RecordedDownlinks++
+			}
+			continue
+		}
+
+		if r.Speed > 0 {
+			due := start.Add(time.Duration(float64(e.Time.Sub(first)) / r.Speed))
+			select {
+			case <-time.After(time.Until(due)):
+			case <-ctx.Done():
+				return result, ctx.Err()
+			}
+		} else if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if err := r.replay(ctx, e, downlinks); err != nil {
+			return result, errors.Wrapf(err, "frame %d (%s at %s)", i+1, e.MType, e.Time.Format(time.RFC3339Nano))
+		}
+		if e.MType == lorawan.JoinRequest.String() {
+			result.Joins++
+		} else {
+			result.Uplinks++
+		}
+	}
+
+	select {
+	case <-time.After(r.Wait):
+	case <-ctx.Done():
+	}
+	return result, nil
+}
+
+func (r *Replayer) replay(ctx context.Context, e *JournalEntry, downlinks *Expectations) error {
+	if r.RecordedRadio {
+		radio, err := recordedRadio(e, r.Target.UplinkRadio())
+		if err != nil {
+			return err
+		}
+		if err := r.Target.SetUplinkRadio(radio); err != nil {
+			return err
+		}
+	}
+
+	switch e.MType {
+	case lorawan.JoinRequest.String():
+		//Only the answer to this request may be taken as the join accept.
+		downlinks.Skip()
+		if err := r.Target.Join(); err != nil {
+			return err
+		}
+		log.Infoln("replay: join sent")
+		if r.JoinTimeout > 0 {
+			_, err := downlinks.ExpectJoinAccept(ctx, r.JoinTimeout)
+			return err
+		}
+	case lorawan.UnconfirmedDataUp.String(), lorawan.ConfirmedDataUp.String():
+		mType, fPort, payload, macCommands, fCtrl, err := recordedUplink(e)
+		if err != nil {
+			return err
+		}
+		fCnt, err := r.Target.Uplink(mType, fPort, payload, macCommands, fCtrl)
+		if err != nil {
+			return err
+		}
+		log.Infof("replay: %s sent, uplink framecounter is now %d", mType, fCnt)
+	default:
+		log.Warningf("replay: %s frames aren't replayed", e.MType)
+	}
+	return nil
+}
+
+// recordedUplink returns the uplink parameters of a recorded data uplink. The FCtrl bits are read from the recorded frame,
+// whose header isn't encrypted.
+func recordedUplink(e *JournalEntry) (lorawan.MType, uint8, []byte, []*lorawan.MACCommand, lorawan.FCtrl, error) {
+	var fCtrl lorawan.FCtrl
+
+	mType := lorawan.UnconfirmedDataUp
+	if e.MType == lorawan.ConfirmedDataUp.String() {
+		mType = lorawan.ConfirmedDataUp
+	}
+
+	var fPort uint8
+	if e.FPort != nil {
+		fPort = *e.FPort
+	}
+
+	payload, err := hex.DecodeString(e.FRMPayload)
+	if err != nil {
+		return mType, fPort, nil, nil, fCtrl, errors.Wrap(err, "frm payload")
+	}
+
+	macCommands, err := recordedMACCommands(e.MACCommands)
+	if err != nil {
+		return mType, fPort, nil, nil, fCtrl, err
+	}
+
+	b, err := hex.DecodeString(e.PHYPayload)
+	if err != nil {
+		return mType, fPort, nil, nil, fCtrl, errors.Wrap(err, "phy payload")
+	}
+	var phy lorawan.PHYPayload
+	if err := phy.UnmarshalBinary(b); err != nil {
+		return mType, fPort, nil, nil, fCtrl, errors.Wrap(err, "phy payload")
+	}
+	if mp, ok := phy.MACPayload.(*lorawan.MACPayload); ok {
+		fCtrl = lorawan.FCtrl{
+			ADR:       mp.FHDR.FCtrl.ADR,
+			ADRACKReq: mp.FHDR.FCtrl.ADRACKReq,
+			ACK:       mp.FHDR.FCtrl.ACK,
+			ClassB:    mp.FHDR.FCtrl.ClassB,
+		}
+	}
+
+	return mType, fPort, payload, macCommands, fCtrl, nil
+}
+
+// recordedMACCommands decodes the uplink MAC commands of a journal entry.
+func recordedMACCommands(raw json.RawMessage) ([]*lorawan.MACCommand, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var recorded []struct {
+		CID     string          `json:"cid"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(raw, &recorded); err != nil {
+		return nil, errors.Wrap(err, "mac commands")
+	}
+
+	var macCommands []*lorawan.MACCommand
+	for _, rc := range recorded {
+		cid, ok := cidByName(rc.CID)
+		if !ok {
+			return nil, fmt.Errorf("unknown mac command %q", rc.CID)
+		}
+		mac := &lorawan.MACCommand{CID: cid}
+		pl, _, err := lorawan.GetMACPayloadAndSize(true, cid)
+		if err == nil && len(rc.Payload) > 0 && string(rc.Payload) != "null" {
+			if err := json.Unmarshal(rc.Payload, pl); err != nil {
+				return nil, errors.Wrapf(err, "mac command %s", rc.CID)
+			}
+			mac.Payload = pl
+		}
+		macCommands = append(macCommands, mac)
+	}
+	return macCommands, nil
+}
+
+// recordedRadio returns the radio of a recorded uplink, in the current band and with the current CRC status, which isn't recorded.
+func recordedRadio(e *JournalEntry, current UplinkRadio) (UplinkRadio, error) {
+	var rxInfo gw.UplinkRXInfo
+	var txInfo gw.UplinkTXInfo
+	um := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if len(e.RXInfo) == 0 || len(e.TXInfo) == 0 {
+		return current, errors.New("the uplink metadata wasn't recorded")
+	}
+	if err := um.Unmarshal(strings.NewReader(string(e.RXInfo)), &rxInfo); err != nil {
+		return current, errors.Wrap(err, "rx info")
+	}
+	if err := um.Unmarshal(strings.NewReader(string(e.TXInfo)), &txInfo); err != nil {
+		return current, errors.Wrap(err, "tx info")
+	}
+
+	mod := txInfo.GetLoraModulationInfo()
+	return UplinkRadio{
+		Band: current.Band,
+		DR: DataRateConfig{
+			Bandwidth:    int(mod.GetBandwidth()),
+			SpreadFactor: int(mod.GetSpreadingFactor()),
+		},
+		RXInfo: RXInfoConfig{
+			Channel:   int(rxInfo.GetChannel()),
+			CodeRate:  mod.GetCodeRate(),
+			CrcStatus: current.RXInfo.CrcStatus,
+			Frequency: int(txInfo.GetFrequency()),
+			LoRaSNR:   rxInfo.GetLoraSnr(),
+			RfChain:   int(rxInfo.GetRfChain()),
+			Rssi:      int(rxInfo.GetRssi()),
+		},
+	}, nil
+}
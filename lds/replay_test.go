@@ -0,0 +1,296 @@
+package lds
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/band"
+	"github.com/pkg/errors"
+)
+
+// fakeReplayTarget adds the uplink radio to a fakeTarget, keeping the radio of every uplink.
+type fakeReplayTarget struct {
+	*fakeTarget
+	radio  UplinkRadio
+	radios []UplinkRadio
+}
+
+func (f *fakeReplayTarget) UplinkRadio() UplinkRadio {
+	return f.radio
+}
+
+func (f *fakeReplayTarget) SetUplinkRadio(r UplinkRadio) error {
+	f.radio = r
+	return nil
+}
+
+func (f *fakeReplayTarget) Uplink(mType lorawan.MType, fPort uint8, payload []byte, macCommands []*lorawan.MACCommand, fCtrl lorawan.FCtrl) (uint32, error) {
+	f.radios = append(f.radios, f.radio)
+	return f.fakeTarget.Uplink(mType, fPort, payload, macCommands, fCtrl)
+}
+
+// recordedEntry returns the journal entry of a data uplink as the device records it.
+func recordedEntry(t *testing.T, at time.Time, mType lorawan.MType, fCtrl lorawan.FCtrl, fPort uint8, payload []byte, macCommands []lorawan.MACCommand) *JournalEntry {
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{MType: mType, Major: lorawan.LoRaWANR1},
+		MACPayload: &lorawan.MACPayload{
+			FHDR:       lorawan.FHDR{DevAddr: lorawan.DevAddr{1, 2, 3, 4}, FCtrl: fCtrl, FCnt: 9},
+			FPort:      &fPort,
+			FRMPayload: []lorawan.Payload{&lorawan.DataPayload{Bytes: payload}},
+		},
+	}
+	b, err := phy.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rxInfo := &gw.UplinkRXInfo{GatewayId: testGatewayID, Rssi: -80, LoraSnr: -3.5, Channel: 2, RfChain: 1}
+	return NewJournalEntry(&Event{
+		Type: EventUplinkSent,
+		Time: at,
+		Uplink: &Uplink{
+			MType:       mType,
+			PHYPayload:  b,
+			RXInfo:      rxInfo,
+			TXInfo:      loraTXInfo(868500000, 10),
+			DevAddr:     lorawan.DevAddr{1, 2, 3, 4},
+			FCtrl:       fCtrl,
+			FCnt:        9,
+			FPort:       fPort,
+			FRMPayload:  payload,
+			MACCommands: macCommands,
+		},
+	})
+}
+
+func TestRecordedUplink(t *testing.T) {
+	linkADRAns := lorawan.MACCommand{CID: lorawan.LinkADRAns, Payload: &lorawan.LinkADRAnsPayload{ChannelMaskACK: true, DataRateACK: true}}
+
+	tests := []struct {
+		name        string
+		entry       func() *JournalEntry
+		mType       lorawan.MType
+		fCtrl       lorawan.FCtrl
+		payload     []byte
+		macCommands []lorawan.MACCommand
+		err         bool
+	}{
+		{
+			name: "unconfirmed",
+			entry: func() *JournalEntry {
+				return recordedEntry(t, time.Now(), lorawan.UnconfirmedDataUp, lorawan.FCtrl{}, 2, []byte{1, 2}, nil)
+			},
+			mType:   lorawan.UnconfirmedDataUp,
+			payload: []byte{1, 2},
+		},
+		{
+			name: "confirmed with FCtrl bits and MAC commands",
+			entry: func() *JournalEntry {
+				return recordedEntry(t, time.Now(), lorawan.ConfirmedDataUp, lorawan.FCtrl{ADR: true, ACK: true}, 2, []byte{3},
+					[]lorawan.MACCommand{{CID: lorawan.LinkCheckReq}, linkADRAns})
+			},
+			mType:       lorawan.ConfirmedDataUp,
+			fCtrl:       lorawan.FCtrl{ADR: true, ACK: true},
+			payload:     []byte{3},
+			macCommands: []lorawan.MACCommand{{CID: lorawan.LinkCheckReq}, linkADRAns},
+		},
+		{
+			name: "invalid payload",
+			entry: func() *JournalEntry {
+				e := recordedEntry(t, time.Now(), lorawan.UnconfirmedDataUp, lorawan.FCtrl{}, 2, []byte{1}, nil)
+				e.FRMPayload = "0g"
+				return e
+			},
+			err: true,
+		},
+		{
+			name: "invalid PHYPayload",
+			entry: func() *JournalEntry {
+				e := recordedEntry(t, time.Now(), lorawan.UnconfirmedDataUp, lorawan.FCtrl{}, 2, []byte{1}, nil)
+				e.PHYPayload = "40"
+				return e
+			},
+			err: true,
+		},
+		{
+			name: "unknown MAC command",
+			entry: func() *JournalEntry {
+				e := recordedEntry(t, time.Now(), lorawan.UnconfirmedDataUp, lorawan.FCtrl{}, 2, []byte{1}, nil)
+				e.MACCommands = []byte(`[{"cid":"FooReq","payload":null}]`)
+				return e
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mType, fPort, payload, macCommands, fCtrl, err := recordedUplink(tt.entry())
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			if mType != tt.mType || fPort != 2 || !bytes.Equal(payload, tt.payload) || fCtrl != tt.fCtrl {
+				t.Errorf("got %s on fPort %d with payload %x and FCtrl %+v", mType, fPort, payload, fCtrl)
+			}
+			if len(macCommands) != len(tt.macCommands) {
+				t.Fatalf("got mac commands %v, expected %v", macCommands, tt.macCommands)
+			}
+			for i, mac := range macCommands {
+				got, _ := mac.MarshalBinary()
+				expected, _ := tt.macCommands[i].MarshalBinary()
+				if !bytes.Equal(got, expected) {
+					t.Errorf("got mac command %x, expected %x", got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordedRadio(t *testing.T) {
+	current := UplinkRadio{Band: band.EU868, RXInfo: RXInfoConfig{CrcStatus: 1, Frequency: 868100000}}
+	e := recordedEntry(t, time.Now(), lorawan.UnconfirmedDataUp, lorawan.FCtrl{}, 2, []byte{1}, nil)
+
+	radio, err := recordedRadio(e, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := UplinkRadio{
+		Band:   band.EU868,
+		DR:     DataRateConfig{Bandwidth: 125, SpreadFactor: 10},
+		RXInfo: RXInfoConfig{Channel: 2, CodeRate: "4/5", CrcStatus: 1, Frequency: 868500000, LoRaSNR: -3.5, RfChain: 1, Rssi: -80},
+	}
+	if radio != expected {
+		t.Errorf("got %+v, expected %+v", radio, expected)
+	}
+
+	e.TXInfo = nil
+	if radio, err := recordedRadio(e, current); err == nil || radio != current {
+		t.Errorf("got %+v and error %v, expected the current radio and an error", radio, err)
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	join := NewJournalEntry(&Event{Type: EventUplinkSent, Time: start, Uplink: &Uplink{MType: lorawan.JoinRequest, RXInfo: &gw.UplinkRXInfo{}, TXInfo: loraTXInfo(868100000, 12)}})
+	joinAccept := NewJournalEntry(&Event{Type: EventJoinAccepted, Time: start.Add(5 * time.Second), Downlink: &Downlink{MType: lorawan.JoinAccept}})
+	uplink := recordedEntry(t, start.Add(10*time.Second), lorawan.ConfirmedDataUp, lorawan.FCtrl{}, 2, []byte{1}, nil)
+	downlink := NewJournalEntry(&Event{Type: EventDownlinkReceived, Time: start.Add(11 * time.Second), Downlink: &Downlink{MType: lorawan.UnconfirmedDataDown}})
+	micFailure := NewJournalEntry(&Event{Type: EventMICFailure, Time: start.Add(12 * time.Second), MICFailure: &MICFailure{MType: lorawan.UnconfirmedDataDown, Err: errors.New("invalid MIC")}})
+	entries := []*JournalEntry{join, joinAccept, uplink, downlink, micFailure, uplink}
+	noMetadata := &JournalEntry{Time: start, Direction: DirectionUp, MType: lorawan.JoinRequest.String()}
+
+	configured := UplinkRadio{Band: band.EU868, DR: DataRateConfig{Bandwidth: 125, SpreadFactor: 7}, RXInfo: RXInfoConfig{Frequency: 868100000}}
+	newTarget := func() *fakeReplayTarget {
+		return &fakeReplayTarget{
+			fakeTarget: &fakeTarget{
+				joinAccept: func() *Downlink { return &Downlink{MType: lorawan.JoinAccept} },
+				uplinkAnswers: func(up sentUplink) *Downlink {
+					return &Downlink{MType: lorawan.UnconfirmedDataDown, FCtrl: lorawan.FCtrl{ACK: true}}
+				},
+			},
+			radio: configured,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		replayer Replayer
+		entries  []*JournalEntry
+		result   ReplayResult
+		err      bool
+	}{
+		{"configured radio", Replayer{JoinTimeout: time.Second}, entries, ReplayResult{Joins: 1, Uplinks: 2, Downlinks: 3, RecordedDownlinks: 2}, false},
+		{"recorded radio", Replayer{RecordedRadio: true}, []*JournalEntry{uplink, uplink}, ReplayResult{Uplinks: 2, Downlinks: 2}, false},
+		{"recorded radio without metadata", Replayer{RecordedRadio: true}, []*JournalEntry{uplink, noMetadata}, ReplayResult{Uplinks: 1, Downlinks: 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := newTarget()
+			r := tt.replayer
+			r.Target = target
+
+			result, err := r.Run(context.Background(), tt.entries)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			result.Duration = 0
+			if *result != tt.result {
+				t.Errorf("got %+v, expected %+v", result, tt.result)
+			}
+			if target.radio != configured {
+				t.Errorf("got radio %+v after the replay, expected the configured one", target.radio)
+			}
+			for _, radio := range target.radios {
+				if freq := radio.RXInfo.Frequency; (freq == 868500000) != r.RecordedRadio {
+					t.Errorf("got an uplink at %d", freq)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayerSpeed(t *testing.T) {
+	start := time.Now()
+	first := recordedEntry(t, start, lorawan.UnconfirmedDataUp, lorawan.FCtrl{}, 2, []byte{1}, nil)
+	second := recordedEntry(t, start.Add(time.Second), lorawan.UnconfirmedDataUp, lorawan.FCtrl{}, 2, []byte{2}, nil)
+
+	r := &Replayer{Target: &fakeReplayTarget{fakeTarget: &fakeTarget{}}, Speed: 10}
+	result, err := r.Run(context.Background(), []*JournalEntry{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Uplinks != 2 || result.Duration < 100*time.Millisecond || result.Duration > time.Second {
+		t.Errorf("got %s, expected the second uplink after 100ms", result)
+	}
+
+	//The replay stops while waiting for a frame.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	r.Speed = 1
+	if result, err = r.Run(ctx, []*JournalEntry{first, second}); err != context.DeadlineExceeded || result.Uplinks != 1 {
+		t.Errorf("got %s and error %v, expected one uplink and the context error", result, err)
+	}
+}
+
+func TestLoadRecording(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lds-replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "journal")
+
+	j, err := OpenJournal(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := recordedEntry(t, time.Now(), lorawan.UnconfirmedDataUp, lorawan.FCtrl{}, 2, []byte{1}, nil)
+	if err := j.Write(entry); err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+
+	entries, err := LoadRecording(path, &JournalFilter{DevEUI: entry.DevEUI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].PHYPayload != entry.PHYPayload || hex.EncodeToString([]byte{1}) != entries[0].FRMPayload {
+		t.Errorf("got entries %+v", entries)
+	}
+	if _, err := LoadRecording(path, &JournalFilter{Direction: DirectionDown}); err == nil {
+		t.Error("expected an error without frames")
+	}
+}
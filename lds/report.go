@@ -0,0 +1,137 @@
+package lds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// StepResult is the outcome of a scenario step, it passed when it ran with no error.
+type StepResult struct {
+	Step     *ScenarioStep
+	Duration time.Duration
+	//Skipped tells the step didn't run because an earlier one failed.
+	Skipped bool
+	Err     error
+}
+
+// ScenarioResult holds the outcome of every step of a scenario run.
+type ScenarioResult struct {
+	Name     string
+	Duration time.Duration
+	Steps    []*StepResult
+}
+
+// Failed tells if any step failed.
+func (r *ScenarioResult) Failed() bool {
+	for _, s := range r.Steps {
+		if s.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *StepResult) name(i int) string {
+	return fmt.Sprintf("%d. %s", i+1, r.Step)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// WriteJUnit writes the results as JUnit XML, with a test suite per scenario and a test case per step.
+func WriteJUnit(w io.Writer, results []*ScenarioResult) error {
+	var report junitTestSuites
+	for _, r := range results {
+		suite := junitTestSuite{
+			Name:  r.Name,
+			Tests: len(r.Steps),
+			Time:  junitTime(r.Duration),
+		}
+		for i, s := range r.Steps {
+			tc := junitTestCase{
+				Name:      s.name(i),
+				ClassName: r.Name,
+				Time:      junitTime(s.Duration),
+			}
+			switch {
+			case s.Skipped:
+				tc.Skipped = &struct{}{}
+				suite.Skipped++
+			case s.Err != nil:
+				tc.Failure = &junitFailure{Message: s.Err.Error()}
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		report.Suites = append(report.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteTAP writes the results in the Test Anything Protocol, with a test point per step.
+func WriteTAP(w io.Writer, results []*ScenarioResult) error {
+	total := 0
+	for _, r := range results {
+		total += len(r.Steps)
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "TAP version 13\n1..%d\n", total)
+	n := 0
+	for _, r := range results {
+		for i, s := range r.Steps {
+			n++
+			switch {
+			case s.Skipped:
+				fmt.Fprintf(b, "ok %d - %s: %s # SKIP an earlier step failed\n", n, r.Name, s.name(i))
+			case s.Err != nil:
+				fmt.Fprintf(b, "not ok %d - %s: %s\n", n, r.Name, s.name(i))
+				fmt.Fprintf(b, "  ---\n  message: %q\n  ...\n", s.Err.Error())
+			default:
+				fmt.Fprintf(b, "ok %d - %s: %s\n", n, r.Name, s.name(i))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,80 @@
+package lds
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testResults returns a passed scenario and a failed one with a skipped step.
+func testResults() []*ScenarioResult {
+	join := &ScenarioStep{Action: StepJoin}
+	uplink := &ScenarioStep{Action: StepUplink, Confirmed: true}
+	downlink := &ScenarioStep{Action: StepExpectDownlink, Timeout: Duration{time.Second}}
+	return []*ScenarioResult{
+		{Name: "join", Duration: 1500 * time.Millisecond, Steps: []*StepResult{
+			{Step: join, Duration: 1500 * time.Millisecond},
+		}},
+		{Name: "confirmed", Duration: 2 * time.Second, Steps: []*StepResult{
+			{Step: uplink, Duration: time.Second},
+			{Step: downlink, Duration: time.Second, Err: errors.New(`expected fPort 1, got "nothing"`)},
+			{Step: uplink, Skipped: true},
+		}},
+	}
+}
+
+func TestScenarioResultFailed(t *testing.T) {
+	results := testResults()
+	if results[0].Failed() || !results[1].Failed() {
+		t.Errorf("got failed %t and %t, expected false and true", results[0].Failed(), results[1].Failed())
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteJUnit(&b, testResults()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="join" tests="1" failures="0" skipped="0" time="1.500">
+    <testcase name="1. join" classname="join" time="1.500"></testcase>
+  </testsuite>
+  <testsuite name="confirmed" tests="3" failures="1" skipped="1" time="2.000">
+    <testcase name="1. confirmed uplink" classname="confirmed" time="1.000"></testcase>
+    <testcase name="2. expect downlink" classname="confirmed" time="1.000">
+      <failure message="expected fPort 1, got &#34;nothing&#34;"></failure>
+    </testcase>
+    <testcase name="3. confirmed uplink" classname="confirmed" time="0.000">
+      <skipped></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if b.String() != expected {
+		t.Errorf("got:\n%s\nexpected:\n%s", b.String(), expected)
+	}
+}
+
+func TestWriteTAP(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteTAP(&b, testResults()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `TAP version 13
+1..4
+ok 1 - join: 1. join
+ok 2 - confirmed: 1. confirmed uplink
+not ok 3 - confirmed: 2. expect downlink
+  ---
+  message: "expected fPort 1, got \"nothing\""
+  ...
+ok 4 - confirmed: 3. confirmed uplink # SKIP an earlier step failed
+`
+	if b.String() != expected {
+		t.Errorf("got:\n%s\nexpected:\n%s", b.String(), expected)
+	}
+}
@@ -0,0 +1,384 @@
+package lds
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/brocaar/lorawan"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Scenario step actions.
+const (
+	StepJoin        = "join"
+	StepUplink      = "uplink"
+	StepWait        = "wait"
+	StepFCtrl       = "fctrl"
+	StepMACCommands = "mac_commands"
+	StepReset       = "reset"
+	StepAssert      = "assert"
+
+	StepExpectJoinAccept = "expect_join_accept"
+	StepExpectDownlink   = "expect_downlink"
+	StepExpectNoDownlink = "expect_no_downlink"
+)
+
+// ErrTimeout is returned when a scenario step doesn't complete in time.
+var ErrTimeout = errors.New("timeout")
+
+// Duration is a time.Duration read from strings such as "1m30s".
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText parses the duration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	var err error
+	d.Duration, err = time.ParseDuration(string(text))
+	return err
+}
+
+// MarshalText formats the duration.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.Duration.String()), nil
+}
+
+// Scenario is an ordered list of steps run against a device.
+type Scenario struct {
+	Name  string          `toml:"name"`
+	Steps []*ScenarioStep `toml:"step"`
+}
+
+// ScenarioStep is an action and its options, only the options of the action are used.
+type ScenarioStep struct {
+	Action string `toml:"action"`
+	//Join and expectations: how long to wait for the downlink, a join accept isn't awaited when zero.
+	Timeout Duration `toml:"timeout"`
+	//Wait and no downlink expectation: how long to wait.
+	Duration Duration `toml:"duration"`
+	//Uplink: hex encoded payload (the configured data is sent when empty), fPort (the configured one when not set) and message type.
+	//Downlink expectation: the expected decrypted payload, fPort and message type, only checked when given.
+	Payload   string `toml:"payload"`
+	FPort     *int   `toml:"fport"`
+	Confirmed bool   `toml:"confirmed"`
+	//FCtrl: the bits set on the following uplinks. ACK is checked by downlink expectations too, along with FPending.
+	ADR       bool  `toml:"adr"`
+	ADRACKReq bool  `toml:"adr_ack_req"`
+	ACK       *bool `toml:"ack"`
+	ClassB    bool  `toml:"class_b"`
+	FPending  *bool `toml:"fpending"`
+	//MAC commands: the commands sent on the following uplinks, an empty list clears them. Commands are given by name
+	//when they have no payload (e.g. "LinkCheckReq") or hex encoded, CID included (e.g. "0307" for a LinkADRAns acking everything).
+	MACCommands []string `toml:"mac_commands"`
+	//Downlink expectation: a MAC command the downlink must carry, by name (e.g. "LinkADRReq"), and the values
+	//of its payload fields (e.g. { dataRate = 5 }).
+	MACCommand string                 `toml:"mac_command"`
+	MACFields  map[string]interface{} `toml:"mac_fields"`
+	//Assert: the expected device state, only the given fields are checked.
+	Joined  *bool   `toml:"joined"`
+	DevAddr string  `toml:"dev_addr"`
+	UlFcnt  *uint32 `toml:"ul_fcnt"`
+	DlFcnt  *uint32 `toml:"dl_fcnt"`
+
+	payload     []byte
+	macCommands []*lorawan.MACCommand
+	expectation *DownlinkExpectation
+}
+
+// String describes the step for logs and reports.
+func (s *ScenarioStep) String() string {
+	switch s.Action {
+	case StepUplink:
+		if s.Confirmed {
+			return "confirmed uplink"
+		}
+		return "uplink"
+	case StepWait:
+		return fmt.Sprintf("wait %s", s.Duration)
+	case StepMACCommands:
+		return fmt.Sprintf("mac commands %v", s.MACCommands)
+	case StepExpectDownlink:
+		if s.MACCommand != "" {
+			return fmt.Sprintf("expect downlink with %s", s.MACCommand)
+		}
+		return "expect downlink"
+	case StepExpectNoDownlink:
+		return fmt.Sprintf("expect no downlink for %s", s.Duration)
+	}
+	return s.Action
+}
+
+// ScenarioTarget is the device a scenario drives, together with its connection to the network server.
+type ScenarioTarget interface {
+	//Join sends a join request.
+	Join() error
+	//Uplink sends an uplink and returns the uplink frame counter.
+	Uplink(mType lorawan.MType, fPort uint8, payload []byte, macCommands []*lorawan.MACCommand, fCtrl lorawan.FCtrl) (uint32, error)
+	//Reset deletes the device session, counters and nonces.
+	Reset() error
+	//State returns a copy of the device, safe to read while downlinks are being processed.
+	State() Device
+	//AddDownlinkHandler adds a function called with the downlinks processed by the device, and returns the function removing it.
+	AddDownlinkHandler(h func(*Downlink)) func()
+}
+
+// LoadScenario reads and validates a toml scenario file.
+func LoadScenario(filename string) (*Scenario, error) {
+	var s Scenario
+	if _, err := toml.DecodeFile(filename, &s); err != nil {
+		return nil, err
+	}
+	if s.Name == "" {
+		s.Name = filename
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate checks the actions and decodes the payloads and MAC commands of the steps.
+func (s *Scenario) Validate() error {
+	for i, step := range s.Steps {
+		var err error
+		switch step.Action {
+		case StepJoin, StepFCtrl, StepReset, StepAssert:
+		case StepUplink:
+			step.payload, err = hex.DecodeString(step.Payload)
+		case StepWait, StepExpectNoDownlink:
+			if step.Duration.Duration <= 0 {
+				err = fmt.Errorf("%s needs a duration", step.Action)
+			}
+		case StepExpectJoinAccept:
+			if step.Timeout.Duration <= 0 {
+				err = fmt.Errorf("%s needs a timeout", step.Action)
+			}
+		case StepExpectDownlink:
+			if step.Timeout.Duration <= 0 {
+				err = fmt.Errorf("%s needs a timeout", step.Action)
+				break
+			}
+			step.expectation, err = step.downlinkExpectation()
+		case StepMACCommands:
+			step.macCommands, err = ParseMACCommands(step.MACCommands)
+		default:
+			err = fmt.Errorf("unknown action %q", step.Action)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "step %d", i+1)
+		}
+	}
+	return nil
+}
+
+// ParseMACCommands parses uplink MAC commands given by name or hex encoded.
+func ParseMACCommands(commands []string) ([]*lorawan.MACCommand, error) {
+	var parsed []*lorawan.MACCommand
+	for _, c := range commands {
+		if cid, ok := cidByName(c); ok {
+			parsed = append(parsed, &lorawan.MACCommand{CID: cid})
+			continue
+		}
+
+		b, err := hex.DecodeString(c)
+		if err != nil {
+			return nil, fmt.Errorf("unknown mac command %q", c)
+		}
+		var mac lorawan.MACCommand
+		if err := mac.UnmarshalBinary(true, b); err != nil {
+			return nil, errors.Wrapf(err, "mac command %s", c)
+		}
+		parsed = append(parsed, &mac)
+	}
+	return parsed, nil
+}
+
+func (s *ScenarioStep) downlinkExpectation() (*DownlinkExpectation, error) {
+	x := &DownlinkExpectation{
+		ACK:       s.ACK,
+		FPending:  s.FPending,
+		MACFields: s.MACFields,
+	}
+	if s.Confirmed {
+		x.Confirmed = &s.Confirmed
+	}
+	if s.FPort != nil {
+		fPort := uint8(*s.FPort)
+		x.FPort = &fPort
+	}
+	if s.Payload != "" {
+		var err error
+		if x.FRMPayload, err = hex.DecodeString(s.Payload); err != nil {
+			return nil, err
+		}
+	}
+	if s.MACCommand != "" {
+		cid, ok := cidByName(s.MACCommand)
+		if !ok {
+			return nil, fmt.Errorf("unknown mac command %q", s.MACCommand)
+		}
+		x.MACCommand = &cid
+	} else if len(s.MACFields) > 0 {
+		return nil, errors.New("mac_fields needs a mac_command")
+	}
+	return x, nil
+}
+
+func cidByName(name string) (lorawan.CID, bool) {
+	for i := 0; i < 256; i++ {
+		if lorawan.CID(i).String() == name {
+			return lorawan.CID(i), true
+		}
+	}
+	return 0, false
+}
+
+// ScenarioRunner runs scenarios, keeping the FCtrl and MAC commands set by the steps for the following uplinks.
+type ScenarioRunner struct {
+	Target ScenarioTarget
+	//Config provides the data and fPort of uplinks that don't set them.
+	Config *Config
+
+	fCtrl       lorawan.FCtrl
+	macCommands []*lorawan.MACCommand
+	downlinks   *Expectations
+}
+
+// Run runs the scenario steps in order, stopping at the first failing one or when the context is done.
+// The result holds every step, those after the failing one marked as skipped, and the error is the failure.
+func (r *ScenarioRunner) Run(ctx context.Context, s *Scenario) (*ScenarioResult, error) {
+	r.downlinks = NewExpectations()
+	defer r.Target.AddDownlinkHandler(r.downlinks.Record)()
+
+	result := &ScenarioResult{Name: s.Name}
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+
+	log.Infof("scenario %s: started", s.Name)
+	var failure error
+	for i, step := range s.Steps {
+		sr := &StepResult{Step: step}
+		result.Steps = append(result.Steps, sr)
+		if failure != nil {
+			sr.Skipped = true
+			continue
+		}
+
+		log.Infof("scenario %s: step %d/%d, %s", s.Name, i+1, len(s.Steps), step)
+		stepStart := time.Now()
+		sr.Err = ctx.Err()
+		if sr.Err == nil {
+			sr.Err = r.runStep(ctx, step)
+		}
+		sr.Duration = time.Since(stepStart)
+
+		if sr.Err != nil {
+			log.Errorf("scenario %s: step %d failed: %s", s.Name, i+1, sr.Err)
+			failure = errors.Wrapf(sr.Err, "step %d (%s)", i+1, step)
+		}
+	}
+	if failure != nil {
+		return result, failure
+	}
+	log.Infof("scenario %s: passed", s.Name)
+	return result, nil
+}
+
+func (r *ScenarioRunner) runStep(ctx context.Context, step *ScenarioStep) error {
+	switch step.Action {
+	case StepJoin:
+		//Only the answer to this request may be taken as the join accept.
+		r.downlinks.Skip()
+		if err := r.Target.Join(); err != nil {
+			return err
+		}
+		if step.Timeout.Duration > 0 {
+			_, err := r.downlinks.ExpectJoinAccept(ctx, step.Timeout.Duration)
+			return err
+		}
+	case StepUplink:
+		return r.uplink(step)
+	case StepWait:
+		select {
+		case <-time.After(step.Duration.Duration):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	case StepFCtrl:
+		r.fCtrl = lorawan.FCtrl{
+			ADR:       step.ADR,
+			ADRACKReq: step.ADRACKReq,
+			ACK:       step.ACK != nil && *step.ACK,
+			ClassB:    step.ClassB,
+		}
+	case StepMACCommands:
+		r.macCommands = step.macCommands
+	case StepReset:
+		return r.Target.Reset()
+	case StepAssert:
+		return step.assert(r.Target.State())
+	case StepExpectJoinAccept:
+		_, err := r.downlinks.ExpectJoinAccept(ctx, step.Timeout.Duration)
+		return err
+	case StepExpectDownlink:
+		dl, err := r.downlinks.ExpectDownlink(ctx, step.Timeout.Duration, step.expectation)
+		if dl != nil {
+			log.Infof("downlink received: %s", dl)
+		}
+		return err
+	case StepExpectNoDownlink:
+		return r.downlinks.ExpectNoDownlink(ctx, step.Duration.Duration)
+	}
+	return nil
+}
+
+func (r *ScenarioRunner) uplink(step *ScenarioStep) error {
+	payload := step.payload
+	if len(payload) == 0 {
+		var err error
+		if payload, err = r.Config.Payload(); err != nil {
+			return err
+		}
+	}
+
+	fPort := r.Config.RawPayload.FPort
+	if step.FPort != nil {
+		fPort = *step.FPort
+	}
+
+	mType := lorawan.UnconfirmedDataUp
+	if step.Confirmed {
+		mType = lorawan.ConfirmedDataUp
+	}
+
+	fCnt, err := r.Target.Uplink(mType, uint8(fPort), payload, r.macCommands, r.fCtrl)
+	if err != nil {
+		return err
+	}
+	log.Infof("message sent, uplink framecounter is now %d", fCnt)
+	return nil
+}
+
+// assert checks the device state against the expected one.
+func (s *ScenarioStep) assert(d Device) error {
+	if s.Joined != nil && d.Joined != *s.Joined {
+		return fmt.Errorf("expected joined to be %t", *s.Joined)
+	}
+	if s.DevAddr != "" && !strings.EqualFold(DevAddressToHex(d.DevAddr), s.DevAddr) {
+		return fmt.Errorf("expected dev addr %s, got %s", s.DevAddr, DevAddressToHex(d.DevAddr))
+	}
+	if s.UlFcnt != nil && d.UlFcnt != *s.UlFcnt {
+		return fmt.Errorf("expected uplink frame counter %d, got %d", *s.UlFcnt, d.UlFcnt)
+	}
+	if s.DlFcnt != nil && d.DlFcnt != *s.DlFcnt {
+		return fmt.Errorf("expected downlink frame counter %d, got %d", *s.DlFcnt, d.DlFcnt)
+	}
+	return nil
+}
@@ -0,0 +1,364 @@
+package lds
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/pkg/errors"
+)
+
+// sentUplink is an uplink sent through a fakeTarget.
+type sentUplink struct {
+	mType       lorawan.MType
+	fPort       uint8
+	payload     []byte
+	macCommands []*lorawan.MACCommand
+	fCtrl       lorawan.FCtrl
+}
+
+// fakeTarget is a scenario target that records the frames it's asked to send, and answers them with the downlinks
+// returned by the respond functions when set.
+type fakeTarget struct {
+	mu       sync.Mutex
+	device   Device
+	joins    int
+	uplinks  []sentUplink
+	resets   int
+	handlers []func(*Downlink)
+	err      error
+
+	joinAccept    func() *Downlink
+	uplinkAnswers func(up sentUplink) *Downlink
+}
+
+func (f *fakeTarget) Join() error {
+	f.mu.Lock()
+	f.joins++
+	f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	if f.joinAccept != nil {
+		f.deliver(f.joinAccept())
+	}
+	return nil
+}
+
+func (f *fakeTarget) Uplink(mType lorawan.MType, fPort uint8, payload []byte, macCommands []*lorawan.MACCommand, fCtrl lorawan.FCtrl) (uint32, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	up := sentUplink{mType, fPort, payload, macCommands, fCtrl}
+	f.mu.Lock()
+	f.uplinks = append(f.uplinks, up)
+	f.device.UlFcnt++
+	fCnt := f.device.UlFcnt
+	f.mu.Unlock()
+
+	if f.uplinkAnswers != nil {
+		if dl := f.uplinkAnswers(up); dl != nil {
+			f.deliver(dl)
+		}
+	}
+	return fCnt, nil
+}
+
+func (f *fakeTarget) Reset() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.resets++
+	f.device.UlFcnt = 0
+	return nil
+}
+
+func (f *fakeTarget) State() Device {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.device
+}
+
+func (f *fakeTarget) AddDownlinkHandler(h func(*Downlink)) func() {
+	f.mu.Lock()
+	f.handlers = append(f.handlers, h)
+	f.mu.Unlock()
+	return func() {
+		f.mu.Lock()
+		f.handlers = nil
+		f.mu.Unlock()
+	}
+}
+
+func (f *fakeTarget) deliver(dl *Downlink) {
+	f.mu.Lock()
+	handlers := append([]func(*Downlink){}, f.handlers...)
+	f.mu.Unlock()
+	for _, h := range handlers {
+		h(dl)
+	}
+}
+
+func uint32Ptr(v uint32) *uint32 { return &v }
+func boolPtr(v bool) *bool       { return &v }
+func intPtr(v int) *int          { return &v }
+
+func TestScenarioValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		step ScenarioStep
+		err  bool
+	}{
+		{"join", ScenarioStep{Action: StepJoin}, false},
+		{"uplink", ScenarioStep{Action: StepUplink, Payload: "0102"}, false},
+		{"uplink with an invalid payload", ScenarioStep{Action: StepUplink, Payload: "0g"}, true},
+		{"wait", ScenarioStep{Action: StepWait, Duration: Duration{time.Second}}, false},
+		{"wait without duration", ScenarioStep{Action: StepWait}, true},
+		{"no downlink without duration", ScenarioStep{Action: StepExpectNoDownlink}, true},
+		{"join accept without timeout", ScenarioStep{Action: StepExpectJoinAccept}, true},
+		{"downlink", ScenarioStep{Action: StepExpectDownlink, Timeout: Duration{time.Second}, MACCommand: "LinkADRReq", MACFields: map[string]interface{}{"dataRate": 5}}, false},
+		{"downlink without timeout", ScenarioStep{Action: StepExpectDownlink}, true},
+		{"downlink with an unknown command", ScenarioStep{Action: StepExpectDownlink, Timeout: Duration{time.Second}, MACCommand: "FooReq"}, true},
+		{"downlink fields without a command", ScenarioStep{Action: StepExpectDownlink, Timeout: Duration{time.Second}, MACFields: map[string]interface{}{"dataRate": 5}}, true},
+		{"mac commands", ScenarioStep{Action: StepMACCommands, MACCommands: []string{"LinkCheckReq", "0307"}}, false},
+		{"invalid mac commands", ScenarioStep{Action: StepMACCommands, MACCommands: []string{"LinkCheck"}}, true},
+		{"unknown action", ScenarioStep{Action: "sleep"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := tt.step
+			s := &Scenario{Steps: []*ScenarioStep{&step}}
+			if err := s.Validate(); (err != nil) != tt.err {
+				t.Errorf("got error %v, expected one: %t", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseMACCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []string
+		cids     []lorawan.CID
+		payloads []bool
+		err      bool
+	}{
+		{"by name", []string{"LinkCheckReq", "DeviceTimeReq"}, []lorawan.CID{lorawan.LinkCheckReq, lorawan.DeviceTimeReq}, []bool{false, false}, false},
+		{"hex encoded", []string{"0307"}, []lorawan.CID{lorawan.LinkADRAns}, []bool{true}, false},
+		{"unknown name", []string{"LinkCheck"}, nil, nil, true},
+		{"invalid hex", []string{"03g7"}, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands, err := ParseMACCommands(tt.commands)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if len(commands) != len(tt.cids) {
+				t.Fatalf("got %d commands, expected %d", len(commands), len(tt.cids))
+			}
+			for i, c := range commands {
+				if c.CID != tt.cids[i] || (c.Payload != nil) != tt.payloads[i] {
+					t.Errorf("command %d: got %s with payload %v", i, c.CID, c.Payload)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadScenario(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lds-scenario")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	valid := filepath.Join(dir, "valid.toml")
+	if err := ioutil.WriteFile(valid, []byte(`
+[[step]]
+action = "join"
+timeout = "5s"
+
+[[step]]
+action = "uplink"
+payload = "0102"
+fport = 3
+confirmed = true
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.toml")
+	if err := ioutil.WriteFile(invalid, []byte("[[step]]\naction = \"wait\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := LoadScenario(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != valid || len(s.Steps) != 2 {
+		t.Fatalf("got scenario %s with %d steps", s.Name, len(s.Steps))
+	}
+	if s.Steps[0].Timeout.Duration != 5*time.Second {
+		t.Errorf("got join timeout %s", s.Steps[0].Timeout)
+	}
+	if up := s.Steps[1]; !bytes.Equal(up.payload, []byte{1, 2}) || *up.FPort != 3 || !up.Confirmed || up.String() != "confirmed uplink" {
+		t.Errorf("got uplink step %+v", up)
+	}
+
+	if _, err := LoadScenario(invalid); err == nil {
+		t.Error("expected an error for a wait without duration")
+	}
+	if _, err := LoadScenario(filepath.Join(dir, "missing.toml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestScenarioRunner(t *testing.T) {
+	s := &Scenario{Name: "uplinks", Steps: []*ScenarioStep{
+		{Action: StepReset},
+		{Action: StepJoin},
+		{Action: StepUplink},
+		{Action: StepFCtrl, ADR: true, ACK: boolPtr(true)},
+		{Action: StepMACCommands, MACCommands: []string{"LinkCheckReq"}},
+		{Action: StepUplink, Payload: "0a", FPort: intPtr(7), Confirmed: true},
+		{Action: StepWait, Duration: Duration{time.Millisecond}},
+		{Action: StepAssert, UlFcnt: uint32Ptr(2)},
+	}}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	config := NewConfig()
+	config.RawPayload.UseRaw = true
+	config.RawPayload.Payload = "010203"
+	config.RawPayload.FPort = 2
+	target := &fakeTarget{}
+	runner := &ScenarioRunner{Target: target, Config: config}
+
+	result, err := runner.Run(context.Background(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "uplinks" || len(result.Steps) != len(s.Steps) || result.Failed() {
+		t.Fatalf("got result %+v", result)
+	}
+
+	if target.resets != 1 || target.joins != 1 || len(target.uplinks) != 2 {
+		t.Fatalf("got %d resets, %d joins and %d uplinks", target.resets, target.joins, len(target.uplinks))
+	}
+	first, second := target.uplinks[0], target.uplinks[1]
+	if first.mType != lorawan.UnconfirmedDataUp || first.fPort != 2 || !bytes.Equal(first.payload, []byte{1, 2, 3}) || len(first.macCommands) != 0 || first.fCtrl.ADR {
+		t.Errorf("got first uplink %+v, expected the configured data", first)
+	}
+	if second.mType != lorawan.ConfirmedDataUp || second.fPort != 7 || !bytes.Equal(second.payload, []byte{10}) {
+		t.Errorf("got second uplink %+v", second)
+	}
+	if !second.fCtrl.ADR || !second.fCtrl.ACK || len(second.macCommands) != 1 || second.macCommands[0].CID != lorawan.LinkCheckReq {
+		t.Errorf("got second uplink FCtrl %+v and mac commands %v", second.fCtrl, second.macCommands)
+	}
+	if len(target.handlers) != 0 {
+		t.Error("the downlink handler wasn't removed")
+	}
+}
+
+func TestScenarioRunnerFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		steps  []*ScenarioStep
+		target *fakeTarget
+		ctx    func() context.Context
+		failed int
+	}{
+		{
+			name:   "failed assertion",
+			steps:  []*ScenarioStep{{Action: StepAssert, Joined: boolPtr(true)}, {Action: StepReset}},
+			target: &fakeTarget{},
+			failed: 0,
+		},
+		{
+			name:   "failed uplink",
+			steps:  []*ScenarioStep{{Action: StepUplink, Payload: "01"}, {Action: StepReset}},
+			target: &fakeTarget{err: errors.New("not connected")},
+			failed: 0,
+		},
+		{
+			name:   "join accept not received",
+			steps:  []*ScenarioStep{{Action: StepJoin, Timeout: Duration{10 * time.Millisecond}}, {Action: StepUplink}},
+			target: &fakeTarget{},
+			failed: 0,
+		},
+		{
+			name:  "cancelled",
+			steps: []*ScenarioStep{{Action: StepWait, Duration: Duration{time.Hour}}, {Action: StepReset}},
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(10*time.Millisecond, cancel)
+				return ctx
+			},
+			target: &fakeTarget{},
+			failed: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx()
+			}
+			s := &Scenario{Name: tt.name, Steps: tt.steps}
+			if err := s.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := (&ScenarioRunner{Target: tt.target, Config: NewConfig()}).Run(ctx, s)
+			if err == nil || !result.Failed() {
+				t.Fatalf("got error %v, expected the scenario to fail", err)
+			}
+			for i, sr := range result.Steps {
+				if failed := sr.Err != nil; failed != (i == tt.failed) {
+					t.Errorf("step %d: got error %v", i+1, sr.Err)
+				}
+				if sr.Skipped != (i > tt.failed) {
+					t.Errorf("step %d: got skipped %t", i+1, sr.Skipped)
+				}
+			}
+			if tt.target.resets != 0 {
+				t.Error("a step after the failure ran")
+			}
+		})
+	}
+}
+
+func TestScenarioStepAssert(t *testing.T) {
+	d := Device{Joined: true, DevAddr: [4]byte{1, 2, 3, 4}, UlFcnt: 3, DlFcnt: 1}
+
+	tests := []struct {
+		name string
+		step ScenarioStep
+		err  bool
+	}{
+		{"nothing", ScenarioStep{}, false},
+		{"every field", ScenarioStep{Joined: boolPtr(true), DevAddr: "01020304", UlFcnt: uint32Ptr(3), DlFcnt: uint32Ptr(1)}, false},
+		{"upper case dev addr", ScenarioStep{DevAddr: "0A0B0C0D"}, true},
+		{"not joined", ScenarioStep{Joined: boolPtr(false)}, true},
+		{"uplink counter", ScenarioStep{UlFcnt: uint32Ptr(2)}, true},
+		{"downlink counter", ScenarioStep{DlFcnt: uint32Ptr(0)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.step.assert(d); (err != nil) != tt.err {
+				t.Errorf("got error %v, expected one: %t", err, tt.err)
+			}
+		})
+	}
+}
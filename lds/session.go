@@ -0,0 +1,35 @@
+package lds
+
+// Session is the device session state: address, frame counters, nonces and session keys.
+type Session struct {
+	DevEUI      string `json:"devEUI"`
+	Profile     string `json:"profile"`
+	Joined      bool   `json:"joined"`
+	DevAddr     string `json:"devAddr"`
+	UlFcnt      uint32 `json:"ulFcnt"`
+	DlFcnt      uint32 `json:"dlFcnt"`
+	DevNonce    uint16 `json:"devNonce"`
+	JoinNonce   uint32 `json:"joinNonce"`
+	NwkSEncKey  string `json:"nwkSEncKey"`
+	SNwkSIntKey string `json:"sNwkSIntKey"`
+	FNwkSIntKey string `json:"fNwkSIntKey"`
+	AppSKey     string `json:"appSKey"`
+}
+
+// NewSession returns the session of the device.
+func NewSession(d *Device) *Session {
+	return &Session{
+		DevEUI:      d.DevEUI.String(),
+		Profile:     d.Profile,
+		Joined:      d.Joined,
+		DevAddr:     DevAddressToHex(d.DevAddr),
+		UlFcnt:      d.UlFcnt,
+		DlFcnt:      d.DlFcnt,
+		DevNonce:    uint16(d.DevNonce),
+		JoinNonce:   uint32(d.JoinNonce),
+		NwkSEncKey:  KeyToHex(d.NwkSEncKey),
+		SNwkSIntKey: KeyToHex(d.SNwkSIntKey),
+		FNwkSIntKey: KeyToHex(d.FNwkSIntKey),
+		AppSKey:     KeyToHex(d.AppSKey),
+	}
+}
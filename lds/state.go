@@ -0,0 +1,38 @@
+package lds
+
+import (
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/iegomez/lds/api/gwv3"
+	log "github.com/sirupsen/logrus"
+)
+
+// ConnState returns the connection state message of the given gateway, marshaled for the device's format.
+// It's meant to be used both when connecting and as the MQTT Last Will, so that network servers see the gateway go offline.
+func (d *Device) ConnState(gwMAC string, online bool) ([]byte, error) {
+	gatewayID, err := MACToGatewayID(gwMAC)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &gwv3.ConnState{
+		GatewayId: gatewayID,
+		State:     gwv3.ConnState_OFFLINE,
+	}
+	if online {
+		state.State = gwv3.ConnState_ONLINE
+	}
+
+	return d.marshal(state)
+}
+
+// PublishConnState publishes the connection state of the given gateway as a retained message.
+func (d *Device) PublishConnState(client MQTT.Client, topicTemplate, gwMAC string, online bool) error {
+	b, err := d.ConnState(gwMAC, online)
+	if err != nil {
+		log.Errorf("error marshaling connection state: %s", err)
+		return err
+	}
+
+	//Retained, so that clients subscribing later still get the last known state.
+	return publishWith(client, FormatTopic(topicTemplate, gwMAC), mqttQoS, true, b)
+}
@@ -0,0 +1,82 @@
+package lds
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/iegomez/lds/api/gwv3"
+)
+
+func TestConnState(t *testing.T) {
+	tests := []struct {
+		name   string
+		gwMAC  string
+		online bool
+		state  gwv3.ConnState_State
+		err    bool
+	}{
+		{"online", "0102030405060708", true, gwv3.ConnState_ONLINE, false},
+		{"offline", "0102030405060708", false, gwv3.ConnState_OFFLINE, false},
+		{"invalid MAC", "gateway", true, 0, true},
+	}
+
+	d := &Device{}
+	d.SetMarshaler("protobuf")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := d.ConnState(tt.gwMAC, tt.online)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			var state gwv3.ConnState
+			if err := proto.Unmarshal(b, &state); err != nil {
+				t.Fatal(err)
+			}
+			expected := &gwv3.ConnState{GatewayId: testGatewayID, State: tt.state}
+			if !proto.Equal(&state, expected) {
+				t.Errorf("got %+v, expected %+v", &state, expected)
+			}
+		})
+	}
+}
+
+func TestPublishConnState(t *testing.T) {
+	if err := SetQoS(1); err != nil {
+		t.Fatal(err)
+	}
+	defer SetQoS(0)
+
+	d := &Device{}
+	d.SetMarshaler("json")
+	client := &recordingClient{}
+
+	if err := d.PublishConnState(client, "gateway/%s/state/conn", "0102030405060708", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.PublishConnState(client, "gateway/%s/state/conn", "gateway", true); err == nil {
+		t.Error("expected an error for an invalid MAC")
+	}
+
+	messages := client.messages()
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, expected 1", len(messages))
+	}
+	m := messages[0]
+	if m.topic != "gateway/0102030405060708/state/conn" || m.qos != 1 || !m.retained {
+		t.Errorf("got message on %s with QoS %d, retained: %t", m.topic, m.qos, m.retained)
+	}
+
+	var state gwv3.ConnState
+	if err := d.unmarshal(m.payload, &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.GetState() != gwv3.ConnState_ONLINE {
+		t.Errorf("got state %s", state.GetState())
+	}
+}
@@ -0,0 +1,49 @@
+package lds
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// NewTLSConfig returns the TLS configuration for an MQTT broker connection, or nil when no option is set and TLS is left to the broker URL scheme.
+// caCert, tlsCert and tlsKey are paths to PEM encoded files. The client cert and key must be given together, or an error is returned.
+func NewTLSConfig(caCert, tlsCert, tlsKey string, insecureSkipVerify bool, alpn []string) (*tls.Config, error) {
+	if caCert == "" && tlsCert == "" && tlsKey == "" && !insecureSkipVerify && len(alpn) == 0 {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify,
+		NextProtos:         alpn,
+	}
+
+	if caCert != "" {
+		rawCACert, err := ioutil.ReadFile(caCert)
+		if err != nil {
+			return nil, errors.Wrap(err, "read ca cert error")
+		}
+
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(rawCACert) {
+			return nil, errors.Errorf("no certificates found in ca cert %s", caCert)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	if tlsCert != "" || tlsKey != "" {
+		if tlsCert == "" || tlsKey == "" {
+			return nil, errors.New("both tls cert and key must be set for client certificate authentication")
+		}
+
+		cert, err := tls.LoadX509KeyPair(tlsCert, tlsKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "load client cert/key error")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
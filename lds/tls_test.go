@@ -0,0 +1,162 @@
+package lds
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertificate writes a self-signed certificate and its key as PEM files in dir, returning their paths.
+func writeCertificate(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "lds"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lds-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cert, key := writeCertificate(t, dir)
+	notPEM := filepath.Join(dir, "not.pem")
+	if err := ioutil.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name               string
+		caCert             string
+		tlsCert            string
+		tlsKey             string
+		insecureSkipVerify bool
+		alpn               []string
+		nilConfig          bool
+		rootCAs            bool
+		certificates       int
+		err                bool
+	}{
+		{name: "no options", nilConfig: true},
+		{name: "insecure", insecureSkipVerify: true},
+		{name: "ALPN", alpn: []string{"x-amzn-mqtt-ca"}},
+		{name: "CA certificate", caCert: cert, rootCAs: true},
+		{name: "client certificate", caCert: cert, tlsCert: cert, tlsKey: key, rootCAs: true, certificates: 1},
+		{name: "missing CA certificate", caCert: filepath.Join(dir, "missing.pem"), err: true},
+		{name: "CA certificate without certificates", caCert: notPEM, err: true},
+		{name: "client certificate without key", tlsCert: cert, err: true},
+		{name: "client key without certificate", tlsKey: key, err: true},
+		{name: "client key not matching", tlsCert: cert, tlsKey: notPEM, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := NewTLSConfig(tt.caCert, tt.tlsCert, tt.tlsKey, tt.insecureSkipVerify, tt.alpn)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if (tlsConfig == nil) != tt.nilConfig {
+				t.Fatalf("got config %+v, expected nil: %t", tlsConfig, tt.nilConfig)
+			}
+			if tlsConfig == nil {
+				return
+			}
+
+			if tlsConfig.InsecureSkipVerify != tt.insecureSkipVerify || len(tlsConfig.NextProtos) != len(tt.alpn) {
+				t.Errorf("got insecure %t and ALPN %v", tlsConfig.InsecureSkipVerify, tlsConfig.NextProtos)
+			}
+			if (tlsConfig.RootCAs != nil) != tt.rootCAs || len(tlsConfig.Certificates) != tt.certificates {
+				t.Errorf("got root CAs %v and %d certificates", tlsConfig.RootCAs, len(tlsConfig.Certificates))
+			}
+		})
+	}
+}
+
+func TestSetQoS(t *testing.T) {
+	defer SetQoS(0)
+
+	tests := []struct {
+		qos int
+		err bool
+	}{
+		{0, false},
+		{1, false},
+		{2, false},
+		{3, true},
+		{-1, true},
+	}
+
+	for _, tt := range tests {
+		err := SetQoS(tt.qos)
+		if (err != nil) != tt.err {
+			t.Errorf("%d: got error %v, expected one: %t", tt.qos, err, tt.err)
+			continue
+		}
+		if err == nil && mqttQoS != byte(tt.qos) {
+			t.Errorf("%d: got QoS %d", tt.qos, mqttQoS)
+		}
+	}
+	if mqttQoS != 2 {
+		t.Errorf("an invalid QoS changed the QoS to %d", mqttQoS)
+	}
+}
+
+func TestPublishQoS(t *testing.T) {
+	defer SetQoS(0)
+	if err := SetQoS(2); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &recordingClient{}
+	if err := publish(client, "gateway/0102030405060708/event/up", []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if m := client.messages(); len(m) != 1 || m[0].qos != 2 || m[0].retained {
+		t.Errorf("got messages %+v, expected one with QoS 2", m)
+	}
+
+	client.err = os.ErrClosed
+	if err := publish(client, "gateway/0102030405060708/event/up", []byte{1}); err != os.ErrClosed {
+		t.Errorf("got error %v, expected the publish error", err)
+	}
+}
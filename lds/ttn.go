@@ -0,0 +1,227 @@
+package lds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/iegomez/lds/api/gwv3"
+	"github.com/iegomez/lds/api/ttnpb"
+)
+
+// keepTTNDownlinks is how many downlinks are remembered to put their correlation IDs in the TX acks.
+const keepTTNDownlinks = 64
+
+// ttnDownlinks holds the correlation IDs of the last downlinks by the token assigned to them,
+// as The Things Stack matches TX acks with their downlinks by correlation IDs.
+var ttnDownlinks = struct {
+	sync.Mutex
+	token          uint32
+	correlationIDs map[uint32][]string
+}{correlationIDs: make(map[uint32][]string)}
+
+// marshalTTN encodes a message in The Things Stack gateway MQTT protobuf format.
+func marshalTTN(msg proto.Message) ([]byte, error) {
+	var ttn proto.Message
+	switch m := msg.(type) {
+	case *gw.UplinkFrame:
+		ttn = uplinkFrameToTTN(m)
+	case *gw.GatewayStats:
+		ttn = statsToTTN(m)
+	case *gwv3.DownlinkTXAck:
+		ttn = ackToTTN(m)
+	default:
+		return nil, fmt.Errorf("ttn_protobuf: can't marshal %T", msg)
+	}
+	return proto.Marshal(ttn)
+}
+
+// unmarshalTTN decodes a message in The Things Stack gateway MQTT protobuf format.
+func unmarshalTTN(b []byte, msg proto.Message) error {
+	switch m := msg.(type) {
+	case *gwv3.DownlinkFrame:
+		var down ttnpb.GatewayDown
+		if err := proto.Unmarshal(b, &down); err != nil {
+			return err
+		}
+		return ttnToDownlinkFrame(down.GetDownlinkMessage(), m)
+	default:
+		return fmt.Errorf("ttn_protobuf: can't unmarshal %T", msg)
+	}
+}
+
+func uplinkFrameToTTN(f *gw.UplinkFrame) *ttnpb.UplinkMessage {
+	rx := f.GetRxInfo()
+	tx := f.GetTxInfo()
+
+	var timestamp uint32
+	if len(rx.GetContext()) == 4 {
+		timestamp = binary.BigEndian.Uint32(rx.GetContext())
+	}
+
+	settings := &ttnpb.TxSettings{
+		Frequency: uint64(tx.GetFrequency()),
+		EnableCrc: true,
+		Timestamp: timestamp,
+		Time:      rx.GetTime(),
+		DataRate:  &ttnpb.DataRate{},
+	}
+	if lora := tx.GetLoraModulationInfo(); lora != nil {
+		settings.CodingRate = lora.GetCodeRate()
+		settings.DataRate.Modulation = &ttnpb.DataRate_Lora{
+			Lora: &ttnpb.LoRaDataRate{
+				//TTN bandwidths are in Hz.
+				Bandwidth:       lora.GetBandwidth() * 1000,
+				SpreadingFactor: lora.GetSpreadingFactor(),
+				CodingRate:      lora.GetCodeRate(),
+			},
+		}
+	} else if fsk := tx.GetFskModulationInfo(); fsk != nil {
+		settings.DataRate.Modulation = &ttnpb.DataRate_Fsk{
+			Fsk: &ttnpb.FSKDataRate{
+				BitRate: fsk.GetBitrate(),
+			},
+		}
+	}
+
+	return &ttnpb.UplinkMessage{
+		RawPayload: f.GetPhyPayload(),
+		Settings:   settings,
+		RxMetadata: []*ttnpb.RxMetadata{
+			{
+				GatewayIds:   &ttnpb.GatewayIdentifiers{Eui: rx.GetGatewayId()},
+				AntennaIndex: rx.GetAntenna(),
+				Time:         rx.GetTime(),
+				Timestamp:    timestamp,
+				Rssi:         float32(rx.GetRssi()),
+				ChannelRssi:  float32(rx.GetRssi()),
+				Snr:          float32(rx.GetLoraSnr()),
+				ChannelIndex: rx.GetChannel(),
+			},
+		},
+		ReceivedAt: rx.GetTime(),
+	}
+}
+
+// statsToTTN converts the gateway stats to a status with the metrics the Gateway Server gets from packet forwarder stats.
+func statsToTTN(s *gw.GatewayStats) *ttnpb.GatewayStatus {
+	status := &ttnpb.GatewayStatus{
+		Time: s.GetTime(),
+		Metrics: map[string]float32{
+			"rxin": float32(s.GetRxPacketsReceived()),
+			"rxok": float32(s.GetRxPacketsReceivedOk()),
+			"txin": float32(s.GetTxPacketsReceived()),
+			"txok": float32(s.GetTxPacketsEmitted()),
+		},
+	}
+	if s.GetConfigVersion() != "" {
+		status.Versions = map[string]string{"config": s.GetConfigVersion()}
+	}
+
+	return status
+}
+
+// ackToTTN converts a TX ack. The Things Stack only expects the result of the emitted item, or the first error otherwise.
+func ackToTTN(a *gwv3.DownlinkTXAck) *ttnpb.TxAcknowledgment {
+	ttnDownlinks.Lock()
+	correlationIDs := ttnDownlinks.correlationIDs[a.GetToken()]
+	ttnDownlinks.Unlock()
+
+	ack := &ttnpb.TxAcknowledgment{
+		CorrelationIds: correlationIDs,
+		Result:         ttnpb.TxAcknowledgment_UNKNOWN_ERROR,
+	}
+	firstError := true
+	for _, item := range a.GetItems() {
+		switch status := item.GetStatus(); {
+		case status == gwv3.TxAckStatus_OK:
+			ack.Result = ttnpb.TxAcknowledgment_SUCCESS
+			return ack
+		case status == gwv3.TxAckStatus_IGNORED || !firstError:
+		default:
+			firstError = false
+			//Both versions share the status values from TOO_LATE to GPS_UNLOCKED.
+			if _, ok := ttnpb.TxAcknowledgment_Result_name[int32(status)]; ok {
+				ack.Result = ttnpb.TxAcknowledgment_Result(status)
+			}
+		}
+	}
+	return ack
+}
+
+// ttnToDownlinkFrame converts a downlink scheduled by the Gateway Server to a single item downlink frame.
+// Like v2 downlinks, they are scheduled at a concentrator counter value, which is carried in the context with a zero delay.
+func ttnToDownlinkFrame(dl *ttnpb.DownlinkMessage, f *gwv3.DownlinkFrame) error {
+	settings := dl.GetScheduled()
+	if settings == nil {
+		return fmt.Errorf("ttn_protobuf: downlink isn't scheduled")
+	}
+
+	txInfo := &gw.DownlinkTXInfo{
+		Frequency: uint32(settings.GetFrequency()),
+		Power:     int32(math.Round(float64(settings.GetDownlink().GetTxPower()))),
+		Antenna:   settings.GetDownlink().GetAntennaIndex(),
+	}
+
+	dr := settings.GetDataRate()
+	switch {
+	case dr.GetLora() != nil:
+		codeRate := dr.GetLora().GetCodingRate()
+		if codeRate == "" {
+			codeRate = settings.GetCodingRate()
+		}
+		txInfo.ModulationInfo = &gw.DownlinkTXInfo_LoraModulationInfo{
+			LoraModulationInfo: &gw.LoRaModulationInfo{
+				Bandwidth:             dr.GetLora().GetBandwidth() / 1000,
+				SpreadingFactor:       dr.GetLora().GetSpreadingFactor(),
+				CodeRate:              codeRate,
+				PolarizationInversion: settings.GetDownlink().GetInvertPolarization(),
+			},
+		}
+	case dr.GetFsk() != nil:
+		txInfo.ModulationInfo = &gw.DownlinkTXInfo_FskModulationInfo{
+			FskModulationInfo: &gw.FSKModulationInfo{
+				Bitrate: dr.GetFsk().GetBitRate(),
+			},
+		}
+	default:
+		return fmt.Errorf("ttn_protobuf: unsupported downlink data rate %v", dr)
+	}
+
+	if settings.GetTimestamp() != 0 {
+		txInfo.Timing = gw.DownlinkTiming_DELAY
+		txInfo.TimingInfo = &gw.DownlinkTXInfo_DelayTimingInfo{
+			DelayTimingInfo: &gw.DelayTimingInfo{
+				Delay: ptypes.DurationProto(0),
+			},
+		}
+		txInfo.Context = make([]byte, 4)
+		binary.BigEndian.PutUint32(txInfo.Context, settings.GetTimestamp())
+	} else {
+		txInfo.Timing = gw.DownlinkTiming_IMMEDIATELY
+		txInfo.TimingInfo = &gw.DownlinkTXInfo_ImmediatelyTimingInfo{
+			ImmediatelyTimingInfo: &gw.ImmediatelyTimingInfo{},
+		}
+	}
+
+	//Downlinks have no token, so one is assigned to find their correlation IDs when acking them.
+	ttnDownlinks.Lock()
+	ttnDownlinks.token++
+	token := ttnDownlinks.token
+	ttnDownlinks.correlationIDs[token] = dl.GetCorrelationIds()
+	delete(ttnDownlinks.correlationIDs, token-keepTTNDownlinks)
+	ttnDownlinks.Unlock()
+
+	f.Token = token
+	f.Items = []*gwv3.DownlinkFrameItem{
+		{
+			PhyPayload: dl.GetRawPayload(),
+			TxInfo:     txInfo,
+		},
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package lds
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/iegomez/lds/api/gwv3"
+	"github.com/iegomez/lds/api/ttnpb"
+)
+
+func TestMarshalTTNUplink(t *testing.T) {
+	rxInfo := &gw.UplinkRXInfo{
+		GatewayId: testGatewayID,
+		Rssi:      -60,
+		LoraSnr:   7.5,
+		Channel:   2,
+		Antenna:   1,
+		Context:   []byte{0, 0x0f, 0x42, 0x40},
+	}
+	rxInfo.Time, _ = ptypes.TimestampProto(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	b, err := marshalTTN(&gw.UplinkFrame{PhyPayload: []byte{1, 2, 3}, RxInfo: rxInfo, TxInfo: loraTXInfo(868100000, 7)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var up ttnpb.UplinkMessage
+	if err := proto.Unmarshal(b, &up); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(up.GetRawPayload(), []byte{1, 2, 3}) || !proto.Equal(up.GetReceivedAt(), rxInfo.GetTime()) {
+		t.Errorf("got payload %x received at %v", up.GetRawPayload(), up.GetReceivedAt())
+	}
+	expected := &ttnpb.TxSettings{
+		Frequency:  868100000,
+		EnableCrc:  true,
+		Timestamp:  1000000,
+		Time:       rxInfo.GetTime(),
+		CodingRate: "4/5",
+		DataRate: &ttnpb.DataRate{Modulation: &ttnpb.DataRate_Lora{
+			Lora: &ttnpb.LoRaDataRate{Bandwidth: 125000, SpreadingFactor: 7, CodingRate: "4/5"},
+		}},
+	}
+	if !proto.Equal(up.GetSettings(), expected) {
+		t.Errorf("got settings %+v, expected %+v", up.GetSettings(), expected)
+	}
+	if len(up.GetRxMetadata()) != 1 {
+		t.Fatalf("got metadata %+v", up.GetRxMetadata())
+	}
+	md := up.GetRxMetadata()[0]
+	if !bytes.Equal(md.GetGatewayIds().GetEui(), testGatewayID) || md.GetTimestamp() != 1000000 || md.GetAntennaIndex() != 1 || md.GetChannelIndex() != 2 {
+		t.Errorf("got metadata %+v", md)
+	}
+	if md.GetRssi() != -60 || md.GetChannelRssi() != -60 || md.GetSnr() != 7.5 {
+		t.Errorf("got metadata %+v", md)
+	}
+}
+
+func TestMarshalTTNStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		stats    *gw.GatewayStats
+		versions map[string]string
+	}{
+		{"without configuration", &gw.GatewayStats{RxPacketsReceived: 3, RxPacketsReceivedOk: 2, TxPacketsReceived: 2, TxPacketsEmitted: 1}, nil},
+		{"configured", &gw.GatewayStats{RxPacketsReceived: 3, RxPacketsReceivedOk: 2, TxPacketsReceived: 2, TxPacketsEmitted: 1, ConfigVersion: "4"}, map[string]string{"config": "4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := marshalTTN(tt.stats)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var status ttnpb.GatewayStatus
+			if err := proto.Unmarshal(b, &status); err != nil {
+				t.Fatal(err)
+			}
+			expected := &ttnpb.GatewayStatus{
+				Metrics:  map[string]float32{"rxin": 3, "rxok": 2, "txin": 2, "txok": 1},
+				Versions: tt.versions,
+			}
+			if !proto.Equal(&status, expected) {
+				t.Errorf("got %+v, expected %+v", &status, expected)
+			}
+		})
+	}
+}
+
+func TestMarshalTTNAck(t *testing.T) {
+	ack := func(statuses ...gwv3.TxAckStatus) *gwv3.DownlinkTXAck {
+		a := &gwv3.DownlinkTXAck{}
+		for _, status := range statuses {
+			a.Items = append(a.Items, &gwv3.DownlinkTXAckItem{Status: status})
+		}
+		return a
+	}
+
+	tests := []struct {
+		name   string
+		ack    *gwv3.DownlinkTXAck
+		result ttnpb.TxAcknowledgment_Result
+	}{
+		{"emitted", ack(gwv3.TxAckStatus_OK), ttnpb.TxAcknowledgment_SUCCESS},
+		{"emitted after an error", ack(gwv3.TxAckStatus_TOO_LATE, gwv3.TxAckStatus_OK), ttnpb.TxAcknowledgment_SUCCESS},
+		{"first error", ack(gwv3.TxAckStatus_TX_FREQ, gwv3.TxAckStatus_TX_POWER), ttnpb.TxAcknowledgment_TX_FREQ},
+		{"error after ignored items", ack(gwv3.TxAckStatus_IGNORED, gwv3.TxAckStatus_GPS_UNLOCKED), ttnpb.TxAcknowledgment_GPS_UNLOCKED},
+		{"error without a TTN result", ack(gwv3.TxAckStatus_QUEUE_FULL), ttnpb.TxAcknowledgment_UNKNOWN_ERROR},
+		{"no items", ack(), ttnpb.TxAcknowledgment_UNKNOWN_ERROR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := marshalTTN(tt.ack)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ack ttnpb.TxAcknowledgment
+			if err := proto.Unmarshal(b, &ack); err != nil {
+				t.Fatal(err)
+			}
+			if ack.GetResult() != tt.result {
+				t.Errorf("got %s, expected %s", ack.GetResult(), tt.result)
+			}
+		})
+	}
+}
+
+func TestMarshalTTNUnsupported(t *testing.T) {
+	if _, err := marshalTTN(&gwv3.ConnState{}); err == nil {
+		t.Error("expected an error for a message without a TTN format")
+	}
+	if err := unmarshalTTN(nil, &gw.GatewayConfiguration{}); err == nil {
+		t.Error("expected an error for a message without a TTN format")
+	}
+}
+
+func TestUnmarshalTTNDownlink(t *testing.T) {
+	lora := &ttnpb.DataRate{Modulation: &ttnpb.DataRate_Lora{Lora: &ttnpb.LoRaDataRate{Bandwidth: 125000, SpreadingFactor: 7}}}
+	fsk := &ttnpb.DataRate{Modulation: &ttnpb.DataRate_Fsk{Fsk: &ttnpb.FSKDataRate{BitRate: 50000}}}
+	settings := func(dr *ttnpb.DataRate, timestamp uint32) *ttnpb.TxSettings {
+		return &ttnpb.TxSettings{
+			DataRate:   dr,
+			CodingRate: "4/5",
+			Frequency:  868100000,
+			Timestamp:  timestamp,
+			Downlink:   &ttnpb.TxSettings_Downlink{TxPower: 14.2, AntennaIndex: 1, InvertPolarization: true},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		settings *ttnpb.TxSettings
+		timing   gw.DownlinkTiming
+		context  []byte
+		err      bool
+	}{
+		{"LoRa at a timestamp", settings(lora, 2000000), gw.DownlinkTiming_DELAY, []byte{0, 0x1e, 0x84, 0x80}, false},
+		{"FSK immediately", settings(fsk, 0), gw.DownlinkTiming_IMMEDIATELY, nil, false},
+		{"no data rate", settings(&ttnpb.DataRate{}, 2000000), 0, nil, true},
+		{"not scheduled", nil, 0, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dl := &ttnpb.DownlinkMessage{RawPayload: []byte{1, 2, 3}, CorrelationIds: []string{"gs:downlink:" + tt.name}}
+			if tt.settings != nil {
+				dl.Settings = &ttnpb.DownlinkMessage_Scheduled{Scheduled: tt.settings}
+			}
+			b, err := proto.Marshal(&ttnpb.GatewayDown{DownlinkMessage: dl})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var df gwv3.DownlinkFrame
+			err = unmarshalTTN(b, &df)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, expected one: %t", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(df.GetItems()) != 1 || !bytes.Equal(df.GetItems()[0].GetPhyPayload(), []byte{1, 2, 3}) {
+				t.Fatalf("got items %+v", df.GetItems())
+			}
+			txInfo := df.GetItems()[0].GetTxInfo()
+			if txInfo.GetFrequency() != 868100000 || txInfo.GetPower() != 14 || txInfo.GetAntenna() != 1 {
+				t.Errorf("got tx info %+v", txInfo)
+			}
+			if txInfo.GetTiming() != tt.timing || !bytes.Equal(txInfo.GetContext(), tt.context) {
+				t.Errorf("got timing %s and context %x, expected %s and %x", txInfo.GetTiming(), txInfo.GetContext(), tt.timing, tt.context)
+			}
+			if lora := txInfo.GetLoraModulationInfo(); lora != nil {
+				expected := &gw.LoRaModulationInfo{Bandwidth: 125, SpreadingFactor: 7, CodeRate: "4/5", PolarizationInversion: true}
+				if !proto.Equal(lora, expected) {
+					t.Errorf("got modulation %+v, expected %+v", lora, expected)
+				}
+			} else if txInfo.GetFskModulationInfo().GetBitrate() != 50000 {
+				t.Errorf("got modulation %+v", txInfo.GetModulationInfo())
+			}
+
+			//The ack of the downlink carries its correlation IDs.
+			b, err = marshalTTN(&gwv3.DownlinkTXAck{Token: df.GetToken(), Items: []*gwv3.DownlinkTXAckItem{{Status: gwv3.TxAckStatus_OK}}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ack ttnpb.TxAcknowledgment
+			if err := proto.Unmarshal(b, &ack); err != nil {
+				t.Fatal(err)
+			}
+			if len(ack.GetCorrelationIds()) != 1 || ack.GetCorrelationIds()[0] != dl.GetCorrelationIds()[0] {
+				t.Errorf("got correlation IDs %v, expected %v", ack.GetCorrelationIds(), dl.GetCorrelationIds())
+			}
+		})
+	}
+}
+
+func TestTTNDownlinksForgotten(t *testing.T) {
+	var first uint32
+	for i := 0; i <= keepTTNDownlinks; i++ {
+		dl := &ttnpb.DownlinkMessage{
+			CorrelationIds: []string{"gs:downlink"},
+			Settings:       &ttnpb.DownlinkMessage_Scheduled{Scheduled: &ttnpb.TxSettings{DataRate: &ttnpb.DataRate{Modulation: &ttnpb.DataRate_Fsk{Fsk: &ttnpb.FSKDataRate{BitRate: 50000}}}}},
+		}
+		var df gwv3.DownlinkFrame
+		if err := ttnToDownlinkFrame(dl, &df); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			first = df.GetToken()
+		}
+	}
+
+	if ack := ackToTTN(&gwv3.DownlinkTXAck{Token: first}); len(ack.GetCorrelationIds()) != 0 {
+		t.Errorf("got correlation IDs %v for a downlink older than the last %d", ack.GetCorrelationIds(), keepTTNDownlinks)
+	}
+	if ack := ackToTTN(&gwv3.DownlinkTXAck{Token: first + 1}); len(ack.GetCorrelationIds()) != 1 {
+		t.Errorf("got correlation IDs %v for one of the last %d downlinks", ack.GetCorrelationIds(), keepTTNDownlinks)
+	}
+}
@@ -0,0 +1,320 @@
+package lds
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/common"
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/band"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/iegomez/lds/api/gwv3"
+)
+
+// v2RXPacket is the uplink published by the LoRa Gateway Bridge v2 (gateway/<mac>/rx).
+type v2RXPacket struct {
+	RXInfo     v2RXInfo `json:"rxInfo"`
+	PHYPayload []byte   `json:"phyPayload"`
+}
+
+type v2RXInfo struct {
+	MAC               lorawan.EUI64 `json:"mac"`
+	Time              *time.Time    `json:"time,omitempty"`
+	TimeSinceGPSEpoch *v2Duration   `json:"timeSinceGPSEpoch,omitempty"`
+	Timestamp         uint32        `json:"timestamp"`
+	Frequency         int           `json:"frequency"`
+	Channel           int           `json:"channel"`
+	RFChain           int           `json:"rfChain"`
+	CRCStatus         int           `json:"crcStatus"`
+	CodeRate          string        `json:"codeRate"`
+	RSSI              int           `json:"rssi"`
+	LoRaSNR           float64       `json:"loRaSNR"`
+	Size              int           `json:"size"`
+	DataRate          band.DataRate `json:"dataRate"`
+	Board             int           `json:"board"`
+	Antenna           int           `json:"antenna"`
+}
+
+// v2TXPacket is the downlink consumed by the LoRa Gateway Bridge v2 (gateway/<mac>/tx).
+type v2TXPacket struct {
+	Token      uint16   `json:"token"`
+	TXInfo     v2TXInfo `json:"txInfo"`
+	PHYPayload []byte   `json:"phyPayload"`
+}
+
+type v2TXInfo struct {
+	MAC               lorawan.EUI64 `json:"mac"`
+	Immediately       bool          `json:"immediately"`
+	TimeSinceGPSEpoch *v2Duration   `json:"timeSinceGPSEpoch,omitempty"`
+	Timestamp         *uint32       `json:"timestamp,omitempty"`
+	Frequency         int           `json:"frequency"`
+	Power             int           `json:"power"`
+	DataRate          band.DataRate `json:"dataRate"`
+	CodeRate          string        `json:"codeRate"`
+	IPol              *bool         `json:"iPol"`
+	Board             int           `json:"board"`
+	Antenna           int           `json:"antenna"`
+}
+
+// v2StatsPacket is the gateway stats published by the LoRa Gateway Bridge v2 (gateway/<mac>/stats).
+type v2StatsPacket struct {
+	MAC                 lorawan.EUI64 `json:"mac"`
+	Time                time.Time     `json:"time"`
+	RXPacketsReceived   int           `json:"rxPacketsReceived"`
+	RXPacketsReceivedOK int           `json:"rxPacketsReceivedOK"`
+	TXPacketsReceived   int           `json:"txPacketsReceived"`
+	TXPacketsEmitted    int           `json:"txPacketsEmitted"`
+	ConfigVersion       string        `json:"configVersion,omitempty"`
+}
+
+// v2TXAck is the downlink ack published by the LoRa Gateway Bridge v2 (gateway/<mac>/ack).
+type v2TXAck struct {
+	MAC   lorawan.EUI64 `json:"mac"`
+	Token uint16        `json:"token"`
+	Error string        `json:"error"`
+}
+
+// v2ConfigPacket is the gateway configuration consumed by the LoRa Gateway Bridge v2 (gateway/<mac>/config).
+type v2ConfigPacket struct {
+	MAC      lorawan.EUI64 `json:"mac"`
+	Version  string        `json:"version"`
+	Channels []v2Channel   `json:"channels"`
+}
+
+type v2Channel struct {
+	Modulation       band.Modulation `json:"modulation"`
+	Frequency        int             `json:"frequency"`
+	Bandwidth        int             `json:"bandwidth"`
+	Bitrate          int             `json:"bitrate,omitempty"`
+	SpreadingFactors []int           `json:"spreadingFactors,omitempty"`
+}
+
+// v2Duration is a duration encoded as a string ("1.5s") like the v2 bridge did.
+type v2Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d v2Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *v2Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	dur, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = v2Duration(dur)
+	return nil
+}
+
+// marshalV2 encodes a message in the legacy LoRa Gateway Bridge v2 JSON format.
+func marshalV2(msg proto.Message) ([]byte, error) {
+	switch m := msg.(type) {
+	case *gw.UplinkFrame:
+		return json.Marshal(uplinkFrameToV2(m))
+	case *gw.GatewayStats:
+		return json.Marshal(statsToV2(m))
+	case *gwv3.DownlinkTXAck:
+		ack := v2TXAck{
+			Token: uint16(m.GetToken()),
+			Error: m.GetError(),
+		}
+		copy(ack.MAC[:], m.GetGatewayId())
+		return json.Marshal(ack)
+	default:
+		return nil, fmt.Errorf("v2_json: can't marshal %T", msg)
+	}
+}
+
+// unmarshalV2 decodes a message in the legacy LoRa Gateway Bridge v2 JSON format.
+func unmarshalV2(b []byte, msg proto.Message) error {
+	switch m := msg.(type) {
+	case *gwv3.DownlinkFrame:
+		var pl v2TXPacket
+		if err := json.Unmarshal(b, &pl); err != nil {
+			return err
+		}
+		v2ToDownlinkFrame(&pl, m)
+		return nil
+	case *gw.GatewayConfiguration:
+		var pl v2ConfigPacket
+		if err := json.Unmarshal(b, &pl); err != nil {
+			return err
+		}
+		v2ToGatewayConfiguration(&pl, m)
+		return nil
+	default:
+		return fmt.Errorf("v2_json: can't unmarshal %T", msg)
+	}
+}
+
+func uplinkFrameToV2(f *gw.UplinkFrame) *v2RXPacket {
+	rx := f.GetRxInfo()
+	tx := f.GetTxInfo()
+
+	pl := &v2RXPacket{
+		PHYPayload: f.GetPhyPayload(),
+		RXInfo: v2RXInfo{
+			Frequency: int(tx.GetFrequency()),
+			Channel:   int(rx.GetChannel()),
+			RFChain:   int(rx.GetRfChain()),
+			CRCStatus: 1,
+			RSSI:      int(rx.GetRssi()),
+			LoRaSNR:   rx.GetLoraSnr(),
+			Size:      len(f.GetPhyPayload()),
+			Board:     int(rx.GetBoard()),
+			Antenna:   int(rx.GetAntenna()),
+		},
+	}
+	copy(pl.RXInfo.MAC[:], rx.GetGatewayId())
+
+	if t, err := ptypes.Timestamp(rx.GetTime()); err == nil {
+		pl.RXInfo.Time = &t
+	}
+	if d, err := ptypes.Duration(rx.GetTimeSinceGpsEpoch()); err == nil {
+		gps := v2Duration(d)
+		pl.RXInfo.TimeSinceGPSEpoch = &gps
+	}
+	if len(rx.GetContext()) == 4 {
+		pl.RXInfo.Timestamp = binary.BigEndian.Uint32(rx.GetContext())
+	}
+
+	if lora := tx.GetLoraModulationInfo(); lora != nil {
+		pl.RXInfo.CodeRate = lora.GetCodeRate()
+		pl.RXInfo.DataRate = band.DataRate{
+			Modulation:   band.LoRaModulation,
+			SpreadFactor: int(lora.GetSpreadingFactor()),
+			Bandwidth:    int(lora.GetBandwidth()),
+		}
+	} else if fsk := tx.GetFskModulationInfo(); fsk != nil {
+		pl.RXInfo.DataRate = band.DataRate{
+			Modulation: band.FSKModulation,
+			BitRate:    int(fsk.GetBitrate()),
+		}
+	}
+
+	return pl
+}
+
+func statsToV2(s *gw.GatewayStats) *v2StatsPacket {
+	pl := &v2StatsPacket{
+		RXPacketsReceived:   int(s.GetRxPacketsReceived()),
+		RXPacketsReceivedOK: int(s.GetRxPacketsReceivedOk()),
+		TXPacketsReceived:   int(s.GetTxPacketsReceived()),
+		TXPacketsEmitted:    int(s.GetTxPacketsEmitted()),
+		ConfigVersion:       s.GetConfigVersion(),
+	}
+	copy(pl.MAC[:], s.GetGatewayId())
+	if t, err := ptypes.Timestamp(s.GetTime()); err == nil {
+		pl.Time = t
+	}
+	return pl
+}
+
+// v2ToDownlinkFrame converts a v2 downlink to a single item downlink frame.
+// v2 downlinks are scheduled at a concentrator counter value instead of a delay after an uplink context,
+// so the counter is carried in the context with a zero delay.
+func v2ToDownlinkFrame(pl *v2TXPacket, f *gwv3.DownlinkFrame) {
+	txInfo := &gw.DownlinkTXInfo{
+		GatewayId: pl.TXInfo.MAC[:],
+		Frequency: uint32(pl.TXInfo.Frequency),
+		Power:     int32(pl.TXInfo.Power),
+		Board:     uint32(pl.TXInfo.Board),
+		Antenna:   uint32(pl.TXInfo.Antenna),
+	}
+
+	switch pl.TXInfo.DataRate.Modulation {
+	case band.FSKModulation:
+		txInfo.ModulationInfo = &gw.DownlinkTXInfo_FskModulationInfo{
+			FskModulationInfo: &gw.FSKModulationInfo{
+				Bitrate: uint32(pl.TXInfo.DataRate.BitRate),
+			},
+		}
+	default:
+		txInfo.ModulationInfo = &gw.DownlinkTXInfo_LoraModulationInfo{
+			LoraModulationInfo: &gw.LoRaModulationInfo{
+				SpreadingFactor:       uint32(pl.TXInfo.DataRate.SpreadFactor),
+				Bandwidth:             uint32(pl.TXInfo.DataRate.Bandwidth),
+				CodeRate:              pl.TXInfo.CodeRate,
+				PolarizationInversion: pl.TXInfo.IPol == nil || *pl.TXInfo.IPol,
+			},
+		}
+	}
+
+	switch {
+	case pl.TXInfo.Immediately:
+		txInfo.Timing = gw.DownlinkTiming_IMMEDIATELY
+		txInfo.TimingInfo = &gw.DownlinkTXInfo_ImmediatelyTimingInfo{
+			ImmediatelyTimingInfo: &gw.ImmediatelyTimingInfo{},
+		}
+	case pl.TXInfo.TimeSinceGPSEpoch != nil:
+		txInfo.Timing = gw.DownlinkTiming_GPS_EPOCH
+		txInfo.TimingInfo = &gw.DownlinkTXInfo_GpsEpochTimingInfo{
+			GpsEpochTimingInfo: &gw.GPSEpochTimingInfo{
+				TimeSinceGpsEpoch: ptypes.DurationProto(time.Duration(*pl.TXInfo.TimeSinceGPSEpoch)),
+			},
+		}
+	case pl.TXInfo.Timestamp != nil:
+		txInfo.Timing = gw.DownlinkTiming_DELAY
+		txInfo.TimingInfo = &gw.DownlinkTXInfo_DelayTimingInfo{
+			DelayTimingInfo: &gw.DelayTimingInfo{
+				Delay: ptypes.DurationProto(0),
+			},
+		}
+		txInfo.Context = make([]byte, 4)
+		binary.BigEndian.PutUint32(txInfo.Context, *pl.TXInfo.Timestamp)
+	}
+
+	f.Token = uint32(pl.Token)
+	f.GatewayId = pl.TXInfo.MAC[:]
+	f.Items = []*gwv3.DownlinkFrameItem{
+		{
+			PhyPayload: pl.PHYPayload,
+			TxInfo:     txInfo,
+		},
+	}
+}
+
+func v2ToGatewayConfiguration(pl *v2ConfigPacket, f *gw.GatewayConfiguration) {
+	f.GatewayId = pl.MAC[:]
+	f.Version = pl.Version
+	f.Channels = nil
+
+	for _, ch := range pl.Channels {
+		channel := &gw.ChannelConfiguration{
+			Frequency: uint32(ch.Frequency),
+		}
+
+		switch ch.Modulation {
+		case band.FSKModulation:
+			channel.Modulation = common.Modulation_FSK
+			channel.ModulationConfig = &gw.ChannelConfiguration_FskModulationConfig{
+				FskModulationConfig: &gw.FSKModulationConfig{
+					Bandwidth: uint32(ch.Bandwidth),
+					Bitrate:   uint32(ch.Bitrate),
+				},
+			}
+		default:
+			conf := &gw.LoRaModulationConfig{
+				Bandwidth: uint32(ch.Bandwidth),
+			}
+			for _, sf := range ch.SpreadingFactors {
+				conf.SpreadingFactors = append(conf.SpreadingFactors, uint32(sf))
+			}
+			channel.Modulation = common.Modulation_LORA
+			channel.ModulationConfig = &gw.ChannelConfiguration_LoraModulationConfig{
+				LoraModulationConfig: conf,
+			}
+		}
+
+		f.Channels = append(f.Channels, channel)
+	}
+}
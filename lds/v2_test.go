@@ -0,0 +1,203 @@
+package lds
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/iegomez/lds/api/gwv3"
+)
+
+var testGatewayID = []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+func TestMarshalV2Uplink(t *testing.T) {
+	rxTime := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	rxInfo := &gw.UplinkRXInfo{
+		GatewayId:         testGatewayID,
+		Rssi:              -60,
+		LoraSnr:           7.5,
+		Channel:           1,
+		Context:           []byte{0, 0x0f, 0x42, 0x40},
+		TimeSinceGpsEpoch: ptypes.DurationProto(1500 * time.Millisecond),
+	}
+	rxInfo.Time, _ = ptypes.TimestampProto(rxTime)
+
+	b, err := marshalV2(&gw.UplinkFrame{PhyPayload: []byte{1, 2, 3}, RxInfo: rxInfo, TxInfo: loraTXInfo(868100000, 7)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pl v2RXPacket
+	if err := json.Unmarshal(b, &pl); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pl.PHYPayload, []byte{1, 2, 3}) || pl.RXInfo.Size != 3 {
+		t.Errorf("got payload %x of size %d", pl.PHYPayload, pl.RXInfo.Size)
+	}
+	if pl.RXInfo.MAC.String() != "0102030405060708" {
+		t.Errorf("got MAC %s", pl.RXInfo.MAC)
+	}
+	if pl.RXInfo.Timestamp != 1000000 {
+		t.Errorf("got timestamp %d, expected the context counter", pl.RXInfo.Timestamp)
+	}
+	if pl.RXInfo.Time == nil || !pl.RXInfo.Time.Equal(rxTime) {
+		t.Errorf("got time %v, expected %s", pl.RXInfo.Time, rxTime)
+	}
+	if pl.RXInfo.TimeSinceGPSEpoch == nil || time.Duration(*pl.RXInfo.TimeSinceGPSEpoch) != 1500*time.Millisecond {
+		t.Errorf("got GPS time %v", pl.RXInfo.TimeSinceGPSEpoch)
+	}
+	if pl.RXInfo.Frequency != 868100000 || pl.RXInfo.DataRate.SpreadFactor != 7 || pl.RXInfo.DataRate.Bandwidth != 125 || pl.RXInfo.CodeRate != "4/5" {
+		t.Errorf("got rx info %+v", pl.RXInfo)
+	}
+	if pl.RXInfo.RSSI != -60 || pl.RXInfo.LoRaSNR != 7.5 || pl.RXInfo.Channel != 1 || pl.RXInfo.CRCStatus != 1 {
+		t.Errorf("got rx info %+v", pl.RXInfo)
+	}
+}
+
+func TestMarshalV2Unsupported(t *testing.T) {
+	if _, err := marshalV2(&gwv3.ConnState{}); err == nil {
+		t.Error("expected an error for a message without a v2 format")
+	}
+	if err := unmarshalV2([]byte(`{}`), &gw.UplinkFrame{}); err == nil {
+		t.Error("expected an error for a message without a v2 format")
+	}
+}
+
+func TestUnmarshalV2Downlink(t *testing.T) {
+	tests := []struct {
+		name    string
+		txInfo  string
+		timing  gw.DownlinkTiming
+		context []byte
+		ipol    bool
+	}{
+		{
+			name:    "timestamp",
+			txInfo:  `"timestamp":2000000,"frequency":868100000,"power":14,"dataRate":{"modulation":"LORA","spreadFactor":7,"bandwidth":125},"codeRate":"4/5"`,
+			timing:  gw.DownlinkTiming_DELAY,
+			context: []byte{0, 0x1e, 0x84, 0x80},
+			ipol:    true,
+		},
+		{
+			name:   "immediately",
+			txInfo: `"immediately":true,"frequency":869525000,"power":14,"dataRate":{"modulation":"LORA","spreadFactor":12,"bandwidth":125},"iPol":false`,
+			timing: gw.DownlinkTiming_IMMEDIATELY,
+		},
+		{
+			name:   "GPS time",
+			txInfo: `"timeSinceGPSEpoch":"1h0m0s","frequency":868100000,"power":14,"dataRate":{"modulation":"LORA","spreadFactor":7,"bandwidth":125}`,
+			timing: gw.DownlinkTiming_GPS_EPOCH,
+			ipol:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := []byte(`{"token":12,"phyPayload":"AQID","txInfo":{"mac":"0102030405060708",` + tt.txInfo + `}}`)
+			var df gwv3.DownlinkFrame
+			if err := unmarshalV2(b, &df); err != nil {
+				t.Fatal(err)
+			}
+
+			if df.GetToken() != 12 || !bytes.Equal(df.GetGatewayId(), testGatewayID) || len(df.GetItems()) != 1 {
+				t.Fatalf("got frame %+v", &df)
+			}
+			item := df.GetItems()[0]
+			if !bytes.Equal(item.GetPhyPayload(), []byte{1, 2, 3}) {
+				t.Errorf("got payload %x", item.GetPhyPayload())
+			}
+
+			txInfo := item.GetTxInfo()
+			if txInfo.GetTiming() != tt.timing || !bytes.Equal(txInfo.GetContext(), tt.context) {
+				t.Errorf("got timing %s and context %x, expected %s and %x", txInfo.GetTiming(), txInfo.GetContext(), tt.timing, tt.context)
+			}
+			if txInfo.GetLoraModulationInfo().GetPolarizationInversion() != tt.ipol {
+				t.Errorf("got polarization inversion %t, expected %t", !tt.ipol, tt.ipol)
+			}
+			if tt.timing == gw.DownlinkTiming_DELAY {
+				if delay, err := ptypes.Duration(txInfo.GetDelayTimingInfo().GetDelay()); err != nil || delay != 0 {
+					t.Errorf("got delay %s, expected the timestamp in the context with no delay", delay)
+				}
+			}
+			if tt.timing == gw.DownlinkTiming_GPS_EPOCH {
+				if gps, err := ptypes.Duration(txInfo.GetGpsEpochTimingInfo().GetTimeSinceGpsEpoch()); err != nil || gps != time.Hour {
+					t.Errorf("got GPS time %s, expected an hour", gps)
+				}
+			}
+		})
+	}
+}
+
+func TestUnmarshalV2Configuration(t *testing.T) {
+	b := []byte(`{"mac":"0102030405060708","version":"1","channels":[
+		{"modulation":"LORA","frequency":868100000,"bandwidth":125,"spreadingFactors":[7,8,9]},
+		{"modulation":"FSK","frequency":868800000,"bandwidth":125,"bitrate":50000}]}`)
+
+	var conf gw.GatewayConfiguration
+	if err := unmarshalV2(b, &conf); err != nil {
+		t.Fatal(err)
+	}
+	if conf.GetVersion() != "1" || len(conf.GetChannels()) != 2 {
+		t.Fatalf("got configuration %+v", &conf)
+	}
+	if lora := conf.GetChannels()[0].GetLoraModulationConfig(); lora == nil || len(lora.GetSpreadingFactors()) != 3 {
+		t.Errorf("got LoRa channel %+v", conf.GetChannels()[0])
+	}
+	if fsk := conf.GetChannels()[1].GetFskModulationConfig(); fsk == nil || fsk.GetBitrate() != 50000 {
+		t.Errorf("got FSK channel %+v", conf.GetChannels()[1])
+	}
+}
+
+// TestMarshalerDownlinks checks that downlinks are decoded with the marshaler of the device.
+func TestMarshalerDownlinks(t *testing.T) {
+	df := &gwv3.DownlinkFrame{
+		Token:     7,
+		GatewayId: testGatewayID,
+		Items: []*gwv3.DownlinkFrameItem{
+			{PhyPayload: []byte{1, 2, 3}, TxInfo: delayTXInfo(868100000, 7, time.Second, []byte{1, 2, 3, 4})},
+			{PhyPayload: []byte{1, 2, 3}, TxInfo: delayTXInfo(869525000, 12, 2*time.Second, []byte{1, 2, 3, 4})},
+		},
+	}
+
+	for _, marshaler := range []string{"json", "protobuf"} {
+		t.Run(marshaler, func(t *testing.T) {
+			d := &Device{}
+			d.SetMarshaler(marshaler)
+
+			b, err := d.marshal(df)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded gwv3.DownlinkFrame
+			if err := d.unmarshal(b, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(df, &decoded) {
+				t.Errorf("got %+v, expected %+v", &decoded, df)
+			}
+		})
+	}
+}
+
+func TestV2Duration(t *testing.T) {
+	b, err := json.Marshal(v2Duration(1500 * time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"1.5s"` {
+		t.Errorf("got %s", b)
+	}
+
+	var d v2Duration
+	if err := json.Unmarshal([]byte(`"2m"`), &d); err != nil || time.Duration(d) != 2*time.Minute {
+		t.Errorf("got %s and error %v", time.Duration(d), err)
+	}
+	if err := json.Unmarshal([]byte(`"2 minutes"`), &d); err == nil {
+		t.Error("expected an error for an invalid duration")
+	}
+}
@@ -0,0 +1,286 @@
+package lds
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/brocaar/chirpstack-api/go/common"
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/golang/protobuf/proto"
+	"github.com/iegomez/lds/api/gwv3"
+	"github.com/iegomez/lds/api/gwv4"
+)
+
+// marshalV4 wraps the given marshal function so that messages are converted to their ChirpStack v4 counterparts first.
+func marshalV4(marshal func(msg proto.Message) ([]byte, error)) func(msg proto.Message) ([]byte, error) {
+	return func(msg proto.Message) ([]byte, error) {
+		var v4 proto.Message
+		switch m := msg.(type) {
+		case *gw.UplinkFrame:
+			v4 = uplinkFrameToV4(m)
+		case *gw.GatewayStats:
+			v4 = statsToV4(m)
+		case *gwv3.DownlinkTXAck:
+			v4 = ackToV4(m)
+		case *gwv3.ConnState:
+			v4 = &gwv4.ConnState{
+				GatewayId: hex.EncodeToString(m.GetGatewayId()),
+				State:     gwv4.ConnState_State(m.GetState()),
+			}
+		default:
+			return nil, fmt.Errorf("v4: can't marshal %T", msg)
+		}
+		return marshal(v4)
+	}
+}
+
+// unmarshalV4 wraps the given unmarshal function so that ChirpStack v4 messages are converted to the ones lds works with.
+func unmarshalV4(unmarshal func(b []byte, msg proto.Message) error) func(b []byte, msg proto.Message) error {
+	return func(b []byte, msg proto.Message) error {
+		switch m := msg.(type) {
+		case *gwv3.DownlinkFrame:
+			var df gwv4.DownlinkFrame
+			if err := unmarshal(b, &df); err != nil {
+				return err
+			}
+			return v4ToDownlinkFrame(&df, m)
+		case *gw.GatewayConfiguration:
+			var c gwv4.GatewayConfiguration
+			if err := unmarshal(b, &c); err != nil {
+				return err
+			}
+			return v4ToGatewayConfiguration(&c, m)
+		default:
+			return fmt.Errorf("v4: can't unmarshal %T", msg)
+		}
+	}
+}
+
+func uplinkFrameToV4(f *gw.UplinkFrame) *gwv4.UplinkFrame {
+	rx := f.GetRxInfo()
+	tx := f.GetTxInfo()
+
+	txInfo := &gwv4.UplinkTxInfo{
+		Frequency: tx.GetFrequency(),
+	}
+	if lora := tx.GetLoraModulationInfo(); lora != nil {
+		txInfo.Modulation = &gwv4.Modulation{
+			Parameters: &gwv4.Modulation_Lora{
+				Lora: &gwv4.LoraModulationInfo{
+					//v4 bandwidths are in Hz.
+					Bandwidth:       lora.GetBandwidth() * 1000,
+					SpreadingFactor: lora.GetSpreadingFactor(),
+					CodeRate:        codeRateToV4(lora.GetCodeRate()),
+				},
+			},
+		}
+	} else if fsk := tx.GetFskModulationInfo(); fsk != nil {
+		txInfo.Modulation = &gwv4.Modulation{
+			Parameters: &gwv4.Modulation_Fsk{
+				Fsk: &gwv4.FskModulationInfo{
+					Datarate: fsk.GetBitrate(),
+				},
+			},
+		}
+	}
+
+	return &gwv4.UplinkFrame{
+		PhyPayload: f.GetPhyPayload(),
+		TxInfo:     txInfo,
+		RxInfo: &gwv4.UplinkRxInfo{
+			GatewayId:         hex.EncodeToString(rx.GetGatewayId()),
+			UplinkId:          rand.Uint32(),
+			GwTime:            rx.GetTime(),
+			TimeSinceGpsEpoch: rx.GetTimeSinceGpsEpoch(),
+			Rssi:              rx.GetRssi(),
+			Snr:               float32(rx.GetLoraSnr()),
+			Channel:           rx.GetChannel(),
+			RfChain:           rx.GetRfChain(),
+			Board:             rx.GetBoard(),
+			Antenna:           rx.GetAntenna(),
+			Context:           rx.GetContext(),
+			CrcStatus:         gwv4.CRCStatus_CRC_OK,
+		},
+	}
+}
+
+func statsToV4(s *gw.GatewayStats) *gwv4.GatewayStats {
+	return &gwv4.GatewayStats{
+		GatewayId:           hex.EncodeToString(s.GetGatewayId()),
+		Time:                s.GetTime(),
+		ConfigVersion:       s.GetConfigVersion(),
+		RxPacketsReceived:   s.GetRxPacketsReceived(),
+		RxPacketsReceivedOk: s.GetRxPacketsReceivedOk(),
+		TxPacketsReceived:   s.GetTxPacketsReceived(),
+		TxPacketsEmitted:    s.GetTxPacketsEmitted(),
+		Metadata:            s.GetMetaData(),
+	}
+}
+
+func ackToV4(a *gwv3.DownlinkTXAck) *gwv4.DownlinkTxAck {
+	ack := &gwv4.DownlinkTxAck{
+		GatewayId: hex.EncodeToString(a.GetGatewayId()),
+	}
+	if len(a.GetDownlinkId()) == 4 {
+		ack.DownlinkId = binary.BigEndian.Uint32(a.GetDownlinkId())
+	}
+	for _, item := range a.GetItems() {
+		//Both versions share the status values.
+		ack.Items = append(ack.Items, &gwv4.DownlinkTxAckItem{Status: gwv4.TxAckStatus(item.GetStatus())})
+	}
+	return ack
+}
+
+// v4ToDownlinkFrame converts a v4 downlink frame. The v4 downlink ID is kept big endian encoded in the downlink ID.
+func v4ToDownlinkFrame(df *gwv4.DownlinkFrame, f *gwv3.DownlinkFrame) error {
+	gatewayID, err := hex.DecodeString(df.GetGatewayId())
+	if err != nil {
+		return err
+	}
+
+	f.GatewayId = gatewayID
+	f.Token = df.GetDownlinkId() & 0xffff
+	f.DownlinkId = make([]byte, 4)
+	binary.BigEndian.PutUint32(f.DownlinkId, df.GetDownlinkId())
+	f.Items = nil
+
+	for _, item := range df.GetItems() {
+		txInfo, err := v4ToDownlinkTXInfo(item.GetTxInfo())
+		if err != nil {
+			return err
+		}
+		txInfo.GatewayId = gatewayID
+
+		f.Items = append(f.Items, &gwv3.DownlinkFrameItem{
+			PhyPayload: item.GetPhyPayload(),
+			TxInfo:     txInfo,
+		})
+	}
+
+	return nil
+}
+
+// v4ToGatewayConfiguration converts a v4 gateway configuration. The stats interval isn't applied.
+func v4ToGatewayConfiguration(c *gwv4.GatewayConfiguration, f *gw.GatewayConfiguration) error {
+	gatewayID, err := hex.DecodeString(c.GetGatewayId())
+	if err != nil {
+		return err
+	}
+
+	f.GatewayId = gatewayID
+	f.Version = c.GetVersion()
+	f.Channels = nil
+
+	for _, ch := range c.GetChannels() {
+		channel := &gw.ChannelConfiguration{
+			Frequency:   ch.GetFrequency(),
+			Board:       ch.GetBoard(),
+			Demodulator: ch.GetDemodulator(),
+		}
+
+		if lora := ch.GetLoraModulationConfig(); lora != nil {
+			channel.Modulation = common.Modulation_LORA
+			channel.ModulationConfig = &gw.ChannelConfiguration_LoraModulationConfig{
+				LoraModulationConfig: &gw.LoRaModulationConfig{
+					Bandwidth:        lora.GetBandwidth() / 1000,
+					SpreadingFactors: lora.GetSpreadingFactors(),
+				},
+			}
+		} else if fsk := ch.GetFskModulationConfig(); fsk != nil {
+			channel.Modulation = common.Modulation_FSK
+			channel.ModulationConfig = &gw.ChannelConfiguration_FskModulationConfig{
+				FskModulationConfig: &gw.FSKModulationConfig{
+					Bandwidth: fsk.GetBandwidth() / 1000,
+					Bitrate:   fsk.GetBitrate(),
+				},
+			}
+		}
+
+		f.Channels = append(f.Channels, channel)
+	}
+
+	return nil
+}
+
+func v4ToDownlinkTXInfo(tx *gwv4.DownlinkTxInfo) (*gw.DownlinkTXInfo, error) {
+	txInfo := &gw.DownlinkTXInfo{
+		Frequency: tx.GetFrequency(),
+		Power:     tx.GetPower(),
+		Board:     tx.GetBoard(),
+		Antenna:   tx.GetAntenna(),
+		Context:   tx.GetContext(),
+	}
+
+	mod := tx.GetModulation()
+	switch {
+	case mod.GetLora() != nil:
+		lora := mod.GetLora()
+		txInfo.ModulationInfo = &gw.DownlinkTXInfo_LoraModulationInfo{
+			LoraModulationInfo: &gw.LoRaModulationInfo{
+				Bandwidth:             lora.GetBandwidth() / 1000,
+				SpreadingFactor:       lora.GetSpreadingFactor(),
+				CodeRate:              codeRateFromV4(lora.GetCodeRate()),
+				PolarizationInversion: lora.GetPolarizationInversion(),
+			},
+		}
+	case mod.GetFsk() != nil:
+		txInfo.ModulationInfo = &gw.DownlinkTXInfo_FskModulationInfo{
+			FskModulationInfo: &gw.FSKModulationInfo{
+				Bitrate: mod.GetFsk().GetDatarate(),
+			},
+		}
+	default:
+		return nil, fmt.Errorf("v4: unsupported downlink modulation %v", mod)
+	}
+
+	timing := tx.GetTiming()
+	switch {
+	case timing.GetDelay() != nil:
+		txInfo.Timing = gw.DownlinkTiming_DELAY
+		txInfo.TimingInfo = &gw.DownlinkTXInfo_DelayTimingInfo{
+			DelayTimingInfo: &gw.DelayTimingInfo{
+				Delay: timing.GetDelay().GetDelay(),
+			},
+		}
+	case timing.GetGpsEpoch() != nil:
+		txInfo.Timing = gw.DownlinkTiming_GPS_EPOCH
+		txInfo.TimingInfo = &gw.DownlinkTXInfo_GpsEpochTimingInfo{
+			GpsEpochTimingInfo: &gw.GPSEpochTimingInfo{
+				TimeSinceGpsEpoch: timing.GetGpsEpoch().GetTimeSinceGpsEpoch(),
+			},
+		}
+	default:
+		txInfo.Timing = gw.DownlinkTiming_IMMEDIATELY
+		txInfo.TimingInfo = &gw.DownlinkTXInfo_ImmediatelyTimingInfo{
+			ImmediatelyTimingInfo: &gw.ImmediatelyTimingInfo{},
+		}
+	}
+
+	return txInfo, nil
+}
+
+// codeRateToV4 converts a "4/x" code rate to its v4 enum value.
+func codeRateToV4(codeRate string) gwv4.CodeRate {
+	cr, ok := gwv4.CodeRate_value["CR_"+strings.Replace(codeRate, "/", "_", 1)]
+	if !ok {
+		return gwv4.CodeRate_CR_UNDEFINED
+	}
+	return gwv4.CodeRate(cr)
+}
+
+// codeRateFromV4 converts a v4 code rate to its "4/x" representation.
+func codeRateFromV4(codeRate gwv4.CodeRate) string {
+	if codeRate == gwv4.CodeRate_CR_UNDEFINED {
+		return ""
+	}
+	parts := strings.Split(strings.TrimPrefix(codeRate.String(), "CR_"), "_")
+	if _, err := strconv.Atoi(parts[0]); err != nil {
+		//Long interleaver code rates (CR_LI_4_x) can't be represented.
+		return ""
+	}
+	return strings.Join(parts, "/")
+}
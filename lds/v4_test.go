@@ -0,0 +1,355 @@
+package lds
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/brocaar/chirpstack-api/go/gw"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/iegomez/lds/api/gwv3"
+	"github.com/iegomez/lds/api/gwv4"
+)
+
+// v4Devices returns a device with a v4 marshaler and one with the base marshaler it wraps, for each v4 option.
+func v4Devices() map[string][2]*Device {
+	devices := make(map[string][2]*Device)
+	for _, base := range []string{"json", "protobuf"} {
+		v4, plain := &Device{}, &Device{}
+		v4.SetMarshaler("v4_" + base)
+		plain.SetMarshaler(base)
+		devices["v4_"+base] = [2]*Device{v4, plain}
+	}
+	return devices
+}
+
+func TestMarshalV4Uplink(t *testing.T) {
+	rxInfo := &gw.UplinkRXInfo{
+		GatewayId: testGatewayID,
+		Rssi:      -60,
+		LoraSnr:   7.5,
+		Channel:   1,
+		Context:   []byte{0, 0x0f, 0x42, 0x40},
+	}
+	fsk := &gw.UplinkTXInfo{
+		Frequency:      868800000,
+		ModulationInfo: &gw.UplinkTXInfo_FskModulationInfo{FskModulationInfo: &gw.FSKModulationInfo{Bitrate: 50000}},
+	}
+
+	tests := []struct {
+		name       string
+		txInfo     *gw.UplinkTXInfo
+		modulation *gwv4.Modulation
+	}{
+		{
+			name:   "LoRa",
+			txInfo: loraTXInfo(868100000, 7),
+			modulation: &gwv4.Modulation{Parameters: &gwv4.Modulation_Lora{
+				Lora: &gwv4.LoraModulationInfo{Bandwidth: 125000, SpreadingFactor: 7, CodeRate: gwv4.CodeRate_CR_4_5},
+			}},
+		},
+		{
+			name:   "FSK",
+			txInfo: fsk,
+			modulation: &gwv4.Modulation{Parameters: &gwv4.Modulation_Fsk{
+				Fsk: &gwv4.FskModulationInfo{Datarate: 50000},
+			}},
+		},
+	}
+
+	for opt, devices := range v4Devices() {
+		for _, tt := range tests {
+			t.Run(opt+" "+tt.name, func(t *testing.T) {
+				b, err := devices[0].marshal(&gw.UplinkFrame{PhyPayload: []byte{1, 2, 3}, RxInfo: rxInfo, TxInfo: tt.txInfo})
+				if err != nil {
+					t.Fatal(err)
+				}
+				var up gwv4.UplinkFrame
+				if err := devices[1].unmarshal(b, &up); err != nil {
+					t.Fatal(err)
+				}
+
+				if !bytes.Equal(up.GetPhyPayload(), []byte{1, 2, 3}) {
+					t.Errorf("got payload %x", up.GetPhyPayload())
+				}
+				if up.GetTxInfo().GetFrequency() != tt.txInfo.GetFrequency() || !proto.Equal(up.GetTxInfo().GetModulation(), tt.modulation) {
+					t.Errorf("got tx info %+v, expected modulation %+v", up.GetTxInfo(), tt.modulation)
+				}
+				rx := up.GetRxInfo()
+				if rx.GetGatewayId() != "0102030405060708" || !bytes.Equal(rx.GetContext(), rxInfo.GetContext()) {
+					t.Errorf("got gateway %s and context %x", rx.GetGatewayId(), rx.GetContext())
+				}
+				if rx.GetRssi() != -60 || rx.GetSnr() != 7.5 || rx.GetChannel() != 1 || rx.GetCrcStatus() != gwv4.CRCStatus_CRC_OK {
+					t.Errorf("got rx info %+v", rx)
+				}
+			})
+		}
+	}
+}
+
+func TestMarshalV4Events(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      proto.Message
+		v4       proto.Message
+		expected proto.Message
+	}{
+		{
+			name:     "stats",
+			msg:      &gw.GatewayStats{GatewayId: testGatewayID, ConfigVersion: "1", RxPacketsReceived: 3, RxPacketsReceivedOk: 2, TxPacketsReceived: 2, TxPacketsEmitted: 1},
+			v4:       &gwv4.GatewayStats{},
+			expected: &gwv4.GatewayStats{GatewayId: "0102030405060708", ConfigVersion: "1", RxPacketsReceived: 3, RxPacketsReceivedOk: 2, TxPacketsReceived: 2, TxPacketsEmitted: 1},
+		},
+		{
+			name: "ack",
+			msg: &gwv3.DownlinkTXAck{
+				GatewayId:  testGatewayID,
+				DownlinkId: []byte{0, 1, 0, 2},
+				Items:      []*gwv3.DownlinkTXAckItem{{Status: gwv3.TxAckStatus_TOO_LATE}, {Status: gwv3.TxAckStatus_OK}},
+			},
+			v4: &gwv4.DownlinkTxAck{},
+			expected: &gwv4.DownlinkTxAck{
+				GatewayId:  "0102030405060708",
+				DownlinkId: 0x10002,
+				Items:      []*gwv4.DownlinkTxAckItem{{Status: gwv4.TxAckStatus_TOO_LATE}, {Status: gwv4.TxAckStatus_OK}},
+			},
+		},
+		{
+			name:     "ack without a downlink ID",
+			msg:      &gwv3.DownlinkTXAck{GatewayId: testGatewayID, Token: 12},
+			v4:       &gwv4.DownlinkTxAck{},
+			expected: &gwv4.DownlinkTxAck{GatewayId: "0102030405060708"},
+		},
+		{
+			name:     "conn state",
+			msg:      &gwv3.ConnState{GatewayId: testGatewayID, State: gwv3.ConnState_ONLINE},
+			v4:       &gwv4.ConnState{},
+			expected: &gwv4.ConnState{GatewayId: "0102030405060708", State: gwv4.ConnState_ONLINE},
+		},
+	}
+
+	for opt, devices := range v4Devices() {
+		for _, tt := range tests {
+			t.Run(opt+" "+tt.name, func(t *testing.T) {
+				b, err := devices[0].marshal(tt.msg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				v4 := proto.Clone(tt.v4)
+				if err := devices[1].unmarshal(b, v4); err != nil {
+					t.Fatal(err)
+				}
+				if !proto.Equal(v4, tt.expected) {
+					t.Errorf("got %+v, expected %+v", v4, tt.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestMarshalV4Unsupported(t *testing.T) {
+	for opt, devices := range v4Devices() {
+		if _, err := devices[0].marshal(&gwv3.DownlinkFrame{}); err == nil {
+			t.Errorf("%s: expected an error for a message without a v4 counterpart", opt)
+		}
+		if err := devices[0].unmarshal([]byte{}, &gw.UplinkFrame{}); err == nil {
+			t.Errorf("%s: expected an error for a message without a v4 counterpart", opt)
+		}
+	}
+}
+
+func TestUnmarshalV4Downlink(t *testing.T) {
+	lora := &gwv4.Modulation{Parameters: &gwv4.Modulation_Lora{
+		Lora: &gwv4.LoraModulationInfo{Bandwidth: 125000, SpreadingFactor: 7, CodeRate: gwv4.CodeRate_CR_4_5, PolarizationInversion: true},
+	}}
+	v4Frame := func(timing *gwv4.Timing, modulation *gwv4.Modulation) *gwv4.DownlinkFrame {
+		return &gwv4.DownlinkFrame{
+			GatewayId:  "0102030405060708",
+			DownlinkId: 0x10002,
+			Items: []*gwv4.DownlinkFrameItem{{
+				PhyPayload: []byte{1, 2, 3},
+				TxInfo:     &gwv4.DownlinkTxInfo{Frequency: 868100000, Power: 14, Modulation: modulation, Timing: timing, Context: []byte{1, 2, 3, 4}},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		frame  *gwv4.DownlinkFrame
+		timing gw.DownlinkTiming
+		err    bool
+	}{
+		{
+			name:   "delay",
+			frame:  v4Frame(&gwv4.Timing{Parameters: &gwv4.Timing_Delay{Delay: &gwv4.DelayTimingInfo{Delay: ptypes.DurationProto(time.Second)}}}, lora),
+			timing: gw.DownlinkTiming_DELAY,
+		},
+		{
+			name:   "GPS epoch",
+			frame:  v4Frame(&gwv4.Timing{Parameters: &gwv4.Timing_GpsEpoch{GpsEpoch: &gwv4.GPSEpochTimingInfo{TimeSinceGpsEpoch: ptypes.DurationProto(time.Hour)}}}, lora),
+			timing: gw.DownlinkTiming_GPS_EPOCH,
+		},
+		{
+			name:   "immediately",
+			frame:  v4Frame(&gwv4.Timing{Parameters: &gwv4.Timing_Immediately{Immediately: &gwv4.ImmediatelyTimingInfo{}}}, lora),
+			timing: gw.DownlinkTiming_IMMEDIATELY,
+		},
+		{
+			name:  "no modulation",
+			frame: v4Frame(&gwv4.Timing{}, nil),
+			err:   true,
+		},
+		{
+			name:  "invalid gateway ID",
+			frame: &gwv4.DownlinkFrame{GatewayId: "gateway"},
+			err:   true,
+		},
+	}
+
+	for opt, devices := range v4Devices() {
+		for _, tt := range tests {
+			t.Run(opt+" "+tt.name, func(t *testing.T) {
+				b, err := devices[1].marshal(tt.frame)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var df gwv3.DownlinkFrame
+				err = devices[0].unmarshal(b, &df)
+				if (err != nil) != tt.err {
+					t.Fatalf("got error %v, expected one: %t", err, tt.err)
+				}
+				if err != nil {
+					return
+				}
+
+				if !bytes.Equal(df.GetGatewayId(), testGatewayID) || df.GetToken() != 2 || !bytes.Equal(df.GetDownlinkId(), []byte{0, 1, 0, 2}) {
+					t.Errorf("got gateway %x, token %d and downlink ID %x", df.GetGatewayId(), df.GetToken(), df.GetDownlinkId())
+				}
+				if len(df.GetItems()) != 1 || !bytes.Equal(df.GetItems()[0].GetPhyPayload(), []byte{1, 2, 3}) {
+					t.Fatalf("got items %+v", df.GetItems())
+				}
+
+				txInfo := df.GetItems()[0].GetTxInfo()
+				if !bytes.Equal(txInfo.GetGatewayId(), testGatewayID) || !bytes.Equal(txInfo.GetContext(), []byte{1, 2, 3, 4}) {
+					t.Errorf("got gateway %x and context %x", txInfo.GetGatewayId(), txInfo.GetContext())
+				}
+				if txInfo.GetFrequency() != 868100000 || txInfo.GetPower() != 14 || txInfo.GetTiming() != tt.timing {
+					t.Errorf("got tx info %+v", txInfo)
+				}
+				expected := &gw.LoRaModulationInfo{Bandwidth: 125, SpreadingFactor: 7, CodeRate: "4/5", PolarizationInversion: true}
+				if !proto.Equal(txInfo.GetLoraModulationInfo(), expected) {
+					t.Errorf("got modulation %+v, expected %+v", txInfo.GetLoraModulationInfo(), expected)
+				}
+				switch tt.timing {
+				case gw.DownlinkTiming_DELAY:
+					if delay, err := ptypes.Duration(txInfo.GetDelayTimingInfo().GetDelay()); err != nil || delay != time.Second {
+						t.Errorf("got delay %s, expected a second", delay)
+					}
+				case gw.DownlinkTiming_GPS_EPOCH:
+					if gps, err := ptypes.Duration(txInfo.GetGpsEpochTimingInfo().GetTimeSinceGpsEpoch()); err != nil || gps != time.Hour {
+						t.Errorf("got GPS time %s, expected an hour", gps)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestUnmarshalV4Configuration(t *testing.T) {
+	c := &gwv4.GatewayConfiguration{
+		GatewayId: "0102030405060708",
+		Version:   "2",
+		Channels: []*gwv4.ChannelConfiguration{
+			{Frequency: 868100000, ModulationConfig: &gwv4.ChannelConfiguration_LoraModulationConfig{
+				LoraModulationConfig: &gwv4.LoraModulationConfig{Bandwidth: 125000, SpreadingFactors: []uint32{7, 8, 9}},
+			}},
+			{Frequency: 868800000, ModulationConfig: &gwv4.ChannelConfiguration_FskModulationConfig{
+				FskModulationConfig: &gwv4.FskModulationConfig{Bandwidth: 125000, Bitrate: 50000},
+			}},
+		},
+	}
+
+	for opt, devices := range v4Devices() {
+		t.Run(opt, func(t *testing.T) {
+			b, err := devices[1].marshal(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var conf gw.GatewayConfiguration
+			if err := devices[0].unmarshal(b, &conf); err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(conf.GetGatewayId(), testGatewayID) || conf.GetVersion() != "2" || len(conf.GetChannels()) != 2 {
+				t.Fatalf("got configuration %+v", &conf)
+			}
+			expected := &gw.LoRaModulationConfig{Bandwidth: 125, SpreadingFactors: []uint32{7, 8, 9}}
+			if lora := conf.GetChannels()[0].GetLoraModulationConfig(); !proto.Equal(lora, expected) {
+				t.Errorf("got LoRa channel %+v, expected %+v", lora, expected)
+			}
+			if fsk := conf.GetChannels()[1].GetFskModulationConfig(); fsk.GetBandwidth() != 125 || fsk.GetBitrate() != 50000 {
+				t.Errorf("got FSK channel %+v", conf.GetChannels()[1])
+			}
+		})
+	}
+}
+
+func TestCodeRateV4(t *testing.T) {
+	tests := []struct {
+		codeRate string
+		v4       gwv4.CodeRate
+	}{
+		{"4/5", gwv4.CodeRate_CR_4_5},
+		{"4/8", gwv4.CodeRate_CR_4_8},
+		{"2/6", gwv4.CodeRate_CR_2_6},
+		{"", gwv4.CodeRate_CR_UNDEFINED},
+	}
+
+	for _, tt := range tests {
+		if v4 := codeRateToV4(tt.codeRate); v4 != tt.v4 {
+			t.Errorf("%q: got %s, expected %s", tt.codeRate, v4, tt.v4)
+		}
+		if codeRate := codeRateFromV4(tt.v4); codeRate != tt.codeRate {
+			t.Errorf("%s: got %q, expected %q", tt.v4, codeRate, tt.codeRate)
+		}
+	}
+
+	if v4 := codeRateToV4("4/9"); v4 != gwv4.CodeRate_CR_UNDEFINED {
+		t.Errorf("got %s for an unknown code rate", v4)
+	}
+	if codeRate := codeRateFromV4(gwv4.CodeRate_CR_LI_4_5); codeRate != "" {
+		t.Errorf("got %q for a long interleaver code rate", codeRate)
+	}
+}
+
+func TestGatewayStats(t *testing.T) {
+	g := &Gateway{MAC: "0102030405060708", boot: time.Now()}
+	for i := 0; i < 2; i++ {
+		if err := g.receive(&gw.UplinkRXInfo{}, loraTXInfo(868100000, 7), 13); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.countDownlink(true)
+	g.countDownlink(false)
+	//Without channels the configuration would drop the uplinks above.
+	g.config = &gw.GatewayConfiguration{Version: "3"}
+
+	stats := g.Stats()
+	expected := &gw.GatewayStats{
+		GatewayId:           testGatewayID,
+		ConfigVersion:       "3",
+		RxPacketsReceived:   2,
+		RxPacketsReceivedOk: 2,
+		TxPacketsReceived:   2,
+		TxPacketsEmitted:    1,
+	}
+	if stats.GetTime() == nil {
+		t.Error("the stats have no time")
+	}
+	stats.Time = nil
+	if !proto.Equal(stats, expected) {
+		t.Errorf("got %+v, expected %+v", stats, expected)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"strconv"
 
 	l "gioui.org/layout"
@@ -8,6 +9,7 @@ import (
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 	lwband "github.com/brocaar/lorawan/band"
+	"github.com/iegomez/lds/lds"
 	"github.com/scartill/giox"
 	xmat "github.com/scartill/giox/material"
 )
@@ -30,34 +32,6 @@ var (
 	}
 )
 
-type band struct {
-	Name lwband.Name `toml:"name"`
-}
-
-type dataRate struct {
-	Bandwidth    int    `toml:"bandwith"`
-	SpreadFactor int    `toml:"spread_factor"`
-	BitRate      int    `toml:"bit_rate"`
-	BitRateS     string `toml:"-"`
-}
-
-type rxInfo struct {
-	Channel   int     `toml:"channel"`
-	CodeRate  string  `toml:"code_rate"`
-	CrcStatus int     `toml:"crc_status"`
-	Frequency int     `toml:"frequency"`
-	LoRaSNR   float64 `toml:"lora_snr"`
-	RfChain   int     `toml:"rf_chain"`
-	Rssi      int     `toml:"rssi"`
-	//String representations for numeric values so that we can manage them with input texts.
-	ChannelS   string `toml:"-"`
-	CrcStatusS string `toml:"-"`
-	FrequencyS string `toml:"-"`
-	LoRASNRS   string `toml:"-"`
-	RfChainS   string `toml:"-"`
-	RssiS      string `toml:"-"`
-}
-
 var (
 	loraBandCombo     giox.Combo
 	bandwidthCombo    giox.Combo
@@ -69,6 +43,10 @@ var (
 	snrEdit           widget.Editor
 	rfChainEdit       widget.Editor
 	rssiEdit          widget.Editor
+
+	channelSimCheckbox   widget.Bool
+	captureThresholdEdit widget.Editor
+	demodulatorsEdit     widget.Editor
 )
 
 func createLoRaForm() {
@@ -95,13 +73,16 @@ func loraResetGuiValues() {
 	loraBandCombo.SelectItem(string(config.Band.Name))
 	bandwidthCombo.SelectItem(strconv.Itoa(config.DR.Bandwidth))
 	spreadFactorCombo.SelectItem(strconv.Itoa(config.DR.SpreadFactor))
-	bitrateEdit.SetText(config.DR.BitRateS)
-	channelEdit.SetText(config.RXInfo.ChannelS)
-	crcEdit.SetText(config.RXInfo.CrcStatusS)
-	frequencyEdit.SetText(config.RXInfo.FrequencyS)
-	snrEdit.SetText(config.RXInfo.LoRASNRS)
-	rfChainEdit.SetText(config.RXInfo.RfChainS)
-	rssiEdit.SetText(config.RXInfo.RssiS)
+	bitrateEdit.SetText(strconv.Itoa(config.DR.BitRate))
+	channelEdit.SetText(strconv.Itoa(config.RXInfo.Channel))
+	crcEdit.SetText(strconv.Itoa(config.RXInfo.CrcStatus))
+	frequencyEdit.SetText(strconv.Itoa(config.RXInfo.Frequency))
+	snrEdit.SetText(strconv.FormatFloat(config.RXInfo.LoRaSNR, 'f', -1, 64))
+	rfChainEdit.SetText(strconv.Itoa(config.RXInfo.RfChain))
+	rssiEdit.SetText(strconv.Itoa(config.RXInfo.Rssi))
+	channelSimCheckbox.Value = config.Channel.Enabled
+	captureThresholdEdit.SetText(strconv.FormatFloat(config.Channel.CaptureThreshold, 'f', -1, 64))
+	demodulatorsEdit.SetText(strconv.Itoa(config.Channel.Demodulators))
 }
 
 func loRaForm(th *material.Theme) l.FlexChild {
@@ -125,6 +106,10 @@ func loRaForm(th *material.Theme) l.FlexChild {
 	extractInt(&rfChainEdit, &config.RXInfo.RfChain, 1)
 	extractInt(&rssiEdit, &config.RXInfo.Rssi, -57)
 
+	config.Channel.Enabled = channelSimCheckbox.Value
+	extractFloat(&captureThresholdEdit, &config.Channel.CaptureThreshold, lds.DefaultCaptureThreshold)
+	extractInt(&demodulatorsEdit, &config.Channel.Demodulators, lds.DefaultDemodulators)
+
 	widgets := []l.FlexChild{
 		xmat.RigidSection(th, "LoRa Configuration"),
 	}
@@ -151,7 +136,24 @@ func loRaForm(th *material.Theme) l.FlexChild {
 			xmat.RigidEditor(th, "Lora SNR", "<snr>", &snrEdit),
 			xmat.RigidEditor(th, "RF Chain", "<rfchain>", &rfChainEdit),
 			xmat.RigidEditor(th, "RSSI", "<RSSI>", &rssiEdit),
+			xmat.RigidSection(th, "Channel simulation"),
+			xmat.RigidCheckBox(th, "Simulate collisions and demodulator capacity", &channelSimCheckbox),
+			xmat.RigidEditor(th, "Capture threshold (dB)", "6", &captureThresholdEdit),
+			xmat.RigidEditor(th, "Demodulators", "8", &demodulatorsEdit),
 		}...)
+
+		if config.Channel.Enabled {
+			stats := lds.GetChannelStats()
+			widgets = append(widgets, xmat.RigidLabel(th, fmt.Sprintf("Received: %d - Collided: %d - No demodulator: %d", stats.Received, stats.Collided, stats.Dropped)))
+		}
+
+		//Channel plan pushed by the network server, uplinks on any other channel are dropped by the gateway.
+		if gwConfig := lds.GetGateway(config.GW.MAC).Configuration(); gwConfig != nil {
+			widgets = append(widgets, xmat.RigidSection(th, fmt.Sprintf("Gateway channels (version %s)", gwConfig.GetVersion())))
+			for i, ch := range gwConfig.GetChannels() {
+				widgets = append(widgets, xmat.RigidLabel(th, fmt.Sprintf("%d: %s", i, lds.FormatChannel(ch))))
+			}
+		}
 	}
 
 	inset := l.Inset{Left: unit.Dp(30)}
@@ -161,3 +163,11 @@ func loRaForm(th *material.Theme) l.FlexChild {
 		})
 	})
 }
+
+func setChannelSimulator() {
+	if config.Channel.Enabled {
+		lds.SetChannelSimulator(config.Channel.CaptureThreshold, config.Channel.Demodulators)
+	} else {
+		lds.StopChannelSimulator()
+	}
+}
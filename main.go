@@ -63,6 +63,9 @@ var (
 	fileOpenBtn      widget.Clickable
 	fileSaveBtn      widget.Clickable
 	fileProvisionBtn widget.Clickable
+	fileScenarioBtn  widget.Clickable
+	fileReplayBtn    widget.Clickable
+	fileDecodeBtn    widget.Clickable
 	fileCancelBtn    widget.Clickable
 
 	consoleMI        bool
@@ -105,6 +108,18 @@ func buildMenu(th *material.Theme) (l.FlexChild, bool) {
 		return buildSaveFile(th)
 	}
 
+	if openScenario {
+		return buildScenario(th)
+	}
+
+	if openReplay {
+		return buildReplay(th)
+	}
+
+	if openDecode {
+		return buildDecode(th)
+	}
+
 	for fileMIBtn.Clicked() {
 		fileMI = true
 	}
@@ -149,6 +164,22 @@ func buildMenu(th *material.Theme) (l.FlexChild, bool) {
 		fileMI = false
 	}
 
+	for fileScenarioBtn.Clicked() {
+		openScenario = true
+		listScenarios()
+		fileMI = false
+	}
+
+	for fileReplayBtn.Clicked() {
+		openReplay = true
+		fileMI = false
+	}
+
+	for fileDecodeBtn.Clicked() {
+		openDecode = true
+		fileMI = false
+	}
+
 	for fileCancelBtn.Clicked() {
 		fileMI = false
 	}
@@ -201,6 +232,9 @@ func buildMenu(th *material.Theme) (l.FlexChild, bool) {
 				xmat.RigidButton(th, "Open", &fileOpenBtn),
 				xmat.RigidButton(th, "Save", &fileSaveBtn),
 				xmat.RigidButton(th, "Provision", &fileProvisionBtn),
+				xmat.RigidButton(th, "Run scenario", &fileScenarioBtn),
+				xmat.RigidButton(th, "Replay", &fileReplayBtn),
+				xmat.RigidButton(th, "Decode", &fileDecodeBtn),
 				xmat.RigidButton(th, "Cancel", &fileCancelBtn),
 			)
 		})
@@ -300,11 +334,17 @@ func buildSaveFile(th *material.Theme) (l.FlexChild, bool) {
 func resetGuiValues() {
 	mqttResetGuiValue()
 	forwarderResetGuiValues()
+	brokerResetGuiValues()
+	netServerResetGuiValues()
+	joinServerResetGuiValues()
+	apiResetGuiValues()
+	grpcResetGuiValues()
 	loraResetGuiValues()
 	deviceResetGuiValues()
 	macResetGuiValues()
 	dataResetGuiValues()
 	provResetGuiValues()
+	replayResetGuiValues()
 }
 
 var (
@@ -368,6 +408,11 @@ func mainWindow(gtx l.Context, th *material.Theme) {
 
 	wMqttForm := mqttForm(th)
 	wForwarderForm := forwarderForm(th)
+	wBrokerForm := brokerForm(th)
+	wNetServerForm := netServerForm(th)
+	wJoinServerForm := joinServerForm(th)
+	wAPIForm := apiForm(th)
+	wGRPCForm := grpcForm(th)
 	wDeviceForm := deviceForm(th)
 	wLoraForm := loRaForm(th)
 	wControlForm := controlForm(th)
@@ -381,6 +426,16 @@ func mainWindow(gtx l.Context, th *material.Theme) {
 				wMqttForm,
 				xmat.RigidSeparator(th, &giox.Separator{}),
 				wForwarderForm,
+				xmat.RigidSeparator(th, &giox.Separator{}),
+				wBrokerForm,
+				xmat.RigidSeparator(th, &giox.Separator{}),
+				wNetServerForm,
+				xmat.RigidSeparator(th, &giox.Separator{}),
+				wJoinServerForm,
+				xmat.RigidSeparator(th, &giox.Separator{}),
+				wAPIForm,
+				xmat.RigidSeparator(th, &giox.Separator{}),
+				wGRPCForm,
 			)
 		})
 	case 1:
@@ -435,6 +490,7 @@ func main() {
 	confFile = flag.String("conf", "conf.toml", "path to toml configuration file")
 	flag.Parse()
 
+	createMQTTForm()
 	createLoRaForm()
 	createDeviceForm()
 	createDataForm()
@@ -445,6 +501,25 @@ func main() {
 	resetGuiValues()
 	setDevice()
 
+	if config.Journal.Enabled {
+		startJournal()
+	}
+	if config.Pcap.Enabled {
+		startCapture()
+	}
+	if config.API.Enabled {
+		startAPI()
+	}
+	if config.GRPC.Enabled {
+		startGRPC()
+	}
+	if config.Metrics.Enabled {
+		startMetrics()
+	}
+	if config.JoinServer.Enabled {
+		startJoinServer()
+	}
+
 	go func() {
 		defer os.Exit(0)
 		w := app.NewWindow(app.Size(unit.Dp(1024), unit.Dp(768)))
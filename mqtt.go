@@ -1,7 +1,8 @@
 package main
 
 import (
-	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	l "gioui.org/layout"
@@ -9,70 +10,156 @@ import (
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/iegomez/lds/api/gwv3"
+	"github.com/iegomez/lds/lds"
+	"github.com/scartill/giox"
 	matx "github.com/scartill/giox/material"
 	log "github.com/sirupsen/logrus"
 )
 
 var mqttClient paho.Client
 
-type mqtt struct {
-	Server        string `toml:"server"`
-	User          string `toml:"user"`
-	Password      string `toml:"password"`
-	DownlinkTopic string `toml:"downlink_topic"`
-	UplinkTopic   string `toml:"uplink_topic"`
-}
+//stopStats stops the stats publishing of the current client.
+var stopStats chan struct{}
 
-type gateway struct {
-	MAC           string `toml:"mac"`
-	BridgeVersion string `toml:"bridge_version"`
-}
+//statsInterval is how often the gateway stats are published, like the packet forwarder does by default.
+const statsInterval = 30 * time.Second
 
 var (
+	mqttTTNCheckbox      widget.Bool
+	mqttGatewayIDEdit    widget.Editor
 	mqttServerEdit       widget.Editor
 	mqttUserEdit         widget.Editor
 	mqttPasswordEdit     widget.Editor
+	mqttClientIDEdit     widget.Editor
+	mqttQoSEdit          widget.Editor
+	mqttCleanCheckbox    widget.Bool
+	mqttCACertEdit       widget.Editor
+	mqttTLSCertEdit      widget.Editor
+	mqttTLSKeyEdit       widget.Editor
+	mqttInsecureCheckbox widget.Bool
+	mqttALPNEdit         widget.Editor
 	mqttMACEdit          widget.Editor
 	mqttDownlinkEdit     widget.Editor
 	mqttUplinkEdit       widget.Editor
+	mqttStatsEdit        widget.Editor
+	mqttConfigEdit       widget.Editor
+	mqttStateEdit        widget.Editor
+	mqttAckEdit          widget.Editor
+	ackStatusCombo       giox.Combo
+	ackErrorItemsEdit    widget.Editor
+	ackRejectCheckbox    widget.Bool
 	mqttConnectButton    widget.Clickable
 	mqttDisconnectButton widget.Clickable
 )
 
+func createMQTTForm() {
+	//Every status but IGNORED and OK may be forced.
+	statusItems := []string{}
+	for i := int32(gwv3.TxAckStatus_OK) + 1; i < int32(len(gwv3.TxAckStatus_name)); i++ {
+		statusItems = append(statusItems, gwv3.TxAckStatus_name[i])
+	}
+	ackStatusCombo = giox.MakeCombo(statusItems, "<no error>")
+}
+
 func mqttResetGuiValue() {
+	mqttTTNCheckbox.Value = config.MQTT.Transport == lds.TTNTransport
+	mqttGatewayIDEdit.SetText(config.MQTT.GatewayID)
 	mqttServerEdit.SetText(config.MQTT.Server)
 	mqttUserEdit.SetText(config.MQTT.User)
 	mqttPasswordEdit.SetText(config.MQTT.Password)
+	mqttClientIDEdit.SetText(config.MQTT.ClientID)
+	mqttQoSEdit.SetText(strconv.Itoa(config.MQTT.QoS))
+	mqttCleanCheckbox.Value = config.MQTT.CleanSession
+	mqttCACertEdit.SetText(config.MQTT.CACert)
+	mqttTLSCertEdit.SetText(config.MQTT.TLSCert)
+	mqttTLSKeyEdit.SetText(config.MQTT.TLSKey)
+	mqttInsecureCheckbox.Value = config.MQTT.InsecureSkipVerify
+	mqttALPNEdit.SetText(strings.Join(config.MQTT.ALPN, ","))
 	mqttMACEdit.SetText(config.GW.MAC)
 	mqttDownlinkEdit.SetText(config.MQTT.DownlinkTopic)
 	mqttUplinkEdit.SetText(config.MQTT.UplinkTopic)
+	mqttStatsEdit.SetText(config.MQTT.StatsTopic)
+	mqttConfigEdit.SetText(config.MQTT.ConfigTopic)
+	mqttStateEdit.SetText(config.MQTT.StateTopic)
+	mqttAckEdit.SetText(config.MQTT.AckTopic)
+	ackStatusCombo.SelectItem(config.MQTT.AckErrorStatus)
+	ackErrorItemsEdit.SetText(strconv.Itoa(config.MQTT.AckErrorItems))
+	ackRejectCheckbox.Value = config.MQTT.AckRejectInvalid
 }
 
 func mqttForm(th *material.Theme) l.FlexChild {
 
+	config.MQTT.Transport = lds.ChirpStackTransport
+	if mqttTTNCheckbox.Value {
+		config.MQTT.Transport = lds.TTNTransport
+	}
+	config.MQTT.GatewayID = mqttGatewayIDEdit.Text()
 	config.MQTT.Server = mqttServerEdit.Text()
 	config.MQTT.User = mqttUserEdit.Text()
 	config.MQTT.Password = mqttPasswordEdit.Text()
+	config.MQTT.ClientID = mqttClientIDEdit.Text()
+	extractInt(&mqttQoSEdit, &config.MQTT.QoS, 0)
+	config.MQTT.CleanSession = mqttCleanCheckbox.Value
+	config.MQTT.CACert = mqttCACertEdit.Text()
+	config.MQTT.TLSCert = mqttTLSCertEdit.Text()
+	config.MQTT.TLSKey = mqttTLSKeyEdit.Text()
+	config.MQTT.InsecureSkipVerify = mqttInsecureCheckbox.Value
+	config.MQTT.ALPN = splitList(mqttALPNEdit.Text())
 	config.GW.MAC = mqttMACEdit.Text()
 	config.MQTT.DownlinkTopic = mqttDownlinkEdit.Text()
 	config.MQTT.UplinkTopic = mqttUplinkEdit.Text()
+	config.MQTT.StatsTopic = mqttStatsEdit.Text()
+	config.MQTT.ConfigTopic = mqttConfigEdit.Text()
+	config.MQTT.StateTopic = mqttStateEdit.Text()
+	config.MQTT.AckTopic = mqttAckEdit.Text()
+	if ackStatusCombo.HasSelected() {
+		config.MQTT.AckErrorStatus = ackStatusCombo.SelectedText()
+	}
+	extractInt(&ackErrorItemsEdit, &config.MQTT.AckErrorItems, 0)
+	config.MQTT.AckRejectInvalid = ackRejectCheckbox.Value
 
 	for mqttConnectButton.Clicked() {
 		connectClient()
 	}
 
 	for mqttDisconnectButton.Clicked() {
-		mqttClient.Disconnect(200)
+		disconnectClient()
 	}
 
 	widgets := []l.FlexChild{
 		matx.RigidSection(th, "MQTT & Gateway"),
+		matx.RigidCheckBox(th, "The Things Stack", &mqttTTNCheckbox),
+		matx.RigidEditor(th, "TTS Gateway ID:", "<gateway-id>@<tenant-id>", &mqttGatewayIDEdit),
 		matx.RigidEditor(th, "MQTT Server:", "192.168.1.1", &mqttServerEdit),
 		matx.RigidEditor(th, "MQTT User:", "<username>", &mqttUserEdit),
 		matx.RigidEditor(th, "MQTT Password:", "<password>", &mqttPasswordEdit),
+		matx.RigidEditor(th, "Client ID:", "<random>", &mqttClientIDEdit),
+		matx.RigidEditor(th, "QoS:", "0", &mqttQoSEdit),
+		matx.RigidCheckBox(th, "Clean session", &mqttCleanCheckbox),
+		matx.RigidEditor(th, "CA Cert:", "path/to/ca.pem", &mqttCACertEdit),
+		matx.RigidEditor(th, "TLS Cert:", "path/to/cert.pem", &mqttTLSCertEdit),
+		matx.RigidEditor(th, "TLS Key:", "path/to/key.pem", &mqttTLSKeyEdit),
+		matx.RigidCheckBox(th, "Insecure skip verify", &mqttInsecureCheckbox),
+		matx.RigidEditor(th, "ALPN:", "x-amzn-mqtt-ca", &mqttALPNEdit),
 		matx.RigidEditor(th, "Gateway MAC:", "DEADBEEFDEADBEEF", &mqttMACEdit),
 		matx.RigidEditor(th, "Downlink Topic:", "gateway/%s/command/down", &mqttDownlinkEdit),
-		matx.RigidEditor(th, "Uplink Topic:", "gateway/%s/event/up", &mqttUplinkEdit)}
+		matx.RigidEditor(th, "Uplink Topic:", "gateway/%s/event/up", &mqttUplinkEdit),
+		matx.RigidEditor(th, "Stats Topic:", "gateway/%s/event/stats", &mqttStatsEdit),
+		matx.RigidEditor(th, "Config Topic:", "gateway/%s/command/config", &mqttConfigEdit),
+		matx.RigidEditor(th, "State Topic:", "gateway/%s/state/conn", &mqttStateEdit),
+		matx.RigidEditor(th, "Ack Topic:", "gateway/%s/event/ack", &mqttAckEdit),
+		labelCombo(th, "Ack error status", &ackStatusCombo),
+		matx.RigidEditor(th, "Ack error items:", "0", &ackErrorItemsEdit),
+		matx.RigidCheckBox(th, "Reject invalid downlinks", &ackRejectCheckbox)}
+
+	//Only show the combo while it's expanded.
+	if ackStatusCombo.IsExpanded() {
+		widgets = []l.FlexChild{
+			matx.RigidSection(th, "MQTT & Gateway"),
+			labelCombo(th, "Ack error status", &ackStatusCombo),
+		}
+	}
 
 	if !cNSClient.IsConnected() {
 		widgets = append(widgets, matx.RigidButton(th, "Connect", &mqttConnectButton))
@@ -93,13 +180,27 @@ func mqttForm(th *material.Theme) l.FlexChild {
 }
 
 func connectClient() error {
-	//Connect to the broker
-	opts := paho.NewClientOptions()
-	opts.AddBroker(config.MQTT.Server)
-	opts.SetUsername(config.MQTT.User)
-	opts.SetPassword(config.MQTT.Password)
-	opts.SetAutoReconnect(true)
-	opts.SetClientID(fmt.Sprintf("lds-%d", time.Now().UnixNano()))
+	if config.Broker.Enabled {
+		if err := startBroker(); err != nil {
+			return err
+		}
+	}
+	if config.NetworkServer.Enabled {
+		if err := startNetServer(); err != nil {
+			return err
+		}
+	}
+
+	if err := lds.SetQoS(config.MQTT.QoS); err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	opts, err := config.MQTTClientOptions()
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
 
 	mqttClient = paho.NewClient(opts)
 	log.Infoln("MQTT connecting...")
@@ -108,8 +209,76 @@ func connectClient() error {
 		return token.Error()
 	}
 	log.Infoln("connection established")
-	mqttClient.Subscribe(fmt.Sprintf(config.MQTT.DownlinkTopic, config.GW.MAC), 1, func(c paho.Client, msg paho.Message) {
+	mqttClient.Subscribe(lds.FormatTopic(config.Topics().Downlink, config.GW.MAC), byte(config.MQTT.QoS), func(c paho.Client, msg paho.Message) {
 		onIncomingDownlink(msg.Payload())
 	})
+	if config.Topics().Config != "" {
+		mqttClient.Subscribe(lds.FormatTopic(config.Topics().Config, config.GW.MAC), byte(config.MQTT.QoS), func(c paho.Client, msg paho.Message) {
+			if err := config.MarshalingDevice().ApplyGatewayConfig(msg.Payload(), config.GW.MAC); err != nil {
+				log.Errorf("couldn't apply gateway configuration: %s", err)
+			}
+		})
+	}
+	if config.Topics().State != "" {
+		if err := config.MarshalingDevice().PublishConnState(mqttClient, config.Topics().State, config.GW.MAC, true); err != nil {
+			log.Errorf("couldn't publish the online state: %s", err)
+		}
+	}
+	if stopStats != nil {
+		close(stopStats)
+	}
+	stopStats = make(chan struct{})
+	go publishStats(mqttClient, stopStats)
 	return nil
 }
+
+//disconnectClient publishes the OFFLINE state, as the last will is discarded on a clean disconnect, and disconnects from the broker.
+func disconnectClient() {
+	if config.Topics().State != "" {
+		if err := config.MarshalingDevice().PublishConnState(mqttClient, config.Topics().State, config.GW.MAC, false); err != nil {
+			log.Errorf("couldn't publish the offline state: %s", err)
+		}
+	}
+	if stopStats != nil {
+		close(stopStats)
+		stopStats = nil
+	}
+	mqttClient.Disconnect(200)
+}
+
+//publishStats publishes the gateway stats every statsInterval until stop is closed, skipping the ticks while the client is reconnecting or no stats topic is set.
+func publishStats(client paho.Client, stop <-chan struct{}) {
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+
+		if !client.IsConnected() {
+			continue
+		}
+
+		if config.Topics().Stats == "" || cDevice == nil {
+			continue
+		}
+
+		if err := cDevice.PublishStats(client, config.Topics().Stats, config.GW.MAC); err != nil {
+			log.Errorf("couldn't publish stats: %s", err)
+		}
+	}
+}
+
+//splitList splits a comma separated list, dropping empty values.
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	l "gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/iegomez/lds/lds"
+	matx "github.com/scartill/giox/material"
+	log "github.com/sirupsen/logrus"
+)
+
+// netServer is the embedded network server, when running.
+var netServer *lds.NetworkServer
+
+var (
+	nsEnabledCheckbox   widget.Bool
+	nsTransportEdit     widget.Editor
+	nsBindEdit          widget.Editor
+	nsNetIDEdit         widget.Editor
+	nsJoinNonceEdit     widget.Editor
+	nsStartButton       widget.Clickable
+	nsStopButton        widget.Clickable
+	nsFPortEdit         widget.Editor
+	nsPayloadEdit       widget.Editor
+	nsMACCommandsEdit   widget.Editor
+	nsConfirmedCheckbox widget.Bool
+	nsEnqueueButton     widget.Clickable
+)
+
+func netServerResetGuiValues() {
+	nsEnabledCheckbox.Value = config.NetworkServer.Enabled
+	nsTransportEdit.SetText(config.NetworkServer.Transport)
+	nsBindEdit.SetText(config.NetworkServer.Bind)
+	nsNetIDEdit.SetText(config.NetworkServer.NetID)
+	nsJoinNonceEdit.SetText(fmt.Sprintf("%d", config.NetworkServer.JoinNonce))
+}
+
+func netServerForm(th *material.Theme) l.FlexChild {
+
+	config.NetworkServer.Enabled = nsEnabledCheckbox.Value
+	config.NetworkServer.Transport = nsTransportEdit.Text()
+	config.NetworkServer.Bind = nsBindEdit.Text()
+	config.NetworkServer.NetID = nsNetIDEdit.Text()
+	if jn, err := strconv.ParseUint(nsJoinNonceEdit.Text(), 10, 24); err == nil {
+		config.NetworkServer.JoinNonce = uint32(jn)
+	}
+
+	for nsStartButton.Clicked() {
+		startNetServer()
+	}
+
+	for nsStopButton.Clicked() {
+		stopNetServer()
+	}
+
+	for nsEnqueueButton.Clicked() {
+		enqueueDownlink()
+	}
+
+	widgets := []l.FlexChild{
+		matx.RigidSection(th, "Network server"),
+		matx.RigidCheckBox(th, "Start on connect", &nsEnabledCheckbox),
+		matx.RigidEditor(th, "Transport:", "udp or mqtt", &nsTransportEdit),
+		matx.RigidEditor(th, "UDP bind:", lds.DefaultNSBind, &nsBindEdit),
+		matx.RigidEditor(th, "NetID:", lds.DefaultNSNetID, &nsNetIDEdit),
+		matx.RigidEditor(th, "JoinNonce:", "1", &nsJoinNonceEdit),
+	}
+
+	if netServer == nil {
+		widgets = append(widgets, matx.RigidButton(th, "Start", &nsStartButton))
+	} else {
+		widgets = append(widgets,
+			matx.RigidLabel(th, fmt.Sprintf("Running on %s", netServer.Addr())),
+			matx.RigidButton(th, "Stop", &nsStopButton),
+			matx.RigidEditor(th, "Downlink fPort:", "1", &nsFPortEdit),
+			matx.RigidEditor(th, "Downlink payload:", "<hex>", &nsPayloadEdit),
+			matx.RigidEditor(th, "MAC commands:", "DevStatusReq, 0350ff0001", &nsMACCommandsEdit),
+			matx.RigidCheckBox(th, "Confirmed", &nsConfirmedCheckbox),
+			matx.RigidButton(th, "Queue downlink", &nsEnqueueButton),
+		)
+	}
+
+	inset := l.Inset{Left: unit.Dp(30)}
+	return l.Rigid(func(gtx l.Context) l.Dimensions {
+		return inset.Layout(gtx, func(gtx l.Context) l.Dimensions {
+			return l.Flex{Axis: l.Vertical}.Layout(gtx, widgets...)
+		})
+	})
+}
+
+func startNetServer() error {
+	if netServer != nil {
+		return nil
+	}
+
+	ns, err := lds.NewNetworkServer(config)
+	if err != nil {
+		log.Errorf("couldn't start the network server: %s", err)
+		return err
+	}
+	netServer = ns
+	return nil
+}
+
+func stopNetServer() {
+	if netServer == nil {
+		return
+	}
+
+	if err := netServer.Close(); err != nil {
+		log.Errorf("network server close error: %s", err)
+	}
+	netServer = nil
+	log.Infoln("network server stopped")
+}
+
+// enqueueDownlink queues the downlink of the form on the network server.
+func enqueueDownlink() {
+	dl := &lds.NSDownlink{Confirmed: nsConfirmedCheckbox.Value}
+
+	var err error
+	if dl.Payload, err = hex.DecodeString(nsPayloadEdit.Text()); err != nil {
+		log.Errorf("downlink payload error: %s", err)
+		return
+	}
+	if len(dl.Payload) > 0 {
+		fPort, err := strconv.Atoi(nsFPortEdit.Text())
+		if err != nil || fPort < 1 || fPort > 223 {
+			log.Errorln("the downlink fPort must be between 1 and 223")
+			return
+		}
+		dl.FPort = uint8(fPort)
+	}
+
+	var commands []string
+	for _, c := range strings.Split(nsMACCommandsEdit.Text(), ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			commands = append(commands, c)
+		}
+	}
+	if dl.MACCommands, err = lds.ParseDownlinkMACCommands(commands); err != nil {
+		log.Errorln(err)
+		return
+	}
+
+	netServer.Enqueue(dl)
+}
+
+func (guiTarget) EnqueueDownlink(dl *lds.NSDownlink) (int, error) {
+	if netServer == nil {
+		return 0, lds.ErrNoNetworkServer
+	}
+	return netServer.Enqueue(dl), nil
+}
@@ -8,6 +8,7 @@ import (
 	l "gioui.org/layout"
 	"gioui.org/unit"
 	"gioui.org/widget/material"
+	"github.com/iegomez/lds/lds"
 	xmat "github.com/scartill/giox/material"
 	log "github.com/sirupsen/logrus"
 )
@@ -24,6 +25,32 @@ func writeHistory() {
 	log.Infof("wrote %d bytes to %s", n, f.Name())
 }
 
+// startJournal records the frames of the device to the journal until the GUI exits.
+func startJournal() {
+	if cDevice == nil {
+		log.Errorln("couldn't start the journal: the device isn't set")
+		return
+	}
+	journal, err := config.Journal.Open()
+	if err != nil {
+		log.Errorf("couldn't start the journal: %s", err)
+		return
+	}
+	cDevice.AddEventHandler(journal.Record)
+	log.Infof("recording frames to %s", config.Journal.Path)
+}
+
+// startCapture writes the traffic to the pcap files until the GUI exits.
+func startCapture() {
+	capture, err := config.Pcap.Open()
+	if err != nil {
+		log.Errorf("couldn't start the capture: %s", err)
+		return
+	}
+	lds.SetCapture(capture)
+	log.Infoln("capturing the traffic")
+}
+
 func setLevel(level log.Level) {
 	log.SetLevel(level)
 }
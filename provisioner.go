@@ -9,14 +9,11 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-type provisioner struct {
-	Hostname string `toml:"hostname"`
-	Username string `toml:"username"`
-	Password string `toml:"password"`
-	Path     string `toml:"path"`
-	Token    string
-	Devices  []*lsp.Device
-}
+//Provisioner session: the login token and the devices loaded from the csv file.
+var (
+	provToken   string
+	provDevices []*lsp.Device
+)
 
 var openProvisioner bool
 
@@ -51,7 +48,7 @@ func buildProvisioner(th *material.Theme) (l.FlexChild, bool) {
 		if err != nil {
 			log.Errorf("provisioner login error: %s", err)
 		} else {
-			config.Provisioner.Token = token
+			provToken = token
 			log.Infoln("login successful")
 		}
 	}
@@ -61,13 +58,13 @@ func buildProvisioner(th *material.Theme) (l.FlexChild, bool) {
 		if err != nil {
 			log.Errorf("provisioner load error: %s", err)
 		} else {
-			config.Provisioner.Devices = devices
+			provDevices = devices
 			log.Infoln("devices successfully loaded")
 		}
 	}
 
 	for provProvBtn.Clicked() {
-		lsp.Provision(config.Provisioner.Devices, config.Provisioner.Hostname, config.Provisioner.Token)
+		lsp.Provision(provDevices, config.Provisioner.Hostname, provToken)
 	}
 
 	for provCancelBtn.Clicked() {
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	l "gioui.org/layout"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	xmat "github.com/scartill/giox/material"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/iegomez/lds/lds"
+)
+
+var openReplay bool
+
+// replayCancel stops the running replay, it's nil when none is running.
+var replayCancel context.CancelFunc
+
+// Widgets
+var (
+	replayFileEdit         widget.Editor
+	replaySpeedEdit        widget.Editor
+	replayRecordedRadioChk widget.Bool
+	replayRunBtn           widget.Clickable
+	replayStopBtn          widget.Clickable
+	replayCancelBtn        widget.Clickable
+)
+
+func replayResetGuiValues() {
+	replayFileEdit.SetText(config.Journal.Path)
+	replaySpeedEdit.SetText("1")
+	replayRecordedRadioChk.Value = true
+}
+
+func runReplay(filename string, speed float64) {
+	if running || scenarioCancel != nil || replayCancel != nil {
+		log.Errorln("stop sending data, the running scenario or replay first")
+		return
+	}
+
+	if err := checkConnected(); err != nil {
+		log.Errorln(err)
+		return
+	}
+
+	entries, err := lds.LoadRecording(filename, &lds.JournalFilter{})
+	if err != nil {
+		log.Errorf("couldn't load the recording: %s", err)
+		return
+	}
+
+	setDevice()
+	setChannelSimulator()
+
+	var ctx context.Context
+	ctx, replayCancel = context.WithCancel(context.Background())
+	go func() {
+		replayer := &lds.Replayer{
+			Target:        guiTarget{},
+			Speed:         speed,
+			RecordedRadio: replayRecordedRadioChk.Value,
+			JoinTimeout:   10 * time.Second,
+			Wait:          5 * time.Second,
+		}
+		result, err := replayer.Run(ctx, entries)
+		log.Infof("replay: %s", result)
+		if err != nil {
+			log.Errorf("replay failed: %s", err)
+		}
+		replayCancel()
+		replayCancel = nil
+	}()
+}
+
+func buildReplay(th *material.Theme) (l.FlexChild, bool) {
+
+	for replayRunBtn.Clicked() {
+		speed, err := strconv.ParseFloat(replaySpeedEdit.Text(), 64)
+		if err != nil || speed < 0 {
+			log.Errorf("invalid replay speed %q", replaySpeedEdit.Text())
+		} else {
+			runReplay(replayFileEdit.Text(), speed)
+		}
+	}
+
+	for replayStopBtn.Clicked() {
+		if replayCancel != nil {
+			replayCancel()
+		}
+	}
+
+	for replayCancelBtn.Clicked() {
+		openReplay = false
+	}
+
+	button := xmat.RigidButton(th, "Run", &replayRunBtn)
+	if replayCancel != nil {
+		button = xmat.RigidButton(th, "Stop", &replayStopBtn)
+	}
+
+	widgets := l.Rigid(func(gtx l.Context) l.Dimensions {
+		return l.Flex{Axis: l.Vertical}.Layout(gtx,
+			xmat.RigidSection(th, "Replay recording"),
+			xmat.RigidEditor(th, "Journal:", lds.DefaultJournalPath, &replayFileEdit),
+			xmat.RigidEditor(th, "Speed:", "1", &replaySpeedEdit),
+			xmat.RigidCheckBox(th, "Recorded radio", &replayRecordedRadioChk),
+			xmat.RigidButton(th, "Cancel", &replayCancelBtn),
+			button,
+		)
+	})
+
+	return widgets, true
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	l "gioui.org/layout"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/brocaar/lorawan"
+	"github.com/scartill/giox"
+	xmat "github.com/scartill/giox/material"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/iegomez/lds/lds"
+)
+
+// Scenario files are listed from this directory.
+const scenariosDir = "scenarios"
+
+var openScenario bool
+
+// scenarioCancel stops the running scenario, it's nil when none is running.
+var scenarioCancel context.CancelFunc
+
+// Widgets
+var (
+	scenarioCombo     giox.Combo
+	scenarioRunBtn    widget.Clickable
+	scenarioStopBtn   widget.Clickable
+	scenarioCancelBtn widget.Clickable
+)
+
+// guiTarget runs scenarios and serves the HTTP API on the GUI device through the connected client.
+type guiTarget struct{}
+
+func (guiTarget) Join() error {
+	if !hasDevice() {
+		return lds.ErrNoDevice
+	}
+	return sendJoin()
+}
+
+func (guiTarget) Uplink(mType lorawan.MType, fPort uint8, payload []byte, macCommands []*lorawan.MACCommand, fCtrl lorawan.FCtrl) (uint32, error) {
+	if !hasDevice() {
+		return 0, lds.ErrNoDevice
+	}
+	return sendUplink(mType, fPort, payload, macCommands, fCtrl)
+}
+
+func (guiTarget) Reset() error {
+	if !hasDevice() {
+		return lds.ErrNoDevice
+	}
+	if err := cDevice.Reset(); err != nil {
+		return err
+	}
+	setDevice()
+	return nil
+}
+
+func (guiTarget) State() lds.Device {
+	return *cDevice
+}
+
+func (guiTarget) AddDownlinkHandler(h func(*lds.Downlink)) func() {
+	return cDevice.AddDownlinkHandler(h)
+}
+
+// listScenarios fills the combo with the toml files of the scenarios directory.
+func listScenarios() {
+	files, err := ioutil.ReadDir(scenariosDir)
+	if err != nil {
+		log.Errorf("couldn't list scenarios: %s", err)
+	}
+
+	names := []string{}
+	for _, info := range files {
+		filename := fmt.Sprintf("%s/%s", scenariosDir, info.Name())
+		if !strings.HasSuffix(filename, ".toml") {
+			continue
+		}
+
+		names = append(names, filename)
+	}
+
+	scenarioCombo = giox.MakeCombo(names, "<scenario>")
+}
+
+func runScenario(filename string) {
+	if running || scenarioCancel != nil || replayCancel != nil {
+		log.Errorln("stop sending data, the running scenario or replay first")
+		return
+	}
+
+	if err := checkConnected(); err != nil {
+		log.Errorln(err)
+		return
+	}
+
+	scenario, err := lds.LoadScenario(filename)
+	if err != nil {
+		log.Errorf("couldn't load the scenario: %s", err)
+		return
+	}
+
+	setDevice()
+	setChannelSimulator()
+
+	var ctx context.Context
+	ctx, scenarioCancel = context.WithCancel(context.Background())
+	go func() {
+		//Errors are logged by the runner.
+		runner := &lds.ScenarioRunner{Target: guiTarget{}, Config: config}
+		runner.Run(ctx, scenario)
+		scenarioCancel()
+		scenarioCancel = nil
+	}()
+}
+
+func buildScenario(th *material.Theme) (l.FlexChild, bool) {
+
+	for scenarioRunBtn.Clicked() {
+		runScenario(scenarioCombo.SelectedText())
+	}
+
+	for scenarioStopBtn.Clicked() {
+		if scenarioCancel != nil {
+			scenarioCancel()
+		}
+	}
+
+	for scenarioCancelBtn.Clicked() {
+		openScenario = false
+	}
+
+	button := xmat.RigidButton(th, "Run", &scenarioRunBtn)
+	if scenarioCancel != nil {
+		button = xmat.RigidButton(th, "Stop", &scenarioStopBtn)
+	}
+
+	widgets := l.Rigid(func(gtx l.Context) l.Dimensions {
+		return l.Flex{Axis: l.Vertical}.Layout(gtx,
+			xmat.RigidSection(th, "Run scenario"),
+			labelCombo(th, "Scenario", &scenarioCombo),
+			xmat.RigidButton(th, "Cancel", &scenarioCancelBtn),
+			button,
+		)
+	})
+
+	return widgets, true
+}